@@ -42,21 +42,47 @@ func main() {
 	}
 
 	// Initialize repositories
+	leagueRepo := repository.NewLeagueRepository(db)
 	teamRepo := repository.NewTeamRepository(db)
 	matchRepo := repository.NewMatchRepository(db)
-	leagueRepo := repository.NewLeagueStateRepository(db)
+	leagueStateRepo := repository.NewLeagueStateRepository(db)
+	seasonRepo := repository.NewSeasonRepository(db)
+	knockoutRepo := repository.NewKnockoutRepository(db)
+	groupRepo := repository.NewGroupRepository(db)
+	ratingRepo := repository.NewRatingRepository(db)
+	matchEventRepo := repository.NewMatchEventRepository(db)
+	playerRepo := repository.NewPlayerRepository(db)
+	absenceRepo := repository.NewAbsenceRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	snapshotRepo := repository.NewSnapshotRepository(db)
+	transactor := repository.NewTransactor(db)
 
 	// Initialize services
+	eventBroker := services.NewEventBroker()
+	leagueService := services.NewLeagueService(leagueRepo, leagueStateRepo, teamRepo)
 	teamService := services.NewTeamService(teamRepo)
-	fixtureService := services.NewFixtureService(teamRepo, matchRepo, leagueRepo)
-	simulationService := services.NewSimulationService(matchRepo, teamRepo, leagueRepo)
-	standingsService := services.NewStandingsService(matchRepo, teamRepo, leagueRepo)
+	seasonService := services.NewSeasonService(matchRepo, teamRepo, leagueStateRepo, seasonRepo, knockoutRepo)
+	knockoutService := services.NewKnockoutService(matchRepo, teamRepo, leagueStateRepo, knockoutRepo, groupRepo)
+	ratingService := services.NewRatingService(teamRepo, matchRepo, leagueStateRepo, ratingRepo, transactor)
+	availabilityService := services.NewAvailabilityService(playerRepo, absenceRepo, matchEventRepo, matchRepo, teamRepo, leagueStateRepo)
+	simulationService := services.NewSimulationService(matchRepo, teamRepo, leagueStateRepo, groupRepo, seasonService, knockoutService, ratingService, matchEventRepo, availabilityService, transactor)
+	fixtureService := services.NewFixtureService(teamRepo, matchRepo, leagueStateRepo, groupRepo, transactor, simulationService)
+	oddsService := services.NewOddsService(matchRepo, leagueStateRepo, availabilityService)
+	playerService := services.NewPlayerService(playerRepo, teamRepo, matchEventRepo)
+	standingsService := services.NewStandingsService(matchRepo, teamRepo, leagueStateRepo, knockoutRepo, groupRepo)
+	auditService := services.NewAuditService(auditRepo, snapshotRepo)
 
 	// Initialize handlers
-	teamHandler := handlers.NewTeamHandler(teamService)
-	fixtureHandler := handlers.NewFixtureHandler(fixtureService)
-	simulationHandler := handlers.NewSimulationHandler(simulationService, standingsService)
+	leagueHandler := handlers.NewLeagueHandler(leagueService, auditService, eventBroker)
+	teamHandler := handlers.NewTeamHandler(teamService, ratingService)
+	fixtureHandler := handlers.NewFixtureHandler(fixtureService, availabilityService, oddsService)
+	simulationHandler := handlers.NewSimulationHandler(simulationService, standingsService, auditService, eventBroker)
 	standingsHandler := handlers.NewStandingsHandler(standingsService)
+	seasonHandler := handlers.NewSeasonHandler(seasonService)
+	knockoutHandler := handlers.NewKnockoutHandler(knockoutService)
+	playerHandler := handlers.NewPlayerHandler(playerService)
+	eventHandler := handlers.NewEventHandler(eventBroker)
+	auditHandler := handlers.NewAuditHandler(auditService, standingsService, eventBroker)
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
@@ -68,7 +94,7 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowMethods: "GET,POST,PUT,DELETE,OPTIONS",
-		AllowHeaders: "Origin,Content-Type,Accept",
+		AllowHeaders: "Origin,Content-Type,Accept,Last-Event-ID,X-Actor",
 	}))
 
 	// Swagger documentation (embedded in binary)
@@ -79,7 +105,7 @@ func main() {
 	}))
 
 	// Setup routes
-	routes.Setup(app, teamHandler, fixtureHandler, simulationHandler, standingsHandler)
+	routes.Setup(app, leagueHandler, teamHandler, fixtureHandler, simulationHandler, standingsHandler, seasonHandler, knockoutHandler, playerHandler, eventHandler, auditHandler)
 
 	// Start server
 	log.Printf("Server starting on port %s", cfg.ServerPort)
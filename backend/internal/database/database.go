@@ -19,9 +19,60 @@ func Connect(cfg *config.Config) (*gorm.DB, error) {
 }
 
 func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	// Team names used to be unique across the whole database; they are now unique per league
+	if db.Migrator().HasIndex(&models.Team{}, "idx_teams_name") {
+		if err := db.Migrator().DropIndex(&models.Team{}, "idx_teams_name"); err != nil {
+			return err
+		}
+	}
+
+	if err := db.AutoMigrate(
+		&models.League{},
 		&models.Team{},
 		&models.Match{},
 		&models.LeagueState{},
-	)
+		&models.KnockoutTie{},
+		&models.GroupEntry{},
+		&models.Season{},
+		&models.SeasonStanding{},
+		&models.SeasonMatch{},
+		&models.TeamRating{},
+		&models.MatchEvent{},
+		&models.Player{},
+		&models.Absence{},
+		&models.AuditEntry{},
+		&models.AuditDelta{},
+	); err != nil {
+		return err
+	}
+
+	if err := migrateTeamStrengths(db); err != nil {
+		return err
+	}
+	return migrateToDefaultLeague(db)
+}
+
+// migrateTeamStrengths gives teams created before attack and defence existed both at their power
+func migrateTeamStrengths(db *gorm.DB) error {
+	for _, column := range []string{"attack", "defence"} {
+		if err := db.Model(&models.Team{}).Where(column+" = ?", 0).Update(column, gorm.Expr("power")).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateToDefaultLeague moves rows created before leagues existed into the default league
+func migrateToDefaultLeague(db *gorm.DB) error {
+	var league models.League
+	if err := db.Where(models.League{Name: models.DefaultLeagueName}).FirstOrCreate(&league).Error; err != nil {
+		return err
+	}
+
+	for _, model := range []interface{}{&models.Team{}, &models.Match{}, &models.LeagueState{}} {
+		if err := db.Model(model).Where("league_id = ?", 0).Update("league_id", league.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/services"
+)
+
+// defaultAuditLimit is the number of audit log entries returned unless asked otherwise
+const defaultAuditLimit = 50
+
+// actorHeader names whoever makes a request, for the audit log
+const actorHeader = "X-Actor"
+
+type AuditHandler struct {
+	auditService     services.AuditService
+	standingsService services.StandingsService
+	events           services.EventBroker
+}
+
+func NewAuditHandler(
+	auditService services.AuditService,
+	standingsService services.StandingsService,
+	events services.EventBroker,
+) *AuditHandler {
+	return &AuditHandler{
+		auditService:     auditService,
+		standingsService: standingsService,
+		events:           events,
+	}
+}
+
+// actor returns who made the request: the name sent in the X-Actor header, or the client's IP
+// address without one
+func actor(c *fiber.Ctx) string {
+	if name := strings.TrimSpace(c.Get(actorHeader)); name != "" {
+		return name
+	}
+	return c.IP()
+}
+
+// Track records the route's action in the league's audit log whenever it changes the league, even
+// if it then fails, so that undo never skips over work an action saved before failing. Only one
+// tracked action runs on a league at a time.
+func (h *AuditHandler) Track(action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ran := false
+		var err error
+		trackErr := h.auditService.Track(leagueID(c), action, actor(c), func() {
+			ran = true
+			err = c.Next()
+		})
+		if !ran {
+			if errors.Is(trackErr, services.ErrActionInProgress) {
+				return ErrorResponse(c, fiber.StatusConflict, trackErr.Error())
+			}
+			return ErrorResponse(c, fiber.StatusInternalServerError, trackErr.Error())
+		}
+
+		// The action went through either way, so the client still gets its response
+		if trackErr != nil {
+			log.Printf("Failed to record %s in the audit log of league %d: %v", action, leagueID(c), trackErr)
+		}
+		return err
+	}
+}
+
+// GetLog returns the league's audit log
+//
+//	@Summary		Get audit log
+//	@Description	Returns the actions that changed the league, newest first: who took each (the X-Actor header sent with the request, or the client's IP address), when, and every row it added, changed or removed with the values before and after. Rows are the state, teams, players, matches, knockout ties, group entries and archived seasons; changed rows only list the fields that changed, and before or after is null for a row added or removed. Undo and redo entries name the entry they reverted or reapplied in targetId, and undone marks actions currently undone. The log is append-only.
+//	@Tags			Audit
+//	@Accept			json
+//	@Produce		json
+//	@Param			limit	query		int					false	"Number of entries (default 50, 0 for all)"
+//	@Success		200		{object}	AuditLogResponse	"Success response with the audit log"
+//	@Failure		400		{object}	APIErrorResponse	"Invalid limit"
+//	@Failure		500		{object}	APIErrorResponse	"Internal server error"
+//	@Router			/audit [get]
+func (h *AuditHandler) GetLog(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", defaultAuditLimit)
+	if limit < 0 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid limit")
+	}
+
+	entries, err := h.auditService.GetLog(leagueID(c), limit)
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, auditEntriesToResponse(entries))
+}
+
+// Undo reverts the latest action
+//
+//	@Summary		Undo the latest action
+//	@Description	Puts the league back exactly as it was before the latest action in the audit log that isn't undone yet, and records the undo in the log. Calling it again undoes the action before, and so on. Everything the action changed is reverted, results, match events, absences, ratings and archived seasons included. Watchers get a restore event with the full simulation state.
+//	@Tags			Audit
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	AuditEntryFullResponse	"Success response with the undo's log entry"
+//	@Failure		400	{object}	APIErrorResponse		"Nothing to undo"
+//	@Failure		409	{object}	APIErrorResponse		"A week is being played live"
+//	@Failure		500	{object}	APIErrorResponse		"Internal server error"
+//	@Router			/simulation/undo [post]
+func (h *AuditHandler) Undo(c *fiber.Ctx) error {
+	entry, err := h.auditService.Undo(leagueID(c), actor(c))
+	return h.restored(c, entry, err)
+}
+
+// Redo reapplies the latest undone action
+//
+//	@Summary		Redo the latest undone action
+//	@Description	Puts the league back as the latest undone action left it, and records the redo in the log. Undone actions can be redone in turn until another action is taken. Watchers get a restore event with the full simulation state.
+//	@Tags			Audit
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	AuditEntryFullResponse	"Success response with the redo's log entry"
+//	@Failure		400	{object}	APIErrorResponse		"Nothing to redo"
+//	@Failure		409	{object}	APIErrorResponse		"A week is being played live"
+//	@Failure		500	{object}	APIErrorResponse		"Internal server error"
+//	@Router			/simulation/redo [post]
+func (h *AuditHandler) Redo(c *fiber.Ctx) error {
+	entry, err := h.auditService.Redo(leagueID(c), actor(c))
+	return h.restored(c, entry, err)
+}
+
+// restored responds to an undo or redo, pushing the restored state to the league's watchers
+func (h *AuditHandler) restored(c *fiber.Ctx, entry *models.AuditEntry, err error) error {
+	switch {
+	case errors.Is(err, services.ErrActionInProgress):
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrNothingToUndo), errors.Is(err, services.ErrNothingToRedo):
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	case err != nil:
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	state, err := h.standingsService.GetFullState(leagueID(c))
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	publish(h.events, leagueID(c), models.LeagueEventRestore, SimulationStateToResponse(state))
+	return SuccessResponse(c, auditEntryToResponse(entry))
+}
@@ -1,13 +1,39 @@
 package handlers
 
-import "github.com/zahidcakici/champions-league/internal/models"
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// leagueToResponse converts a League model to LeagueResponse
+func leagueToResponse(league *models.League) LeagueResponse {
+	return LeagueResponse{
+		ID:   league.ID,
+		Name: league.Name,
+	}
+}
+
+// leaguesToResponse converts a slice of League models to LeagueResponse slice
+func leaguesToResponse(leagues []models.League) []LeagueResponse {
+	responses := make([]LeagueResponse, len(leagues))
+	for i := range leagues {
+		responses[i] = leagueToResponse(&leagues[i])
+	}
+	return responses
+}
 
 // teamToResponse converts a Team model to TeamResponse
 func teamToResponse(team *models.Team) TeamResponse {
 	return TeamResponse{
-		ID:    team.ID,
-		Name:  team.Name,
-		Power: team.Power,
+		ID:      team.ID,
+		Name:    team.Name,
+		Power:   team.Power,
+		Country: team.Country,
+		Attack:  team.Attack,
+		Defence: team.Defence,
 	}
 }
 
@@ -23,13 +49,24 @@ func teamsToResponse(teams []models.Team) []TeamResponse {
 // matchToResponse converts a Match model to MatchResponse
 func matchToResponse(match *models.Match) MatchResponse {
 	return MatchResponse{
-		ID:        match.ID,
-		Week:      match.Week,
-		HomeTeam:  teamToResponse(&match.HomeTeam),
-		AwayTeam:  teamToResponse(&match.AwayTeam),
-		HomeScore: match.HomeScore,
-		AwayScore: match.AwayScore,
-		Played:    match.Played,
+		ID:                 match.ID,
+		Week:               match.Week,
+		Stage:              match.Stage,
+		Group:              match.Group,
+		HomeTeam:           teamToResponse(&match.HomeTeam),
+		AwayTeam:           teamToResponse(&match.AwayTeam),
+		HomeScore:          match.HomeScore,
+		AwayScore:          match.AwayScore,
+		Played:             match.Played,
+		Seed:               match.Seed,
+		TieID:              match.TieID,
+		Leg:                match.Leg,
+		Neutral:            match.Neutral,
+		Date:               dateToResponse(match.Date),
+		HomeExtraTimeScore: match.HomeExtraTimeScore,
+		AwayExtraTimeScore: match.AwayExtraTimeScore,
+		HomePenalties:      match.HomePenalties,
+		AwayPenalties:      match.AwayPenalties,
 	}
 }
 
@@ -42,17 +79,107 @@ func matchesToResponse(matches []models.Match) []MatchResponse {
 	return responses
 }
 
+// matchesWithOddsToResponse converts matches to MatchResponse slice, adding the odds of those that have them
+func matchesWithOddsToResponse(matches []models.Match, odds map[uint]models.MatchOdds) []MatchResponse {
+	responses := matchesToResponse(matches)
+	for i := range responses {
+		if matchOdds, ok := odds[responses[i].ID]; ok {
+			oddsResponse := matchOddsToResponse(&matchOdds)
+			responses[i].Odds = &oddsResponse
+		}
+	}
+	return responses
+}
+
+// matchOddsToResponse converts a MatchOdds model to MatchOddsResponse
+func matchOddsToResponse(odds *models.MatchOdds) MatchOddsResponse {
+	scores := make([]ScoreProbabilityResponse, len(odds.LikelyScores))
+	for i, score := range odds.LikelyScores {
+		scores[i] = ScoreProbabilityResponse{
+			HomeGoals:   score.HomeGoals,
+			AwayGoals:   score.AwayGoals,
+			Probability: score.Probability,
+		}
+	}
+	return MatchOddsResponse{
+		MatchID:           odds.MatchID,
+		HomeWin:           odds.HomeWin,
+		Draw:              odds.Draw,
+		AwayWin:           odds.AwayWin,
+		HomeExpectedGoals: odds.HomeExpectedGoals,
+		AwayExpectedGoals: odds.AwayExpectedGoals,
+		LikelyScores:      scores,
+		Over25:            odds.Over25,
+		Under25:           odds.Under25,
+	}
+}
+
+// weekFixturesToResponse converts a WeekFixtures model to WeekFixturesResponse, with the odds of
+// the matches that have them
+func weekFixturesToResponse(fixtures *models.WeekFixtures, odds map[uint]models.MatchOdds) WeekFixturesResponse {
+	return WeekFixturesResponse{
+		Week:     fixtures.Week,
+		Matches:  matchesWithOddsToResponse(fixtures.Matches, odds),
+		ByeTeams: teamsToResponse(fixtures.ByeTeams),
+	}
+}
+
 // LeagueStateToResponse converts a LeagueState model to LeagueStateResponse
 func LeagueStateToResponse(state *models.LeagueState) LeagueStateResponse {
 	return LeagueStateResponse{
-		CurrentWeek:     state.CurrentWeek,
-		TotalWeeks:      state.TotalWeeks,
-		FixturesCreated: state.FixturesCreated,
-		Started:         state.Started,
-		Completed:       state.Completed,
+		LeagueID:         state.LeagueID,
+		CurrentWeek:      state.CurrentWeek,
+		TotalWeeks:       state.TotalWeeks,
+		FixturesCreated:  state.FixturesCreated,
+		Started:          state.Started,
+		Completed:        state.Completed,
+		Seed:             state.Seed,
+		TiebreakerPreset: state.TiebreakerPreset,
+		Tiebreakers:      tiebreakersToResponse(state.TiebreakerRules()),
+		KnockoutTeams:    state.KnockoutTeams,
+		Groups:           state.Groups,
+		Format:           state.Format,
+		Engine:           state.Engine,
+		EngineParams: EngineParamsResponse{
+			HomeAdvantage:    state.EngineParams.HomeAdvantage,
+			BaseGoals:        state.EngineParams.BaseGoals,
+			MaxGoals:         state.EngineParams.MaxGoals,
+			Rho:              state.EngineParams.Rho,
+			EloHomeAdvantage: state.EngineParams.EloHomeAdvantage,
+			EloDrawRate:      state.EngineParams.EloDrawRate,
+		},
+		RatingUpdates:    state.RatingUpdates,
+		RatingK:          state.RatingK,
+		StartDate:        dateToResponse(state.StartDate),
+		MatchdayInterval: state.MatchdayInterval,
+		Fatigue: FatigueResponse{
+			Enabled:          state.Fatigue.Enabled,
+			RecoveryHalfLife: state.Fatigue.RecoveryHalfLife,
+			Penalty:          state.Fatigue.Penalty,
+			MaxPenalty:       state.Fatigue.MaxPenalty,
+		},
+		FutureEdits: state.FutureEdits,
 	}
 }
 
+// dateToResponse formats a date as 2006-01-02
+func dateToResponse(date *time.Time) *string {
+	if date == nil {
+		return nil
+	}
+	formatted := date.Format(time.DateOnly)
+	return &formatted
+}
+
+// tiebreakersToResponse converts a tiebreaker chain to its rule names
+func tiebreakersToResponse(rules []models.TiebreakerRule) []string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = string(rule)
+	}
+	return names
+}
+
 // TeamStandingToResponse converts a TeamStanding model to TeamStandingResponse
 func TeamStandingToResponse(standing *models.TeamStanding) TeamStandingResponse {
 	return TeamStandingResponse{
@@ -66,6 +193,26 @@ func TeamStandingToResponse(standing *models.TeamStanding) TeamStandingResponse
 		GoalsAgainst:   standing.GoalsAgainst,
 		GoalDifference: standing.GoalDifference,
 		Points:         standing.Points,
+		Remaining:      standing.Remaining,
+		DecidedBy:      string(standing.DecidedBy),
+		Qualification:  standing.Qualification,
+
+		TitleRace:         placeRaceToResponse(standing.TitleRace),
+		QualificationRace: placeRaceToResponse(standing.QualificationRace),
+	}
+}
+
+// placeRaceToResponse converts a PlaceRace model to PlaceRaceResponse, nil if the team has no such race
+func placeRaceToResponse(race *models.PlaceRace) *PlaceRaceResponse {
+	if race == nil {
+		return nil
+	}
+	return &PlaceRaceResponse{
+		Places:       race.Places,
+		Clinched:     race.Clinched,
+		Eliminated:   race.Eliminated,
+		PointsNeeded: race.PointsNeeded,
+		Exact:        race.Exact,
 	}
 }
 
@@ -78,6 +225,18 @@ func TeamStandingsToResponse(standings []models.TeamStanding) []TeamStandingResp
 	return responses
 }
 
+// groupTablesToResponse converts a slice of GroupTable models to GroupTableResponse slice
+func groupTablesToResponse(tables []models.GroupTable) []GroupTableResponse {
+	responses := make([]GroupTableResponse, len(tables))
+	for i := range tables {
+		responses[i] = GroupTableResponse{
+			Group:     tables[i].Group,
+			Standings: TeamStandingsToResponse(tables[i].Standings),
+		}
+	}
+	return responses
+}
+
 // ChampionshipPredictionToResponse converts a ChampionshipPrediction model to ChampionshipPredictionResponse
 func ChampionshipPredictionToResponse(prediction *models.ChampionshipPrediction) ChampionshipPredictionResponse {
 	return ChampionshipPredictionResponse{
@@ -96,6 +255,38 @@ func ChampionshipPredictionsToResponse(predictions []models.ChampionshipPredicti
 	return responses
 }
 
+// PredictionResultToResponse converts a PredictionResult model to PredictionsResponse
+func PredictionResultToResponse(result *models.PredictionResult) PredictionsResponse {
+	return PredictionsResponse{
+		Iterations:  result.Iterations,
+		Predictions: ChampionshipPredictionsToResponse(result.Predictions),
+	}
+}
+
+// positionPredictionsToResponse converts a PositionPredictionResult model to PositionPredictionsResponse
+func positionPredictionsToResponse(result *models.PositionPredictionResult) PositionPredictionsResponse {
+	predictions := make([]PositionPredictionResponse, len(result.Predictions))
+	for i, prediction := range result.Predictions {
+		predictions[i] = PositionPredictionResponse{
+			TeamID:         prediction.TeamID,
+			TeamName:       prediction.TeamName,
+			Positions:      prediction.Positions,
+			ExpectedPoints: prediction.ExpectedPoints,
+			Points: PointsBandsResponse{
+				P5:     prediction.Points.P5,
+				P25:    prediction.Points.P25,
+				Median: prediction.Points.Median,
+				P75:    prediction.Points.P75,
+				P95:    prediction.Points.P95,
+			},
+		}
+	}
+	return PositionPredictionsResponse{
+		Iterations:  result.Iterations,
+		Predictions: predictions,
+	}
+}
+
 // MatchResultToResponse converts a MatchResult model to MatchResultResponse
 func MatchResultToResponse(result *models.MatchResult) MatchResultResponse {
 	return MatchResultResponse{
@@ -127,10 +318,462 @@ func AllMatchesToResponse(allMatches map[int][]models.MatchResult) map[int][]Mat
 // SimulationStateToResponse converts a SimulationState model to SimulationStateResponse
 func SimulationStateToResponse(state *models.SimulationState) SimulationStateResponse {
 	return SimulationStateResponse{
-		LeagueState:        LeagueStateToResponse(&state.LeagueState),
-		Standings:          TeamStandingsToResponse(state.Standings),
-		CurrentWeekResults: MatchResultsToResponse(state.CurrentWeek),
-		AllMatches:         AllMatchesToResponse(state.AllMatches),
-		Predictions:        ChampionshipPredictionsToResponse(state.Predictions),
+		LeagueState:          LeagueStateToResponse(&state.LeagueState),
+		Standings:            TeamStandingsToResponse(state.Standings),
+		CurrentWeekResults:   MatchResultsToResponse(state.CurrentWeek),
+		AllMatches:           AllMatchesToResponse(state.AllMatches),
+		Predictions:          ChampionshipPredictionsToResponse(state.Predictions),
+		PredictionIterations: state.PredictionIterations,
+		Live:                 liveWeekToResponse(state.Live),
+		Edit:                 resultEditToResponse(state.Edit),
+	}
+}
+
+// resultEditToResponse converts a ResultEdit to ResultEditResponse, describing each change it made
+func resultEditToResponse(edit *models.ResultEdit) *ResultEditResponse {
+	if edit == nil {
+		return nil
+	}
+
+	changes := []string{}
+	match := &edit.Match
+	switch {
+	case edit.Cleared:
+		changes = append(changes, fmt.Sprintf("%s v %s (week %d) is unplayed again and will be simulated when its week is played",
+			match.HomeTeam.Name, match.AwayTeam.Name, match.Week))
+	case edit.PrePlayed:
+		changes = append(changes, fmt.Sprintf("%s v %s counts as played before week %d; the week's other matches will be simulated when it comes",
+			match.HomeTeam.Name, match.AwayTeam.Name, match.Week))
+	}
+	if edit.CurrentWeek < edit.PreviousWeek {
+		changes = append(changes, fmt.Sprintf("The current week went back from %d to %d, the last week with every match played",
+			edit.PreviousWeek, edit.CurrentWeek))
+	}
+	if edit.CurrentWeek > edit.PreviousWeek {
+		changes = append(changes, fmt.Sprintf("The current week moved on from %d to %d, every match up to it now being played",
+			edit.PreviousWeek, edit.CurrentWeek))
+	}
+	if edit.KnockoutDrawn {
+		changes = append(changes, "The league phase is over and the knockout bracket has been drawn")
+	}
+	if edit.Completed {
+		changes = append(changes, "The league is complete and the season has been archived")
+	}
+
+	return &ResultEditResponse{
+		Match:         matchToResponse(&edit.Match),
+		Cleared:       edit.Cleared,
+		PrePlayed:     edit.PrePlayed,
+		PreviousWeek:  edit.PreviousWeek,
+		CurrentWeek:   edit.CurrentWeek,
+		Completed:     edit.Completed,
+		KnockoutDrawn: edit.KnockoutDrawn,
+		Changes:       changes,
+	}
+}
+
+// seasonToResponse converts a Season model to SeasonResponse
+func seasonToResponse(season *models.Season) SeasonResponse {
+	return SeasonResponse{
+		ID:             season.ID,
+		Number:         season.Number,
+		Completed:      season.Completed,
+		WeeksPlayed:    season.WeeksPlayed,
+		TotalWeeks:     season.TotalWeeks,
+		Seed:           season.Seed,
+		ChampionTeamID: season.ChampionTeamID,
+		ChampionName:   season.ChampionName,
+		ArchivedAt:     season.ArchivedAt,
+	}
+}
+
+// seasonsToResponse converts a slice of Season models to SeasonResponse slice
+func seasonsToResponse(seasons []models.Season) []SeasonResponse {
+	responses := make([]SeasonResponse, len(seasons))
+	for i := range seasons {
+		responses[i] = seasonToResponse(&seasons[i])
+	}
+	return responses
+}
+
+// seasonDetailToResponse converts a Season model with its standings to SeasonDetailResponse
+func seasonDetailToResponse(season *models.Season) SeasonDetailResponse {
+	return SeasonDetailResponse{
+		SeasonResponse: seasonToResponse(season),
+		Standings:      seasonStandingsToResponse(season.Standings),
+	}
+}
+
+// seasonStandingsToResponse converts a slice of SeasonStanding models to SeasonStandingResponse slice
+func seasonStandingsToResponse(standings []models.SeasonStanding) []SeasonStandingResponse {
+	responses := make([]SeasonStandingResponse, len(standings))
+	for i, standing := range standings {
+		responses[i] = SeasonStandingResponse{
+			Position:       standing.Position,
+			TeamID:         standing.TeamID,
+			TeamName:       standing.TeamName,
+			Power:          standing.Power,
+			Played:         standing.Played,
+			Won:            standing.Won,
+			Drawn:          standing.Drawn,
+			Lost:           standing.Lost,
+			GoalsFor:       standing.GoalsFor,
+			GoalsAgainst:   standing.GoalsAgainst,
+			GoalDifference: standing.GoalDifference,
+			Points:         standing.Points,
+		}
+	}
+	return responses
+}
+
+// seasonMatchesToResponse converts a slice of SeasonMatch models to SeasonMatchResponse slice
+func seasonMatchesToResponse(matches []models.SeasonMatch) []SeasonMatchResponse {
+	responses := make([]SeasonMatchResponse, len(matches))
+	for i, match := range matches {
+		responses[i] = SeasonMatchResponse{
+			SeasonID:     match.SeasonID,
+			Week:         match.Week,
+			Stage:        match.Stage,
+			HomeTeamID:   match.HomeTeamID,
+			AwayTeamID:   match.AwayTeamID,
+			HomeTeamName: match.HomeTeamName,
+			AwayTeamName: match.AwayTeamName,
+			HomeScore:    match.HomeScore,
+			AwayScore:    match.AwayScore,
+			Seed:         match.Seed,
+		}
+	}
+	return responses
+}
+
+// allTimeTableToResponse converts a slice of AllTimeStanding models to AllTimeStandingResponse slice
+func allTimeTableToResponse(table []models.AllTimeStanding) []AllTimeStandingResponse {
+	responses := make([]AllTimeStandingResponse, len(table))
+	for i, row := range table {
+		responses[i] = AllTimeStandingResponse{
+			TeamID:         row.TeamID,
+			TeamName:       row.TeamName,
+			Seasons:        row.Seasons,
+			Titles:         row.Titles,
+			Played:         row.Played,
+			Won:            row.Won,
+			Drawn:          row.Drawn,
+			Lost:           row.Lost,
+			GoalsFor:       row.GoalsFor,
+			GoalsAgainst:   row.GoalsAgainst,
+			GoalDifference: row.GoalDifference,
+			Points:         row.Points,
+		}
+	}
+	return responses
+}
+
+// headToHeadToResponse converts a HeadToHeadRecord model to HeadToHeadResponse
+func headToHeadToResponse(record *models.HeadToHeadRecord) HeadToHeadResponse {
+	return HeadToHeadResponse{
+		TeamAID:    record.TeamAID,
+		TeamAName:  record.TeamAName,
+		TeamBID:    record.TeamBID,
+		TeamBName:  record.TeamBName,
+		Played:     record.Played,
+		TeamAWins:  record.TeamAWins,
+		Draws:      record.Draws,
+		TeamBWins:  record.TeamBWins,
+		TeamAGoals: record.TeamAGoals,
+		TeamBGoals: record.TeamBGoals,
+		Matches:    seasonMatchesToResponse(record.Matches),
+	}
+}
+
+// knockoutTieToResponse converts a KnockoutTie model to KnockoutTieResponse
+func knockoutTieToResponse(tie *models.KnockoutTie) KnockoutTieResponse {
+	return KnockoutTieResponse{
+		ID:             tie.ID,
+		Round:          tie.Round,
+		Slot:           tie.Slot,
+		TeamA:          teamToResponse(&tie.TeamA),
+		TeamB:          teamToResponse(&tie.TeamB),
+		TeamASeed:      tie.TeamASeed,
+		TeamBSeed:      tie.TeamBSeed,
+		SingleLeg:      tie.SingleLeg,
+		TeamAAggregate: tie.TeamAAggregate,
+		TeamBAggregate: tie.TeamBAggregate,
+		WinnerID:       tie.WinnerID,
+		DecidedBy:      tie.DecidedBy,
+		Matches:        matchesToResponse(tie.Matches),
+	}
+}
+
+// knockoutBracketToResponse converts a KnockoutBracket model to KnockoutBracketResponse
+func knockoutBracketToResponse(bracket *models.KnockoutBracket) KnockoutBracketResponse {
+	rounds := make([]KnockoutRoundResponse, len(bracket.Rounds))
+	for i := range bracket.Rounds {
+		round := &bracket.Rounds[i]
+		ties := make([]KnockoutTieResponse, len(round.Ties))
+		for j := range round.Ties {
+			ties[j] = knockoutTieToResponse(&round.Ties[j])
+		}
+		rounds[i] = KnockoutRoundResponse{Round: round.Round, Name: round.Name, Ties: ties}
+	}
+	return KnockoutBracketResponse{
+		Teams:      bracket.Teams,
+		Rounds:     rounds,
+		ChampionID: bracket.ChampionID,
+	}
+}
+
+// ratingHistoryToResponse converts a RatingHistory model to RatingHistoryResponse
+func ratingHistoryToResponse(history *models.RatingHistory) RatingHistoryResponse {
+	ratings := make([]TeamRatingResponse, len(history.Ratings))
+	for i, rating := range history.Ratings {
+		ratings[i] = TeamRatingResponse{
+			Week:   rating.Week,
+			Rating: rating.Rating,
+			Change: rating.Change,
+		}
+	}
+	return RatingHistoryResponse{
+		Team:          teamToResponse(&history.Team),
+		Rating:        history.Rating,
+		RatingUpdates: history.Enabled,
+		History:       ratings,
+	}
+}
+
+// ratingFitToResponse converts a RatingFit model to RatingFitResponse
+func ratingFitToResponse(fit *models.RatingFit) RatingFitResponse {
+	teams := make([]TeamFitResponse, len(fit.Teams))
+	for i, team := range fit.Teams {
+		teams[i] = TeamFitResponse{
+			TeamID:          team.TeamID,
+			TeamName:        team.TeamName,
+			Matches:         team.Matches,
+			Attack:          team.Attack,
+			Defence:         team.Defence,
+			Power:           team.Power,
+			PreviousAttack:  team.PreviousAttack,
+			PreviousDefence: team.PreviousDefence,
+			PreviousPower:   team.PreviousPower,
+		}
+	}
+	return RatingFitResponse{
+		Matches:                 fit.Matches,
+		HomeAdvantage:           fit.HomeAdvantage,
+		ConfiguredHomeAdvantage: fit.ConfiguredHomeAdvantage,
+		BaseGoals:               fit.BaseGoals,
+		LogLikelihood:           fit.LogLikelihood,
+		NullLogLikelihood:       fit.NullLogLikelihood,
+		Deviance:                fit.Deviance,
+		DegreesOfFreedom:        fit.DegreesOfFreedom,
+		PseudoR2:                fit.PseudoR2,
+		Iterations:              fit.Iterations,
+		Converged:               fit.Converged,
+		Applied:                 fit.Applied,
+		Teams:                   teams,
+	}
+}
+
+func matchTimelineToResponse(timeline *models.MatchTimeline) MatchTimelineResponse {
+	events := make([]MatchEventResponse, len(timeline.Events))
+	for i := range timeline.Events {
+		events[i] = matchEventToResponse(&timeline.Events[i], timeline.Match.HomeTeamID, timeline.Match.AwayTeamID)
+	}
+	return MatchTimelineResponse{
+		Match:             matchToResponse(&timeline.Match),
+		HalfTimeHomeScore: timeline.HalfTimeHomeScore,
+		HalfTimeAwayScore: timeline.HalfTimeAwayScore,
+		Events:            events,
+	}
+}
+
+// matchEventToResponse converts a MatchEvent model of a match between the given teams to MatchEventResponse
+func matchEventToResponse(event *models.MatchEvent, homeTeamID, awayTeamID uint) MatchEventResponse {
+	response := MatchEventResponse{
+		Sequence:        event.Sequence,
+		Period:          event.Period,
+		Minute:          event.Minute,
+		AddedTime:       event.AddedTime,
+		Type:            event.Type,
+		TeamID:          event.TeamID,
+		Slot:            event.Slot,
+		RelatedSlot:     event.RelatedSlot,
+		PlayerID:        event.PlayerID,
+		RelatedPlayerID: event.RelatedPlayerID,
+		Detail:          event.Detail,
+		HomeScore:       event.HomeScore,
+		AwayScore:       event.AwayScore,
+	}
+	switch event.TeamID {
+	case homeTeamID:
+		response.Side = "home"
+	case awayTeamID:
+		response.Side = "away"
+	}
+	if event.Slot > 0 {
+		response.Position = models.SlotPosition(event.Slot)
+	}
+	return response
+}
+
+func matchTimelinesToResponse(timelines []models.MatchTimeline) []MatchTimelineResponse {
+	result := make([]MatchTimelineResponse, len(timelines))
+	for i := range timelines {
+		result[i] = matchTimelineToResponse(&timelines[i])
+	}
+	return result
+}
+
+func playerToResponse(player *models.Player) PlayerResponse {
+	return PlayerResponse{
+		ID:          player.ID,
+		TeamID:      player.TeamID,
+		Name:        player.Name,
+		Position:    player.Position,
+		Rating:      player.Rating,
+		ShirtNumber: player.ShirtNumber,
+	}
+}
+
+func playersToResponse(players []models.Player) []PlayerResponse {
+	result := make([]PlayerResponse, len(players))
+	for i := range players {
+		result[i] = playerToResponse(&players[i])
+	}
+	return result
+}
+
+// leaderboardToResponse ranks the board, players level on both goals and assists sharing a rank
+func leaderboardToResponse(board []models.PlayerStats) []PlayerStatsResponse {
+	result := make([]PlayerStatsResponse, len(board))
+	for i, stats := range board {
+		rank := i + 1
+		if i > 0 && stats.Goals == board[i-1].Goals && stats.Assists == board[i-1].Assists {
+			rank = result[i-1].Rank
+		}
+		result[i] = PlayerStatsResponse{
+			Rank:        rank,
+			PlayerID:    stats.PlayerID,
+			PlayerName:  stats.PlayerName,
+			TeamID:      stats.TeamID,
+			TeamName:    stats.TeamName,
+			Position:    stats.Position,
+			ShirtNumber: stats.ShirtNumber,
+			Goals:       stats.Goals,
+			Assists:     stats.Assists,
+		}
+	}
+	return result
+}
+
+func availabilityToResponse(report []models.TeamAvailability) []TeamAvailabilityResponse {
+	result := make([]TeamAvailabilityResponse, len(report))
+	for i, team := range report {
+		absences := make([]PlayerAbsenceResponse, len(team.Absences))
+		for j, absence := range team.Absences {
+			absences[j] = PlayerAbsenceResponse{
+				PlayerID:     absence.PlayerID,
+				PlayerName:   absence.PlayerName,
+				Position:     absence.Position,
+				Reason:       absence.Reason,
+				Detail:       absence.Detail,
+				MatchID:      absence.MatchID,
+				IncurredWeek: absence.Week,
+				Length:       absence.Length,
+				ReturnWeek:   absence.ReturnWeek,
+			}
+		}
+		result[i] = TeamAvailabilityResponse{
+			TeamID:              team.TeamID,
+			TeamName:            team.TeamName,
+			Week:                team.Week,
+			SquadSize:           team.SquadSize,
+			Available:           playersToResponse(team.Available),
+			Absences:            absences,
+			AttackAvailability:  team.AttackAvailability,
+			DefenceAvailability: team.DefenceAvailability,
+			MatchDate:           dateToResponse(team.MatchDate),
+			RestDays:            team.RestDays,
+			Freshness:           team.Freshness,
+		}
+	}
+	return result
+}
+
+// leagueEventToResponse converts a LeagueEvent model to LeagueEventResponse
+func leagueEventToResponse(event *models.LeagueEvent) LeagueEventResponse {
+	return LeagueEventResponse{
+		ID:   event.ID,
+		Type: event.Type,
+		Data: json.RawMessage(event.Data),
+	}
+}
+
+// liveWeekToResponse converts a LiveWeek model to LiveWeekResponse, nil if no week is live
+func liveWeekToResponse(week *models.LiveWeek) *LiveWeekResponse {
+	if week == nil {
+		return nil
+	}
+	matches := make([]LiveMatchResponse, len(week.Matches))
+	for i := range week.Matches {
+		matches[i] = liveMatchToResponse(&week.Matches[i])
+	}
+	return &LiveWeekResponse{
+		Week:      week.Week,
+		StartedAt: week.StartedAt,
+		Duration:  int(week.Duration / time.Second),
+		Minute:    week.Minute,
+		Matches:   matches,
+	}
+}
+
+func liveMatchToResponse(match *models.LiveMatch) LiveMatchResponse {
+	return LiveMatchResponse{
+		MatchID:      match.MatchID,
+		HomeTeamID:   match.HomeTeamID,
+		HomeTeamName: match.HomeTeamName,
+		AwayTeamID:   match.AwayTeamID,
+		AwayTeamName: match.AwayTeamName,
+		HomeScore:    match.HomeScore,
+		AwayScore:    match.AwayScore,
+		Finished:     match.Finished,
+	}
+}
+
+// liveGoalToResponse converts a goal of a live match to LiveGoalResponse
+func liveGoalToResponse(week int, match *models.LiveMatch, goal *models.MatchEvent) LiveGoalResponse {
+	return LiveGoalResponse{
+		Week:  week,
+		Match: liveMatchToResponse(match),
+		Goal:  matchEventToResponse(goal, match.HomeTeamID, match.AwayTeamID),
+	}
+}
+
+// auditEntryToResponse converts an audit log entry to AuditEntryResponse
+func auditEntryToResponse(entry *models.AuditEntry) AuditEntryResponse {
+	changes := make([]AuditChangeResponse, len(entry.Changes))
+	for i, change := range entry.Changes {
+		changes[i] = AuditChangeResponse{
+			Kind:   change.Kind,
+			ID:     change.ID,
+			Before: change.Before,
+			After:  change.After,
+		}
+	}
+	return AuditEntryResponse{
+		ID:        entry.ID,
+		Action:    entry.Action,
+		Actor:     entry.Actor,
+		TargetID:  entry.TargetID,
+		Undone:    entry.Undone,
+		Changes:   changes,
+		CreatedAt: entry.CreatedAt,
+	}
+}
+
+func auditEntriesToResponse(entries []models.AuditEntry) []AuditEntryResponse {
+	result := make([]AuditEntryResponse, len(entries))
+	for i := range entries {
+		result[i] = auditEntryToResponse(&entries[i])
 	}
+	return result
 }
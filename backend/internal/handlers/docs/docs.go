@@ -22,9 +22,117 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/audit": {
+            "get": {
+                "description": "Returns the actions that changed the league, newest first: who took each (the X-Actor header sent with the request, or the client's IP address), when, and every row it added, changed or removed with the values before and after. Rows are the state, teams, players, matches, knockout ties, group entries and archived seasons; changed rows only list the fields that changed, and before or after is null for a row added or removed. Undo and redo entries name the entry they reverted or reapplied in targetId, and undone marks actions currently undone. The log is append-only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Get audit log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of entries (default 50, 0 for all)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the audit log",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.AuditLogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Streams the league's changes as Server-Sent Events, each with its id, type and JSON data: results (the week and the matches just played or edited, one event per week), standings (the table they leave), predictions (the championship predictions) reset (the full simulation state after a reset) and restore (the full simulation state after an undo or redo). Playing a week, playing all weeks, editing a result, resetting, undoing and redoing all push events to every subscriber. A reconnecting client sends the last id it saw in Last-Event-ID (or lastEventId) and gets the events it missed; if they are no longer kept, or the id is from before the server restarted, a resync event tells it to refetch the state. Deleting the league closes the stream. A client more than 64 events behind is disconnected and can reconnect the same way.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Stream league events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Last event seen",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last event seen, for clients that can't set headers",
+                        "name": "lastEventId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Event stream",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/ws": {
+            "get": {
+                "description": "Sends the same events as GET /events over a WebSocket, each as a JSON message with its id, type and data. A reconnecting client passes the last id it saw as lastEventId. Messages from the client are ignored.",
+                "tags": [
+                    "Events"
+                ],
+                "summary": "Stream league events over a WebSocket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Last event seen",
+                        "name": "lastEventId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "WebSocket of league events",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.LeagueEventResponse"
+                        }
+                    },
+                    "426": {
+                        "description": "Not a WebSocket upgrade request",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/fixtures": {
             "get": {
-                "description": "Returns all fixtures across all weeks",
+                "description": "Returns all fixtures across all weeks. With odds=true every unplayed match carries its odds, as from /fixtures/{id}/odds.",
                 "consumes": [
                     "application/json"
                 ],
@@ -35,6 +143,14 @@ const docTemplate = `{
                     "Fixtures"
                 ],
                 "summary": "Get all fixtures",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include the odds of unplayed matches",
+                        "name": "odds",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "Success response with fixtures array",
@@ -53,7 +169,7 @@ const docTemplate = `{
         },
         "/fixtures/generate": {
             "post": {
-                "description": "Creates a round-robin fixture schedule for all teams (6 weeks, home and away)",
+                "description": "Creates a double round-robin fixture schedule for all teams (home and away). Odd-sized leagues get a bye each week.",
                 "consumes": [
                     "application/json"
                 ],
@@ -71,6 +187,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/internal_handlers.FixturesListResponse"
                         }
                     },
+                    "409": {
+                        "description": "A week is being played live, or another request changed the league first",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
@@ -80,9 +202,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/fixtures/{week}": {
-            "get": {
-                "description": "Returns all fixtures for a specific week number",
+        "/fixtures/match/{id}/date": {
+            "put": {
+                "description": "Moves an unplayed match to another day in its week: from the week's date up to the day before the next week's. Fixtures are scheduled from the league's startDate every matchdayInterval days; with fatigue turned on, the rest a team gets between matches changes its strength.",
                 "consumes": [
                     "application/json"
                 ],
@@ -92,25 +214,46 @@ const docTemplate = `{
                 "tags": [
                     "Fixtures"
                 ],
-                "summary": "Get fixtures by week",
+                "summary": "Reschedule a match",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Week number (1-6)",
-                        "name": "week",
+                        "description": "Match ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New match date",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RescheduleMatchRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success response with fixtures for the week",
+                        "description": "Success response with the rescheduled match",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.FixturesListResponse"
+                            "$ref": "#/definitions/internal_handlers.MatchFullResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid week number",
+                        "description": "Invalid request, match already played or date outside the match's week",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Match not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or another request changed the league first",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.APIErrorResponse"
                         }
@@ -124,9 +267,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/predictions": {
+        "/fixtures/{id}/odds": {
             "get": {
-                "description": "Returns the probability of each team winning the championship (available from week 4)",
+                "description": "Returns the home win, draw and away win probabilities of an unplayed match over 90 minutes, each side's expected goals, the five most likely scores and the chances of over and under 2.5 goals. They are worked out from the score distribution of the league's match engine, with each side at the strength its absences and tiredness leave it that week; the minute-by-minute engine is taken as Poisson goals of the same means.",
                 "consumes": [
                     "application/json"
                 ],
@@ -134,14 +277,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Standings"
+                    "Fixtures"
+                ],
+                "summary": "Get match odds",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get championship predictions",
                 "responses": {
                     "200": {
-                        "description": "Success response with predictions array",
+                        "description": "Success response with the match odds",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.PredictionsListResponse"
+                            "$ref": "#/definitions/internal_handlers.MatchOddsFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid match ID or match already played",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Match not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
                         }
                     },
                     "500": {
@@ -153,9 +317,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/simulation/match/{id}": {
-            "put": {
-                "description": "Manually update the score of a specific match",
+        "/fixtures/{week}": {
+            "get": {
+                "description": "Returns all fixtures for a specific week number and the teams with a bye that week. With odds=true every unplayed match carries its odds, as from /fixtures/{id}/odds.",
                 "consumes": [
                     "application/json"
                 ],
@@ -163,36 +327,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Simulation"
+                    "Fixtures"
                 ],
-                "summary": "Update match result",
+                "summary": "Get fixtures by week",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Match ID",
-                        "name": "id",
+                        "description": "Week number",
+                        "name": "week",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Match score update",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.UpdateMatchResultRequest"
-                        }
+                        "type": "boolean",
+                        "description": "Include the odds of unplayed matches",
+                        "name": "odds",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Success response with updated simulation state",
+                        "description": "Success response with fixtures and byes for the week",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
+                            "$ref": "#/definitions/internal_handlers.WeekFixturesFullResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid match ID or request body",
+                        "description": "Invalid week number",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.APIErrorResponse"
                         }
@@ -206,9 +367,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/simulation/play-all": {
-            "post": {
-                "description": "Simulates all remaining matches until the season is complete",
+        "/fixtures/{week}/availability": {
+            "get": {
+                "description": "Returns every team's players available for a week and those missing it injured or suspended, with the share of its attack and defence the team is left with, and its match date, days of rest and freshness (the share of its strength tiredness leaves it, 1 unless the league models fatigue). Injuries keep a player out for 1 to 5 weeks; a straight red card bans the player for 3 of the team's matches, a second booking for 1 and every fifth yellow card of the season for 1. Teams without a squad are always at full strength.",
                 "consumes": [
                     "application/json"
                 ],
@@ -216,18 +377,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Simulation"
+                    "Fixtures"
+                ],
+                "summary": "Get squad availability by week",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Week number",
+                        "name": "week",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Play all remaining weeks",
                 "responses": {
                     "200": {
-                        "description": "Success response with final simulation state",
+                        "description": "Success response with each team's availability",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
+                            "$ref": "#/definitions/internal_handlers.TeamAvailabilityListResponse"
                         }
                     },
                     "400": {
-                        "description": "Bad request (e.g., season already complete)",
+                        "description": "Invalid week number",
                         "schema": {
                             "$ref": "#/definitions/internal_handlers.APIErrorResponse"
                         }
@@ -241,9 +411,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/simulation/play-week": {
-            "post": {
-                "description": "Simulates all matches for the next week and returns updated state",
+        "/knockout": {
+            "get": {
+                "description": "Returns the knockout stage drawn so far. The top knockoutTeams of the league table are seeded into the bracket after the last league week; each later round is drawn once the previous one is decided. Ties are two-legged (aggregate, then extra time and penalties) except the single-leg final on neutral ground.",
                 "consumes": [
                     "application/json"
                 ],
@@ -251,20 +421,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Simulation"
+                    "Knockout"
                 ],
-                "summary": "Play next week",
+                "summary": "Get the knockout bracket",
                 "responses": {
                     "200": {
-                        "description": "Success response with updated simulation state",
-                        "schema": {
-                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad request (e.g., no more weeks to play)",
+                        "description": "Success response with the bracket",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                            "$ref": "#/definitions/internal_handlers.KnockoutBracketFullResponse"
                         }
                     },
                     "500": {
@@ -276,9 +440,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/simulation/reset": {
-            "post": {
-                "description": "Resets all match results and league state while keeping fixtures",
+        "/knockout/ties/{id}": {
+            "get": {
+                "description": "Returns a knockout tie with its legs, aggregate score and how it was decided",
                 "consumes": [
                     "application/json"
                 ],
@@ -286,14 +450,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Simulation"
+                    "Knockout"
+                ],
+                "summary": "Get a knockout tie",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Tie ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Reset simulation",
                 "responses": {
                     "200": {
-                        "description": "Success response with reset confirmation",
+                        "description": "Success response with the tie",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                            "$ref": "#/definitions/internal_handlers.KnockoutTieFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid tie ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Tie not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
                         }
                     },
                     "500": {
@@ -305,9 +490,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/simulation/state": {
+        "/leagues": {
             "get": {
-                "description": "Returns the complete current state including standings, predictions, and match results",
+                "description": "Returns all leagues. Every league-scoped endpoint is also available under /leagues/{leagueId}/...; the un-nested routes use the default league.",
                 "consumes": [
                     "application/json"
                 ],
@@ -315,14 +500,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Simulation"
+                    "Leagues"
                 ],
-                "summary": "Get simulation state",
+                "summary": "Get all leagues",
                 "responses": {
                     "200": {
-                        "description": "Success response with full simulation state",
+                        "description": "Success response with leagues array",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
+                            "$ref": "#/definitions/internal_handlers.LeaguesListResponse"
                         }
                     },
                     "500": {
@@ -332,11 +517,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/standings": {
-            "get": {
-                "description": "Returns the current league table with points, goals, and positions",
+            },
+            "post": {
+                "description": "Creates a new independent league with its own teams, fixtures and seed, starting with the default teams.",
                 "consumes": [
                     "application/json"
                 ],
@@ -344,14 +527,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Standings"
+                    "Leagues"
+                ],
+                "summary": "Create a league",
+                "parameters": [
+                    {
+                        "description": "League creation payload",
+                        "name": "league",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreateLeagueRequest"
+                        }
+                    }
                 ],
-                "summary": "Get league standings",
                 "responses": {
                     "200": {
-                        "description": "Success response with standings array",
+                        "description": "Success response with created league",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.StandingsListResponse"
+                            "$ref": "#/definitions/internal_handlers.LeagueFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (e.g., invalid input)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
                         }
                     },
                     "500": {
@@ -363,9 +563,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/teams": {
+        "/leagues/{leagueId}": {
             "get": {
-                "description": "Returns all teams participating in the tournament with their power ratings",
+                "description": "Returns a league by its ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -373,14 +573,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Teams"
+                    "Leagues"
+                ],
+                "summary": "Get a league",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "League ID",
+                        "name": "leagueId",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get all teams",
                 "responses": {
                     "200": {
-                        "description": "Success response with teams array",
+                        "description": "Success response with the league",
                         "schema": {
-                            "$ref": "#/definitions/internal_handlers.TeamsListResponse"
+                            "$ref": "#/definitions/internal_handlers.LeagueFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid league ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "League not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
                         }
                     },
                     "500": {
@@ -390,153 +611,3398 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
+            },
+            "delete": {
+                "description": "Deletes a league with its teams, fixtures, state and audit log, closing its event streams. It can't be undone. The default league cannot be deleted, nor a league playing a week live.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Leagues"
+                ],
+                "summary": "Delete a league",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "League ID",
+                        "name": "leagueId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (e.g., default league)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "League not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or another action on the league is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/predictions": {
+            "get": {
+                "description": "Returns the probability of each team winning the championship, estimated by simulating the remaining fixtures",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Standings"
+                ],
+                "summary": "Get championship predictions",
+                "responses": {
+                    "200": {
+                        "description": "Success response with predictions array",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PredictionsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/predictions/positions": {
+            "get": {
+                "description": "Returns each team's probability of finishing in every position of the league table (first place first, in percent), its expected final points and the 5th, 25th, 50th, 75th and 95th percentiles of its final points, estimated by simulating the remaining league fixtures. Summing the first two positions gives a team's chance of a top-two finish.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Standings"
+                ],
+                "summary": "Get finishing position predictions",
+                "responses": {
+                    "200": {
+                        "description": "Success response with position predictions",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PositionPredictionsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons": {
+            "get": {
+                "description": "Returns every archived season of the league. A season is archived when its last week is played, or when the simulation is reset with results on the board.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Get archived seasons",
+                "responses": {
+                    "200": {
+                        "description": "Success response with seasons array",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SeasonsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/all-time": {
+            "get": {
+                "description": "Returns every team's accumulated results and titles across all archived seasons",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Get the all-time table",
+                "responses": {
+                    "200": {
+                        "description": "Success response with the all-time table",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.AllTimeStandingsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/head-to-head": {
+            "get": {
+                "description": "Returns the wins, draws, goals and results of every archived meeting between two teams",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Get a head-to-head record",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "First team ID",
+                        "name": "teamA",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Second team ID",
+                        "name": "teamB",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the head-to-head record",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.HeadToHeadFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid team IDs",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Team not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}": {
+            "get": {
+                "description": "Returns an archived season with its champion and final table, including each team's power at the time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Get an archived season",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the season",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SeasonFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid season ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Season not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}/matches": {
+            "get": {
+                "description": "Returns every match result of an archived season",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Get an archived season's results",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the results",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SeasonMatchesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid season ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Season not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/seasons/{id}/standings": {
+            "get": {
+                "description": "Returns the final table of an archived season",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Seasons"
+                ],
+                "summary": "Get an archived season's table",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Season ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the final table",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SeasonStandingsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid season ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Season not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/match/{id}": {
+            "put": {
+                "description": "Manually update the score of a league match. A match after the current week can only be entered if the league's futureEdits setting is allow; it then counts as played, and its week only simulates the other matches. The current week moves on over every week the edit leaves fully played, which can draw the knockout bracket or complete the league. Editing a completed league updates its archived season. The returned state's edit explains what changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Update match result",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Match score update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdateMatchResultRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with updated simulation state and what the edit changed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid match ID or request body, or a future match while future edits are rejected",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Match not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or another request changed the league first",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Puts a played league match back to unplayed, without a score, to be simulated again when its week is played. The current week goes back to the last week with every match played. Results of a completed league can't be cleared. The returned state's edit explains what changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Clear match result",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with updated simulation state and what the edit changed",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid match ID, or a match that can't be cleared",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Match not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or another request changed the league first",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/match/{id}/timeline": {
+            "get": {
+                "description": "Returns a match with its events in the order they happened: goals (slot the scorer, relatedSlot the assist), yellow and red cards, injuries, substitutions (slot off, relatedSlot on) and the half_time, full_time and extra_time_end whistles, each with the score once it happened. Players are team-sheet slots: 1 goalkeeper, 2-5 defenders, 6-8 midfielders, 9-11 forwards, 12-18 substitutes. Matches not yet played, or whose result was edited by hand, have no events.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Get match timeline",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Match ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the match timeline",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.MatchTimelineFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid match ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Match not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/play-all": {
+            "post": {
+                "description": "Simulates all remaining matches until the season is complete. An optional seed replaces the league seed for these weeks.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Play all remaining weeks",
+                "parameters": [
+                    {
+                        "description": "Optional simulation seed",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PlayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with final simulation state",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (e.g., season already complete)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or another request changed the league first",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/play-week": {
+            "post": {
+                "description": "Simulates all matches for the next week and returns updated state. An optional seed replaces the league seed for this week.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Play next week",
+                "parameters": [
+                    {
+                        "description": "Optional simulation seed",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PlayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with updated simulation state",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (e.g., no more weeks to play)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or another request changed the league first",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/play-week/live": {
+            "post": {
+                "description": "Starts playing the next week out in real time: the 90 minutes take duration seconds (180 if omitted), extra time running on at the same pace. Kick-off and every goal are pushed to GET /events as kick_off and goal events as they happen, and the scores so far show in live on GET /simulation/state. The results are those play-week would give with the same seed, and are saved when the last match ends, followed by the usual results, standings and predictions events (or live_error if they can't be saved). Until then playing, editing results, changing settings, resetting and every other change to the league are refused with 409. The week is recorded in the audit log once it ends.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Play next week live",
+                "parameters": [
+                    {
+                        "description": "Optional simulation seed and duration",
+                        "name": "body",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.LivePlayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Success response with the week at kick-off",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.LiveWeekFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (e.g., no more weeks to play)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is already being played live",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/redo": {
+            "post": {
+                "description": "Puts the league back as the latest undone action left it, and records the redo in the log. Undone actions can be redone in turn until another action is taken. Watchers get a restore event with the full simulation state.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Redo the latest undone action",
+                "responses": {
+                    "200": {
+                        "description": "Success response with the redo's log entry",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.AuditEntryFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Nothing to redo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/reset": {
+            "post": {
+                "description": "Resets all match results and league state while keeping fixtures",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Reset simulation",
+                "responses": {
+                    "200": {
+                        "description": "Success response with reset confirmation",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.MessageResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or another request changed the league first",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/settings": {
+            "put": {
+                "description": "Updates league settings. The seed is the base for every simulated match, so the same seed and fixtures replay the same season. Tiebreakers are chosen with a preset (premier_league, uefa_group_stage) or a custom list of rules: head_to_head_points, head_to_head_goal_difference, head_to_head_goals_for, head_to_head_away_goals, goal_difference, goals_for, away_goals, wins, away_wins, drawing_of_lots. knockoutTeams (0, or a power of two up to the number of teams) adds a knockout stage for the top of the table after the league; it can only be changed before the league phase ends. groups (0, or a power of two up to 16) splits the teams into groups of four drawn from pots by power, keeping teams from the same country apart; the top two of each group reach the knockout stage. Groups can only be changed before fixtures are generated. format (round_robin or swiss) chooses a double round-robin or a Swiss league phase: four pots by power, each team playing two opponents from each pot, one at home and one away, in a single table; with a knockout stage of n teams the top n/2 qualify directly and the next n play off for the other places. The format can only be changed before fixtures are generated. engine (poisson, dixon_coles, elo or minute_by_minute) picks the match engine for matches played from then on, tuned with engineParams: homeAdvantage and baseGoals for the power-ratio expected goals, maxGoals, rho for the Dixon-Coles low-score correction, eloHomeAdvantage and eloDrawRate for the Elo model. ratingUpdates turns on Elo rating updates after every played or edited match, replaying the season's results so far; ratingK (above 0, at most 100) is their K-factor. Rated teams are simulated at their current rating instead of their power. startDate (YYYY-MM-DD) and matchdayInterval (1-28 days) schedule the weeks, and can only be changed before fixtures are generated. fatigue turns on the fatigue model: each match tires a team by its minutes over 90, half of which wears off every recoveryHalfLife days, and the team loses penalty of its strength per 90 minutes still in its legs, at most maxPenalty. futureEdits (reject or allow) decides whether results can be entered by hand for matches after the current week.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Update league settings",
+                "parameters": [
+                    {
+                        "description": "Settings to update",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdateSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with updated league state",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.LeagueStateFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, or settings the league can't take at this point",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or another request changed the league first",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/state": {
+            "get": {
+                "description": "Returns the complete current state including standings, predictions, and match results, and the scores so far while a week is played live",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Get simulation state",
+                "responses": {
+                    "200": {
+                        "description": "Success response with full simulation state",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.SimulationStateFullResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/undo": {
+            "post": {
+                "description": "Puts the league back exactly as it was before the latest action in the audit log that isn't undone yet, and records the undo in the log. Calling it again undoes the action before, and so on. Everything the action changed is reverted, results, match events, absences, ratings and archived seasons included. Watchers get a restore event with the full simulation state.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Undo the latest action",
+                "responses": {
+                    "200": {
+                        "description": "Success response with the undo's log entry",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.AuditEntryFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Nothing to undo",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/simulation/week/{week}/timeline": {
+            "get": {
+                "description": "Returns every match of a week with its events, as for a single match timeline",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Simulation"
+                ],
+                "summary": "Get week timelines",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Week number",
+                        "name": "week",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the week's timelines",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.MatchTimelinesListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid week number",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/standings": {
+            "get": {
+                "description": "Returns the current league table with points, goals, and positions. For a league without groups each team also carries its race for first place (when there is no knockout stage) and for the knockout places (when there is one): whether it has clinched them or is eliminated on points, and the fewest more points that make sure of them, worked out from the remaining fixtures between the teams.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Standings"
+                ],
+                "summary": "Get league standings",
+                "responses": {
+                    "200": {
+                        "description": "Success response with standings array",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.StandingsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/standings/assists": {
+            "get": {
+                "description": "Ranks the players by assists this season, goals breaking ties; players level on both share a rank. Players without an assist are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Standings"
+                ],
+                "summary": "Get top assists",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of players (default 10, 0 for all)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the leaderboard",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PlayerStatsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/standings/groups": {
+            "get": {
+                "description": "Returns the table of every group in group order; empty for a league without a group stage or before the groups are drawn. The top two of each group reach the knockout stage, and each team carries its race for those places from the group's remaining fixtures.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Standings"
+                ],
+                "summary": "Get group standings",
+                "responses": {
+                    "200": {
+                        "description": "Success response with group tables",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.GroupStandingsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/standings/scorers": {
+            "get": {
+                "description": "Ranks the players by goals this season, assists breaking ties; players level on both share a rank. Goals are credited from the match timelines, so manually edited results count for nobody. Players without a goal are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Standings"
+                ],
+                "summary": "Get top scorers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of players (default 10, 0 for all)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the leaderboard",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PlayerStatsListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid limit",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/teams": {
+            "get": {
+                "description": "Returns all teams participating in the tournament with their power ratings",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Get all teams",
+                "responses": {
+                    "200": {
+                        "description": "Success response with teams array",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.TeamsListResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/teams/ratings/fit": {
+            "post": {
+                "description": "Fits every listed team's attack and defence, the home advantage and the base goals to past results by maximum likelihood, using the power-ratio Poisson model the match engines play with: the home side expects 2 × baseGoals × attack × homeAdvantage / (attack × homeAdvantage + opposing defence) goals. Teams are named as in the league; teams without results keep their strengths. The strengths are scaled so the fitted teams keep their average power, then written onto the teams unless dryRun is set; that is only allowed between seasons. The response reports the fitted home advantage next to the league's homeAdvantage engine parameter, and the log-likelihood, deviance and McFadden pseudo-R² of the fit.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Fit team strengths to historical results",
+                "parameters": [
+                    {
+                        "description": "Historical results",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.FitRatingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the fitted strengths",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RatingFitFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (e.g., unknown team or too few results)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live, or a season is in progress",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/teams/{id}/players": {
+            "get": {
+                "description": "Returns the team's players by shirt number. For every match the best-rated players of each position make the team sheet: a goalkeeper, four defenders, three midfielders and three forwards to start, and a goalkeeper, two defenders, two midfielders and two forwards on the bench.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Get a team's squad",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the squad",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PlayersListResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid team ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Team not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a player to the team's squad. position is goalkeeper, defender, midfielder or forward; rating (1-100) makes a player more likely to score and assist among players of the same position; shirtNumber (1-99) must be free in the squad. Squads can change at any time and are used from the next match played.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Add a player",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Player to add",
+                        "name": "player",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.CreatePlayerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the created player",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PlayerFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (e.g., invalid input or shirt number taken)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Team not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/teams/{id}/players/{playerId}": {
+            "put": {
+                "description": "Changes a player's name, position, rating or shirt number; omitted fields are left unchanged. The player keeps their goals and assists.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Update a player",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "playerId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes to make",
+                        "name": "player",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.UpdatePlayerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the updated player",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.PlayerFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad request (e.g., invalid input or shirt number taken)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Team or player not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a player from the team's squad. Their goals and assists drop off the leaderboards; match timelines keep the player's ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Players"
+                ],
+                "summary": "Delete a player",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Player ID",
+                        "name": "playerId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid team or player ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Team or player not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A week is being played live",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/teams/{id}/ratings": {
+            "get": {
+                "description": "Returns the team's current Elo rating and its rating after each week of the season, starting from week 0. Ratings only move while rating updates are on (see PUT /simulation/settings); otherwise the rating is derived from the team's power and the history is empty.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Teams"
+                ],
+                "summary": "Get a team's rating history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Team ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Success response with the rating history",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.RatingHistoryFullResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid team ID",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Team not found",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handlers.APIErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
         "internal_handlers.APIErrorResponse": {
             "description": "Standard API error response",
             "type": "object",
             "properties": {
-                "error": {
+                "error": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Something went wrong"
+                }
+            }
+        },
+        "internal_handlers.APIResponse": {
+            "description": "Standard API response wrapper",
+            "type": "object",
+            "properties": {
+                "data": {},
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.AllTimeStandingResponse": {
+            "description": "All-time table row",
+            "type": "object",
+            "properties": {
+                "drawn": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "goalDifference": {
+                    "type": "integer",
+                    "example": 25
+                },
+                "goalsAgainst": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "goalsFor": {
+                    "type": "integer",
+                    "example": 55
+                },
+                "lost": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "played": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "points": {
+                    "type": "integer",
+                    "example": 60
+                },
+                "seasons": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "teamId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "teamName": {
+                    "type": "string",
+                    "example": "Manchester City"
+                },
+                "titles": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "won": {
+                    "type": "integer",
+                    "example": 18
+                }
+            }
+        },
+        "internal_handlers.AllTimeStandingsListResponse": {
+            "description": "All-time table across archived seasons",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.AllTimeStandingResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.AuditChangeResponse": {
+            "description": "A row an action added, changed or removed, with the values of its changed fields before and after; before is null for a row added and after for a row removed",
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "kind": {
+                    "type": "string",
+                    "example": "match"
+                }
+            }
+        },
+        "internal_handlers.AuditEntryFullResponse": {
+            "description": "Single audit log entry response",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.AuditEntryResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.AuditEntryResponse": {
+            "description": "An action that changed the league: who took it, when, and the rows it changed",
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update_result"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "alice"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.AuditChangeResponse"
+                    }
+                },
+                "createdAt": {
+                    "type": "string",
+                    "example": "2025-08-16T15:04:05Z"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "targetId": {
+                    "description": "Entry an undo or redo reverted or reapplied",
+                    "type": "integer",
+                    "example": 6
+                },
+                "undone": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "internal_handlers.AuditLogResponse": {
+            "description": "The league's audit log, newest first",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.AuditEntryResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.ChampionshipPredictionResponse": {
+            "description": "Championship prediction for a team",
+            "type": "object",
+            "properties": {
+                "percentage": {
+                    "type": "number",
+                    "example": 45.5
+                },
+                "teamId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "teamName": {
+                    "type": "string",
+                    "example": "Manchester City"
+                }
+            }
+        },
+        "internal_handlers.CreateLeagueRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Sunday League"
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "internal_handlers.CreatePlayerRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "position"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Erling Haaland"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "forward"
+                },
+                "rating": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1,
+                    "example": 91
+                },
+                "shirtNumber": {
+                    "type": "integer",
+                    "maximum": 99,
+                    "minimum": 1,
+                    "example": 9
+                }
+            }
+        },
+        "internal_handlers.EngineParamsRequest": {
+            "type": "object",
+            "properties": {
+                "baseGoals": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "eloDrawRate": {
+                    "type": "number",
+                    "example": 0.28
+                },
+                "eloHomeAdvantage": {
+                    "type": "number",
+                    "example": 65
+                },
+                "homeAdvantage": {
+                    "type": "number",
+                    "example": 1.1
+                },
+                "maxGoals": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "rho": {
+                    "type": "number",
+                    "example": -0.1
+                }
+            }
+        },
+        "internal_handlers.EngineParamsResponse": {
+            "description": "Match engine parameters",
+            "type": "object",
+            "properties": {
+                "baseGoals": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "eloDrawRate": {
+                    "type": "number",
+                    "example": 0.28
+                },
+                "eloHomeAdvantage": {
+                    "type": "number",
+                    "example": 65
+                },
+                "homeAdvantage": {
+                    "type": "number",
+                    "example": 1.1
+                },
+                "maxGoals": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "rho": {
+                    "type": "number",
+                    "example": -0.1
+                }
+            }
+        },
+        "internal_handlers.FatigueRequest": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "maxPenalty": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "penalty": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "recoveryHalfLife": {
+                    "type": "number",
+                    "example": 2
+                }
+            }
+        },
+        "internal_handlers.FatigueResponse": {
+            "description": "Fatigue model parameters",
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "maxPenalty": {
+                    "type": "number",
+                    "example": 0.25
+                },
+                "penalty": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "recoveryHalfLife": {
+                    "type": "number",
+                    "example": 2
+                }
+            }
+        },
+        "internal_handlers.FitRatingsRequest": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.HistoricalResultRequest"
+                    }
+                }
+            }
+        },
+        "internal_handlers.FixturesListResponse": {
+            "description": "List of all fixtures",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.MatchResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.GroupStandingsListResponse": {
+            "description": "Every group table of the group stage",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.GroupTableResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.GroupTableResponse": {
+            "description": "Group table",
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string",
+                    "example": "A"
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.TeamStandingResponse"
+                    }
+                }
+            }
+        },
+        "internal_handlers.HeadToHeadFullResponse": {
+            "description": "Head-to-head record between two teams",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.HeadToHeadResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.HeadToHeadResponse": {
+            "description": "Head-to-head record between two teams across archived seasons",
+            "type": "object",
+            "properties": {
+                "draws": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.SeasonMatchResponse"
+                    }
+                },
+                "played": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "teamAGoals": {
+                    "type": "integer",
+                    "example": 15
+                },
+                "teamAId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "teamAName": {
+                    "type": "string",
+                    "example": "Chelsea"
+                },
+                "teamAWins": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "teamBGoals": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "teamBId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "teamBName": {
+                    "type": "string",
+                    "example": "Arsenal"
+                },
+                "teamBWins": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "internal_handlers.HistoricalResultRequest": {
+            "type": "object",
+            "required": [
+                "awayTeam",
+                "homeTeam"
+            ],
+            "properties": {
+                "awayScore": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 1
+                },
+                "awayTeam": {
+                    "type": "string",
+                    "example": "Arsenal"
+                },
+                "homeScore": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 2
+                },
+                "homeTeam": {
+                    "type": "string",
+                    "example": "Chelsea"
+                },
+                "neutral": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "internal_handlers.KnockoutBracketFullResponse": {
+            "description": "Knockout bracket",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.KnockoutBracketResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.KnockoutBracketResponse": {
+            "description": "Knockout bracket; rounds appear as they are drawn",
+            "type": "object",
+            "properties": {
+                "championId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "rounds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.KnockoutRoundResponse"
+                    }
+                },
+                "teams": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "internal_handlers.KnockoutRoundResponse": {
+            "description": "Knockout round with its ties",
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Semi-finals"
+                },
+                "round": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ties": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.KnockoutTieResponse"
+                    }
+                }
+            }
+        },
+        "internal_handlers.KnockoutTieFullResponse": {
+            "description": "Single knockout tie",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.KnockoutTieResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.KnockoutTieResponse": {
+            "description": "Knockout tie; team A is the higher seed and hosts the second leg",
+            "type": "object",
+            "properties": {
+                "decidedBy": {
+                    "type": "string",
+                    "example": "aggregate"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.MatchResponse"
+                    }
+                },
+                "round": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "singleLeg": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "slot": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "teamA": {
+                    "$ref": "#/definitions/internal_handlers.TeamResponse"
+                },
+                "teamAAggregate": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "teamASeed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "teamB": {
+                    "$ref": "#/definitions/internal_handlers.TeamResponse"
+                },
+                "teamBAggregate": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "teamBSeed": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "winnerId": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_handlers.LeagueEventResponse": {
+            "description": "A change to the league: results (ResultsEventResponse), standings (array of TeamStandingResponse), predictions (PredictionsResponse), reset (SimulationStateResponse), restore (SimulationStateResponse), kick_off (LiveWeekResponse), goal (LiveGoalResponse), live_error (MessageData) or resync (null; refetch the state)",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "m2x9k7q1c4-12"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "results"
+                }
+            }
+        },
+        "internal_handlers.LeagueFullResponse": {
+            "description": "Single league response",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.LeagueResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.LeagueResponse": {
+            "description": "League information",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Default"
+                }
+            }
+        },
+        "internal_handlers.LeagueStateFullResponse": {
+            "description": "League state response",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.LeagueStateResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.LeagueStateResponse": {
+            "description": "Current league state",
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "currentWeek": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "engine": {
+                    "type": "string",
+                    "example": "poisson"
+                },
+                "engineParams": {
+                    "$ref": "#/definitions/internal_handlers.EngineParamsResponse"
+                },
+                "fatigue": {
+                    "$ref": "#/definitions/internal_handlers.FatigueResponse"
+                },
+                "fixturesCreated": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "format": {
+                    "type": "string",
+                    "example": "round_robin"
+                },
+                "futureEdits": {
+                    "type": "string",
+                    "example": "reject"
+                },
+                "groups": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "knockoutTeams": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "leagueId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "matchdayInterval": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "ratingK": {
+                    "type": "number",
+                    "example": 20
+                },
+                "ratingUpdates": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2025-08-16"
+                },
+                "started": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "tiebreakerPreset": {
+                    "type": "string",
+                    "example": "premier_league"
+                },
+                "tiebreakers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "goal_difference",
+                        "goals_for",
+                        "head_to_head_points",
+                        "head_to_head_away_goals",
+                        "drawing_of_lots"
+                    ]
+                },
+                "totalWeeks": {
+                    "type": "integer",
+                    "example": 6
+                }
+            }
+        },
+        "internal_handlers.LeaguesListResponse": {
+            "description": "List of all leagues",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.LeagueResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.LiveMatchResponse": {
+            "description": "Score so far of a match in a live week, extra time goals included",
+            "type": "object",
+            "properties": {
+                "awayScore": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "awayTeamId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "awayTeamName": {
+                    "type": "string",
+                    "example": "Arsenal"
+                },
+                "finished": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "homeScore": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "homeTeamId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "homeTeamName": {
+                    "type": "string",
+                    "example": "Chelsea"
+                },
+                "matchId": {
+                    "type": "integer",
+                    "example": 5
+                }
+            }
+        },
+        "internal_handlers.LivePlayRequest": {
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Real seconds for the 90 minutes, 180 if omitted",
+                    "type": "integer",
+                    "example": 180
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "internal_handlers.LiveWeekFullResponse": {
+            "description": "Live week response",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.LiveWeekResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.LiveWeekResponse": {
+            "description": "A week played out in real time, with the scores so far; its results are saved at the final whistle",
+            "type": "object",
+            "properties": {
+                "duration": {
+                    "description": "Real seconds for the 90 minutes",
+                    "type": "integer",
+                    "example": 180
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.LiveMatchResponse"
+                    }
+                },
+                "minute": {
+                    "type": "integer",
+                    "example": 37
+                },
+                "startedAt": {
+                    "type": "string",
+                    "example": "2025-08-16T15:00:00Z"
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "internal_handlers.MatchEventResponse": {
+            "description": "Match event. Players are team-sheet slots: 1 goalkeeper, 2-5 defenders, 6-8 midfielders, 9-11 forwards, 12-18 substitutes",
+            "type": "object",
+            "properties": {
+                "addedTime": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "awayScore": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "second_yellow"
+                },
+                "homeScore": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "minute": {
+                    "type": "integer",
+                    "example": 45
+                },
+                "period": {
+                    "type": "string",
+                    "example": "first_half"
+                },
+                "playerId": {
+                    "description": "Player in the slot, if the squad had one",
+                    "type": "integer",
+                    "example": 12
+                },
+                "position": {
+                    "type": "string",
+                    "example": "forward"
+                },
+                "relatedPlayerId": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "relatedSlot": {
+                    "description": "Assist of a goal, substitute coming on",
+                    "type": "integer",
+                    "example": 7
+                },
+                "sequence": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "side": {
+                    "type": "string",
+                    "example": "home"
+                },
+                "slot": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "teamId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "type": "string",
+                    "example": "goal"
+                }
+            }
+        },
+        "internal_handlers.MatchFullResponse": {
+            "description": "Single match response",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.MatchResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.MatchOddsFullResponse": {
+            "description": "Odds of a single match",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.MatchOddsResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.MatchOddsResponse": {
+            "description": "Outcome, goal and score probabilities of an unplayed match over 90 minutes",
+            "type": "object",
+            "properties": {
+                "awayExpectedGoals": {
+                    "type": "number",
+                    "example": 1.08
+                },
+                "awayWin": {
+                    "type": "number",
+                    "example": 0.277
+                },
+                "draw": {
+                    "type": "number",
+                    "example": 0.241
+                },
+                "homeExpectedGoals": {
+                    "type": "number",
+                    "example": 1.62
+                },
+                "homeWin": {
+                    "type": "number",
+                    "example": 0.482
+                },
+                "likelyScores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.ScoreProbabilityResponse"
+                    }
+                },
+                "matchId": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "over25": {
+                    "type": "number",
+                    "example": 0.508
+                },
+                "under25": {
+                    "type": "number",
+                    "example": 0.492
+                }
+            }
+        },
+        "internal_handlers.MatchResponse": {
+            "description": "Match information",
+            "type": "object",
+            "properties": {
+                "awayExtraTimeScore": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "awayPenalties": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "awayScore": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "awayTeam": {
+                    "$ref": "#/definitions/internal_handlers.TeamResponse"
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-08-16"
+                },
+                "group": {
+                    "type": "string",
+                    "example": "A"
+                },
+                "homeExtraTimeScore": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "homePenalties": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "homeScore": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "homeTeam": {
+                    "$ref": "#/definitions/internal_handlers.TeamResponse"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "leg": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "neutral": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "odds": {
+                    "description": "Unplayed matches, when asked for",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handlers.MatchOddsResponse"
+                        }
+                    ]
+                },
+                "played": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 8034217719
+                },
+                "stage": {
+                    "type": "string",
+                    "example": "league"
+                },
+                "tieId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_handlers.MatchResultResponse": {
+            "description": "Match result",
+            "type": "object",
+            "properties": {
+                "awayScore": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "awayTeamName": {
+                    "type": "string",
+                    "example": "Arsenal"
+                },
+                "homeScore": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "homeTeamName": {
+                    "type": "string",
+                    "example": "Chelsea"
+                }
+            }
+        },
+        "internal_handlers.MatchTimelineFullResponse": {
+            "description": "Match timeline",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.MatchTimelineResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.MatchTimelineResponse": {
+            "description": "Match with its events in order and the half-time score; matches not yet played or edited by hand have no events",
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.MatchEventResponse"
+                    }
+                },
+                "halfTimeAwayScore": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "halfTimeHomeScore": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "match": {
+                    "$ref": "#/definitions/internal_handlers.MatchResponse"
+                }
+            }
+        },
+        "internal_handlers.MatchTimelinesListResponse": {
+            "description": "Timelines of a week's matches",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.MatchTimelineResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.MessageData": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Operation completed successfully"
+                }
+            }
+        },
+        "internal_handlers.MessageResponse": {
+            "description": "Simple message response",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.MessageData"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.PlaceRaceResponse": {
+            "description": "Whether a team is sure of the top places or out of them on points, and the points that make sure",
+            "type": "object",
+            "properties": {
+                "clinched": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "eliminated": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "exact": {
+                    "description": "false if the analysis gave up: pointsNeeded is then only an upper bound, and the place may be clinched or lost already",
+                    "type": "boolean",
+                    "example": true
+                },
+                "places": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "pointsNeeded": {
+                    "description": "null if even winning every remaining match doesn't make sure",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "internal_handlers.PlayRequest": {
+            "type": "object",
+            "properties": {
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                }
+            }
+        },
+        "internal_handlers.PlayerAbsenceResponse": {
+            "description": "Player out injured or suspended",
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "red_card"
+                },
+                "incurredWeek": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "length": {
+                    "description": "Weeks injured, or team matches suspended",
+                    "type": "integer",
+                    "example": 3
+                },
+                "matchId": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "playerId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "playerName": {
+                    "type": "string",
+                    "example": "Erling Haaland"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "forward"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "suspension"
+                },
+                "returnWeek": {
+                    "description": "0 if the fixtures run out first",
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "internal_handlers.PlayerFullResponse": {
+            "description": "Single player response",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.PlayerResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.PlayerResponse": {
+            "description": "Player of a team's squad",
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Erling Haaland"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "forward"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 91
+                },
+                "shirtNumber": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "teamId": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "internal_handlers.PlayerStatsListResponse": {
+            "description": "Player leaderboard",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.PlayerStatsResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.PlayerStatsResponse": {
+            "description": "Player's goals and assists this season",
+            "type": "object",
+            "properties": {
+                "assists": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "goals": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "playerId": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "playerName": {
+                    "type": "string",
+                    "example": "Erling Haaland"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "forward"
+                },
+                "rank": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "shirtNumber": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "teamId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "teamName": {
+                    "type": "string",
+                    "example": "Manchester City"
+                }
+            }
+        },
+        "internal_handlers.PlayersListResponse": {
+            "description": "A team's squad by shirt number",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.PlayerResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.PointsBandsResponse": {
+            "description": "Final points percentiles across the simulated seasons",
+            "type": "object",
+            "properties": {
+                "median": {
+                    "type": "integer",
+                    "example": 68
+                },
+                "p25": {
+                    "type": "integer",
+                    "example": 64
+                },
+                "p5": {
+                    "type": "integer",
+                    "example": 58
+                },
+                "p75": {
+                    "type": "integer",
+                    "example": 72
+                },
+                "p95": {
+                    "type": "integer",
+                    "example": 77
+                }
+            }
+        },
+        "internal_handlers.PositionPredictionResponse": {
+            "description": "Probability of each final league position, first place first, with expected points",
+            "type": "object",
+            "properties": {
+                "expectedPoints": {
+                    "type": "number",
+                    "example": 67.8
+                },
+                "points": {
+                    "$ref": "#/definitions/internal_handlers.PointsBandsResponse"
+                },
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    },
+                    "example": [
+                        38.2,
+                        27.5,
+                        19.1,
+                        15.2
+                    ]
+                },
+                "teamId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "teamName": {
+                    "type": "string",
+                    "example": "Arsenal"
+                }
+            }
+        },
+        "internal_handlers.PositionPredictionsListResponse": {
+            "description": "Finishing position predictions",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.PositionPredictionsResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.PositionPredictionsResponse": {
+            "description": "Finishing position probabilities computed by Monte Carlo simulation of the remaining fixtures",
+            "type": "object",
+            "properties": {
+                "iterations": {
+                    "type": "integer",
+                    "example": 10000
+                },
+                "predictions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.PositionPredictionResponse"
+                    }
+                }
+            }
+        },
+        "internal_handlers.PredictionsListResponse": {
+            "description": "Championship predictions",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.PredictionsResponse"
+                },
+                "success": {
                     "type": "boolean",
                     "example": true
+                }
+            }
+        },
+        "internal_handlers.PredictionsResponse": {
+            "description": "Championship predictions computed by Monte Carlo simulation of the remaining fixtures",
+            "type": "object",
+            "properties": {
+                "iterations": {
+                    "type": "integer",
+                    "example": 10000
                 },
-                "message": {
-                    "type": "string",
-                    "example": "Something went wrong"
+                "predictions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.ChampionshipPredictionResponse"
+                    }
                 }
             }
         },
-        "internal_handlers.ChampionshipPredictionResponse": {
-            "description": "Championship prediction for a team",
+        "internal_handlers.RatingFitFullResponse": {
+            "description": "Team strengths fitted from historical results",
             "type": "object",
             "properties": {
-                "percentage": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.RatingFitResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.RatingFitResponse": {
+            "description": "Maximum likelihood fit of attack, defence and home advantage with its goodness of fit",
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "baseGoals": {
                     "type": "number",
-                    "example": 45.5
+                    "example": 1.38
                 },
-                "teamId": {
+                "configuredHomeAdvantage": {
+                    "type": "number",
+                    "example": 1.1
+                },
+                "converged": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "degreesOfFreedom": {
                     "type": "integer",
-                    "example": 1
+                    "example": 720
                 },
-                "teamName": {
-                    "type": "string",
-                    "example": "Manchester City"
+                "deviance": {
+                    "type": "number",
+                    "example": 812.4
+                },
+                "homeAdvantage": {
+                    "type": "number",
+                    "example": 1.27
+                },
+                "iterations": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "logLikelihood": {
+                    "type": "number",
+                    "example": -1104.6
+                },
+                "matches": {
+                    "type": "integer",
+                    "example": 380
+                },
+                "nullLogLikelihood": {
+                    "type": "number",
+                    "example": -1152.3
+                },
+                "pseudoR2": {
+                    "type": "number",
+                    "example": 0.041
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.TeamFitResponse"
+                    }
                 }
             }
         },
-        "internal_handlers.FixturesListResponse": {
-            "description": "List of all fixtures",
+        "internal_handlers.RatingHistoryFullResponse": {
+            "description": "Rating history of a team",
             "type": "object",
             "properties": {
                 "data": {
+                    "$ref": "#/definitions/internal_handlers.RatingHistoryResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.RatingHistoryResponse": {
+            "description": "Current Elo rating of a team and its rating after each week",
+            "type": "object",
+            "properties": {
+                "history": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/internal_handlers.MatchResponse"
+                        "$ref": "#/definitions/internal_handlers.TeamRatingResponse"
                     }
                 },
-                "success": {
+                "rating": {
+                    "type": "number",
+                    "example": 1912.4
+                },
+                "ratingUpdates": {
                     "type": "boolean",
                     "example": true
+                },
+                "team": {
+                    "$ref": "#/definitions/internal_handlers.TeamResponse"
                 }
             }
         },
-        "internal_handlers.LeagueStateResponse": {
-            "description": "Current league state",
+        "internal_handlers.RescheduleMatchRequest": {
+            "type": "object",
+            "required": [
+                "date"
+            ],
+            "properties": {
+                "date": {
+                    "type": "string",
+                    "example": "2025-08-19"
+                }
+            }
+        },
+        "internal_handlers.ResultEditResponse": {
+            "description": "A result entered or cleared by hand and what it changed in the league, each change also described in words",
             "type": "object",
             "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Week 5's match counts as played before its week; the rest of week 5 will be simulated when it comes"
+                    ]
+                },
+                "cleared": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "completed": {
+                    "description": "The edit completed the league",
                     "type": "boolean",
                     "example": false
                 },
                 "currentWeek": {
                     "type": "integer",
-                    "example": 3
+                    "example": 2
                 },
-                "fixturesCreated": {
+                "knockoutDrawn": {
+                    "description": "The edit ended the league phase and drew the bracket",
+                    "type": "boolean",
+                    "example": false
+                },
+                "match": {
+                    "$ref": "#/definitions/internal_handlers.MatchResponse"
+                },
+                "prePlayed": {
+                    "description": "Entered ahead of its week, counting as played before it",
                     "type": "boolean",
                     "example": true
                 },
-                "started": {
+                "previousWeek": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "internal_handlers.ScoreProbabilityResponse": {
+            "description": "Score after 90 minutes and its probability",
+            "type": "object",
+            "properties": {
+                "awayGoals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "homeGoals": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "probability": {
+                    "type": "number",
+                    "example": 0.118
+                }
+            }
+        },
+        "internal_handlers.SeasonDetailResponse": {
+            "description": "Archived season with its final table",
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "type": "string"
+                },
+                "championName": {
+                    "type": "string",
+                    "example": "Manchester City"
+                },
+                "championTeamId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "completed": {
                     "type": "boolean",
                     "example": true
                 },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "standings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.SeasonStandingResponse"
+                    }
+                },
                 "totalWeeks": {
                     "type": "integer",
                     "example": 6
+                },
+                "weeksPlayed": {
+                    "type": "integer",
+                    "example": 6
                 }
             }
         },
-        "internal_handlers.MatchResponse": {
-            "description": "Match information",
+        "internal_handlers.SeasonFullResponse": {
+            "description": "Archived season with its final table",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.SeasonDetailResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.SeasonMatchResponse": {
+            "description": "Archived match result",
             "type": "object",
             "properties": {
                 "awayScore": {
                     "type": "integer",
                     "example": 1
                 },
-                "awayTeam": {
-                    "$ref": "#/definitions/internal_handlers.TeamResponse"
+                "awayTeamId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "awayTeamName": {
+                    "type": "string",
+                    "example": "Arsenal"
+                },
+                "homeScore": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "homeTeamId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "homeTeamName": {
+                    "type": "string",
+                    "example": "Chelsea"
+                },
+                "seasonId": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 8034217719
+                },
+                "stage": {
+                    "type": "string",
+                    "example": "league"
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "internal_handlers.SeasonMatchesListResponse": {
+            "description": "Results of an archived season",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.SeasonMatchResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.SeasonResponse": {
+            "description": "Archived season summary",
+            "type": "object",
+            "properties": {
+                "archivedAt": {
+                    "type": "string"
+                },
+                "championName": {
+                    "type": "string",
+                    "example": "Manchester City"
+                },
+                "championTeamId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "completed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "totalWeeks": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "weeksPlayed": {
+                    "type": "integer",
+                    "example": 6
+                }
+            }
+        },
+        "internal_handlers.SeasonStandingResponse": {
+            "description": "Final table row of an archived season, with the team's power at the time",
+            "type": "object",
+            "properties": {
+                "drawn": {
+                    "type": "integer",
+                    "example": 1
                 },
-                "homeScore": {
+                "goalDifference": {
                     "type": "integer",
-                    "example": 2
+                    "example": 6
                 },
-                "homeTeam": {
-                    "$ref": "#/definitions/internal_handlers.TeamResponse"
+                "goalsAgainst": {
+                    "type": "integer",
+                    "example": 6
                 },
-                "id": {
+                "goalsFor": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "lost": {
                     "type": "integer",
                     "example": 1
                 },
                 "played": {
-                    "type": "boolean",
-                    "example": true
+                    "type": "integer",
+                    "example": 6
                 },
-                "week": {
+                "points": {
                     "type": "integer",
-                    "example": 1
-                }
-            }
-        },
-        "internal_handlers.MatchResultResponse": {
-            "description": "Match result",
-            "type": "object",
-            "properties": {
-                "awayScore": {
+                    "example": 13
+                },
+                "position": {
                     "type": "integer",
                     "example": 1
                 },
-                "awayTeamName": {
-                    "type": "string",
-                    "example": "Arsenal"
+                "power": {
+                    "type": "integer",
+                    "example": 90
                 },
-                "homeScore": {
+                "teamId": {
                     "type": "integer",
-                    "example": 2
+                    "example": 3
                 },
-                "homeTeamName": {
-                    "type": "string",
-                    "example": "Chelsea"
-                }
-            }
-        },
-        "internal_handlers.MessageData": {
-            "type": "object",
-            "properties": {
-                "message": {
+                "teamName": {
                     "type": "string",
-                    "example": "Operation completed successfully"
+                    "example": "Manchester City"
+                },
+                "won": {
+                    "type": "integer",
+                    "example": 4
                 }
             }
         },
-        "internal_handlers.MessageResponse": {
-            "description": "Simple message response",
+        "internal_handlers.SeasonStandingsListResponse": {
+            "description": "Final table of an archived season",
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/internal_handlers.MessageData"
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.SeasonStandingResponse"
+                    }
                 },
                 "success": {
                     "type": "boolean",
@@ -544,14 +4010,14 @@ const docTemplate = `{
                 }
             }
         },
-        "internal_handlers.PredictionsListResponse": {
-            "description": "Championship predictions",
+        "internal_handlers.SeasonsListResponse": {
+            "description": "List of archived seasons",
             "type": "object",
             "properties": {
                 "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/internal_handlers.ChampionshipPredictionResponse"
+                        "$ref": "#/definitions/internal_handlers.SeasonResponse"
                     }
                 },
                 "success": {
@@ -592,9 +4058,29 @@ const docTemplate = `{
                         "$ref": "#/definitions/internal_handlers.MatchResultResponse"
                     }
                 },
+                "edit": {
+                    "description": "After a result is entered or cleared by hand",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handlers.ResultEditResponse"
+                        }
+                    ]
+                },
                 "leagueState": {
                     "$ref": "#/definitions/internal_handlers.LeagueStateResponse"
                 },
+                "live": {
+                    "description": "While a week is played live",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handlers.LiveWeekResponse"
+                        }
+                    ]
+                },
+                "predictionIterations": {
+                    "type": "integer",
+                    "example": 10000
+                },
                 "predictions": {
                     "type": "array",
                     "items": {
@@ -625,10 +4111,152 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_handlers.TeamAvailabilityListResponse": {
+            "description": "Every team's squad availability for a week",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.TeamAvailabilityResponse"
+                    }
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.TeamAvailabilityResponse": {
+            "description": "Who a team can pick in a week and the strength they leave it",
+            "type": "object",
+            "properties": {
+                "absences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.PlayerAbsenceResponse"
+                    }
+                },
+                "attackAvailability": {
+                    "type": "number",
+                    "example": 0.93
+                },
+                "available": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.PlayerResponse"
+                    }
+                },
+                "defenceAvailability": {
+                    "type": "number",
+                    "example": 1
+                },
+                "freshness": {
+                    "type": "number",
+                    "example": 0.96
+                },
+                "matchDate": {
+                    "type": "string",
+                    "example": "2025-09-06"
+                },
+                "restDays": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "squadSize": {
+                    "type": "integer",
+                    "example": 18
+                },
+                "teamId": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "teamName": {
+                    "type": "string",
+                    "example": "Manchester City"
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "internal_handlers.TeamFitResponse": {
+            "description": "Attack and defence fitted from historical results, next to the team's previous strengths",
+            "type": "object",
+            "properties": {
+                "attack": {
+                    "type": "integer",
+                    "example": 88
+                },
+                "defence": {
+                    "type": "integer",
+                    "example": 71
+                },
+                "matches": {
+                    "type": "integer",
+                    "example": 38
+                },
+                "power": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "previousAttack": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "previousDefence": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "previousPower": {
+                    "type": "integer",
+                    "example": 80
+                },
+                "teamId": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "teamName": {
+                    "type": "string",
+                    "example": "Arsenal"
+                }
+            }
+        },
+        "internal_handlers.TeamRatingResponse": {
+            "description": "Elo rating after a week; week 0 is the rating the season started from",
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number",
+                    "example": -7.6
+                },
+                "rating": {
+                    "type": "number",
+                    "example": 1912.4
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
         "internal_handlers.TeamResponse": {
             "description": "Team information",
             "type": "object",
             "properties": {
+                "attack": {
+                    "type": "integer",
+                    "example": 92
+                },
+                "country": {
+                    "type": "string",
+                    "example": "England"
+                },
+                "defence": {
+                    "type": "integer",
+                    "example": 88
+                },
                 "id": {
                     "type": "integer",
                     "example": 1
@@ -647,6 +4275,11 @@ const docTemplate = `{
             "description": "Team standing in league table",
             "type": "object",
             "properties": {
+                "decidedBy": {
+                    "description": "Rule that ranked the team above ahead; empty for the leader or if level",
+                    "type": "string",
+                    "example": "goal_difference"
+                },
                 "drawn": {
                     "type": "integer",
                     "example": 1
@@ -675,6 +4308,22 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 7
                 },
+                "qualification": {
+                    "type": "string",
+                    "example": "playoff"
+                },
+                "qualificationRace": {
+                    "description": "Knockout places, or the top two of a group",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handlers.PlaceRaceResponse"
+                        }
+                    ]
+                },
+                "remaining": {
+                    "type": "integer",
+                    "example": 3
+                },
                 "teamId": {
                     "type": "integer",
                     "example": 1
@@ -683,6 +4332,14 @@ const docTemplate = `{
                     "type": "string",
                     "example": "Manchester City"
                 },
+                "titleRace": {
+                    "description": "Leagues without a knockout stage",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/internal_handlers.PlaceRaceResponse"
+                        }
+                    ]
+                },
                 "won": {
                     "type": "integer",
                     "example": 2
@@ -720,6 +4377,128 @@ const docTemplate = `{
                     "example": 2
                 }
             }
+        },
+        "internal_handlers.UpdatePlayerRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Erling Haaland"
+                },
+                "position": {
+                    "type": "string",
+                    "example": "forward"
+                },
+                "rating": {
+                    "type": "integer",
+                    "example": 92
+                },
+                "shirtNumber": {
+                    "type": "integer",
+                    "example": 9
+                }
+            }
+        },
+        "internal_handlers.UpdateSettingsRequest": {
+            "type": "object",
+            "properties": {
+                "engine": {
+                    "type": "string",
+                    "example": "dixon_coles"
+                },
+                "engineParams": {
+                    "$ref": "#/definitions/internal_handlers.EngineParamsRequest"
+                },
+                "fatigue": {
+                    "$ref": "#/definitions/internal_handlers.FatigueRequest"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "swiss"
+                },
+                "futureEdits": {
+                    "type": "string",
+                    "example": "allow"
+                },
+                "groups": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "knockoutTeams": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "matchdayInterval": {
+                    "type": "integer",
+                    "example": 7
+                },
+                "ratingK": {
+                    "type": "number",
+                    "example": 20
+                },
+                "ratingUpdates": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "seed": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "startDate": {
+                    "type": "string",
+                    "example": "2025-08-16"
+                },
+                "tiebreakerPreset": {
+                    "type": "string",
+                    "example": "uefa_group_stage"
+                },
+                "tiebreakers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "head_to_head_points",
+                        "goal_difference",
+                        "drawing_of_lots"
+                    ]
+                }
+            }
+        },
+        "internal_handlers.WeekFixturesFullResponse": {
+            "description": "Fixtures for a single week",
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/internal_handlers.WeekFixturesResponse"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_handlers.WeekFixturesResponse": {
+            "description": "Fixtures for a week, including the teams resting that week in odd-sized leagues",
+            "type": "object",
+            "properties": {
+                "byeTeams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.TeamResponse"
+                    }
+                },
+                "matches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_handlers.MatchResponse"
+                    }
+                },
+                "week": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
         }
     }
 }`
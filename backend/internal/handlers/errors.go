@@ -4,6 +4,24 @@ import "errors"
 
 // Custom validation errors
 var (
-	ErrInvalidHomeScore = errors.New("home score must be non-negative")
-	ErrInvalidAwayScore = errors.New("away score must be non-negative")
+	ErrInvalidHomeScore    = errors.New("home score must be non-negative")
+	ErrInvalidAwayScore    = errors.New("away score must be non-negative")
+	ErrInvalidSeed         = errors.New("seed must be between 0 and 2^53-1")
+	ErrInvalidLiveDuration = errors.New("duration must be between 1 and 3600 seconds")
+	ErrLeagueNameRequired  = errors.New("league name is required")
+
+	ErrInvalidTeamPower    = errors.New("Team power must be between 1 and 100")
+	ErrInvalidTeamStrength = errors.New("Team attack and defence must be between 1 and 100")
+
+	ErrInvalidStartDate = errors.New("startDate must be a date like 2025-08-16")
+	ErrInvalidMatchDate = errors.New("date must be a date like 2025-08-19")
+
+	ErrResultsRequired     = errors.New("results must list at least one match")
+	ErrInvalidResultTeams  = errors.New("every result needs a homeTeam and an awayTeam")
+	ErrInvalidResultScores = errors.New("result scores must be non-negative")
+
+	ErrPlayerNameRequired  = errors.New("player name is required")
+	ErrInvalidPosition     = errors.New("position must be goalkeeper, defender, midfielder or forward")
+	ErrInvalidPlayerRating = errors.New("player rating must be between 1 and 100")
+	ErrInvalidShirtNumber  = errors.New("shirt number must be between 1 and 99")
 )
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/services"
+)
+
+// eventHeartbeat is how often an idle stream is pinged, so dead connections are noticed
+const eventHeartbeat = 15 * time.Second
+
+type EventHandler struct {
+	events services.EventBroker
+}
+
+func NewEventHandler(events services.EventBroker) *EventHandler {
+	return &EventHandler{events: events}
+}
+
+// publish pushes an event to the league's watchers. The change it reports is saved by then, so
+// an event that can't be sent is only logged and the request still succeeds.
+func publish(events services.EventBroker, leagueID uint, eventType string, data interface{}) {
+	if err := events.Publish(leagueID, eventType, data); err != nil {
+		log.Printf("Failed to publish a %s event to league %d: %v", eventType, leagueID, err)
+	}
+}
+
+// lastEventID reads the last event a reconnecting subscriber saw, from the Last-Event-ID header
+// browsers send or the lastEventId query parameter; empty if it saw none
+func lastEventID(c *fiber.Ctx) string {
+	return c.Get("Last-Event-ID", c.Query("lastEventId"))
+}
+
+// StreamEvents streams the league's events as Server-Sent Events
+//
+//	@Summary		Stream league events
+//	@Description	Streams the league's changes as Server-Sent Events, each with its id, type and JSON data: results (the week and the matches just played or edited, one event per week), standings (the table they leave), predictions (the championship predictions) reset (the full simulation state after a reset) and restore (the full simulation state after an undo or redo). Playing a week, playing all weeks, editing a result, resetting, undoing and redoing all push events to every subscriber. A reconnecting client sends the last id it saw in Last-Event-ID (or lastEventId) and gets the events it missed; if they are no longer kept, or the id is from before the server restarted, a resync event tells it to refetch the state. Deleting the league closes the stream. A client more than 64 events behind is disconnected and can reconnect the same way.
+//	@Tags			Events
+//	@Produce		text/event-stream
+//	@Param			Last-Event-ID	header		string	false	"Last event seen"
+//	@Param			lastEventId		query		string	false	"Last event seen, for clients that can't set headers"
+//	@Success		200				{string}	string	"Event stream"
+//	@Router			/events [get]
+func (h *EventHandler) StreamEvents(c *fiber.Ctx) error {
+	c.Set("Content-Type", "text/event-stream")
+	c.Set("Cache-Control", "no-cache")
+	c.Set("Connection", "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // Keeps nginx from holding events back
+
+	subscription := h.events.Subscribe(leagueID(c), lastEventID(c))
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer subscription.Close()
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+
+		// Send the headers straight away rather than with the first event
+		fmt.Fprint(w, ": connected\n\n")
+		if err := w.Flush(); err != nil {
+			return
+		}
+		for {
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+				fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+			// A failed flush means the client has gone
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// StreamEventsWebSocket streams the league's events over a WebSocket
+//
+//	@Summary		Stream league events over a WebSocket
+//	@Description	Sends the same events as GET /events over a WebSocket, each as a JSON message with its id, type and data. A reconnecting client passes the last id it saw as lastEventId. Messages from the client are ignored.
+//	@Tags			Events
+//	@Param			lastEventId	query		string				false	"Last event seen"
+//	@Success		101			{object}	LeagueEventResponse	"WebSocket of league events"
+//	@Failure		426			{object}	APIErrorResponse	"Not a WebSocket upgrade request"
+//	@Router			/events/ws [get]
+func (h *EventHandler) StreamEventsWebSocket(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		return ErrorResponse(c, fiber.StatusUpgradeRequired, "Expected a WebSocket upgrade")
+	}
+	league, lastID := leagueID(c), lastEventID(c)
+	return websocket.New(func(conn *websocket.Conn) {
+		subscription := h.events.Subscribe(league, lastID)
+		defer subscription.Close()
+		heartbeat := time.NewTicker(eventHeartbeat)
+		defer heartbeat.Stop()
+
+		// Reading is what notices the client closing
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				if _, _, err := conn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+
+		for {
+			var err error
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+				err = conn.WriteJSON(leagueEventToResponse(&event))
+			case <-heartbeat.C:
+				err = conn.WriteMessage(websocket.PingMessage, nil)
+			case <-closed:
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	})(c)
+}
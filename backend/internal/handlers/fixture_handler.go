@@ -1,30 +1,43 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/models"
 	"github.com/zahidcakici/champions-league/internal/services"
 )
 
 type FixtureHandler struct {
-	fixtureService services.FixtureService
+	fixtureService      services.FixtureService
+	availabilityService services.AvailabilityService
+	oddsService         services.OddsService
 }
 
-func NewFixtureHandler(fixtureService services.FixtureService) *FixtureHandler {
-	return &FixtureHandler{fixtureService: fixtureService}
+func NewFixtureHandler(
+	fixtureService services.FixtureService,
+	availabilityService services.AvailabilityService,
+	oddsService services.OddsService,
+) *FixtureHandler {
+	return &FixtureHandler{fixtureService: fixtureService, availabilityService: availabilityService, oddsService: oddsService}
 }
 
 // GenerateFixtures creates the fixture schedule
 //
 //	@Summary		Generate fixtures
-//	@Description	Creates a round-robin fixture schedule for all teams (6 weeks, home and away)
+//	@Description	Creates a double round-robin fixture schedule for all teams (home and away). Odd-sized leagues get a bye each week.
 //	@Tags			Fixtures
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	FixturesListResponse	"Success response with generated fixtures"
+//	@Failure		409	{object}	APIErrorResponse		"A week is being played live, or another request changed the league first"
 //	@Failure		500	{object}	APIErrorResponse		"Internal server error"
 //	@Router			/fixtures/generate [post]
 func (h *FixtureHandler) GenerateFixtures(c *fiber.Ctx) error {
-	fixtures, err := h.fixtureService.GenerateFixtures()
+	fixtures, err := h.fixtureService.GenerateFixtures(leagueID(c))
+	if conflicting(err) {
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -34,30 +47,36 @@ func (h *FixtureHandler) GenerateFixtures(c *fiber.Ctx) error {
 // GetAllFixtures returns all fixtures
 //
 //	@Summary		Get all fixtures
-//	@Description	Returns all fixtures across all weeks
+//	@Description	Returns all fixtures across all weeks. With odds=true every unplayed match carries its odds, as from /fixtures/{id}/odds.
 //	@Tags			Fixtures
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	FixturesListResponse	"Success response with fixtures array"
-//	@Failure		500	{object}	APIErrorResponse		"Internal server error"
+//	@Param			odds	query		bool					false	"Include the odds of unplayed matches"
+//	@Success		200		{object}	FixturesListResponse	"Success response with fixtures array"
+//	@Failure		500		{object}	APIErrorResponse		"Internal server error"
 //	@Router			/fixtures [get]
 func (h *FixtureHandler) GetAllFixtures(c *fiber.Ctx) error {
-	fixtures, err := h.fixtureService.GetAllFixtures()
+	fixtures, err := h.fixtureService.GetAllFixtures(leagueID(c))
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
-	return SuccessResponse(c, matchesToResponse(fixtures))
+	odds, err := h.fixtureOdds(c, fixtures)
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, matchesWithOddsToResponse(fixtures, odds))
 }
 
 // GetFixturesByWeek returns fixtures for a specific week
 //
 //	@Summary		Get fixtures by week
-//	@Description	Returns all fixtures for a specific week number
+//	@Description	Returns all fixtures for a specific week number and the teams with a bye that week. With odds=true every unplayed match carries its odds, as from /fixtures/{id}/odds.
 //	@Tags			Fixtures
 //	@Accept			json
 //	@Produce		json
-//	@Param			week	path		int						true	"Week number (1-6)"
-//	@Success		200		{object}	FixturesListResponse	"Success response with fixtures for the week"
+//	@Param			week	path		int							true	"Week number"
+//	@Param			odds	query		bool						false	"Include the odds of unplayed matches"
+//	@Success		200		{object}	WeekFixturesFullResponse	"Success response with fixtures and byes for the week"
 //	@Failure		400		{object}	APIErrorResponse		"Invalid week number"
 //	@Failure		500		{object}	APIErrorResponse		"Internal server error"
 //	@Router			/fixtures/{week} [get]
@@ -67,9 +86,120 @@ func (h *FixtureHandler) GetFixturesByWeek(c *fiber.Ctx) error {
 		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid week number")
 	}
 
-	fixtures, err := h.fixtureService.GetFixturesByWeek(week)
+	fixtures, err := h.fixtureService.GetFixturesByWeek(leagueID(c), week)
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
-	return SuccessResponse(c, matchesToResponse(fixtures))
+	odds, err := h.fixtureOdds(c, fixtures.Matches)
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, weekFixturesToResponse(fixtures, odds))
+}
+
+// fixtureOdds returns the odds of the unplayed matches when the request asks for them with odds=true
+func (h *FixtureHandler) fixtureOdds(c *fiber.Ctx, matches []models.Match) (map[uint]models.MatchOdds, error) {
+	if !c.QueryBool("odds") {
+		return nil, nil
+	}
+	return h.oddsService.GetFixtureOdds(leagueID(c), matches)
+}
+
+// GetMatchOdds returns the odds of an unplayed match
+//
+//	@Summary		Get match odds
+//	@Description	Returns the home win, draw and away win probabilities of an unplayed match over 90 minutes, each side's expected goals, the five most likely scores and the chances of over and under 2.5 goals. They are worked out from the score distribution of the league's match engine, with each side at the strength its absences and tiredness leave it that week; the minute-by-minute engine is taken as Poisson goals of the same means.
+//	@Tags			Fixtures
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Match ID"
+//	@Success		200	{object}	MatchOddsFullResponse	"Success response with the match odds"
+//	@Failure		400	{object}	APIErrorResponse		"Invalid match ID or match already played"
+//	@Failure		404	{object}	APIErrorResponse		"Match not found"
+//	@Failure		500	{object}	APIErrorResponse		"Internal server error"
+//	@Router			/fixtures/{id}/odds [get]
+func (h *FixtureHandler) GetMatchOdds(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid match ID")
+	}
+
+	odds, err := h.oddsService.GetMatchOdds(leagueID(c), uint(id))
+	switch {
+	case errors.Is(err, services.ErrMatchNotFound):
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrMatchNotUpcoming):
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	case err != nil:
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, matchOddsToResponse(odds))
+}
+
+// GetWeekAvailability returns every team's squad availability for a week
+//
+//	@Summary		Get squad availability by week
+//	@Description	Returns every team's players available for a week and those missing it injured or suspended, with the share of its attack and defence the team is left with, and its match date, days of rest and freshness (the share of its strength tiredness leaves it, 1 unless the league models fatigue). Injuries keep a player out for 1 to 5 weeks; a straight red card bans the player for 3 of the team's matches, a second booking for 1 and every fifth yellow card of the season for 1. Teams without a squad are always at full strength.
+//	@Tags			Fixtures
+//	@Accept			json
+//	@Produce		json
+//	@Param			week	path		int								true	"Week number"
+//	@Success		200		{object}	TeamAvailabilityListResponse	"Success response with each team's availability"
+//	@Failure		400		{object}	APIErrorResponse				"Invalid week number"
+//	@Failure		500		{object}	APIErrorResponse				"Internal server error"
+//	@Router			/fixtures/{week}/availability [get]
+func (h *FixtureHandler) GetWeekAvailability(c *fiber.Ctx) error {
+	week, err := c.ParamsInt("week")
+	if err != nil || week < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid week number")
+	}
+
+	report, err := h.availabilityService.GetWeekAvailability(leagueID(c), week)
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, availabilityToResponse(report))
+}
+
+// RescheduleMatch moves an unplayed match to another day
+//
+//	@Summary		Reschedule a match
+//	@Description	Moves an unplayed match to another day in its week: from the week's date up to the day before the next week's. Fixtures are scheduled from the league's startDate every matchdayInterval days; with fatigue turned on, the rest a team gets between matches changes its strength.
+//	@Tags			Fixtures
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"Match ID"
+//	@Param			body	body		RescheduleMatchRequest	true	"New match date"
+//	@Success		200		{object}	MatchFullResponse		"Success response with the rescheduled match"
+//	@Failure		400		{object}	APIErrorResponse		"Invalid request, match already played or date outside the match's week"
+//	@Failure		404		{object}	APIErrorResponse		"Match not found"
+//	@Failure		409		{object}	APIErrorResponse		"A week is being played live, or another request changed the league first"
+//	@Failure		500		{object}	APIErrorResponse		"Internal server error"
+//	@Router			/fixtures/match/{id}/date [put]
+func (h *FixtureHandler) RescheduleMatch(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid match ID")
+	}
+
+	var req RescheduleMatchRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	match, err := h.fixtureService.RescheduleMatch(leagueID(c), uint(id), req.MatchDate())
+	switch {
+	case errors.Is(err, services.ErrMatchNotFound):
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrMatchAlreadyPlayed), errors.Is(err, services.ErrDateOutsideWeek):
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	case conflicting(err):
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	case err != nil:
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, matchToResponse(match))
 }
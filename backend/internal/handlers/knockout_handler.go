@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/services"
+)
+
+type KnockoutHandler struct {
+	knockoutService services.KnockoutService
+}
+
+func NewKnockoutHandler(knockoutService services.KnockoutService) *KnockoutHandler {
+	return &KnockoutHandler{knockoutService: knockoutService}
+}
+
+// GetBracket returns the knockout bracket
+//
+//	@Summary		Get the knockout bracket
+//	@Description	Returns the knockout stage drawn so far. The top knockoutTeams of the league table are seeded into the bracket after the last league week; each later round is drawn once the previous one is decided. Ties are two-legged (aggregate, then extra time and penalties) except the single-leg final on neutral ground.
+//	@Tags			Knockout
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	KnockoutBracketFullResponse	"Success response with the bracket"
+//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Router			/knockout [get]
+func (h *KnockoutHandler) GetBracket(c *fiber.Ctx) error {
+	bracket, err := h.knockoutService.GetBracket(leagueID(c))
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, knockoutBracketToResponse(bracket))
+}
+
+// GetTie returns a single knockout tie with its legs
+//
+//	@Summary		Get a knockout tie
+//	@Description	Returns a knockout tie with its legs, aggregate score and how it was decided
+//	@Tags			Knockout
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int						true	"Tie ID"
+//	@Success		200	{object}	KnockoutTieFullResponse	"Success response with the tie"
+//	@Failure		400	{object}	APIErrorResponse		"Invalid tie ID"
+//	@Failure		404	{object}	APIErrorResponse		"Tie not found"
+//	@Failure		500	{object}	APIErrorResponse		"Internal server error"
+//	@Router			/knockout/ties/{id} [get]
+func (h *KnockoutHandler) GetTie(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid tie ID")
+	}
+
+	tie, err := h.knockoutService.GetTie(leagueID(c), uint(id))
+	if errors.Is(err, services.ErrTieNotFound) {
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, knockoutTieToResponse(tie))
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/services"
+)
+
+// leagueIDKey is the fiber.Ctx locals key holding the league a request operates on
+const leagueIDKey = "leagueID"
+
+type LeagueHandler struct {
+	leagueService services.LeagueService
+	auditService  services.AuditService
+	events        services.EventBroker
+}
+
+func NewLeagueHandler(
+	leagueService services.LeagueService,
+	auditService services.AuditService,
+	events services.EventBroker,
+) *LeagueHandler {
+	return &LeagueHandler{leagueService: leagueService, auditService: auditService, events: events}
+}
+
+// ResolveLeague is a middleware that loads the league from the :leagueId path parameter
+func (h *LeagueHandler) ResolveLeague(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("leagueId")
+	if err != nil || id < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid league ID")
+	}
+
+	league, err := h.leagueService.GetLeague(uint(id))
+	if errors.Is(err, services.ErrLeagueNotFound) {
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Locals(leagueIDKey, league.ID)
+	return c.Next()
+}
+
+// ResolveDefaultLeague is a middleware that points the un-nested routes at the default league
+func (h *LeagueHandler) ResolveDefaultLeague(c *fiber.Ctx) error {
+	league, err := h.leagueService.GetDefaultLeague()
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Locals(leagueIDKey, league.ID)
+	return c.Next()
+}
+
+// leagueID returns the league resolved for the current request
+func leagueID(c *fiber.Ctx) uint {
+	id, ok := c.Locals(leagueIDKey).(uint)
+	if !ok {
+		return 0
+	}
+	return id
+}
+
+// GetAllLeagues returns all leagues
+//
+//	@Summary		Get all leagues
+//	@Description	Returns all leagues. Every league-scoped endpoint is also available under /leagues/{leagueId}/...; the un-nested routes use the default league.
+//	@Tags			Leagues
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	LeaguesListResponse	"Success response with leagues array"
+//	@Failure		500	{object}	APIErrorResponse	"Internal server error"
+//	@Router			/leagues [get]
+func (h *LeagueHandler) GetAllLeagues(c *fiber.Ctx) error {
+	leagues, err := h.leagueService.GetAllLeagues()
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, leaguesToResponse(leagues))
+}
+
+// GetLeague returns a single league
+//
+//	@Summary		Get a league
+//	@Description	Returns a league by its ID
+//	@Tags			Leagues
+//	@Accept			json
+//	@Produce		json
+//	@Param			leagueId	path		int					true	"League ID"
+//	@Success		200			{object}	LeagueFullResponse	"Success response with the league"
+//	@Failure		400			{object}	APIErrorResponse	"Invalid league ID"
+//	@Failure		404			{object}	APIErrorResponse	"League not found"
+//	@Failure		500			{object}	APIErrorResponse	"Internal server error"
+//	@Router			/leagues/{leagueId} [get]
+func (h *LeagueHandler) GetLeague(c *fiber.Ctx) error {
+	league, err := h.leagueService.GetLeague(leagueID(c))
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, leagueToResponse(league))
+}
+
+// CreateLeague creates a new league
+//
+//	@Summary		Create a league
+//	@Description	Creates a new independent league with its own teams, fixtures and seed, starting with the default teams.
+//	@Tags			Leagues
+//	@Accept			json
+//	@Produce		json
+//	@Param			league	body		CreateLeagueRequest	true	"League creation payload"
+//	@Success		200		{object}	LeagueFullResponse	"Success response with created league"
+//	@Failure		400		{object}	APIErrorResponse	"Bad request (e.g., invalid input)"
+//	@Failure		500		{object}	APIErrorResponse	"Internal server error"
+//	@Router			/leagues [post]
+func (h *LeagueHandler) CreateLeague(c *fiber.Ctx) error {
+	var req CreateLeagueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request payload")
+	}
+
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	league, err := h.leagueService.CreateLeague(req.Name, req.Seed)
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	return SuccessResponse(c, leagueToResponse(league))
+}
+
+// DeleteLeague deletes a league and everything it owns
+//
+//	@Summary		Delete a league
+//	@Description	Deletes a league with its teams, fixtures, state and audit log, closing its event streams. It can't be undone. The default league cannot be deleted, nor a league playing a week live.
+//	@Tags			Leagues
+//	@Accept			json
+//	@Produce		json
+//	@Param			leagueId	path		int					true	"League ID"
+//	@Success		200			{object}	APIResponse			"Success response"
+//	@Failure		400			{object}	APIErrorResponse	"Bad request (e.g., default league)"
+//	@Failure		404			{object}	APIErrorResponse	"League not found"
+//	@Failure		409			{object}	APIErrorResponse	"A week is being played live, or another action on the league is in progress"
+//	@Failure		500			{object}	APIErrorResponse	"Internal server error"
+//	@Router			/leagues/{leagueId} [delete]
+func (h *LeagueHandler) DeleteLeague(c *fiber.Ctx) error {
+	// The audit service holds off the league's actions while it is deleted
+	err := h.auditService.Remove(leagueID(c), func() error {
+		return h.leagueService.DeleteLeague(leagueID(c))
+	})
+	switch {
+	case errors.Is(err, services.ErrDefaultLeagueInUse):
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrActionInProgress):
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	case err != nil:
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	h.events.Drop(leagueID(c))
+	return SuccessResponse(c, fiber.Map{"deleted": true})
+}
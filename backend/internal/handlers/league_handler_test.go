@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/services"
+)
+
+// stubLeagueService implements services.LeagueService for testing
+type stubLeagueService struct {
+	leagues []models.League
+}
+
+func (s *stubLeagueService) GetAllLeagues() ([]models.League, error) {
+	return s.leagues, nil
+}
+
+func (s *stubLeagueService) GetLeague(id uint) (*models.League, error) {
+	for i := range s.leagues {
+		if s.leagues[i].ID == id {
+			return &s.leagues[i], nil
+		}
+	}
+	return nil, services.ErrLeagueNotFound
+}
+
+func (s *stubLeagueService) GetDefaultLeague() (*models.League, error) {
+	return &s.leagues[0], nil
+}
+
+func (s *stubLeagueService) CreateLeague(name string, _ *int64) (*models.League, error) {
+	league := models.League{ID: uint(len(s.leagues) + 1), Name: name}
+	s.leagues = append(s.leagues, league)
+	return &league, nil
+}
+
+func (s *stubLeagueService) DeleteLeague(_ uint) error {
+	return nil
+}
+
+func TestResolveLeague(t *testing.T) {
+	handler := NewLeagueHandler(&stubLeagueService{leagues: []models.League{
+		{ID: 1, Name: models.DefaultLeagueName},
+		{ID: 2, Name: "Mini League"},
+	}}, services.NewAuditService(nil, nil), services.NewEventBroker())
+
+	app := fiber.New()
+	echo := func(c *fiber.Ctx) error {
+		return c.SendString(strconv.Itoa(int(leagueID(c))))
+	}
+	app.Get("/leagues/:leagueId/echo", handler.ResolveLeague, echo)
+	app.Get("/echo", handler.ResolveDefaultLeague, echo)
+
+	testCases := []struct {
+		name     string
+		path     string
+		status   int
+		leagueID string
+	}{
+		{"Nested league", "/leagues/2/echo", fiber.StatusOK, "2"},
+		{"Default alias", "/echo", fiber.StatusOK, "1"},
+		{"Unknown league", "/leagues/9/echo", fiber.StatusNotFound, ""},
+		{"Invalid league ID", "/leagues/abc/echo", fiber.StatusBadRequest, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tc.path, nil))
+			if err != nil {
+				t.Fatalf("Failed to test: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+
+			body, _ := io.ReadAll(resp.Body)
+			if tc.leagueID != "" && string(body) != tc.leagueID {
+				t.Errorf("Expected league %s, got %s", tc.leagueID, string(body))
+			}
+		})
+	}
+}
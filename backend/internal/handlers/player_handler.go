@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/services"
+)
+
+// defaultLeaderboardLimit is the number of players on a leaderboard unless asked otherwise
+const defaultLeaderboardLimit = 10
+
+type PlayerHandler struct {
+	playerService services.PlayerService
+}
+
+func NewPlayerHandler(playerService services.PlayerService) *PlayerHandler {
+	return &PlayerHandler{playerService: playerService}
+}
+
+// GetSquad returns a team's players
+//
+//	@Summary		Get a team's squad
+//	@Description	Returns the team's players by shirt number. For every match the best-rated players of each position make the team sheet: a goalkeeper, four defenders, three midfielders and three forwards to start, and a goalkeeper, two defenders, two midfielders and two forwards on the bench.
+//	@Tags			Players
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int					true	"Team ID"
+//	@Success		200	{object}	PlayersListResponse	"Success response with the squad"
+//	@Failure		400	{object}	APIErrorResponse	"Invalid team ID"
+//	@Failure		404	{object}	APIErrorResponse	"Team not found"
+//	@Failure		500	{object}	APIErrorResponse	"Internal server error"
+//	@Router			/teams/{id}/players [get]
+func (h *PlayerHandler) GetSquad(c *fiber.Ctx) error {
+	teamID, err := c.ParamsInt("id")
+	if err != nil || teamID < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid team ID")
+	}
+
+	players, err := h.playerService.GetSquad(leagueID(c), uint(teamID))
+	if err != nil {
+		return playerErrorResponse(c, err)
+	}
+	return SuccessResponse(c, playersToResponse(players))
+}
+
+// CreatePlayer adds a player to a team's squad
+//
+//	@Summary		Add a player
+//	@Description	Adds a player to the team's squad. position is goalkeeper, defender, midfielder or forward; rating (1-100) makes a player more likely to score and assist among players of the same position; shirtNumber (1-99) must be free in the squad. Squads can change at any time and are used from the next match played.
+//	@Tags			Players
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int					true	"Team ID"
+//	@Param			player	body		CreatePlayerRequest	true	"Player to add"
+//	@Success		200		{object}	PlayerFullResponse	"Success response with the created player"
+//	@Failure		400		{object}	APIErrorResponse	"Bad request (e.g., invalid input or shirt number taken)"
+//	@Failure		404		{object}	APIErrorResponse	"Team not found"
+//	@Failure		409		{object}	APIErrorResponse	"A week is being played live"
+//	@Failure		500		{object}	APIErrorResponse	"Internal server error"
+//	@Router			/teams/{id}/players [post]
+func (h *PlayerHandler) CreatePlayer(c *fiber.Ctx) error {
+	teamID, err := c.ParamsInt("id")
+	if err != nil || teamID < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid team ID")
+	}
+
+	var req CreatePlayerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request payload")
+	}
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	player, err := h.playerService.CreatePlayer(leagueID(c), uint(teamID), req.Player())
+	if err != nil {
+		return playerErrorResponse(c, err)
+	}
+	return SuccessResponse(c, playerToResponse(player))
+}
+
+// UpdatePlayer changes a player of a team
+//
+//	@Summary		Update a player
+//	@Description	Changes a player's name, position, rating or shirt number; omitted fields are left unchanged. The player keeps their goals and assists.
+//	@Tags			Players
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int					true	"Team ID"
+//	@Param			playerId	path		int					true	"Player ID"
+//	@Param			player		body		UpdatePlayerRequest	true	"Changes to make"
+//	@Success		200			{object}	PlayerFullResponse	"Success response with the updated player"
+//	@Failure		400			{object}	APIErrorResponse	"Bad request (e.g., invalid input or shirt number taken)"
+//	@Failure		404			{object}	APIErrorResponse	"Team or player not found"
+//	@Failure		409			{object}	APIErrorResponse	"A week is being played live"
+//	@Failure		500			{object}	APIErrorResponse	"Internal server error"
+//	@Router			/teams/{id}/players/{playerId} [put]
+func (h *PlayerHandler) UpdatePlayer(c *fiber.Ctx) error {
+	teamID, err := c.ParamsInt("id")
+	if err != nil || teamID < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid team ID")
+	}
+	playerID, err := c.ParamsInt("playerId")
+	if err != nil || playerID < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid player ID")
+	}
+
+	var req UpdatePlayerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request payload")
+	}
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	player, err := h.playerService.UpdatePlayer(leagueID(c), uint(teamID), uint(playerID), req.Update())
+	if err != nil {
+		return playerErrorResponse(c, err)
+	}
+	return SuccessResponse(c, playerToResponse(player))
+}
+
+// DeletePlayer removes a player from a team
+//
+//	@Summary		Delete a player
+//	@Description	Removes a player from the team's squad. Their goals and assists drop off the leaderboards; match timelines keep the player's ID.
+//	@Tags			Players
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		int					true	"Team ID"
+//	@Param			playerId	path		int					true	"Player ID"
+//	@Success		200			{object}	APIResponse			"Success response"
+//	@Failure		400			{object}	APIErrorResponse	"Invalid team or player ID"
+//	@Failure		404			{object}	APIErrorResponse	"Team or player not found"
+//	@Failure		409			{object}	APIErrorResponse	"A week is being played live"
+//	@Failure		500			{object}	APIErrorResponse	"Internal server error"
+//	@Router			/teams/{id}/players/{playerId} [delete]
+func (h *PlayerHandler) DeletePlayer(c *fiber.Ctx) error {
+	teamID, err := c.ParamsInt("id")
+	if err != nil || teamID < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid team ID")
+	}
+	playerID, err := c.ParamsInt("playerId")
+	if err != nil || playerID < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid player ID")
+	}
+
+	if err := h.playerService.DeletePlayer(leagueID(c), uint(teamID), uint(playerID)); err != nil {
+		return playerErrorResponse(c, err)
+	}
+	return SuccessResponse(c, fiber.Map{"deleted": true})
+}
+
+// GetTopScorers returns the top scorers of the season
+//
+//	@Summary		Get top scorers
+//	@Description	Ranks the players by goals this season, assists breaking ties; players level on both share a rank. Goals are credited from the match timelines, so manually edited results count for nobody. Players without a goal are left out.
+//	@Tags			Standings
+//	@Accept			json
+//	@Produce		json
+//	@Param			limit	query		int						false	"Number of players (default 10, 0 for all)"
+//	@Success		200		{object}	PlayerStatsListResponse	"Success response with the leaderboard"
+//	@Failure		400		{object}	APIErrorResponse		"Invalid limit"
+//	@Failure		500		{object}	APIErrorResponse		"Internal server error"
+//	@Router			/standings/scorers [get]
+func (h *PlayerHandler) GetTopScorers(c *fiber.Ctx) error {
+	return h.leaderboard(c, services.LeaderboardGoals)
+}
+
+// GetTopAssists returns the players with the most assists this season
+//
+//	@Summary		Get top assists
+//	@Description	Ranks the players by assists this season, goals breaking ties; players level on both share a rank. Players without an assist are left out.
+//	@Tags			Standings
+//	@Accept			json
+//	@Produce		json
+//	@Param			limit	query		int						false	"Number of players (default 10, 0 for all)"
+//	@Success		200		{object}	PlayerStatsListResponse	"Success response with the leaderboard"
+//	@Failure		400		{object}	APIErrorResponse		"Invalid limit"
+//	@Failure		500		{object}	APIErrorResponse		"Internal server error"
+//	@Router			/standings/assists [get]
+func (h *PlayerHandler) GetTopAssists(c *fiber.Ctx) error {
+	return h.leaderboard(c, services.LeaderboardAssists)
+}
+
+func (h *PlayerHandler) leaderboard(c *fiber.Ctx, leaderboard string) error {
+	limit := c.QueryInt("limit", defaultLeaderboardLimit)
+	if limit < 0 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid limit")
+	}
+
+	board, err := h.playerService.GetLeaderboard(leagueID(c), leaderboard, limit)
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, leaderboardToResponse(board))
+}
+
+// playerErrorResponse maps a player service error to its status
+func playerErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, services.ErrTeamNotFound), errors.Is(err, services.ErrPlayerNotFound):
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrShirtNumberTaken):
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	default:
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+}
@@ -1,13 +1,120 @@
 package handlers
 
+import (
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
 type UpdateMatchResultRequest struct {
 	HomeScore int `json:"homeScore" validate:"gte=0" example:"2"`
 	AwayScore int `json:"awayScore" validate:"gte=0" example:"1"`
 }
 
+// PlayRequest is the optional body for play-week and play-all
+type PlayRequest struct {
+	Seed *int64 `json:"seed" example:"42"`
+}
+
+const (
+	// defaultLiveDuration is how long a live week's 90 minutes take unless asked otherwise
+	defaultLiveDuration = 3 * time.Minute
+	// maxLiveDuration is the longest a live week's 90 minutes can take, in seconds
+	maxLiveDuration = 3600
+)
+
+// LivePlayRequest is the optional body for play-week/live
+type LivePlayRequest struct {
+	Seed     *int64 `json:"seed" example:"42"`
+	Duration *int   `json:"duration" example:"180"` // Real seconds for the 90 minutes, 180 if omitted
+}
+
+// UpdateSettingsRequest updates league settings; omitted fields are left unchanged
+type UpdateSettingsRequest struct {
+	Seed             *int64   `json:"seed" example:"42"`
+	TiebreakerPreset *string  `json:"tiebreakerPreset" example:"uefa_group_stage"`
+	Tiebreakers      []string `json:"tiebreakers" example:"head_to_head_points,goal_difference,drawing_of_lots"`
+	KnockoutTeams    *int     `json:"knockoutTeams" example:"4"`
+	Groups           *int     `json:"groups" example:"2"`
+	Format           *string  `json:"format" example:"swiss"`
+
+	Engine       *string              `json:"engine" example:"dixon_coles"`
+	EngineParams *EngineParamsRequest `json:"engineParams"`
+
+	RatingUpdates *bool    `json:"ratingUpdates" example:"true"`
+	RatingK       *float64 `json:"ratingK" example:"20"`
+
+	StartDate        *string         `json:"startDate" example:"2025-08-16"`
+	MatchdayInterval *int            `json:"matchdayInterval" example:"7"`
+	Fatigue          *FatigueRequest `json:"fatigue"`
+
+	FutureEdits *string `json:"futureEdits" example:"allow"`
+}
+
+// FatigueRequest changes the fatigue model; omitted fields are left unchanged
+type FatigueRequest struct {
+	Enabled          *bool    `json:"enabled" example:"true"`
+	RecoveryHalfLife *float64 `json:"recoveryHalfLife" example:"2"`
+	Penalty          *float64 `json:"penalty" example:"0.1"`
+	MaxPenalty       *float64 `json:"maxPenalty" example:"0.25"`
+}
+
+// RescheduleMatchRequest moves a match to another day
+type RescheduleMatchRequest struct {
+	Date string `json:"date" validate:"required" example:"2025-08-19"`
+}
+
+// EngineParamsRequest changes match engine parameters; omitted fields are left unchanged
+type EngineParamsRequest struct {
+	HomeAdvantage    *float64 `json:"homeAdvantage" example:"1.1"`
+	BaseGoals        *float64 `json:"baseGoals" example:"1.5"`
+	MaxGoals         *int     `json:"maxGoals" example:"7"`
+	Rho              *float64 `json:"rho" example:"-0.1"`
+	EloHomeAdvantage *float64 `json:"eloHomeAdvantage" example:"65"`
+	EloDrawRate      *float64 `json:"eloDrawRate" example:"0.28"`
+}
+
+type CreateLeagueRequest struct {
+	Name string `json:"name" validate:"required" example:"Sunday League"`
+	Seed *int64 `json:"seed" example:"42"`
+}
+
 type CreateTeamRequest struct {
-	Name  string `json:"name" validate:"required" example:"Team A"`
-	Power int    `json:"power" validate:"gte=1,lte=100" example:"75"`
+	Name    string `json:"name" validate:"required" example:"Team A"`
+	Power   int    `json:"power" validate:"gte=1,lte=100" example:"75"`
+	Country string `json:"country" example:"England"`
+	Attack  int    `json:"attack" validate:"gte=0,lte=100" example:"82"`
+	Defence int    `json:"defence" validate:"gte=0,lte=100" example:"68"`
+}
+
+// FitRatingsRequest holds the historical results to fit team strengths from
+type FitRatingsRequest struct {
+	Results []HistoricalResultRequest `json:"results"`
+	DryRun  bool                      `json:"dryRun" example:"false"`
+}
+
+// HistoricalResultRequest is a past match result between two teams of the league, by name
+type HistoricalResultRequest struct {
+	HomeTeam  string `json:"homeTeam" validate:"required" example:"Chelsea"`
+	AwayTeam  string `json:"awayTeam" validate:"required" example:"Arsenal"`
+	HomeScore int    `json:"homeScore" validate:"gte=0" example:"2"`
+	AwayScore int    `json:"awayScore" validate:"gte=0" example:"1"`
+	Neutral   bool   `json:"neutral" example:"false"`
+}
+
+type CreatePlayerRequest struct {
+	Name        string `json:"name" validate:"required" example:"Erling Haaland"`
+	Position    string `json:"position" validate:"required" example:"forward"`
+	Rating      int    `json:"rating" validate:"gte=1,lte=100" example:"91"`
+	ShirtNumber int    `json:"shirtNumber" validate:"gte=1,lte=99" example:"9"`
+}
+
+// UpdatePlayerRequest changes a player; omitted fields are left unchanged
+type UpdatePlayerRequest struct {
+	Name        *string `json:"name" example:"Erling Haaland"`
+	Position    *string `json:"position" example:"forward"`
+	Rating      *int    `json:"rating" example:"92"`
+	ShirtNumber *int    `json:"shirtNumber" example:"9"`
 }
 
 // Validate validates the request
@@ -20,3 +127,203 @@ func (r *UpdateMatchResultRequest) Validate() error {
 	}
 	return nil
 }
+
+// Validate validates the request
+func (r *PlayRequest) Validate() error {
+	return validateSeed(r.Seed)
+}
+
+// Validate validates the request
+func (r *LivePlayRequest) Validate() error {
+	if err := validateSeed(r.Seed); err != nil {
+		return err
+	}
+	if r.Duration != nil && (*r.Duration < 1 || *r.Duration > maxLiveDuration) {
+		return ErrInvalidLiveDuration
+	}
+	return nil
+}
+
+// LiveDuration returns the real time the 90 minutes take
+func (r *LivePlayRequest) LiveDuration() time.Duration {
+	if r.Duration == nil {
+		return defaultLiveDuration
+	}
+	return time.Duration(*r.Duration) * time.Second
+}
+
+// Validate validates the request. The settings' ranges are checked by the simulation service,
+// which answers with ErrInvalidSettings; only the start date has to be parsed here.
+func (r *UpdateSettingsRequest) Validate() error {
+	if r.StartDate != nil {
+		if _, err := time.Parse(time.DateOnly, *r.StartDate); err != nil {
+			return ErrInvalidStartDate
+		}
+	}
+	return nil
+}
+
+// Settings converts the request to the league settings it changes
+func (r *UpdateSettingsRequest) Settings() models.LeagueSettings {
+	settings := models.LeagueSettings{
+		Seed:             r.Seed,
+		TiebreakerPreset: r.TiebreakerPreset,
+		KnockoutTeams:    r.KnockoutTeams,
+		Groups:           r.Groups,
+		Format:           r.Format,
+		Engine:           r.Engine,
+		RatingUpdates:    r.RatingUpdates,
+		RatingK:          r.RatingK,
+		MatchdayInterval: r.MatchdayInterval,
+		FutureEdits:      r.FutureEdits,
+	}
+	if r.EngineParams != nil {
+		settings.EngineParams = r.EngineParams.settings()
+	}
+	if r.StartDate != nil {
+		if date, err := time.Parse(time.DateOnly, *r.StartDate); err == nil {
+			settings.StartDate = &date
+		}
+	}
+	if r.Fatigue != nil {
+		settings.Fatigue = r.Fatigue.settings()
+	}
+	if r.Tiebreakers != nil {
+		settings.Tiebreakers = make([]models.TiebreakerRule, len(r.Tiebreakers))
+		for i, rule := range r.Tiebreakers {
+			settings.Tiebreakers[i] = models.TiebreakerRule(rule)
+		}
+	}
+	return settings
+}
+
+func validateSeed(seed *int64) error {
+	if seed != nil && (*seed < 0 || *seed >= models.MaxSeed) {
+		return ErrInvalidSeed
+	}
+	return nil
+}
+
+// Validate validates the request
+func (r *CreateTeamRequest) Validate() error {
+	if r.Attack < 0 || r.Attack > 100 || r.Defence < 0 || r.Defence > 100 {
+		return ErrInvalidTeamStrength
+	}
+	// The power can only be left out when attack and defence are both given
+	if (r.Power != 0 || r.Attack == 0 || r.Defence == 0) && (r.Power < 1 || r.Power > 100) {
+		return ErrInvalidTeamPower
+	}
+	return nil
+}
+
+// Validate validates the request
+func (r *CreateLeagueRequest) Validate() error {
+	if r.Name == "" {
+		return ErrLeagueNameRequired
+	}
+	return validateSeed(r.Seed)
+}
+
+// settings converts the request to the engine parameters it changes
+func (r *EngineParamsRequest) settings() *models.MatchEngineSettings {
+	return &models.MatchEngineSettings{
+		HomeAdvantage:    r.HomeAdvantage,
+		BaseGoals:        r.BaseGoals,
+		MaxGoals:         r.MaxGoals,
+		Rho:              r.Rho,
+		EloHomeAdvantage: r.EloHomeAdvantage,
+		EloDrawRate:      r.EloDrawRate,
+	}
+}
+
+// settings converts the request to the fatigue parameters it changes
+func (r *FatigueRequest) settings() *models.FatigueSettings {
+	return &models.FatigueSettings{
+		Enabled:          r.Enabled,
+		RecoveryHalfLife: r.RecoveryHalfLife,
+		Penalty:          r.Penalty,
+		MaxPenalty:       r.MaxPenalty,
+	}
+}
+
+// Validate validates the request
+func (r *RescheduleMatchRequest) Validate() error {
+	if _, err := time.Parse(time.DateOnly, r.Date); err != nil {
+		return ErrInvalidMatchDate
+	}
+	return nil
+}
+
+// MatchDate returns the day to move the match to
+func (r *RescheduleMatchRequest) MatchDate() time.Time {
+	date, _ := time.Parse(time.DateOnly, r.Date)
+	return date
+}
+
+// Validate validates the request
+func (r *FitRatingsRequest) Validate() error {
+	if len(r.Results) == 0 {
+		return ErrResultsRequired
+	}
+	for _, result := range r.Results {
+		if result.HomeTeam == "" || result.AwayTeam == "" {
+			return ErrInvalidResultTeams
+		}
+		if result.HomeScore < 0 || result.AwayScore < 0 {
+			return ErrInvalidResultScores
+		}
+	}
+	return nil
+}
+
+// HistoricalResults converts the request to the results to fit
+func (r *FitRatingsRequest) HistoricalResults() []models.HistoricalResult {
+	results := make([]models.HistoricalResult, len(r.Results))
+	for i, result := range r.Results {
+		results[i] = models.HistoricalResult{
+			HomeTeam:  result.HomeTeam,
+			AwayTeam:  result.AwayTeam,
+			HomeScore: result.HomeScore,
+			AwayScore: result.AwayScore,
+			Neutral:   result.Neutral,
+		}
+	}
+	return results
+}
+
+// validatePlayer checks the fields a player request sets
+func validatePlayer(name, position *string, rating, shirtNumber *int) error {
+	if name != nil && *name == "" {
+		return ErrPlayerNameRequired
+	}
+	if position != nil && !models.ValidPosition(*position) {
+		return ErrInvalidPosition
+	}
+	if rating != nil && (*rating < 1 || *rating > 100) {
+		return ErrInvalidPlayerRating
+	}
+	if shirtNumber != nil && (*shirtNumber < 1 || *shirtNumber > 99) {
+		return ErrInvalidShirtNumber
+	}
+	return nil
+}
+
+// Validate validates the request
+func (r *CreatePlayerRequest) Validate() error {
+	return validatePlayer(&r.Name, &r.Position, &r.Rating, &r.ShirtNumber)
+}
+
+// Player converts the request to the player to create
+func (r *CreatePlayerRequest) Player() models.Player {
+	return models.Player{Name: r.Name, Position: r.Position, Rating: r.Rating, ShirtNumber: r.ShirtNumber}
+}
+
+// Validate validates the request
+func (r *UpdatePlayerRequest) Validate() error {
+	return validatePlayer(r.Name, r.Position, r.Rating, r.ShirtNumber)
+}
+
+// Update converts the request to the changes to make
+func (r *UpdatePlayerRequest) Update() models.PlayerUpdate {
+	return models.PlayerUpdate{Name: r.Name, Position: r.Position, Rating: r.Rating, ShirtNumber: r.ShirtNumber}
+}
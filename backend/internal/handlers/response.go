@@ -1,5 +1,10 @@
 package handlers
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // APIResponse is the standard API response wrapper
 // @Description Standard API response wrapper
 type APIResponse struct {
@@ -14,34 +19,95 @@ type APIErrorResponse struct {
 	Message string `json:"message" example:"Something went wrong"`
 }
 
+// LeagueResponse represents a league in API responses
+// @Description League information
+type LeagueResponse struct {
+	ID   uint   `json:"id" example:"1"`
+	Name string `json:"name" example:"Default"`
+}
+
 // TeamResponse represents a team in API responses
 // @Description Team information
 type TeamResponse struct {
-	ID    uint   `json:"id" example:"1"`
-	Name  string `json:"name" example:"Manchester City"`
-	Power int    `json:"power" example:"90"`
+	ID      uint   `json:"id" example:"1"`
+	Name    string `json:"name" example:"Manchester City"`
+	Power   int    `json:"power" example:"90"`
+	Country string `json:"country" example:"England"`
+	Attack  int    `json:"attack" example:"92"`
+	Defence int    `json:"defence" example:"88"`
 }
 
 // MatchResponse represents a match in API responses
 // @Description Match information
 type MatchResponse struct {
-	ID        uint         `json:"id" example:"1"`
-	Week      int          `json:"week" example:"1"`
-	HomeTeam  TeamResponse `json:"homeTeam"`
-	AwayTeam  TeamResponse `json:"awayTeam"`
-	HomeScore *int         `json:"homeScore" example:"2"`
-	AwayScore *int         `json:"awayScore" example:"1"`
-	Played    bool         `json:"played" example:"true"`
+	ID                 uint               `json:"id" example:"1"`
+	Week               int                `json:"week" example:"1"`
+	Stage              string             `json:"stage" example:"league"`
+	Group              string             `json:"group,omitempty" example:"A"`
+	HomeTeam           TeamResponse       `json:"homeTeam"`
+	AwayTeam           TeamResponse       `json:"awayTeam"`
+	HomeScore          *int               `json:"homeScore" example:"2"`
+	AwayScore          *int               `json:"awayScore" example:"1"`
+	Played             bool               `json:"played" example:"true"`
+	Seed               *int64             `json:"seed" example:"8034217719"`
+	TieID              *uint              `json:"tieId,omitempty" example:"1"`
+	Leg                int                `json:"leg,omitempty" example:"2"`
+	Neutral            bool               `json:"neutral,omitempty" example:"false"`
+	Date               *string            `json:"date,omitempty" example:"2025-08-16"`
+	HomeExtraTimeScore *int               `json:"homeExtraTimeScore,omitempty" example:"1"`
+	AwayExtraTimeScore *int               `json:"awayExtraTimeScore,omitempty" example:"0"`
+	HomePenalties      *int               `json:"homePenalties,omitempty" example:"4"`
+	AwayPenalties      *int               `json:"awayPenalties,omitempty" example:"3"`
+	Odds               *MatchOddsResponse `json:"odds,omitempty"` // Unplayed matches, when asked for
 }
 
 // LeagueStateResponse represents the league state in API responses
 // @Description Current league state
 type LeagueStateResponse struct {
-	CurrentWeek     int  `json:"currentWeek" example:"3"`
-	TotalWeeks      int  `json:"totalWeeks" example:"6"`
-	FixturesCreated bool `json:"fixturesCreated" example:"true"`
-	Started         bool `json:"started" example:"true"`
-	Completed       bool `json:"completed" example:"false"`
+	LeagueID         uint     `json:"leagueId" example:"1"`
+	CurrentWeek      int      `json:"currentWeek" example:"3"`
+	TotalWeeks       int      `json:"totalWeeks" example:"6"`
+	FixturesCreated  bool     `json:"fixturesCreated" example:"true"`
+	Started          bool     `json:"started" example:"true"`
+	Completed        bool     `json:"completed" example:"false"`
+	Seed             int64    `json:"seed" example:"42"`
+	TiebreakerPreset string   `json:"tiebreakerPreset" example:"premier_league"`
+	Tiebreakers      []string `json:"tiebreakers" example:"goal_difference,goals_for,head_to_head_points,head_to_head_away_goals,drawing_of_lots"`
+	KnockoutTeams    int      `json:"knockoutTeams" example:"4"`
+	Groups           int      `json:"groups" example:"0"`
+	Format           string   `json:"format" example:"round_robin"`
+
+	Engine       string               `json:"engine" example:"poisson"`
+	EngineParams EngineParamsResponse `json:"engineParams"`
+
+	RatingUpdates bool    `json:"ratingUpdates" example:"false"`
+	RatingK       float64 `json:"ratingK" example:"20"`
+
+	StartDate        *string         `json:"startDate" example:"2025-08-16"`
+	MatchdayInterval int             `json:"matchdayInterval" example:"7"`
+	Fatigue          FatigueResponse `json:"fatigue"`
+
+	FutureEdits string `json:"futureEdits" example:"reject"`
+}
+
+// FatigueResponse represents a league's fatigue model
+// @Description Fatigue model parameters
+type FatigueResponse struct {
+	Enabled          bool    `json:"enabled" example:"false"`
+	RecoveryHalfLife float64 `json:"recoveryHalfLife" example:"2"`
+	Penalty          float64 `json:"penalty" example:"0.1"`
+	MaxPenalty       float64 `json:"maxPenalty" example:"0.25"`
+}
+
+// EngineParamsResponse represents the parameters of a league's match engine
+// @Description Match engine parameters
+type EngineParamsResponse struct {
+	HomeAdvantage    float64 `json:"homeAdvantage" example:"1.1"`
+	BaseGoals        float64 `json:"baseGoals" example:"1.5"`
+	MaxGoals         int     `json:"maxGoals" example:"7"`
+	Rho              float64 `json:"rho" example:"-0.1"`
+	EloHomeAdvantage float64 `json:"eloHomeAdvantage" example:"65"`
+	EloDrawRate      float64 `json:"eloDrawRate" example:"0.28"`
 }
 
 // TeamStandingResponse represents a team's standing in the league table
@@ -57,6 +123,22 @@ type TeamStandingResponse struct {
 	GoalsAgainst   int    `json:"goalsAgainst" example:"3"`
 	GoalDifference int    `json:"goalDifference" example:"4"`
 	Points         int    `json:"points" example:"7"`
+	Remaining      int    `json:"remaining" example:"3"`
+	DecidedBy      string `json:"decidedBy" example:"goal_difference"` // Rule that ranked the team above ahead; empty for the leader or if level
+	Qualification  string `json:"qualification,omitempty" example:"playoff"`
+
+	TitleRace         *PlaceRaceResponse `json:"titleRace,omitempty"`         // Leagues without a knockout stage
+	QualificationRace *PlaceRaceResponse `json:"qualificationRace,omitempty"` // Knockout places, or the top two of a group
+}
+
+// PlaceRaceResponse represents a team's race for the top places of its table
+// @Description Whether a team is sure of the top places or out of them on points, and the points that make sure
+type PlaceRaceResponse struct {
+	Places       int  `json:"places" example:"2"`
+	Clinched     bool `json:"clinched" example:"false"`
+	Eliminated   bool `json:"eliminated" example:"false"`
+	PointsNeeded *int `json:"pointsNeeded" example:"4"` // null if even winning every remaining match doesn't make sure
+	Exact        bool `json:"exact" example:"true"`     // false if the analysis gave up: pointsNeeded is then only an upper bound, and the place may be clinched or lost already
 }
 
 // ChampionshipPredictionResponse represents a team's championship probability
@@ -67,6 +149,40 @@ type ChampionshipPredictionResponse struct {
 	Percentage float64 `json:"percentage" example:"45.5"`
 }
 
+// PredictionsResponse represents championship predictions with their simulation size
+// @Description Championship predictions computed by Monte Carlo simulation of the remaining fixtures
+type PredictionsResponse struct {
+	Iterations  int                              `json:"iterations" example:"10000"`
+	Predictions []ChampionshipPredictionResponse `json:"predictions"`
+}
+
+// PointsBandsResponse represents percentiles of a team's final points
+// @Description Final points percentiles across the simulated seasons
+type PointsBandsResponse struct {
+	P5     int `json:"p5" example:"58"`
+	P25    int `json:"p25" example:"64"`
+	Median int `json:"median" example:"68"`
+	P75    int `json:"p75" example:"72"`
+	P95    int `json:"p95" example:"77"`
+}
+
+// PositionPredictionResponse represents a team's chances of every final position
+// @Description Probability of each final league position, first place first, with expected points
+type PositionPredictionResponse struct {
+	TeamID         uint                `json:"teamId" example:"1"`
+	TeamName       string              `json:"teamName" example:"Arsenal"`
+	Positions      []float64           `json:"positions" example:"38.2,27.5,19.1,15.2"`
+	ExpectedPoints float64             `json:"expectedPoints" example:"67.8"`
+	Points         PointsBandsResponse `json:"points"`
+}
+
+// PositionPredictionsResponse represents every team's position predictions with their simulation size
+// @Description Finishing position probabilities computed by Monte Carlo simulation of the remaining fixtures
+type PositionPredictionsResponse struct {
+	Iterations  int                          `json:"iterations" example:"10000"`
+	Predictions []PositionPredictionResponse `json:"predictions"`
+}
+
 // MatchResultResponse represents a played match result
 // @Description Match result
 type MatchResultResponse struct {
@@ -79,11 +195,87 @@ type MatchResultResponse struct {
 // SimulationStateResponse represents the complete simulation state
 // @Description Complete simulation state including standings and predictions
 type SimulationStateResponse struct {
-	LeagueState        LeagueStateResponse              `json:"leagueState"`
-	Standings          []TeamStandingResponse           `json:"standings"`
-	CurrentWeekResults []MatchResultResponse            `json:"currentWeekResults"`
-	AllMatches         map[int][]MatchResultResponse    `json:"allMatches"`
-	Predictions        []ChampionshipPredictionResponse `json:"predictions"`
+	LeagueState          LeagueStateResponse              `json:"leagueState"`
+	Standings            []TeamStandingResponse           `json:"standings"`
+	CurrentWeekResults   []MatchResultResponse            `json:"currentWeekResults"`
+	AllMatches           map[int][]MatchResultResponse    `json:"allMatches"`
+	Predictions          []ChampionshipPredictionResponse `json:"predictions"`
+	PredictionIterations int                              `json:"predictionIterations" example:"10000"`
+	Live                 *LiveWeekResponse                `json:"live,omitempty"` // While a week is played live
+	Edit                 *ResultEditResponse              `json:"edit,omitempty"` // After a result is entered or cleared by hand
+}
+
+// ResultEditResponse represents what a result entered or cleared by hand changed
+// @Description A result entered or cleared by hand and what it changed in the league, each change also described in words
+type ResultEditResponse struct {
+	Match         MatchResponse `json:"match"`
+	Cleared       bool          `json:"cleared" example:"false"`
+	PrePlayed     bool          `json:"prePlayed" example:"true"` // Entered ahead of its week, counting as played before it
+	PreviousWeek  int           `json:"previousWeek" example:"2"`
+	CurrentWeek   int           `json:"currentWeek" example:"2"`
+	Completed     bool          `json:"completed" example:"false"`     // The edit completed the league
+	KnockoutDrawn bool          `json:"knockoutDrawn" example:"false"` // The edit ended the league phase and drew the bracket
+	Changes       []string      `json:"changes" example:"Week 5's match counts as played before its week; the rest of week 5 will be simulated when it comes"`
+}
+
+// LiveWeekResponse represents a week being played live
+// @Description A week played out in real time, with the scores so far; its results are saved at the final whistle
+type LiveWeekResponse struct {
+	Week      int                 `json:"week" example:"3"`
+	StartedAt time.Time           `json:"startedAt" example:"2025-08-16T15:00:00Z"`
+	Duration  int                 `json:"duration" example:"180"` // Real seconds for the 90 minutes
+	Minute    int                 `json:"minute" example:"37"`
+	Matches   []LiveMatchResponse `json:"matches"`
+}
+
+// LiveMatchResponse represents the score so far of a live match
+// @Description Score so far of a match in a live week, extra time goals included
+type LiveMatchResponse struct {
+	MatchID      uint   `json:"matchId" example:"5"`
+	HomeTeamID   uint   `json:"homeTeamId" example:"1"`
+	HomeTeamName string `json:"homeTeamName" example:"Chelsea"`
+	AwayTeamID   uint   `json:"awayTeamId" example:"2"`
+	AwayTeamName string `json:"awayTeamName" example:"Arsenal"`
+	HomeScore    int    `json:"homeScore" example:"1"`
+	AwayScore    int    `json:"awayScore" example:"0"`
+	Finished     bool   `json:"finished" example:"false"`
+}
+
+// LiveGoalResponse is the payload of a goal event
+// @Description A goal of a live week as it goes in, with the match's score after it
+type LiveGoalResponse struct {
+	Week  int                `json:"week" example:"3"`
+	Match LiveMatchResponse  `json:"match"`
+	Goal  MatchEventResponse `json:"goal"`
+}
+
+// ResultsEventResponse is the payload of a results event
+// @Description Matches just played or edited, in the order they were played
+type ResultsEventResponse struct {
+	Week    int             `json:"week" example:"3"`
+	Matches []MatchResponse `json:"matches"`
+}
+
+// LeagueEventResponse is a league event as sent over the WebSocket
+// @Description A change to the league: results (ResultsEventResponse), standings (array of TeamStandingResponse), predictions (PredictionsResponse), reset (SimulationStateResponse), restore (SimulationStateResponse), kick_off (LiveWeekResponse), goal (LiveGoalResponse), live_error (MessageData) or resync (null; refetch the state)
+type LeagueEventResponse struct {
+	ID   string          `json:"id" example:"m2x9k7q1c4-12"`
+	Type string          `json:"type" example:"results"`
+	Data json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// LeaguesListResponse is the response for GET /leagues
+// @Description List of all leagues
+type LeaguesListResponse struct {
+	Success bool             `json:"success" example:"true"`
+	Data    []LeagueResponse `json:"data"`
+}
+
+// LeagueFullResponse is the response for single league endpoints
+// @Description Single league response
+type LeagueFullResponse struct {
+	Success bool           `json:"success" example:"true"`
+	Data    LeagueResponse `json:"data"`
 }
 
 // TeamsListResponse is the response for GET /teams
@@ -100,6 +292,57 @@ type FixturesListResponse struct {
 	Data    []MatchResponse `json:"data"`
 }
 
+// ScoreProbabilityResponse represents the chance of one score
+// @Description Score after 90 minutes and its probability
+type ScoreProbabilityResponse struct {
+	HomeGoals   int     `json:"homeGoals" example:"1"`
+	AwayGoals   int     `json:"awayGoals" example:"1"`
+	Probability float64 `json:"probability" example:"0.118"`
+}
+
+// MatchOddsResponse represents the odds of an unplayed match
+// @Description Outcome, goal and score probabilities of an unplayed match over 90 minutes
+type MatchOddsResponse struct {
+	MatchID           uint                       `json:"matchId" example:"7"`
+	HomeWin           float64                    `json:"homeWin" example:"0.482"`
+	Draw              float64                    `json:"draw" example:"0.241"`
+	AwayWin           float64                    `json:"awayWin" example:"0.277"`
+	HomeExpectedGoals float64                    `json:"homeExpectedGoals" example:"1.62"`
+	AwayExpectedGoals float64                    `json:"awayExpectedGoals" example:"1.08"`
+	LikelyScores      []ScoreProbabilityResponse `json:"likelyScores"`
+	Over25            float64                    `json:"over25" example:"0.508"`
+	Under25           float64                    `json:"under25" example:"0.492"`
+}
+
+// MatchOddsFullResponse is the response for GET /fixtures/{id}/odds
+// @Description Odds of a single match
+type MatchOddsFullResponse struct {
+	Success bool              `json:"success" example:"true"`
+	Data    MatchOddsResponse `json:"data"`
+}
+
+// MatchFullResponse is the response for PUT /fixtures/match/{id}/date
+// @Description Single match response
+type MatchFullResponse struct {
+	Success bool          `json:"success" example:"true"`
+	Data    MatchResponse `json:"data"`
+}
+
+// WeekFixturesResponse represents one week of fixtures
+// @Description Fixtures for a week, including the teams resting that week in odd-sized leagues
+type WeekFixturesResponse struct {
+	Week     int             `json:"week" example:"1"`
+	Matches  []MatchResponse `json:"matches"`
+	ByeTeams []TeamResponse  `json:"byeTeams"`
+}
+
+// WeekFixturesFullResponse is the response for GET /fixtures/{week}
+// @Description Fixtures for a single week
+type WeekFixturesFullResponse struct {
+	Success bool                 `json:"success" example:"true"`
+	Data    WeekFixturesResponse `json:"data"`
+}
+
 // StandingsListResponse is the response for GET /standings
 // @Description Current league standings
 type StandingsListResponse struct {
@@ -107,11 +350,32 @@ type StandingsListResponse struct {
 	Data    []TeamStandingResponse `json:"data"`
 }
 
+// GroupTableResponse represents the table of one group
+// @Description Group table
+type GroupTableResponse struct {
+	Group     string                 `json:"group" example:"A"`
+	Standings []TeamStandingResponse `json:"standings"`
+}
+
+// GroupStandingsListResponse is the response for GET /standings/groups
+// @Description Every group table of the group stage
+type GroupStandingsListResponse struct {
+	Success bool                 `json:"success" example:"true"`
+	Data    []GroupTableResponse `json:"data"`
+}
+
 // PredictionsListResponse is the response for GET /predictions
 // @Description Championship predictions
 type PredictionsListResponse struct {
-	Success bool                             `json:"success" example:"true"`
-	Data    []ChampionshipPredictionResponse `json:"data"`
+	Success bool                `json:"success" example:"true"`
+	Data    PredictionsResponse `json:"data"`
+}
+
+// PositionPredictionsListResponse is the response for GET /predictions/positions
+// @Description Finishing position predictions
+type PositionPredictionsListResponse struct {
+	Success bool                        `json:"success" example:"true"`
+	Data    PositionPredictionsResponse `json:"data"`
 }
 
 // SimulationStateFullResponse is the response for simulation state endpoints
@@ -121,6 +385,20 @@ type SimulationStateFullResponse struct {
 	Data    SimulationStateResponse `json:"data"`
 }
 
+// LiveWeekFullResponse is the response for POST /simulation/play-week/live
+// @Description Live week response
+type LiveWeekFullResponse struct {
+	Success bool             `json:"success" example:"true"`
+	Data    LiveWeekResponse `json:"data"`
+}
+
+// LeagueStateFullResponse is the response for league settings endpoints
+// @Description League state response
+type LeagueStateFullResponse struct {
+	Success bool                `json:"success" example:"true"`
+	Data    LeagueStateResponse `json:"data"`
+}
+
 // MessageResponse is a simple message response
 // @Description Simple message response
 type MessageResponse struct {
@@ -132,3 +410,403 @@ type MessageResponse struct {
 type MessageData struct {
 	Message string `json:"message" example:"Operation completed successfully"`
 }
+
+// SeasonResponse represents an archived season
+// @Description Archived season summary
+type SeasonResponse struct {
+	ID             uint      `json:"id" example:"1"`
+	Number         int       `json:"number" example:"1"`
+	Completed      bool      `json:"completed" example:"true"`
+	WeeksPlayed    int       `json:"weeksPlayed" example:"6"`
+	TotalWeeks     int       `json:"totalWeeks" example:"6"`
+	Seed           int64     `json:"seed" example:"42"`
+	ChampionTeamID *uint     `json:"championTeamId" example:"3"`
+	ChampionName   string    `json:"championName" example:"Manchester City"`
+	ArchivedAt     time.Time `json:"archivedAt"`
+}
+
+// SeasonStandingResponse represents a team's final row in an archived season
+// @Description Final table row of an archived season, with the team's power at the time
+type SeasonStandingResponse struct {
+	Position       int    `json:"position" example:"1"`
+	TeamID         uint   `json:"teamId" example:"3"`
+	TeamName       string `json:"teamName" example:"Manchester City"`
+	Power          int    `json:"power" example:"90"`
+	Played         int    `json:"played" example:"6"`
+	Won            int    `json:"won" example:"4"`
+	Drawn          int    `json:"drawn" example:"1"`
+	Lost           int    `json:"lost" example:"1"`
+	GoalsFor       int    `json:"goalsFor" example:"12"`
+	GoalsAgainst   int    `json:"goalsAgainst" example:"6"`
+	GoalDifference int    `json:"goalDifference" example:"6"`
+	Points         int    `json:"points" example:"13"`
+}
+
+// SeasonDetailResponse represents an archived season with its final table
+// @Description Archived season with its final table
+type SeasonDetailResponse struct {
+	SeasonResponse
+	Standings []SeasonStandingResponse `json:"standings"`
+}
+
+// SeasonMatchResponse represents an archived match result
+// @Description Archived match result
+type SeasonMatchResponse struct {
+	SeasonID     uint   `json:"seasonId" example:"1"`
+	Week         int    `json:"week" example:"1"`
+	Stage        string `json:"stage" example:"league"`
+	HomeTeamID   uint   `json:"homeTeamId" example:"1"`
+	AwayTeamID   uint   `json:"awayTeamId" example:"2"`
+	HomeTeamName string `json:"homeTeamName" example:"Chelsea"`
+	AwayTeamName string `json:"awayTeamName" example:"Arsenal"`
+	HomeScore    int    `json:"homeScore" example:"2"`
+	AwayScore    int    `json:"awayScore" example:"1"`
+	Seed         *int64 `json:"seed" example:"8034217719"`
+}
+
+// AllTimeStandingResponse represents a team's totals over every archived season
+// @Description All-time table row
+type AllTimeStandingResponse struct {
+	TeamID         uint   `json:"teamId" example:"3"`
+	TeamName       string `json:"teamName" example:"Manchester City"`
+	Seasons        int    `json:"seasons" example:"5"`
+	Titles         int    `json:"titles" example:"2"`
+	Played         int    `json:"played" example:"30"`
+	Won            int    `json:"won" example:"18"`
+	Drawn          int    `json:"drawn" example:"6"`
+	Lost           int    `json:"lost" example:"6"`
+	GoalsFor       int    `json:"goalsFor" example:"55"`
+	GoalsAgainst   int    `json:"goalsAgainst" example:"30"`
+	GoalDifference int    `json:"goalDifference" example:"25"`
+	Points         int    `json:"points" example:"60"`
+}
+
+// HeadToHeadResponse represents every archived meeting between two teams
+// @Description Head-to-head record between two teams across archived seasons
+type HeadToHeadResponse struct {
+	TeamAID    uint                  `json:"teamAId" example:"1"`
+	TeamAName  string                `json:"teamAName" example:"Chelsea"`
+	TeamBID    uint                  `json:"teamBId" example:"2"`
+	TeamBName  string                `json:"teamBName" example:"Arsenal"`
+	Played     int                   `json:"played" example:"10"`
+	TeamAWins  int                   `json:"teamAWins" example:"4"`
+	Draws      int                   `json:"draws" example:"3"`
+	TeamBWins  int                   `json:"teamBWins" example:"3"`
+	TeamAGoals int                   `json:"teamAGoals" example:"15"`
+	TeamBGoals int                   `json:"teamBGoals" example:"12"`
+	Matches    []SeasonMatchResponse `json:"matches"`
+}
+
+// SeasonsListResponse is the response for GET /seasons
+// @Description List of archived seasons
+type SeasonsListResponse struct {
+	Success bool             `json:"success" example:"true"`
+	Data    []SeasonResponse `json:"data"`
+}
+
+// SeasonFullResponse is the response for GET /seasons/{id}
+// @Description Archived season with its final table
+type SeasonFullResponse struct {
+	Success bool                 `json:"success" example:"true"`
+	Data    SeasonDetailResponse `json:"data"`
+}
+
+// SeasonStandingsListResponse is the response for GET /seasons/{id}/standings
+// @Description Final table of an archived season
+type SeasonStandingsListResponse struct {
+	Success bool                     `json:"success" example:"true"`
+	Data    []SeasonStandingResponse `json:"data"`
+}
+
+// SeasonMatchesListResponse is the response for GET /seasons/{id}/matches
+// @Description Results of an archived season
+type SeasonMatchesListResponse struct {
+	Success bool                  `json:"success" example:"true"`
+	Data    []SeasonMatchResponse `json:"data"`
+}
+
+// AllTimeStandingsListResponse is the response for GET /seasons/all-time
+// @Description All-time table across archived seasons
+type AllTimeStandingsListResponse struct {
+	Success bool                      `json:"success" example:"true"`
+	Data    []AllTimeStandingResponse `json:"data"`
+}
+
+// HeadToHeadFullResponse is the response for GET /seasons/head-to-head
+// @Description Head-to-head record between two teams
+type HeadToHeadFullResponse struct {
+	Success bool               `json:"success" example:"true"`
+	Data    HeadToHeadResponse `json:"data"`
+}
+
+// KnockoutTieResponse represents a knockout tie in API responses
+// @Description Knockout tie; team A is the higher seed and hosts the second leg
+type KnockoutTieResponse struct {
+	ID             uint            `json:"id" example:"1"`
+	Round          int             `json:"round" example:"1"`
+	Slot           int             `json:"slot" example:"0"`
+	TeamA          TeamResponse    `json:"teamA"`
+	TeamB          TeamResponse    `json:"teamB"`
+	TeamASeed      int             `json:"teamASeed" example:"1"`
+	TeamBSeed      int             `json:"teamBSeed" example:"4"`
+	SingleLeg      bool            `json:"singleLeg" example:"false"`
+	TeamAAggregate int             `json:"teamAAggregate" example:"3"`
+	TeamBAggregate int             `json:"teamBAggregate" example:"2"`
+	WinnerID       *uint           `json:"winnerId" example:"1"`
+	DecidedBy      string          `json:"decidedBy" example:"aggregate"`
+	Matches        []MatchResponse `json:"matches"`
+}
+
+// KnockoutRoundResponse represents a round of the knockout bracket
+// @Description Knockout round with its ties
+type KnockoutRoundResponse struct {
+	Round int                   `json:"round" example:"1"`
+	Name  string                `json:"name" example:"Semi-finals"`
+	Ties  []KnockoutTieResponse `json:"ties"`
+}
+
+// KnockoutBracketResponse represents the knockout bracket drawn so far
+// @Description Knockout bracket; rounds appear as they are drawn
+type KnockoutBracketResponse struct {
+	Teams      int                     `json:"teams" example:"4"`
+	Rounds     []KnockoutRoundResponse `json:"rounds"`
+	ChampionID *uint                   `json:"championId" example:"1"`
+}
+
+// KnockoutBracketFullResponse is the response for GET /knockout
+// @Description Knockout bracket
+type KnockoutBracketFullResponse struct {
+	Success bool                    `json:"success" example:"true"`
+	Data    KnockoutBracketResponse `json:"data"`
+}
+
+// KnockoutTieFullResponse is the response for GET /knockout/ties/{id}
+// @Description Single knockout tie
+type KnockoutTieFullResponse struct {
+	Success bool                `json:"success" example:"true"`
+	Data    KnockoutTieResponse `json:"data"`
+}
+
+// TeamRatingResponse represents a team's rating at the end of a week
+// @Description Elo rating after a week; week 0 is the rating the season started from
+type TeamRatingResponse struct {
+	Week   int     `json:"week" example:"3"`
+	Rating float64 `json:"rating" example:"1912.4"`
+	Change float64 `json:"change" example:"-7.6"`
+}
+
+// RatingHistoryResponse represents a team's rating history
+// @Description Current Elo rating of a team and its rating after each week
+type RatingHistoryResponse struct {
+	Team          TeamResponse         `json:"team"`
+	Rating        float64              `json:"rating" example:"1912.4"`
+	RatingUpdates bool                 `json:"ratingUpdates" example:"true"`
+	History       []TeamRatingResponse `json:"history"`
+}
+
+// RatingHistoryFullResponse is the response for GET /teams/{id}/ratings
+// @Description Rating history of a team
+type RatingHistoryFullResponse struct {
+	Success bool                  `json:"success" example:"true"`
+	Data    RatingHistoryResponse `json:"data"`
+}
+
+// TeamFitResponse represents a team's fitted strengths
+// @Description Attack and defence fitted from historical results, next to the team's previous strengths
+type TeamFitResponse struct {
+	TeamID          uint   `json:"teamId" example:"2"`
+	TeamName        string `json:"teamName" example:"Arsenal"`
+	Matches         int    `json:"matches" example:"38"`
+	Attack          int    `json:"attack" example:"88"`
+	Defence         int    `json:"defence" example:"71"`
+	Power           int    `json:"power" example:"80"`
+	PreviousAttack  int    `json:"previousAttack" example:"80"`
+	PreviousDefence int    `json:"previousDefence" example:"80"`
+	PreviousPower   int    `json:"previousPower" example:"80"`
+}
+
+// RatingFitResponse represents a fit of team strengths to historical results
+// @Description Maximum likelihood fit of attack, defence and home advantage with its goodness of fit
+type RatingFitResponse struct {
+	Matches                 int               `json:"matches" example:"380"`
+	HomeAdvantage           float64           `json:"homeAdvantage" example:"1.27"`
+	ConfiguredHomeAdvantage float64           `json:"configuredHomeAdvantage" example:"1.1"`
+	BaseGoals               float64           `json:"baseGoals" example:"1.38"`
+	LogLikelihood           float64           `json:"logLikelihood" example:"-1104.6"`
+	NullLogLikelihood       float64           `json:"nullLogLikelihood" example:"-1152.3"`
+	Deviance                float64           `json:"deviance" example:"812.4"`
+	DegreesOfFreedom        int               `json:"degreesOfFreedom" example:"720"`
+	PseudoR2                float64           `json:"pseudoR2" example:"0.041"`
+	Iterations              int               `json:"iterations" example:"6"`
+	Converged               bool              `json:"converged" example:"true"`
+	Applied                 bool              `json:"applied" example:"true"`
+	Teams                   []TeamFitResponse `json:"teams"`
+}
+
+// RatingFitFullResponse is the response for POST /teams/ratings/fit
+// @Description Team strengths fitted from historical results
+type RatingFitFullResponse struct {
+	Success bool              `json:"success" example:"true"`
+	Data    RatingFitResponse `json:"data"`
+}
+
+// MatchEventResponse represents an event of a simulated match
+// @Description Match event. Players are team-sheet slots: 1 goalkeeper, 2-5 defenders, 6-8 midfielders, 9-11 forwards, 12-18 substitutes
+type MatchEventResponse struct {
+	Sequence        int    `json:"sequence" example:"3"`
+	Period          string `json:"period" example:"first_half"`
+	Minute          int    `json:"minute" example:"45"`
+	AddedTime       int    `json:"addedTime" example:"2"`
+	Type            string `json:"type" example:"goal"`
+	TeamID          uint   `json:"teamId,omitempty" example:"1"`
+	Side            string `json:"side,omitempty" example:"home"`
+	Slot            int    `json:"slot,omitempty" example:"9"`
+	Position        string `json:"position,omitempty" example:"forward"`
+	RelatedSlot     int    `json:"relatedSlot,omitempty" example:"7"` // Assist of a goal, substitute coming on
+	PlayerID        uint   `json:"playerId,omitempty" example:"12"`   // Player in the slot, if the squad had one
+	RelatedPlayerID uint   `json:"relatedPlayerId,omitempty" example:"10"`
+	Detail          string `json:"detail,omitempty" example:"second_yellow"`
+	HomeScore       int    `json:"homeScore" example:"1"`
+	AwayScore       int    `json:"awayScore" example:"0"`
+}
+
+// MatchTimelineResponse represents a match with its events
+// @Description Match with its events in order and the half-time score; matches not yet played or edited by hand have no events
+type MatchTimelineResponse struct {
+	Match             MatchResponse        `json:"match"`
+	HalfTimeHomeScore *int                 `json:"halfTimeHomeScore" example:"1"`
+	HalfTimeAwayScore *int                 `json:"halfTimeAwayScore" example:"0"`
+	Events            []MatchEventResponse `json:"events"`
+}
+
+// MatchTimelineFullResponse is the response for GET /simulation/match/{id}/timeline
+// @Description Match timeline
+type MatchTimelineFullResponse struct {
+	Success bool                  `json:"success" example:"true"`
+	Data    MatchTimelineResponse `json:"data"`
+}
+
+// MatchTimelinesListResponse is the response for GET /simulation/week/{week}/timeline
+// @Description Timelines of a week's matches
+type MatchTimelinesListResponse struct {
+	Success bool                    `json:"success" example:"true"`
+	Data    []MatchTimelineResponse `json:"data"`
+}
+
+// PlayerResponse represents a player in API responses
+// @Description Player of a team's squad
+type PlayerResponse struct {
+	ID          uint   `json:"id" example:"12"`
+	TeamID      uint   `json:"teamId" example:"3"`
+	Name        string `json:"name" example:"Erling Haaland"`
+	Position    string `json:"position" example:"forward"`
+	Rating      int    `json:"rating" example:"91"`
+	ShirtNumber int    `json:"shirtNumber" example:"9"`
+}
+
+// PlayersListResponse is the response for GET /teams/{id}/players
+// @Description A team's squad by shirt number
+type PlayersListResponse struct {
+	Success bool             `json:"success" example:"true"`
+	Data    []PlayerResponse `json:"data"`
+}
+
+// PlayerFullResponse is the response for single player endpoints
+// @Description Single player response
+type PlayerFullResponse struct {
+	Success bool           `json:"success" example:"true"`
+	Data    PlayerResponse `json:"data"`
+}
+
+// PlayerStatsResponse represents a player's line on a leaderboard
+// @Description Player's goals and assists this season
+type PlayerStatsResponse struct {
+	Rank        int    `json:"rank" example:"1"`
+	PlayerID    uint   `json:"playerId" example:"12"`
+	PlayerName  string `json:"playerName" example:"Erling Haaland"`
+	TeamID      uint   `json:"teamId" example:"3"`
+	TeamName    string `json:"teamName" example:"Manchester City"`
+	Position    string `json:"position" example:"forward"`
+	ShirtNumber int    `json:"shirtNumber" example:"9"`
+	Goals       int    `json:"goals" example:"7"`
+	Assists     int    `json:"assists" example:"2"`
+}
+
+// PlayerStatsListResponse is the response for GET /standings/scorers and /standings/assists
+// @Description Player leaderboard
+type PlayerStatsListResponse struct {
+	Success bool                  `json:"success" example:"true"`
+	Data    []PlayerStatsResponse `json:"data"`
+}
+
+// PlayerAbsenceResponse represents a player missing a week
+// @Description Player out injured or suspended
+type PlayerAbsenceResponse struct {
+	PlayerID     uint   `json:"playerId" example:"12"`
+	PlayerName   string `json:"playerName" example:"Erling Haaland"`
+	Position     string `json:"position" example:"forward"`
+	Reason       string `json:"reason" example:"suspension"`
+	Detail       string `json:"detail,omitempty" example:"red_card"`
+	MatchID      uint   `json:"matchId" example:"5"`
+	IncurredWeek int    `json:"incurredWeek" example:"3"`
+	Length       int    `json:"length" example:"3"`     // Weeks injured, or team matches suspended
+	ReturnWeek   int    `json:"returnWeek" example:"7"` // 0 if the fixtures run out first
+}
+
+// TeamAvailabilityResponse represents a team's squad availability for a week
+// @Description Who a team can pick in a week and the strength they leave it
+type TeamAvailabilityResponse struct {
+	TeamID              uint                    `json:"teamId" example:"3"`
+	TeamName            string                  `json:"teamName" example:"Manchester City"`
+	Week                int                     `json:"week" example:"4"`
+	SquadSize           int                     `json:"squadSize" example:"18"`
+	Available           []PlayerResponse        `json:"available"`
+	Absences            []PlayerAbsenceResponse `json:"absences"`
+	AttackAvailability  float64                 `json:"attackAvailability" example:"0.93"`
+	DefenceAvailability float64                 `json:"defenceAvailability" example:"1"`
+	MatchDate           *string                 `json:"matchDate,omitempty" example:"2025-09-06"`
+	RestDays            int                     `json:"restDays" example:"3"`
+	Freshness           float64                 `json:"freshness" example:"0.96"`
+}
+
+// TeamAvailabilityListResponse is the response for GET /fixtures/{week}/availability
+// @Description Every team's squad availability for a week
+type TeamAvailabilityListResponse struct {
+	Success bool                       `json:"success" example:"true"`
+	Data    []TeamAvailabilityResponse `json:"data"`
+}
+
+// AuditEntryResponse represents an action in the audit log
+// @Description An action that changed the league: who took it, when, and the rows it changed
+type AuditEntryResponse struct {
+	ID        uint                  `json:"id" example:"7"`
+	Action    string                `json:"action" example:"update_result"`
+	Actor     string                `json:"actor" example:"alice"`
+	TargetID  *uint                 `json:"targetId,omitempty" example:"6"` // Entry an undo or redo reverted or reapplied
+	Undone    bool                  `json:"undone" example:"false"`
+	Changes   []AuditChangeResponse `json:"changes"`
+	CreatedAt time.Time             `json:"createdAt" example:"2025-08-16T15:04:05Z"`
+}
+
+// AuditChangeResponse represents a row an action changed
+// @Description A row an action added, changed or removed, with the values of its changed fields before and after; before is null for a row added and after for a row removed
+type AuditChangeResponse struct {
+	Kind   string                 `json:"kind" example:"match"`
+	ID     uint                   `json:"id" example:"5"`
+	Before map[string]interface{} `json:"before" swaggertype:"object"`
+	After  map[string]interface{} `json:"after" swaggertype:"object"`
+}
+
+// AuditLogResponse is the response for GET /audit
+// @Description The league's audit log, newest first
+type AuditLogResponse struct {
+	Success bool                 `json:"success" example:"true"`
+	Data    []AuditEntryResponse `json:"data"`
+}
+
+// AuditEntryFullResponse is the response for POST /simulation/undo and /simulation/redo
+// @Description Single audit log entry response
+type AuditEntryFullResponse struct {
+	Success bool               `json:"success" example:"true"`
+	Data    AuditEntryResponse `json:"data"`
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/services"
+)
+
+type SeasonHandler struct {
+	seasonService services.SeasonService
+}
+
+func NewSeasonHandler(seasonService services.SeasonService) *SeasonHandler {
+	return &SeasonHandler{seasonService: seasonService}
+}
+
+// GetSeasons returns all archived seasons
+//
+//	@Summary		Get archived seasons
+//	@Description	Returns every archived season of the league. A season is archived when its last week is played, or when the simulation is reset with results on the board.
+//	@Tags			Seasons
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	SeasonsListResponse	"Success response with seasons array"
+//	@Failure		500	{object}	APIErrorResponse	"Internal server error"
+//	@Router			/seasons [get]
+func (h *SeasonHandler) GetSeasons(c *fiber.Ctx) error {
+	seasons, err := h.seasonService.GetSeasons(leagueID(c))
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, seasonsToResponse(seasons))
+}
+
+// GetSeason returns an archived season with its final table
+//
+//	@Summary		Get an archived season
+//	@Description	Returns an archived season with its champion and final table, including each team's power at the time
+//	@Tags			Seasons
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int					true	"Season ID"
+//	@Success		200	{object}	SeasonFullResponse	"Success response with the season"
+//	@Failure		400	{object}	APIErrorResponse	"Invalid season ID"
+//	@Failure		404	{object}	APIErrorResponse	"Season not found"
+//	@Failure		500	{object}	APIErrorResponse	"Internal server error"
+//	@Router			/seasons/{id} [get]
+func (h *SeasonHandler) GetSeason(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid season ID")
+	}
+
+	season, err := h.seasonService.GetSeason(leagueID(c), uint(id))
+	if err != nil {
+		return seasonErrorResponse(c, err)
+	}
+	return SuccessResponse(c, seasonDetailToResponse(season))
+}
+
+// GetSeasonStandings returns the final table of an archived season
+//
+//	@Summary		Get an archived season's table
+//	@Description	Returns the final table of an archived season
+//	@Tags			Seasons
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int							true	"Season ID"
+//	@Success		200	{object}	SeasonStandingsListResponse	"Success response with the final table"
+//	@Failure		400	{object}	APIErrorResponse			"Invalid season ID"
+//	@Failure		404	{object}	APIErrorResponse			"Season not found"
+//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Router			/seasons/{id}/standings [get]
+func (h *SeasonHandler) GetSeasonStandings(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid season ID")
+	}
+
+	season, err := h.seasonService.GetSeason(leagueID(c), uint(id))
+	if err != nil {
+		return seasonErrorResponse(c, err)
+	}
+	return SuccessResponse(c, seasonStandingsToResponse(season.Standings))
+}
+
+// GetSeasonMatches returns the results of an archived season
+//
+//	@Summary		Get an archived season's results
+//	@Description	Returns every match result of an archived season
+//	@Tags			Seasons
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int							true	"Season ID"
+//	@Success		200	{object}	SeasonMatchesListResponse	"Success response with the results"
+//	@Failure		400	{object}	APIErrorResponse			"Invalid season ID"
+//	@Failure		404	{object}	APIErrorResponse			"Season not found"
+//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Router			/seasons/{id}/matches [get]
+func (h *SeasonHandler) GetSeasonMatches(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid season ID")
+	}
+
+	matches, err := h.seasonService.GetSeasonMatches(leagueID(c), uint(id))
+	if err != nil {
+		return seasonErrorResponse(c, err)
+	}
+	return SuccessResponse(c, seasonMatchesToResponse(matches))
+}
+
+// GetAllTimeTable returns the all-time table across archived seasons
+//
+//	@Summary		Get the all-time table
+//	@Description	Returns every team's accumulated results and titles across all archived seasons
+//	@Tags			Seasons
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	AllTimeStandingsListResponse	"Success response with the all-time table"
+//	@Failure		500	{object}	APIErrorResponse				"Internal server error"
+//	@Router			/seasons/all-time [get]
+func (h *SeasonHandler) GetAllTimeTable(c *fiber.Ctx) error {
+	table, err := h.seasonService.GetAllTimeTable(leagueID(c))
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, allTimeTableToResponse(table))
+}
+
+// GetHeadToHead returns the head-to-head record of two teams across archived seasons
+//
+//	@Summary		Get a head-to-head record
+//	@Description	Returns the wins, draws, goals and results of every archived meeting between two teams
+//	@Tags			Seasons
+//	@Accept			json
+//	@Produce		json
+//	@Param			teamA	query		int						true	"First team ID"
+//	@Param			teamB	query		int						true	"Second team ID"
+//	@Success		200		{object}	HeadToHeadFullResponse	"Success response with the head-to-head record"
+//	@Failure		400		{object}	APIErrorResponse		"Invalid team IDs"
+//	@Failure		404		{object}	APIErrorResponse		"Team not found"
+//	@Failure		500		{object}	APIErrorResponse		"Internal server error"
+//	@Router			/seasons/head-to-head [get]
+func (h *SeasonHandler) GetHeadToHead(c *fiber.Ctx) error {
+	teamA, teamB := c.QueryInt("teamA"), c.QueryInt("teamB")
+	if teamA < 1 || teamB < 1 || teamA == teamB {
+		return ErrorResponse(c, fiber.StatusBadRequest, "teamA and teamB must be two different team IDs")
+	}
+
+	record, err := h.seasonService.GetHeadToHead(leagueID(c), uint(teamA), uint(teamB))
+	if errors.Is(err, services.ErrTeamNotFound) {
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, headToHeadToResponse(record))
+}
+
+// seasonErrorResponse maps a season lookup error to its HTTP status
+func seasonErrorResponse(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrSeasonNotFound) {
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+}
@@ -1,86 +1,182 @@
 package handlers
 
 import (
+	"errors"
+	"log"
+	"sort"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/zahidcakici/champions-league/internal/models"
 	"github.com/zahidcakici/champions-league/internal/services"
 )
 
 type SimulationHandler struct {
 	simulationService services.SimulationService
 	standingsService  services.StandingsService
+	auditService      services.AuditService
+	events            services.EventBroker
 }
 
 func NewSimulationHandler(
 	simulationService services.SimulationService,
 	standingsService services.StandingsService,
+	auditService services.AuditService,
+	events services.EventBroker,
 ) *SimulationHandler {
 	return &SimulationHandler{
 		simulationService: simulationService,
 		standingsService:  standingsService,
+		auditService:      auditService,
+		events:            events,
 	}
 }
 
 // PlayNextWeek simulates the next week of matches
 //
 //	@Summary		Play next week
-//	@Description	Simulates all matches for the next week and returns updated state
+//	@Description	Simulates all matches for the next week and returns updated state. An optional seed replaces the league seed for this week.
 //	@Tags			Simulation
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	SimulationStateFullResponse	"Success response with updated simulation state"
-//	@Failure		400	{object}	APIErrorResponse			"Bad request (e.g., no more weeks to play)"
-//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Param			body	body		PlayRequest					false	"Optional simulation seed"
+//	@Success		200		{object}	SimulationStateFullResponse	"Success response with updated simulation state"
+//	@Failure		400		{object}	APIErrorResponse			"Bad request (e.g., no more weeks to play)"
+//	@Failure		409		{object}	APIErrorResponse			"A week is being played live, or another request changed the league first"
+//	@Failure		500		{object}	APIErrorResponse			"Internal server error"
 //	@Router			/simulation/play-week [post]
 func (h *SimulationHandler) PlayNextWeek(c *fiber.Ctx) error {
-	_, err := h.simulationService.PlayNextWeek()
+	var req PlayRequest
+	if err := parseOptionalBody(c, &req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	matches, err := h.simulationService.PlayNextWeek(leagueID(c), req.Seed)
+	if conflicting(err) {
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
 	// Return full state after playing
-	state, err := h.standingsService.GetFullState()
+	state, err := h.standingsService.GetFullState(leagueID(c))
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
+	h.broadcast(leagueID(c), [][]models.Match{matches}, state)
 	return SuccessResponse(c, SimulationStateToResponse(state))
 }
 
 // PlayAllWeeks simulates all remaining weeks
 //
 //	@Summary		Play all remaining weeks
-//	@Description	Simulates all remaining matches until the season is complete
+//	@Description	Simulates all remaining matches until the season is complete. An optional seed replaces the league seed for these weeks.
 //	@Tags			Simulation
 //	@Accept			json
 //	@Produce		json
-//	@Success		200	{object}	SimulationStateFullResponse	"Success response with final simulation state"
-//	@Failure		400	{object}	APIErrorResponse			"Bad request (e.g., season already complete)"
-//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Param			body	body		PlayRequest					false	"Optional simulation seed"
+//	@Success		200		{object}	SimulationStateFullResponse	"Success response with final simulation state"
+//	@Failure		400		{object}	APIErrorResponse			"Bad request (e.g., season already complete)"
+//	@Failure		409		{object}	APIErrorResponse			"A week is being played live, or another request changed the league first"
+//	@Failure		500		{object}	APIErrorResponse			"Internal server error"
 //	@Router			/simulation/play-all [post]
 func (h *SimulationHandler) PlayAllWeeks(c *fiber.Ctx) error {
-	_, err := h.simulationService.PlayAllWeeks()
+	var req PlayRequest
+	if err := parseOptionalBody(c, &req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	played, err := h.simulationService.PlayAllWeeks(leagueID(c), req.Seed)
+	if conflicting(err) {
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	}
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
 	// Return full state after playing all
-	state, err := h.standingsService.GetFullState()
+	state, err := h.standingsService.GetFullState(leagueID(c))
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
+	weeks := make([]int, 0, len(played))
+	for week := range played {
+		weeks = append(weeks, week)
+	}
+	sort.Ints(weeks)
+	results := make([][]models.Match, len(weeks))
+	for i, week := range weeks {
+		results[i] = played[week]
+	}
+	h.broadcast(leagueID(c), results, state)
 	return SuccessResponse(c, SimulationStateToResponse(state))
 }
 
+// PlayLiveWeek starts playing the next week in real time
+//
+//	@Summary		Play next week live
+//	@Description	Starts playing the next week out in real time: the 90 minutes take duration seconds (180 if omitted), extra time running on at the same pace. Kick-off and every goal are pushed to GET /events as kick_off and goal events as they happen, and the scores so far show in live on GET /simulation/state. The results are those play-week would give with the same seed, and are saved when the last match ends, followed by the usual results, standings and predictions events (or live_error if they can't be saved). Until then playing, editing results, changing settings, resetting and every other change to the league are refused with 409. The week is recorded in the audit log once it ends.
+//	@Tags			Simulation
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		LivePlayRequest			false	"Optional simulation seed and duration"
+//	@Success		202		{object}	LiveWeekFullResponse	"Success response with the week at kick-off"
+//	@Failure		400		{object}	APIErrorResponse		"Bad request (e.g., no more weeks to play)"
+//	@Failure		409		{object}	APIErrorResponse		"A week is already being played live"
+//	@Router			/simulation/play-week/live [post]
+func (h *SimulationHandler) PlayLiveWeek(c *fiber.Ctx) error {
+	var req LivePlayRequest
+	if err := parseOptionalBody(c, &req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	// The week is recorded in the audit log at the final whistle, and nothing else can be until then
+	var week *models.LiveWeek
+	var err error
+	auditErr := h.auditService.Begin(leagueID(c), models.AuditPlayLiveWeek, actor(c), func(finish func() error) bool {
+		week, err = h.simulationService.PlayLiveWeek(leagueID(c), req.Seed, req.LiveDuration(), liveBroadcaster{h, finish})
+		return err == nil
+	})
+	if errors.Is(auditErr, services.ErrActionInProgress) {
+		return ErrorResponse(c, fiber.StatusConflict, auditErr.Error())
+	}
+	if auditErr != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, auditErr.Error())
+	}
+	if errors.Is(err, services.ErrWeekLive) {
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	return SuccessResponse(c.Status(fiber.StatusAccepted), liveWeekToResponse(week))
+}
+
 // UpdateMatchResult updates the result of a specific match
 //
 //	@Summary		Update match result
-//	@Description	Manually update the score of a specific match
+//	@Description	Manually update the score of a league match. A match after the current week can only be entered if the league's futureEdits setting is allow; it then counts as played, and its week only simulates the other matches. The current week moves on over every week the edit leaves fully played, which can draw the knockout bracket or complete the league. Editing a completed league updates its archived season. The returned state's edit explains what changed.
 //	@Tags			Simulation
 //	@Accept			json
 //	@Produce		json
 //	@Param			id		path		int							true	"Match ID"
 //	@Param			body	body		UpdateMatchResultRequest	true	"Match score update"
-//	@Success		200		{object}	SimulationStateFullResponse	"Success response with updated simulation state"
-//	@Failure		400		{object}	APIErrorResponse			"Invalid match ID or request body"
+//	@Success		200		{object}	SimulationStateFullResponse	"Success response with updated simulation state and what the edit changed"
+//	@Failure		400		{object}	APIErrorResponse			"Invalid match ID or request body, or a future match while future edits are rejected"
+//	@Failure		404		{object}	APIErrorResponse			"Match not found"
+//	@Failure		409		{object}	APIErrorResponse			"A week is being played live, or another request changed the league first"
 //	@Failure		500		{object}	APIErrorResponse			"Internal server error"
 //	@Router			/simulation/match/{id} [put]
 func (h *SimulationHandler) UpdateMatchResult(c *fiber.Ctx) error {
@@ -98,18 +194,101 @@ func (h *SimulationHandler) UpdateMatchResult(c *fiber.Ctx) error {
 		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	if err = h.simulationService.UpdateMatchResult(uint(id), req.HomeScore, req.AwayScore); err != nil {
-		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	edit, err := h.simulationService.UpdateMatchResult(leagueID(c), uint(id), req.HomeScore, req.AwayScore)
+	if err != nil {
+		return editErrorResponse(c, err)
 	}
+	return h.editedState(c, edit)
+}
 
-	// Return full state after update
-	state, err := h.standingsService.GetFullState()
+// ClearMatchResult puts a played match back to unplayed
+//
+//	@Summary		Clear match result
+//	@Description	Puts a played league match back to unplayed, without a score, to be simulated again when its week is played. The current week goes back to the last week with every match played. Results of a completed league can't be cleared. The returned state's edit explains what changed.
+//	@Tags			Simulation
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int							true	"Match ID"
+//	@Success		200	{object}	SimulationStateFullResponse	"Success response with updated simulation state and what the edit changed"
+//	@Failure		400	{object}	APIErrorResponse			"Invalid match ID, or a match that can't be cleared"
+//	@Failure		404	{object}	APIErrorResponse			"Match not found"
+//	@Failure		409	{object}	APIErrorResponse			"A week is being played live, or another request changed the league first"
+//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Router			/simulation/match/{id} [delete]
+func (h *SimulationHandler) ClearMatchResult(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid match ID")
+	}
+
+	edit, err := h.simulationService.ClearMatchResult(leagueID(c), uint(id))
+	if err != nil {
+		return editErrorResponse(c, err)
+	}
+	return h.editedState(c, edit)
+}
+
+// editedState responds to a result edit with the full state and what the edit changed, and
+// pushes the new result to the league's watchers
+func (h *SimulationHandler) editedState(c *fiber.Ctx, edit *models.ResultEdit) error {
+	state, err := h.standingsService.GetFullState(leagueID(c))
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
+	h.broadcast(leagueID(c), [][]models.Match{{edit.Match}}, state)
+	state.Edit = edit
 	return SuccessResponse(c, SimulationStateToResponse(state))
 }
 
+// editErrorResponse responds to a result edit that failed
+func editErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case conflicting(err):
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrMatchNotFound):
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrFutureMatch), errors.Is(err, services.ErrMatchNotPlayed),
+		errors.Is(err, services.ErrSeasonCompleted):
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+	return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+}
+
+// UpdateSettings updates league settings such as the simulation seed, tiebreakers and knockout stage
+//
+//	@Summary		Update league settings
+//	@Description	Updates league settings. The seed is the base for every simulated match, so the same seed and fixtures replay the same season. Tiebreakers are chosen with a preset (premier_league, uefa_group_stage) or a custom list of rules: head_to_head_points, head_to_head_goal_difference, head_to_head_goals_for, head_to_head_away_goals, goal_difference, goals_for, away_goals, wins, away_wins, drawing_of_lots. knockoutTeams (0, or a power of two up to the number of teams) adds a knockout stage for the top of the table after the league; it can only be changed before the league phase ends. groups (0, or a power of two up to 16) splits the teams into groups of four drawn from pots by power, keeping teams from the same country apart; the top two of each group reach the knockout stage. Groups can only be changed before fixtures are generated. format (round_robin or swiss) chooses a double round-robin or a Swiss league phase: four pots by power, each team playing two opponents from each pot, one at home and one away, in a single table; with a knockout stage of n teams the top n/2 qualify directly and the next n play off for the other places. The format can only be changed before fixtures are generated. engine (poisson, dixon_coles, elo or minute_by_minute) picks the match engine for matches played from then on, tuned with engineParams: homeAdvantage and baseGoals for the power-ratio expected goals, maxGoals, rho for the Dixon-Coles low-score correction, eloHomeAdvantage and eloDrawRate for the Elo model. ratingUpdates turns on Elo rating updates after every played or edited match, replaying the season's results so far; ratingK (above 0, at most 100) is their K-factor. Rated teams are simulated at their current rating instead of their power. startDate (YYYY-MM-DD) and matchdayInterval (1-28 days) schedule the weeks, and can only be changed before fixtures are generated. fatigue turns on the fatigue model: each match tires a team by its minutes over 90, half of which wears off every recoveryHalfLife days, and the team loses penalty of its strength per 90 minutes still in its legs, at most maxPenalty. futureEdits (reject or allow) decides whether results can be entered by hand for matches after the current week.
+//	@Tags			Simulation
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		UpdateSettingsRequest	true	"Settings to update"
+//	@Success		200		{object}	LeagueStateFullResponse	"Success response with updated league state"
+//	@Failure		400		{object}	APIErrorResponse		"Invalid request body, or settings the league can't take at this point"
+//	@Failure		409		{object}	APIErrorResponse		"A week is being played live, or another request changed the league first"
+//	@Failure		500		{object}	APIErrorResponse		"Internal server error"
+//	@Router			/simulation/settings [put]
+func (h *SimulationHandler) UpdateSettings(c *fiber.Ctx) error {
+	var req UpdateSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	state, err := h.simulationService.UpdateSettings(leagueID(c), req.Settings())
+	switch {
+	case conflicting(err):
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrInvalidSettings):
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	case err != nil:
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, LeagueStateToResponse(state))
+}
+
 // ResetSimulation resets the simulation to initial state (keeps fixtures)
 //
 //	@Summary		Reset simulation
@@ -118,19 +297,31 @@ func (h *SimulationHandler) UpdateMatchResult(c *fiber.Ctx) error {
 //	@Accept			json
 //	@Produce		json
 //	@Success		200	{object}	MessageResponse		"Success response with reset confirmation"
+//	@Failure		409	{object}	APIErrorResponse	"A week is being played live, or another request changed the league first"
 //	@Failure		500	{object}	APIErrorResponse	"Internal server error"
 //	@Router			/simulation/reset [post]
 func (h *SimulationHandler) ResetSimulation(c *fiber.Ctx) error {
-	if err := h.simulationService.ResetSimulation(); err != nil {
+	err := h.simulationService.ResetSimulation(leagueID(c))
+	if conflicting(err) {
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+
+	// Watchers get the fresh state rather than having to refetch it
+	state, err := h.standingsService.GetFullState(leagueID(c))
+	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
+	publish(h.events, leagueID(c), models.LeagueEventReset, SimulationStateToResponse(state))
 	return SuccessResponse(c, MessageData{Message: "Simulation reset successfully"})
 }
 
 // GetState returns the current simulation state
 //
 //	@Summary		Get simulation state
-//	@Description	Returns the complete current state including standings, predictions, and match results
+//	@Description	Returns the complete current state including standings, predictions, and match results, and the scores so far while a week is played live
 //	@Tags			Simulation
 //	@Accept			json
 //	@Produce		json
@@ -138,9 +329,127 @@ func (h *SimulationHandler) ResetSimulation(c *fiber.Ctx) error {
 //	@Failure		500	{object}	APIErrorResponse			"Internal server error"
 //	@Router			/simulation/state [get]
 func (h *SimulationHandler) GetState(c *fiber.Ctx) error {
-	state, err := h.standingsService.GetFullState()
+	state, err := h.standingsService.GetFullState(leagueID(c))
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
+	state.Live = h.simulationService.GetLiveWeek(leagueID(c))
 	return SuccessResponse(c, SimulationStateToResponse(state))
 }
+
+// GetMatchTimeline returns a match's events
+//
+//	@Summary		Get match timeline
+//	@Description	Returns a match with its events in the order they happened: goals (slot the scorer, relatedSlot the assist), yellow and red cards, injuries, substitutions (slot off, relatedSlot on) and the half_time, full_time and extra_time_end whistles, each with the score once it happened. Players are team-sheet slots: 1 goalkeeper, 2-5 defenders, 6-8 midfielders, 9-11 forwards, 12-18 substitutes. Matches not yet played, or whose result was edited by hand, have no events.
+//	@Tags			Simulation
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int							true	"Match ID"
+//	@Success		200	{object}	MatchTimelineFullResponse	"Success response with the match timeline"
+//	@Failure		400	{object}	APIErrorResponse			"Invalid match ID"
+//	@Failure		404	{object}	APIErrorResponse			"Match not found"
+//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Router			/simulation/match/{id}/timeline [get]
+func (h *SimulationHandler) GetMatchTimeline(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid match ID")
+	}
+
+	timeline, err := h.simulationService.GetMatchTimeline(leagueID(c), uint(id))
+	if errors.Is(err, services.ErrMatchNotFound) {
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, matchTimelineToResponse(timeline))
+}
+
+// GetWeekTimelines returns the events of a week's matches
+//
+//	@Summary		Get week timelines
+//	@Description	Returns every match of a week with its events, as for a single match timeline
+//	@Tags			Simulation
+//	@Accept			json
+//	@Produce		json
+//	@Param			week	path		int							true	"Week number"
+//	@Success		200		{object}	MatchTimelinesListResponse	"Success response with the week's timelines"
+//	@Failure		400		{object}	APIErrorResponse			"Invalid week number"
+//	@Failure		500		{object}	APIErrorResponse			"Internal server error"
+//	@Router			/simulation/week/{week}/timeline [get]
+func (h *SimulationHandler) GetWeekTimelines(c *fiber.Ctx) error {
+	week, err := c.ParamsInt("week")
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid week number")
+	}
+
+	timelines, err := h.simulationService.GetWeekTimelines(leagueID(c), week)
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, matchTimelinesToResponse(timelines))
+}
+
+// broadcast pushes a change to everyone watching the league: a results event for each week of
+// matches, then the standings and predictions they leave
+func (h *SimulationHandler) broadcast(leagueID uint, weeks [][]models.Match, state *models.SimulationState) {
+	for _, matches := range weeks {
+		if len(matches) == 0 {
+			continue
+		}
+		results := ResultsEventResponse{Week: matches[0].Week, Matches: matchesToResponse(matches)}
+		publish(h.events, leagueID, models.LeagueEventResults, results)
+	}
+	publish(h.events, leagueID, models.LeagueEventStandings, TeamStandingsToResponse(state.Standings))
+	predictions := PredictionsResponse{
+		Iterations:  state.PredictionIterations,
+		Predictions: ChampionshipPredictionsToResponse(state.Predictions),
+	}
+	publish(h.events, leagueID, models.LeagueEventPredictions, predictions)
+}
+
+// liveBroadcaster pushes a live week to everyone watching the league, and records it in the
+// audit log with finish once it is over
+type liveBroadcaster struct {
+	h      *SimulationHandler
+	finish func() error
+}
+
+func (b liveBroadcaster) KickOff(leagueID uint, week *models.LiveWeek) {
+	publish(b.h.events, leagueID, models.LeagueEventKickOff, liveWeekToResponse(week))
+}
+
+func (b liveBroadcaster) Goal(leagueID uint, week int, match *models.LiveMatch, goal *models.MatchEvent) {
+	publish(b.h.events, leagueID, models.LeagueEventGoal, liveGoalToResponse(week, match, goal))
+}
+
+// FullTime broadcasts the saved week like play-week does, or the error that kept it from being saved
+func (b liveBroadcaster) FullTime(leagueID uint, matches []models.Match, err error) {
+	var state *models.SimulationState
+	if err == nil {
+		state, err = b.h.standingsService.GetFullState(leagueID)
+	}
+	if err == nil {
+		b.h.broadcast(leagueID, [][]models.Match{matches}, state)
+	} else {
+		publish(b.h.events, leagueID, models.LeagueEventLiveError, MessageData{Message: err.Error()})
+	}
+	if err := b.finish(); err != nil {
+		log.Printf("Failed to record the live week in the audit log of league %d: %v", leagueID, err)
+	}
+}
+
+// parseOptionalBody parses the request body into out, leaving it untouched when the body is empty
+func parseOptionalBody(c *fiber.Ctx, out interface{}) error {
+	if len(c.Body()) == 0 {
+		return nil
+	}
+	return c.BodyParser(out)
+}
+
+// conflicting reports whether err kept a change from being made because of another one: a week
+// being played live, or a request that changed the league first
+func conflicting(err error) bool {
+	return errors.Is(err, services.ErrWeekLive) || errors.Is(err, services.ErrStateChanged)
+}
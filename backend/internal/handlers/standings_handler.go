@@ -16,7 +16,7 @@ func NewStandingsHandler(standingsService services.StandingsService) *StandingsH
 // GetStandings returns the current league standings
 //
 //	@Summary		Get league standings
-//	@Description	Returns the current league table with points, goals, and positions
+//	@Description	Returns the current league table with points, goals, and positions. For a league without groups each team also carries its race for first place (when there is no knockout stage) and for the knockout places (when there is one): whether it has clinched them or is eliminated on points, and the fewest more points that make sure of them, worked out from the remaining fixtures between the teams.
 //	@Tags			Standings
 //	@Accept			json
 //	@Produce		json
@@ -24,17 +24,35 @@ func NewStandingsHandler(standingsService services.StandingsService) *StandingsH
 //	@Failure		500	{object}	APIErrorResponse		"Internal server error"
 //	@Router			/standings [get]
 func (h *StandingsHandler) GetStandings(c *fiber.Ctx) error {
-	standings, err := h.standingsService.GetStandings()
+	standings, err := h.standingsService.GetStandings(leagueID(c))
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 	return SuccessResponse(c, TeamStandingsToResponse(standings))
 }
 
+// GetGroupStandings returns every group table of the group stage
+//
+//	@Summary		Get group standings
+//	@Description	Returns the table of every group in group order; empty for a league without a group stage or before the groups are drawn. The top two of each group reach the knockout stage, and each team carries its race for those places from the group's remaining fixtures.
+//	@Tags			Standings
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	GroupStandingsListResponse	"Success response with group tables"
+//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Router			/standings/groups [get]
+func (h *StandingsHandler) GetGroupStandings(c *fiber.Ctx) error {
+	tables, err := h.standingsService.GetGroupStandings(leagueID(c))
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, groupTablesToResponse(tables))
+}
+
 // GetPredictions returns championship predictions
 //
 //	@Summary		Get championship predictions
-//	@Description	Returns the probability of each team winning the championship (available from week 4)
+//	@Description	Returns the probability of each team winning the championship, estimated by simulating the remaining fixtures
 //	@Tags			Standings
 //	@Accept			json
 //	@Produce		json
@@ -42,9 +60,27 @@ func (h *StandingsHandler) GetStandings(c *fiber.Ctx) error {
 //	@Failure		500	{object}	APIErrorResponse		"Internal server error"
 //	@Router			/predictions [get]
 func (h *StandingsHandler) GetPredictions(c *fiber.Ctx) error {
-	predictions, err := h.standingsService.GetPredictions()
+	predictions, err := h.standingsService.GetPredictions(leagueID(c))
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, PredictionResultToResponse(predictions))
+}
+
+// GetPositionPredictions returns every team's finishing position probabilities
+//
+//	@Summary		Get finishing position predictions
+//	@Description	Returns each team's probability of finishing in every position of the league table (first place first, in percent), its expected final points and the 5th, 25th, 50th, 75th and 95th percentiles of its final points, estimated by simulating the remaining league fixtures. Summing the first two positions gives a team's chance of a top-two finish.
+//	@Tags			Standings
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	PositionPredictionsListResponse	"Success response with position predictions"
+//	@Failure		500	{object}	APIErrorResponse				"Internal server error"
+//	@Router			/predictions/positions [get]
+func (h *StandingsHandler) GetPositionPredictions(c *fiber.Ctx) error {
+	predictions, err := h.standingsService.GetPositionPredictions(leagueID(c))
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
-	return SuccessResponse(c, ChampionshipPredictionsToResponse(predictions))
+	return SuccessResponse(c, positionPredictionsToResponse(predictions))
 }
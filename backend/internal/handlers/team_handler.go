@@ -1,17 +1,19 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/zahidcakici/champions-league/internal/models"
 	"github.com/zahidcakici/champions-league/internal/services"
 )
 
 type TeamHandler struct {
-	teamService services.TeamService
+	teamService   services.TeamService
+	ratingService services.RatingService
 }
 
-func NewTeamHandler(teamService services.TeamService) *TeamHandler {
-	return &TeamHandler{teamService: teamService}
+func NewTeamHandler(teamService services.TeamService, ratingService services.RatingService) *TeamHandler {
+	return &TeamHandler{teamService: teamService, ratingService: ratingService}
 }
 
 // GetAllTeams returns all teams in the league
@@ -25,7 +27,7 @@ func NewTeamHandler(teamService services.TeamService) *TeamHandler {
 //	@Failure		500	{object}	APIErrorResponse	"Internal server error"
 //	@Router			/teams [get]
 func (h *TeamHandler) GetAllTeams(c *fiber.Ctx) error {
-	teams, err := h.teamService.GetAllTeams()
+	teams, err := h.teamService.GetAllTeams(leagueID(c))
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
@@ -35,13 +37,14 @@ func (h *TeamHandler) GetAllTeams(c *fiber.Ctx) error {
 // CreateTeam creates a new team
 //
 //	@Summary		Create a new team
-//	@Description	Creates a new team with a specified name and power rating
+//	@Description	Creates a new team with a specified name, power rating and optional country; teams from the same country are kept apart in the group draw. attack and defence (1-100) default to the power; when both are given the power may be omitted and is their rounded average.
 //	@Tags			Teams
 //	@Accept			json
 //	@Produce		json
 //	@Param			team	body		CreateTeamRequest	true	"Team creation payload"
 //	@Success		201		{object}	TeamResponse			"Success response with created team"
 //	@Failure		400		{object}	APIErrorResponse		"Bad request (e.g., invalid input)"
+//	@Failure		409		{object}	APIErrorResponse		"A week is being played live"
 //	@Failure		500		{object}	APIErrorResponse		"Internal server error"
 //	@Router			/teams [post]
 func (h *TeamHandler) CreateTeam(c *fiber.Ctx) error {
@@ -54,19 +57,16 @@ func (h *TeamHandler) CreateTeam(c *fiber.Ctx) error {
 	if req.Name == "" {
 		return ErrorResponse(c, fiber.StatusBadRequest, "Team name is required")
 	}
-	if req.Power < 1 || req.Power > 100 {
-		return ErrorResponse(c, fiber.StatusBadRequest, "Team power must be between 1 and 100")
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
 	}
 
-	err := h.teamService.CreateTeam(req.Name, req.Power)
+	team, err := h.teamService.CreateTeam(leagueID(c), req.Name, req.Country, req.Power, req.Attack, req.Defence)
 	if err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 
-	return SuccessResponse(c, teamToResponse(&models.Team{
-		Name:  req.Name,
-		Power: req.Power,
-	}))
+	return SuccessResponse(c, teamToResponse(team))
 }
 
 // DeleteTeam deletes a team by ID
@@ -79,6 +79,7 @@ func (h *TeamHandler) CreateTeam(c *fiber.Ctx) error {
 //	@Param			id	path		int					true	"Team ID"
 //	@Success		200	{object}	APISuccessResponse	"Success response"
 //	@Failure		400	{object}	APIErrorResponse	"Bad request (e.g., invalid ID)"
+//	@Failure		409	{object}	APIErrorResponse	"A week is being played live"
 //	@Failure		500	{object}	APIErrorResponse	"Internal server error"
 //	@Router			/teams/{id} [delete]
 func (h *TeamHandler) DeleteTeam(c *fiber.Ctx) error {
@@ -87,9 +88,72 @@ func (h *TeamHandler) DeleteTeam(c *fiber.Ctx) error {
 		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid team ID")
 	}
 
-	if err := h.teamService.DeleteTeam(uint(id)); err != nil {
+	if err := h.teamService.DeleteTeam(leagueID(c), uint(id)); err != nil {
 		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
 	}
 
 	return SuccessResponse(c, fiber.Map{"deleted": true})
 }
+
+// GetRatingHistory returns a team's Elo rating history
+//
+//	@Summary		Get a team's rating history
+//	@Description	Returns the team's current Elo rating and its rating after each week of the season, starting from week 0. Ratings only move while rating updates are on (see PUT /simulation/settings); otherwise the rating is derived from the team's power and the history is empty.
+//	@Tags			Teams
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int							true	"Team ID"
+//	@Success		200	{object}	RatingHistoryFullResponse	"Success response with the rating history"
+//	@Failure		400	{object}	APIErrorResponse			"Invalid team ID"
+//	@Failure		404	{object}	APIErrorResponse			"Team not found"
+//	@Failure		500	{object}	APIErrorResponse			"Internal server error"
+//	@Router			/teams/{id}/ratings [get]
+func (h *TeamHandler) GetRatingHistory(c *fiber.Ctx) error {
+	id, err := c.ParamsInt("id")
+	if err != nil || id < 1 {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid team ID")
+	}
+
+	history, err := h.ratingService.GetRatingHistory(leagueID(c), uint(id))
+	if errors.Is(err, services.ErrTeamNotFound) {
+		return ErrorResponse(c, fiber.StatusNotFound, err.Error())
+	}
+	if err != nil {
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, ratingHistoryToResponse(history))
+}
+
+// FitRatings fits team strengths to historical results
+//
+//	@Summary		Fit team strengths to historical results
+//	@Description	Fits every listed team's attack and defence, the home advantage and the base goals to past results by maximum likelihood, using the power-ratio Poisson model the match engines play with: the home side expects 2 × baseGoals × attack × homeAdvantage / (attack × homeAdvantage + opposing defence) goals. Teams are named as in the league; teams without results keep their strengths. The strengths are scaled so the fitted teams keep their average power, then written onto the teams unless dryRun is set; that is only allowed between seasons. The response reports the fitted home advantage next to the league's homeAdvantage engine parameter, and the log-likelihood, deviance and McFadden pseudo-R² of the fit.
+//	@Tags			Teams
+//	@Accept			json
+//	@Produce		json
+//	@Param			body	body		FitRatingsRequest		true	"Historical results"
+//	@Success		200		{object}	RatingFitFullResponse	"Success response with the fitted strengths"
+//	@Failure		400		{object}	APIErrorResponse		"Bad request (e.g., unknown team or too few results)"
+//	@Failure		409		{object}	APIErrorResponse		"A week is being played live, or a season is in progress"
+//	@Failure		500		{object}	APIErrorResponse		"Internal server error"
+//	@Router			/teams/ratings/fit [post]
+func (h *TeamHandler) FitRatings(c *fiber.Ctx) error {
+	var req FitRatingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, "Invalid request payload")
+	}
+	if err := req.Validate(); err != nil {
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	}
+
+	fit, err := h.ratingService.FitRatings(leagueID(c), req.HistoricalResults(), req.DryRun)
+	switch {
+	case errors.Is(err, services.ErrInvalidFit):
+		return ErrorResponse(c, fiber.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrSeasonInProgress), conflicting(err):
+		return ErrorResponse(c, fiber.StatusConflict, err.Error())
+	case err != nil:
+		return ErrorResponse(c, fiber.StatusInternalServerError, err.Error())
+	}
+	return SuccessResponse(c, ratingFitToResponse(fit))
+}
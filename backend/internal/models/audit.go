@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+)
+
+// AuditEntry is an action that changed a league, kept in the league's append-only audit log
+type AuditEntry struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	LeagueID  uint          `json:"league_id" gorm:"not null;index"`
+	Action    string        `json:"action" gorm:"not null"`
+	Actor     string        `json:"actor" gorm:"not null;default:''"` // Who asked for it, as the client named itself
+	TargetID  *uint         `json:"target_id"`                        // Entry an undo or redo reverted or reapplied
+	Changes   []AuditChange `json:"changes" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time     `json:"created_at"`
+
+	// Whether the action is currently undone. Not stored.
+	Undone bool `json:"undone" gorm:"-"`
+}
+
+// AuditChange is a row an action added, changed or removed. Before and After hold the fields
+// that changed, or every field of a row added or removed, and are nil where the row didn't exist.
+type AuditChange struct {
+	Kind   string         `json:"kind"`
+	ID     uint           `json:"id"`
+	Before map[string]any `json:"before"`
+	After  map[string]any `json:"after"`
+}
+
+// Kinds of rows audit changes are recorded for. Match events, absences and rating history
+// follow from the results and are restored with them without being listed.
+const (
+	AuditKindState  = "state"
+	AuditKindTeam   = "team"
+	AuditKindPlayer = "player"
+	AuditKindMatch  = "match"
+	AuditKindTie    = "knockout_tie"
+	AuditKindGroup  = "group_entry"
+	AuditKindSeason = "season"
+)
+
+// Audited actions
+const (
+	AuditCreateTeam       = "create_team"
+	AuditDeleteTeam       = "delete_team"
+	AuditFitRatings       = "fit_ratings"
+	AuditCreatePlayer     = "create_player"
+	AuditUpdatePlayer     = "update_player"
+	AuditDeletePlayer     = "delete_player"
+	AuditGenerateFixtures = "generate_fixtures"
+	AuditRescheduleMatch  = "reschedule_match"
+	AuditPlayWeek         = "play_week"
+	AuditPlayLiveWeek     = "play_live_week"
+	AuditPlayAll          = "play_all"
+	AuditUpdateResult     = "update_result"
+	AuditClearResult      = "clear_result"
+	AuditUpdateSettings   = "update_settings"
+	AuditReset            = "reset"
+	AuditUndo             = "undo" // Reverts the latest action not undone yet
+	AuditRedo             = "redo" // Reapplies the latest action undone, until another action is taken
+)
+
+// AuditDelta holds the rows an audited action changed, as they were before it and after it, for
+// undo and redo to put back. A row only in Before was removed by the action, one only in After
+// added.
+type AuditDelta struct {
+	EntryID uint           `gorm:"primaryKey;autoIncrement:false"`
+	Before  LeagueSnapshot `gorm:"type:jsonb;serializer:json"`
+	After   LeagueSnapshot `gorm:"type:jsonb;serializer:json"`
+}
+
+// LeagueSnapshot is everything a league owns at one point in time, or some of its rows
+type LeagueSnapshot struct {
+	State           *LeagueState     `json:"state"` // nil before the league's state is first written
+	Teams           []Team           `json:"teams"`
+	Players         []Player         `json:"players"`
+	Matches         []Match          `json:"matches"`
+	MatchEvents     []MatchEvent     `json:"match_events"`
+	Absences        []Absence        `json:"absences"`
+	Ties            []KnockoutTie    `json:"ties"`
+	Groups          []GroupEntry     `json:"groups"`
+	Ratings         []TeamRating     `json:"ratings"`
+	Seasons         []Season         `json:"seasons"` // Without their standings and matches, listed apart
+	SeasonStandings []SeasonStanding `json:"season_standings"`
+	SeasonMatches   []SeasonMatch    `json:"season_matches"`
+}
+
+// SnapshotTables picks the tables of a league a snapshot reads. The state is always read.
+type SnapshotTables uint
+
+const (
+	SnapshotTeams SnapshotTables = 1 << iota
+	SnapshotPlayers
+	SnapshotMatches
+	SnapshotMatchEvents
+	SnapshotAbsences
+	SnapshotTies
+	SnapshotGroups
+	SnapshotRatings
+	SnapshotSeasons // With their standings and matches
+	SnapshotAll     = SnapshotTables(1)<<iota - 1
+)
@@ -0,0 +1,67 @@
+package models
+
+import "time"
+
+// Absence is a player missing matches through injury or suspension, from the match where it
+// happened on
+type Absence struct {
+	ID       uint   `gorm:"primaryKey"`
+	LeagueID uint   `gorm:"not null;index"`
+	TeamID   uint   `gorm:"not null;index"`
+	PlayerID uint   `gorm:"not null;index"`
+	MatchID  uint   `gorm:"not null;index"`
+	Week     int    `gorm:"not null"` // Week of the match where it happened
+	Reason   string `gorm:"not null"`
+	Detail   string `gorm:"not null;default:''"` // What earned a suspension
+	Length   int    `gorm:"not null"`            // Weeks out injured, or team matches suspended
+}
+
+// Absence reasons
+const (
+	AbsenceInjury     = "injury"
+	AbsenceSuspension = "suspension"
+)
+
+// Suspension details
+const (
+	SuspensionRedCard      = "red_card"
+	SuspensionSecondYellow = "second_yellow"
+	SuspensionYellowCards  = "yellow_cards"
+)
+
+const (
+	// Matches banned for a straight red card, a second booking in a match and every fifth
+	// yellow card of the season
+	RedCardBan        = 3
+	SecondYellowBan   = 1
+	YellowCardBan     = 1
+	YellowCardsPerBan = 5
+	MaxInjuryWeeks    = 5 // Injuries last 1 to MaxInjuryWeeks weeks
+)
+
+// PlayerAbsence is a player's absence in a given week
+type PlayerAbsence struct {
+	Absence
+	PlayerName string
+	Position   string
+	ReturnWeek int // First week the player is available again, 0 if not within the fixtures
+}
+
+// TeamAvailability is who a team can pick in a week and what its absences cost it
+type TeamAvailability struct {
+	TeamID    uint
+	TeamName  string
+	Week      int
+	SquadSize int
+	Available []Player
+	Absences  []PlayerAbsence
+	// Share of the team's attack and defence the available players leave it, 1 at full strength
+	AttackAvailability  float64
+	DefenceAvailability float64
+
+	// The team's match of the week, with the days since its previous match (0 without one) and
+	// the share of its strength tiredness leaves it, 1 when rested or without fatigue
+	MatchDate *time.Time
+	RestDays  int
+	Freshness float64
+}
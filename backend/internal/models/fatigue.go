@@ -0,0 +1,48 @@
+package models
+
+// FatigueParams tunes how much matches tire teams and how fast they recover. A match leaves a
+// team tired by its minutes over 90, and half of that wears off every RecoveryHalfLife days; the
+// team loses Penalty of its strength per 90 minutes still in its legs, at most MaxPenalty.
+type FatigueParams struct {
+	Enabled          bool    `json:"enabled" gorm:"not null;default:false"`
+	RecoveryHalfLife float64 `json:"recovery_half_life" gorm:"not null;default:2"` // Days
+	Penalty          float64 `json:"penalty" gorm:"not null;default:0.1"`
+	MaxPenalty       float64 `json:"max_penalty" gorm:"not null;default:0.25"`
+}
+
+// DefaultFatigueParams returns the parameters of a new league, the same as the column defaults
+func DefaultFatigueParams() FatigueParams {
+	return FatigueParams{RecoveryHalfLife: 2, Penalty: 0.1, MaxPenalty: 0.25}
+}
+
+// FatigueSettings changes fatigue parameters; nil fields are left unchanged
+type FatigueSettings struct {
+	Enabled          *bool    `json:"enabled"`
+	RecoveryHalfLife *float64 `json:"recovery_half_life"`
+	Penalty          *float64 `json:"penalty"`
+	MaxPenalty       *float64 `json:"max_penalty"`
+}
+
+// Valid reports whether every parameter set is in range: recovery half-life in (0, 14] days,
+// penalty in [0, 1] and max penalty in [0, 0.9]
+func (s *FatigueSettings) Valid() bool {
+	return (s.RecoveryHalfLife == nil || (*s.RecoveryHalfLife > 0 && *s.RecoveryHalfLife <= 14)) &&
+		(s.Penalty == nil || (*s.Penalty >= 0 && *s.Penalty <= 1)) &&
+		(s.MaxPenalty == nil || (*s.MaxPenalty >= 0 && *s.MaxPenalty <= 0.9))
+}
+
+// Apply copies the parameters that are set onto params
+func (s *FatigueSettings) Apply(params *FatigueParams) {
+	if s.Enabled != nil {
+		params.Enabled = *s.Enabled
+	}
+	if s.RecoveryHalfLife != nil {
+		params.RecoveryHalfLife = *s.RecoveryHalfLife
+	}
+	if s.Penalty != nil {
+		params.Penalty = *s.Penalty
+	}
+	if s.MaxPenalty != nil {
+		params.MaxPenalty = *s.MaxPenalty
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"math/bits"
+	"time"
+)
+
+// GroupSize is the number of teams in each group of the group stage
+const GroupSize = 4
+
+// GroupEntry places a team in a group of the group stage, with the pot it was drawn from
+type GroupEntry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	LeagueID  uint      `json:"league_id" gorm:"not null;index"`
+	Group     string    `json:"group" gorm:"column:group_name;not null"` // "A", "B", ...
+	Pot       int       `json:"pot" gorm:"not null"`                     // 1 for the strongest teams
+	TeamID    uint      `json:"team_id" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Relations
+	Team Team `json:"team" gorm:"foreignKey:TeamID"`
+}
+
+// GroupTable is the standings of one group
+type GroupTable struct {
+	Group     string         `json:"group"`
+	Standings []TeamStanding `json:"standings"`
+}
+
+// ValidGroupCount reports whether a league can be split into the given number of groups: 0 for
+// a single table, otherwise a power of two up to 16 so the top two of each group fill a bracket
+func ValidGroupCount(groups int) bool {
+	return groups == 0 || (groups <= 16 && bits.OnesCount(uint(groups)) == 1)
+}
+
+// GroupName returns the letter of the i-th group, starting at 0
+func GroupName(i int) string {
+	return string(rune('A' + i))
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"fmt"
+	"math/bits"
+	"time"
+)
+
+// KnockoutTie is a pairing in the knockout bracket, played over two legs or, in the final,
+// as a single match at a neutral venue
+type KnockoutTie struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	LeagueID       uint      `json:"league_id" gorm:"not null;index"`
+	Round          int       `json:"round" gorm:"not null"`     // 1 for the first knockout round
+	Slot           int       `json:"slot" gorm:"not null"`      // Winners of slots 2k and 2k+1 meet in the next round
+	TeamAID        uint      `json:"team_a_id" gorm:"not null"` // Higher seed, hosts the second leg
+	TeamBID        uint      `json:"team_b_id" gorm:"not null"`
+	TeamASeed      int       `json:"team_a_seed"`
+	TeamBSeed      int       `json:"team_b_seed"`
+	SingleLeg      bool      `json:"single_leg"`
+	TeamAAggregate int       `json:"team_a_aggregate"` // Including extra time
+	TeamBAggregate int       `json:"team_b_aggregate"`
+	WinnerID       *uint     `json:"winner_id"` // nil until the tie is decided
+	DecidedBy      string    `json:"decided_by"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+
+	// Relations
+	TeamA   Team    `json:"team_a" gorm:"foreignKey:TeamAID"`
+	TeamB   Team    `json:"team_b" gorm:"foreignKey:TeamBID"`
+	Matches []Match `json:"matches" gorm:"foreignKey:TieID"`
+}
+
+// How a knockout tie was decided
+const (
+	TieDecidedByAggregate = "aggregate" // The score over both legs, or of the single-leg final
+	TieDecidedByExtraTime = "extra_time"
+	TieDecidedByPenalties = "penalties"
+)
+
+// KnockoutRound is one round of the bracket
+type KnockoutRound struct {
+	Round int           `json:"round"`
+	Name  string        `json:"name"`
+	Ties  []KnockoutTie `json:"ties"`
+}
+
+// KnockoutBracket is a league's knockout stage; rounds appear as they are drawn
+type KnockoutBracket struct {
+	Teams      int             `json:"teams"` // 0 when the league has no knockout stage
+	Rounds     []KnockoutRound `json:"rounds"`
+	ChampionID *uint           `json:"champion_id"`
+}
+
+// ValidKnockoutTeams reports whether a bracket can start with the given number of teams:
+// 0 disables the knockout stage, otherwise a power of two between 2 and 64
+func ValidKnockoutTeams(teams int) bool {
+	return teams == 0 || (teams >= 2 && teams <= 64 && teams&(teams-1) == 0)
+}
+
+// KnockoutRounds returns the number of rounds of a bracket with the given number of teams
+func KnockoutRounds(teams int) int {
+	if teams < 2 {
+		return 0
+	}
+	return bits.Len(uint(teams)) - 1
+}
+
+// KnockoutWeeks returns the number of weeks a bracket takes: two legs per round and a single-leg final
+func KnockoutWeeks(teams int) int {
+	rounds := KnockoutRounds(teams)
+	if rounds == 0 {
+		return 0
+	}
+	return 2*(rounds-1) + 1
+}
+
+// KnockoutPlayoffName names the play-off round between a Swiss league phase and the bracket
+const KnockoutPlayoffName = "Knockout play-off"
+
+// KnockoutRoundName names a round by the number of teams left in it
+func KnockoutRoundName(teamsLeft int) string {
+	switch teamsLeft {
+	case 2:
+		return "Final"
+	case 4:
+		return "Semi-finals"
+	case 8:
+		return "Quarter-finals"
+	default:
+		return fmt.Sprintf("Round of %d", teamsLeft)
+	}
+}
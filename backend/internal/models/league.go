@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// DefaultLeagueName is the league served by the un-nested /api routes
+const DefaultLeagueName = "Default"
+
+// League is an independent competition that owns its teams, matches and state
+type League struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
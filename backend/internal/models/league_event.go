@@ -0,0 +1,24 @@
+package models
+
+// LeagueEvent is a change to a league pushed to everyone watching it. IDs are a per-process epoch
+// and a number counting up from 1 within each league, so a subscriber who reconnects can ask for
+// what it missed, and one reconnecting after a restart is told to start over.
+type LeagueEvent struct {
+	ID       string
+	LeagueID uint
+	Type     string
+	Data     []byte // JSON payload
+}
+
+// League event types
+const (
+	LeagueEventResults     = "results"
+	LeagueEventStandings   = "standings"
+	LeagueEventPredictions = "predictions"
+	LeagueEventReset       = "reset"
+	LeagueEventKickOff     = "kick_off"   // A week starts being played live
+	LeagueEventGoal        = "goal"       // A goal in a live week
+	LeagueEventLiveError   = "live_error" // A live week couldn't be saved at the final whistle
+	LeagueEventResync      = "resync"     // The events missed are no longer kept; refetch the state
+	LeagueEventRestore     = "restore"    // An undo or redo put the league back as it was
+)
@@ -5,12 +5,166 @@ import (
 )
 
 type LeagueState struct {
-	ID              uint      `json:"id" gorm:"primaryKey"`
-	CurrentWeek     int       `json:"current_week" gorm:"default:0"`
-	TotalWeeks      int       `json:"total_weeks" gorm:"default:6"`
-	FixturesCreated bool      `json:"fixtures_created" gorm:"default:false"`
-	Started         bool      `json:"started" gorm:"default:false"`
-	Completed       bool      `json:"completed" gorm:"default:false"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	LeagueID         uint      `json:"league_id" gorm:"not null;default:0;uniqueIndex"`
+	CurrentWeek      int       `json:"current_week" gorm:"default:0"`
+	TotalWeeks       int       `json:"total_weeks" gorm:"default:6"`
+	FixturesCreated  bool      `json:"fixtures_created" gorm:"default:false"`
+	Started          bool      `json:"started" gorm:"default:false"`
+	Completed        bool      `json:"completed" gorm:"default:false"`
+	Seed             int64     `json:"seed" gorm:"not null;default:0"` // Base seed for match simulation
+	TiebreakerPreset string    `json:"tiebreaker_preset" gorm:"not null;default:'premier_league'"`
+	Tiebreakers      string    `json:"tiebreakers" gorm:"not null;default:''"`   // Comma-separated rules of a custom chain
+	KnockoutTeams    int       `json:"knockout_teams" gorm:"not null;default:0"` // Teams advancing to the knockout stage, 0 for none
+	Groups           int       `json:"groups" gorm:"not null;default:0"`         // Groups of four in the group stage, 0 for a single table
+	Format           string    `json:"format" gorm:"not null;default:'round_robin'"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Bumped by every update, which only goes through if nobody else updated the state since it
+	// was read
+	Version int `json:"version" gorm:"not null;default:0"`
+
+	// Match engine simulating every match, and its parameters
+	Engine       string            `json:"engine" gorm:"not null;default:'poisson'"`
+	EngineParams MatchEngineParams `json:"engine_params" gorm:"embedded;embeddedPrefix:engine_"`
+
+	// Elo rating updates after every match, and their K-factor
+	RatingUpdates bool    `json:"rating_updates" gorm:"not null;default:false"`
+	RatingK       float64 `json:"rating_k" gorm:"not null;default:20"`
+
+	// Fixture dates: week 1 is played on the start date, each later week MatchdayInterval days on.
+	// The start date is set to the day fixtures are generated unless chosen before.
+	StartDate        *time.Time `json:"start_date" gorm:"type:date"`
+	MatchdayInterval int        `json:"matchday_interval" gorm:"not null;default:7"`
+
+	// Tiredness from matches played in quick succession
+	Fatigue FatigueParams `json:"fatigue" gorm:"embedded;embeddedPrefix:fatigue_"`
+
+	// Whether results can be entered by hand for matches after the current week
+	FutureEdits string `json:"future_edits" gorm:"not null;default:'reject'"`
+}
+
+// DefaultMatchdayInterval is the number of days between the weeks of a new league
+const DefaultMatchdayInterval = 7
+
+// ValidMatchdayInterval reports whether days is an allowed number of days between weeks, 1-28
+func ValidMatchdayInterval(days int) bool {
+	return days >= 1 && days <= 28
+}
+
+// WeekDate returns the date a week is scheduled for, nil before the league has a start date
+func (s *LeagueState) WeekDate(week int) *time.Time {
+	if s.StartDate == nil {
+		return nil
+	}
+	interval := s.MatchdayInterval
+	if interval <= 0 {
+		interval = DefaultMatchdayInterval
+	}
+	date := s.StartDate.AddDate(0, 0, (week-1)*interval)
+	return &date
+}
+
+// TiebreakerRules returns the league's tiebreaker chain, falling back to the default preset
+func (s *LeagueState) TiebreakerRules() []TiebreakerRule {
+	if s.TiebreakerPreset == TiebreakerPresetCustom {
+		return ParseTiebreakerRules(s.Tiebreakers)
+	}
+	if rules, ok := TiebreakerPresets[s.TiebreakerPreset]; ok {
+		return rules
+	}
+	return TiebreakerPresets[DefaultTiebreakerPreset]
+}
+
+// LeagueWeeks returns the number of weeks before the knockout stage starts
+func (s *LeagueState) LeagueWeeks() int {
+	return s.TotalWeeks - s.KnockoutWeeks()
+}
+
+// HasPlayoff reports whether a play-off round comes before the bracket: after a Swiss league
+// phase the top half of the bracket qualifies directly and the next places play off for the rest
+func (s *LeagueState) HasPlayoff() bool {
+	return s.Format == LeagueFormatSwiss && s.KnockoutTeams > 0
+}
+
+// KnockoutRounds returns the number of knockout rounds, including the play-off
+func (s *LeagueState) KnockoutRounds() int {
+	rounds := KnockoutRounds(s.KnockoutTeams)
+	if s.HasPlayoff() {
+		rounds++
+	}
+	return rounds
+}
+
+// KnockoutWeeks returns the number of weeks the knockout stage takes, including the play-off
+func (s *LeagueState) KnockoutWeeks() int {
+	if s.HasPlayoff() {
+		return KnockoutWeeks(s.KnockoutTeams) + 2
+	}
+	return KnockoutWeeks(s.KnockoutTeams)
 }
+
+// KnockoutQualifiers returns the number of teams the league phase sends to the knockout stage,
+// play-off teams included
+func (s *LeagueState) KnockoutQualifiers() int {
+	if s.HasPlayoff() {
+		return s.KnockoutTeams + s.KnockoutTeams/2
+	}
+	return s.KnockoutTeams
+}
+
+// KnockoutStarted reports whether the league phase is over and the bracket has been drawn
+func (s *LeagueState) KnockoutStarted() bool {
+	return s.KnockoutTeams > 0 && s.FixturesCreated && s.CurrentWeek >= s.LeagueWeeks()
+}
+
+// LeagueSettings holds league settings that can be changed at any time; nil fields are left unchanged
+type LeagueSettings struct {
+	Seed             *int64           `json:"seed"`
+	TiebreakerPreset *string          `json:"tiebreaker_preset"`
+	Tiebreakers      []TiebreakerRule `json:"tiebreakers"` // Custom chain, selects the custom preset
+	KnockoutTeams    *int             `json:"knockout_teams"`
+	Groups           *int             `json:"groups"`
+	Format           *string          `json:"format"`
+
+	Engine       *string              `json:"engine"`
+	EngineParams *MatchEngineSettings `json:"engine_params"` // Changes to the engine parameters
+
+	RatingUpdates *bool    `json:"rating_updates"`
+	RatingK       *float64 `json:"rating_k"`
+
+	StartDate        *time.Time       `json:"start_date"`
+	MatchdayInterval *int             `json:"matchday_interval"`
+	Fatigue          *FatigueSettings `json:"fatigue"` // Changes to the fatigue parameters
+
+	FutureEdits *string `json:"future_edits"`
+}
+
+// Policies for results entered by hand ahead of the current week
+const (
+	FutureEditsReject = "reject" // Only matches up to the current week can be edited
+	FutureEditsAllow  = "allow"  // Later matches count as played before their week, which then only plays the rest
+)
+
+// ValidFutureEdits reports whether policy is a known policy for editing future matches
+func ValidFutureEdits(policy string) bool {
+	return policy == FutureEditsReject || policy == FutureEditsAllow
+}
+
+// League formats
+const (
+	LeagueFormatRoundRobin = "round_robin" // Every team plays every other team home and away
+	LeagueFormatSwiss      = "swiss"       // Eight opponents each, two from each of four pots
+)
+
+// ValidLeagueFormat reports whether format is a known league format
+func ValidLeagueFormat(format string) bool {
+	return format == LeagueFormatRoundRobin || format == LeagueFormatSwiss
+}
+
+// DefaultRatingK is the K-factor of a new league's rating updates
+const DefaultRatingK = 20.0
+
+// MaxSeed bounds generated seeds so they survive a round trip through JSON numbers (2^53)
+const MaxSeed = 1 << 53
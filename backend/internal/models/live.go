@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// LiveWeek is a week being played out in real time. Its results are saved once the last match
+// ends; until then only the scores so far are known.
+type LiveWeek struct {
+	Week      int
+	StartedAt time.Time
+	Duration  time.Duration // Real time the 90 minutes take
+	Minute    int           // Match minutes played so far
+	Matches   []LiveMatch
+}
+
+// LiveMatch is the score so far of a match in a live week
+type LiveMatch struct {
+	MatchID      uint
+	HomeTeamID   uint
+	HomeTeamName string
+	AwayTeamID   uint
+	AwayTeamName string
+	HomeScore    int // Extra time goals included
+	AwayScore    int
+	Finished     bool
+}
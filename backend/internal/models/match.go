@@ -5,24 +5,57 @@ import (
 )
 
 type Match struct {
-	ID         uint      `json:"id" gorm:"primaryKey"`
-	Week       int       `json:"week" gorm:"not null;index"`
-	HomeTeamID uint      `json:"home_team_id" gorm:"not null"`
-	AwayTeamID uint      `json:"away_team_id" gorm:"not null"`
-	HomeScore  *int      `json:"home_score"` // nil if not played
-	AwayScore  *int      `json:"away_score"` // nil if not played
-	Played     bool      `json:"played" gorm:"default:false"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	LeagueID   uint       `json:"league_id" gorm:"not null;default:0;index"`
+	Week       int        `json:"week" gorm:"not null;index"`
+	HomeTeamID uint       `json:"home_team_id" gorm:"not null"`
+	AwayTeamID uint       `json:"away_team_id" gorm:"not null"`
+	HomeScore  *int       `json:"home_score"` // nil if not played
+	AwayScore  *int       `json:"away_score"` // nil if not played
+	Played     bool       `json:"played" gorm:"default:false"`
+	Seed       *int64     `json:"seed"` // Seed the result was simulated with, nil if entered manually
+	Stage      string     `json:"stage" gorm:"not null;default:'league';index"`
+	Group      string     `json:"group" gorm:"column:group_name;not null;default:''"`
+	TieID      *uint      `json:"tie_id" gorm:"index"`   // Knockout tie the match belongs to
+	Leg        int        `json:"leg"`                   // 1 or 2 in a two-legged tie, 0 for a single match
+	Neutral    bool       `json:"neutral"`               // Played at a neutral venue, without home advantage
+	Date       *time.Time `json:"date" gorm:"type:date"` // Day the match is scheduled for, nil if never scheduled
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+
+	// Extra time and penalties, only set on the match that decides a level knockout tie
+	HomeExtraTimeScore *int `json:"home_extra_time_score"`
+	AwayExtraTimeScore *int `json:"away_extra_time_score"`
+	HomePenalties      *int `json:"home_penalties"`
+	AwayPenalties      *int `json:"away_penalties"`
 
 	// Relations
 	HomeTeam Team `json:"home_team" gorm:"foreignKey:HomeTeamID"`
 	AwayTeam Team `json:"away_team" gorm:"foreignKey:AwayTeamID"`
 }
 
+// Match stages
+const (
+	MatchStageLeague   = "league"
+	MatchStageGroup    = "group"
+	MatchStageKnockout = "knockout"
+)
+
+// IsKnockout reports whether the match belongs to the knockout stage
+func (m *Match) IsKnockout() bool {
+	return m.Stage == MatchStageKnockout
+}
+
 type MatchResult struct {
 	HomeTeamName string `json:"home_team_name"`
 	AwayTeamName string `json:"away_team_name"`
 	HomeScore    int    `json:"home_score"`
 	AwayScore    int    `json:"away_score"`
 }
+
+// WeekFixtures holds a week's matches and the teams without a match that week (byes)
+type WeekFixtures struct {
+	Week     int     `json:"week"`
+	Matches  []Match `json:"matches"`
+	ByeTeams []Team  `json:"bye_teams"`
+}
@@ -0,0 +1,80 @@
+package models
+
+// Match engines
+const (
+	MatchEnginePoisson    = "poisson"          // Independent Poisson goals from the power ratio
+	MatchEngineDixonColes = "dixon_coles"      // Poisson goals with the Dixon-Coles low-score correction
+	MatchEngineElo        = "elo"              // Win, draw or loss from the Elo expectancy, then a score to match
+	MatchEngineMinute     = "minute_by_minute" // Goals, cards, injuries and substitutions minute by minute
+)
+
+// ValidMatchEngine reports whether engine is a known match engine
+func ValidMatchEngine(engine string) bool {
+	return engine == MatchEnginePoisson || engine == MatchEngineDixonColes || engine == MatchEngineElo ||
+		engine == MatchEngineMinute
+}
+
+// MatchEngineParams tunes the match engines; each engine reads the parameters it needs
+type MatchEngineParams struct {
+	HomeAdvantage    float64 `json:"home_advantage" gorm:"not null;default:1.1"`    // Home power multiplier (Poisson, Dixon-Coles)
+	BaseGoals        float64 `json:"base_goals" gorm:"not null;default:1.5"`        // Expected goals of each of two equal teams
+	MaxGoals         int     `json:"max_goals" gorm:"not null;default:7"`           // Cap on one team's goals
+	Rho              float64 `json:"rho" gorm:"not null;default:-0.1"`              // Dixon-Coles dependence of low scores
+	EloHomeAdvantage float64 `json:"elo_home_advantage" gorm:"not null;default:65"` // Rating points added to the home team
+	EloDrawRate      float64 `json:"elo_draw_rate" gorm:"not null;default:0.28"`    // Draw probability between equal teams
+}
+
+// DefaultMatchEngineParams returns the parameters of a new league, the same as the column defaults
+func DefaultMatchEngineParams() MatchEngineParams {
+	return MatchEngineParams{
+		HomeAdvantage:    1.1,
+		BaseGoals:        1.5,
+		MaxGoals:         7,
+		Rho:              -0.1,
+		EloHomeAdvantage: 65,
+		EloDrawRate:      0.28,
+	}
+}
+
+// MatchEngineSettings changes match engine parameters; nil fields are left unchanged
+type MatchEngineSettings struct {
+	HomeAdvantage    *float64 `json:"home_advantage"`
+	BaseGoals        *float64 `json:"base_goals"`
+	MaxGoals         *int     `json:"max_goals"`
+	Rho              *float64 `json:"rho"`
+	EloHomeAdvantage *float64 `json:"elo_home_advantage"`
+	EloDrawRate      *float64 `json:"elo_draw_rate"`
+}
+
+// Valid reports whether every parameter set is in range: home advantage in (0, 3], base goals in
+// (0, 5], max goals 1-20, rho in [-0.3, 0.3], Elo home advantage 0-400 and Elo draw rate 0-0.5
+func (s *MatchEngineSettings) Valid() bool {
+	return (s.HomeAdvantage == nil || (*s.HomeAdvantage > 0 && *s.HomeAdvantage <= 3)) &&
+		(s.BaseGoals == nil || (*s.BaseGoals > 0 && *s.BaseGoals <= 5)) &&
+		(s.MaxGoals == nil || (*s.MaxGoals >= 1 && *s.MaxGoals <= 20)) &&
+		(s.Rho == nil || (*s.Rho >= -0.3 && *s.Rho <= 0.3)) &&
+		(s.EloHomeAdvantage == nil || (*s.EloHomeAdvantage >= 0 && *s.EloHomeAdvantage <= 400)) &&
+		(s.EloDrawRate == nil || (*s.EloDrawRate >= 0 && *s.EloDrawRate <= 0.5))
+}
+
+// Apply copies the parameters that are set onto params
+func (s *MatchEngineSettings) Apply(params *MatchEngineParams) {
+	if s.HomeAdvantage != nil {
+		params.HomeAdvantage = *s.HomeAdvantage
+	}
+	if s.BaseGoals != nil {
+		params.BaseGoals = *s.BaseGoals
+	}
+	if s.MaxGoals != nil {
+		params.MaxGoals = *s.MaxGoals
+	}
+	if s.Rho != nil {
+		params.Rho = *s.Rho
+	}
+	if s.EloHomeAdvantage != nil {
+		params.EloHomeAdvantage = *s.EloHomeAdvantage
+	}
+	if s.EloDrawRate != nil {
+		params.EloDrawRate = *s.EloDrawRate
+	}
+}
@@ -0,0 +1,117 @@
+package models
+
+// MatchEvent is something that happened during a simulated match. Players are identified by
+// their slot on the team sheet: 1-11 the starting eleven (1 the goalkeeper, 2-5 defenders, 6-8
+// midfielders, 9-11 forwards) and 12-18 the substitutes.
+type MatchEvent struct {
+	ID              uint   `json:"id" gorm:"primaryKey"`
+	LeagueID        uint   `json:"league_id" gorm:"not null;index"`
+	MatchID         uint   `json:"match_id" gorm:"not null;index"`
+	Sequence        int    `json:"sequence" gorm:"not null"` // Order of the event within the match
+	Period          string `json:"period" gorm:"not null"`
+	Minute          int    `json:"minute" gorm:"not null"`
+	AddedTime       int    `json:"added_time" gorm:"not null;default:0"` // Minutes into stoppage time, as in 45+2
+	Type            string `json:"type" gorm:"not null"`
+	TeamID          uint   `json:"team_id" gorm:"not null;default:0"` // 0 for the whistles
+	Slot            int    `json:"slot" gorm:"not null;default:0"`
+	RelatedSlot     int    `json:"related_slot" gorm:"not null;default:0"`    // Assist of a goal, substitute coming on
+	PlayerID        uint   `json:"player_id" gorm:"not null;default:0;index"` // Player in the slot, 0 if the squad left it empty
+	RelatedPlayerID uint   `json:"related_player_id" gorm:"not null;default:0;index"`
+	Detail          string `json:"detail" gorm:"not null;default:''"`
+	HomeScore       int    `json:"home_score" gorm:"not null"` // Score once the event has happened
+	AwayScore       int    `json:"away_score" gorm:"not null"`
+}
+
+// Match event types
+const (
+	MatchEventGoal         = "goal"
+	MatchEventYellowCard   = "yellow_card"
+	MatchEventRedCard      = "red_card"
+	MatchEventSubstitution = "substitution"
+	MatchEventInjury       = "injury"
+	MatchEventHalfTime     = "half_time"
+	MatchEventFullTime     = "full_time"
+	MatchEventExtraTimeEnd = "extra_time_end"
+)
+
+// Match event details
+const (
+	MatchEventDetailSecondYellow = "second_yellow" // Red card for a second booking
+	MatchEventDetailInjury       = "injury"        // Substitution forced by an injury
+	MatchEventDetailNoSubstitute = "no_substitute" // Injured player leaving without a replacement
+)
+
+// Match periods
+const (
+	MatchPeriodFirstHalf  = "first_half"
+	MatchPeriodSecondHalf = "second_half"
+	MatchPeriodExtraTime  = "extra_time"
+)
+
+// Player positions
+const (
+	PositionGoalkeeper = "goalkeeper"
+	PositionDefender   = "defender"
+	PositionMidfielder = "midfielder"
+	PositionForward    = "forward"
+)
+
+const (
+	// LineupSize and BenchSize are the players a team starts with and the substitutes it names
+	LineupSize = 11
+	BenchSize  = 7
+	// MaxSubstitutions is the number of substitutes a team may bring on
+	MaxSubstitutions = 5
+)
+
+// benchPositions are the positions of the substitutes, slots 12-18
+var benchPositions = [BenchSize]string{
+	PositionGoalkeeper, PositionDefender, PositionDefender, PositionMidfielder, PositionMidfielder, PositionForward, PositionForward,
+}
+
+// SlotPosition returns the position of a team-sheet slot
+func SlotPosition(slot int) string {
+	if slot > LineupSize {
+		return benchPositions[(slot-LineupSize-1)%BenchSize]
+	}
+	switch {
+	case slot <= 1:
+		return PositionGoalkeeper
+	case slot <= 5:
+		return PositionDefender
+	case slot <= 8:
+		return PositionMidfielder
+	default:
+		return PositionForward
+	}
+}
+
+// IsGoal reports whether the event is a goal scored in the given part of the match: the 90
+// minutes or extra time
+func (e *MatchEvent) IsGoal(extraTime bool) bool {
+	return e.Type == MatchEventGoal && (e.Period == MatchPeriodExtraTime) == extraTime
+}
+
+// TimelineScore counts the goals of a timeline in the 90 minutes, or in extra time
+func TimelineScore(events []MatchEvent, homeTeamID uint, extraTime bool) (int, int) {
+	home, away := 0, 0
+	for i := range events {
+		if !events[i].IsGoal(extraTime) {
+			continue
+		}
+		if events[i].TeamID == homeTeamID {
+			home++
+		} else {
+			away++
+		}
+	}
+	return home, away
+}
+
+// MatchTimeline is a match with its events in order and the score at half-time
+type MatchTimeline struct {
+	Match             Match        `json:"match"`
+	HalfTimeHomeScore *int         `json:"half_time_home_score"` // nil without a timeline
+	HalfTimeAwayScore *int         `json:"half_time_away_score"`
+	Events            []MatchEvent `json:"events"`
+}
@@ -0,0 +1,22 @@
+package models
+
+// ScoreProbability is the chance of a score after 90 minutes
+type ScoreProbability struct {
+	HomeGoals   int
+	AwayGoals   int
+	Probability float64
+}
+
+// MatchOdds is the outlook of an unplayed match over 90 minutes, worked out from the league's
+// match engine. Probabilities run from 0 to 1.
+type MatchOdds struct {
+	MatchID           uint
+	HomeWin           float64
+	Draw              float64
+	AwayWin           float64
+	HomeExpectedGoals float64
+	AwayExpectedGoals float64
+	LikelyScores      []ScoreProbability // Most likely first
+	Over25            float64            // Three goals or more
+	Under25           float64
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+type Player struct {
+	ID          uint      `gorm:"primaryKey"`
+	LeagueID    uint      `gorm:"not null;default:0;index"`
+	TeamID      uint      `gorm:"not null;uniqueIndex:idx_players_team_shirt"`
+	Name        string    `gorm:"not null"`
+	Position    string    `gorm:"not null"`                                    // goalkeeper, defender, midfielder or forward
+	Rating      int       `gorm:"not null;default:50"`                         // Player strength 1-100
+	ShirtNumber int       `gorm:"not null;uniqueIndex:idx_players_team_shirt"` // 1-99, unique within the team
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// DefaultPlayerRating is the rating an empty team-sheet slot plays at
+const DefaultPlayerRating = 50
+
+// PlayerUpdate changes a player; nil fields are left unchanged
+type PlayerUpdate struct {
+	Name        *string
+	Position    *string
+	Rating      *int
+	ShirtNumber *int
+}
+
+// Apply writes the update onto the player
+func (u *PlayerUpdate) Apply(player *Player) {
+	if u.Name != nil {
+		player.Name = *u.Name
+	}
+	if u.Position != nil {
+		player.Position = *u.Position
+	}
+	if u.Rating != nil {
+		player.Rating = *u.Rating
+	}
+	if u.ShirtNumber != nil {
+		player.ShirtNumber = *u.ShirtNumber
+	}
+}
+
+// ValidPosition reports whether position is a known player position
+func ValidPosition(position string) bool {
+	return position == PositionGoalkeeper || position == PositionDefender || position == PositionMidfielder ||
+		position == PositionForward
+}
+
+// TeamSheet picks a match-day squad from the players, indexed by slot - 1: each slot gets the
+// best-rated player left in its position, or the best-rated player left in any position when
+// nobody plays there. Slots stay empty once the squad runs out.
+func TeamSheet(players []Player) []*Player {
+	sorted := append([]Player(nil), players...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Rating != sorted[j].Rating {
+			return sorted[i].Rating > sorted[j].Rating
+		}
+		return sorted[i].ShirtNumber < sorted[j].ShirtNumber
+	})
+
+	used := make([]bool, len(sorted))
+	sheet := make([]*Player, LineupSize+BenchSize)
+	take := func(slot int, match func(p *Player) bool) {
+		for i := range sorted {
+			if !used[i] && match(&sorted[i]) {
+				used[i] = true
+				sheet[slot-1] = &sorted[i]
+				return
+			}
+		}
+	}
+
+	// Fill every slot in its own position before borrowing players from other positions
+	for slot := 1; slot <= len(sheet); slot++ {
+		take(slot, func(p *Player) bool { return p.Position == SlotPosition(slot) })
+	}
+	for slot := 1; slot <= len(sheet); slot++ {
+		if sheet[slot-1] == nil {
+			take(slot, func(*Player) bool { return true })
+		}
+	}
+	return sheet
+}
+
+// PlayerStats is a player's goals and assists in the current season
+type PlayerStats struct {
+	PlayerID    uint   `json:"player_id"`
+	PlayerName  string `json:"player_name"`
+	TeamID      uint   `json:"team_id"`
+	TeamName    string `json:"team_name"`
+	Position    string `json:"position"`
+	ShirtNumber int    `json:"shirt_number"`
+	Goals       int    `json:"goals"`
+	Assists     int    `json:"assists"`
+}
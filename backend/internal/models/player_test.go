@@ -0,0 +1,65 @@
+package models
+
+import "testing"
+
+func TestTeamSheet(t *testing.T) {
+	players := []Player{
+		{ID: 1, Position: PositionGoalkeeper, Rating: 70, ShirtNumber: 1},
+		{ID: 2, Position: PositionGoalkeeper, Rating: 80, ShirtNumber: 13},
+		{ID: 3, Position: PositionForward, Rating: 60, ShirtNumber: 9},
+		{ID: 4, Position: PositionForward, Rating: 60, ShirtNumber: 7},
+		{ID: 5, Position: PositionDefender, Rating: 75, ShirtNumber: 4},
+	}
+	sheet := TeamSheet(players)
+
+	if len(sheet) != LineupSize+BenchSize {
+		t.Fatalf("Expected %d slots, got %d", LineupSize+BenchSize, len(sheet))
+	}
+	// The better goalkeeper starts and the other sits on the bench
+	if sheet[0].ID != 2 || sheet[11].ID != 1 {
+		t.Errorf("Expected goalkeepers 2 and 1 in slots 1 and 12, got %+v and %+v", sheet[0], sheet[11])
+	}
+	// Level forwards go by shirt number
+	if sheet[8].ID != 4 || sheet[9].ID != 3 {
+		t.Errorf("Expected forwards 4 and 3 in slots 9 and 10, got %+v and %+v", sheet[8], sheet[9])
+	}
+	if sheet[1].ID != 5 {
+		t.Errorf("Expected the defender in slot 2, got %+v", sheet[1])
+	}
+
+	filled := 0
+	for _, player := range sheet {
+		if player != nil {
+			filled++
+		}
+	}
+	if filled != len(players) {
+		t.Errorf("Expected %d filled slots, got %d", len(players), filled)
+	}
+}
+
+func TestTeamSheetBorrowsPositions(t *testing.T) {
+	// Midfielders fill the midfield slots first; the one left over goes in goal
+	players := []Player{
+		{ID: 1, Position: PositionMidfielder, Rating: 70, ShirtNumber: 8},
+		{ID: 2, Position: PositionMidfielder, Rating: 65, ShirtNumber: 6},
+		{ID: 3, Position: PositionMidfielder, Rating: 60, ShirtNumber: 10},
+		{ID: 4, Position: PositionMidfielder, Rating: 50, ShirtNumber: 14},
+		{ID: 5, Position: PositionMidfielder, Rating: 40, ShirtNumber: 16},
+		{ID: 6, Position: PositionMidfielder, Rating: 30, ShirtNumber: 18},
+	}
+	sheet := TeamSheet(players)
+
+	if sheet[5].ID != 1 || sheet[6].ID != 2 || sheet[7].ID != 3 {
+		t.Errorf("Expected the best three midfielders in slots 6-8, got %+v", sheet[5:8])
+	}
+	if sheet[14].ID != 4 || sheet[15].ID != 5 {
+		t.Errorf("Expected midfielders 4 and 5 on the bench in slots 15 and 16, got %+v", sheet[14:16])
+	}
+	if sheet[0] == nil || sheet[0].ID != 6 {
+		t.Errorf("Expected the last midfielder in goal, got %+v", sheet[0])
+	}
+	if sheet[1] != nil {
+		t.Errorf("Expected slot 2 to stay empty once the squad runs out, got %+v", sheet[1])
+	}
+}
@@ -0,0 +1,59 @@
+package models
+
+// TeamRating is a team's Elo rating at the end of a week, written while rating updates are on.
+// Week 0 holds the rating the season started from.
+type TeamRating struct {
+	ID       uint    `json:"id" gorm:"primaryKey"`
+	LeagueID uint    `json:"league_id" gorm:"not null;index"`
+	TeamID   uint    `json:"team_id" gorm:"not null;index"`
+	Week     int     `json:"week" gorm:"not null"`
+	Rating   float64 `json:"rating" gorm:"not null"`
+	Change   float64 `json:"change" gorm:"not null"` // Rating gained or lost during the week
+}
+
+// RatingHistory is a team's current rating with its week-by-week history
+type RatingHistory struct {
+	Team    Team         `json:"team"`
+	Rating  float64      `json:"rating"`  // Current Elo rating, derived from the power while updates are off
+	Enabled bool         `json:"enabled"` // Whether the league updates ratings after each match
+	Ratings []TeamRating `json:"ratings"`
+}
+
+// HistoricalResult is a past match result used to fit team strengths, naming teams of the league
+type HistoricalResult struct {
+	HomeTeam  string `json:"home_team"`
+	AwayTeam  string `json:"away_team"`
+	HomeScore int    `json:"home_score"`
+	AwayScore int    `json:"away_score"`
+	Neutral   bool   `json:"neutral"`
+}
+
+// RatingFit reports the team strengths fitted from historical results and how well they fit
+type RatingFit struct {
+	Matches                 int       `json:"matches"`
+	HomeAdvantage           float64   `json:"home_advantage"`            // Fitted multiplier on the home team's attack and defence
+	ConfiguredHomeAdvantage float64   `json:"configured_home_advantage"` // The league's engine parameter, for comparison
+	BaseGoals               float64   `json:"base_goals"`                // Fitted expected goals of each of two equal teams
+	LogLikelihood           float64   `json:"log_likelihood"`
+	NullLogLikelihood       float64   `json:"null_log_likelihood"` // One goal rate for every team in every match
+	Deviance                float64   `json:"deviance"`            // Against a model reproducing every score
+	DegreesOfFreedom        int       `json:"degrees_of_freedom"`
+	PseudoR2                float64   `json:"pseudo_r2"` // McFadden's 1 - LogLikelihood / NullLogLikelihood
+	Iterations              int       `json:"iterations"`
+	Converged               bool      `json:"converged"`
+	Applied                 bool      `json:"applied"` // Whether the strengths were written onto the teams
+	Teams                   []TeamFit `json:"teams"`
+}
+
+// TeamFit is a team's fitted strengths on the 1-100 scale next to the ones it had
+type TeamFit struct {
+	TeamID          uint   `json:"team_id"`
+	TeamName        string `json:"team_name"`
+	Matches         int    `json:"matches"`
+	Attack          int    `json:"attack"`
+	Defence         int    `json:"defence"`
+	Power           int    `json:"power"`
+	PreviousAttack  int    `json:"previous_attack"`
+	PreviousDefence int    `json:"previous_defence"`
+	PreviousPower   int    `json:"previous_power"`
+}
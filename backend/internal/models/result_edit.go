@@ -0,0 +1,12 @@
+package models
+
+// ResultEdit is a result entered or cleared by hand, with what it changed in the league
+type ResultEdit struct {
+	Match         Match
+	Cleared       bool // The match was put back to unplayed
+	PrePlayed     bool // The match is after the current week and counts as played before it
+	PreviousWeek  int  // Current week before the edit
+	CurrentWeek   int  // Current week after it
+	Completed     bool // The edit completed the league
+	KnockoutDrawn bool // The edit ended the league phase and drew the knockout stage
+}
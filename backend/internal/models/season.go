@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// Season is an archived season of a league, written when the season completes or is reset
+type Season struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	LeagueID       uint      `json:"league_id" gorm:"not null;index"`
+	Number         int       `json:"number" gorm:"not null"` // 1-based within the league
+	Completed      bool      `json:"completed"`              // false when archived by a reset mid-season
+	WeeksPlayed    int       `json:"weeks_played"`
+	TotalWeeks     int       `json:"total_weeks"`
+	Seed           int64     `json:"seed"`
+	ChampionTeamID *uint     `json:"champion_team_id"`
+	ChampionName   string    `json:"champion_name"`
+	ArchivedAt     time.Time `json:"archived_at"`
+
+	// Relations
+	Standings []SeasonStanding `json:"standings,omitempty" gorm:"foreignKey:SeasonID"`
+	Matches   []SeasonMatch    `json:"matches,omitempty" gorm:"foreignKey:SeasonID"`
+}
+
+// SeasonStanding is a team's final table row in an archived season, with its power at the time
+type SeasonStanding struct {
+	ID             uint   `json:"id" gorm:"primaryKey"`
+	SeasonID       uint   `json:"season_id" gorm:"not null;index"`
+	Position       int    `json:"position"`
+	TeamID         uint   `json:"team_id"`
+	TeamName       string `json:"team_name"`
+	Power          int    `json:"power"`
+	Played         int    `json:"played"`
+	Won            int    `json:"won"`
+	Drawn          int    `json:"drawn"`
+	Lost           int    `json:"lost"`
+	GoalsFor       int    `json:"goals_for"`
+	GoalsAgainst   int    `json:"goals_against"`
+	GoalDifference int    `json:"goal_difference"`
+	Points         int    `json:"points"`
+}
+
+// SeasonMatch is a played match result in an archived season
+type SeasonMatch struct {
+	ID           uint   `json:"id" gorm:"primaryKey"`
+	SeasonID     uint   `json:"season_id" gorm:"not null;index"`
+	Week         int    `json:"week"`
+	Stage        string `json:"stage" gorm:"not null;default:'league'"`
+	HomeTeamID   uint   `json:"home_team_id"`
+	AwayTeamID   uint   `json:"away_team_id"`
+	HomeTeamName string `json:"home_team_name"`
+	AwayTeamName string `json:"away_team_name"`
+	HomeScore    int    `json:"home_score"`
+	AwayScore    int    `json:"away_score"`
+	Seed         *int64 `json:"seed"`
+}
+
+// AllTimeStanding aggregates a team's results over every archived season of a league
+type AllTimeStanding struct {
+	TeamID         uint   `json:"team_id"`
+	TeamName       string `json:"team_name"`
+	Seasons        int    `json:"seasons"`
+	Titles         int    `json:"titles"`
+	Played         int    `json:"played"`
+	Won            int    `json:"won"`
+	Drawn          int    `json:"drawn"`
+	Lost           int    `json:"lost"`
+	GoalsFor       int    `json:"goals_for"`
+	GoalsAgainst   int    `json:"goals_against"`
+	GoalDifference int    `json:"goal_difference"`
+	Points         int    `json:"points"`
+}
+
+// HeadToHeadRecord summarizes every archived meeting between two teams
+type HeadToHeadRecord struct {
+	TeamAID    uint          `json:"team_a_id"`
+	TeamAName  string        `json:"team_a_name"`
+	TeamBID    uint          `json:"team_b_id"`
+	TeamBName  string        `json:"team_b_name"`
+	Played     int           `json:"played"`
+	TeamAWins  int           `json:"team_a_wins"`
+	Draws      int           `json:"draws"`
+	TeamBWins  int           `json:"team_b_wins"`
+	TeamAGoals int           `json:"team_a_goals"`
+	TeamBGoals int           `json:"team_b_goals"`
+	Matches    []SeasonMatch `json:"matches"`
+}
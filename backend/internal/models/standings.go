@@ -2,18 +2,40 @@ package models
 
 // TeamStanding represents a team's position in the league table
 type TeamStanding struct {
-	TeamID         uint   `json:"team_id"`
-	TeamName       string `json:"team_name"`
-	Played         int    `json:"played"`
-	Won            int    `json:"won"`
-	Drawn          int    `json:"drawn"`
-	Lost           int    `json:"lost"`
-	GoalsFor       int    `json:"goals_for"`
-	GoalsAgainst   int    `json:"goals_against"`
-	GoalDifference int    `json:"goal_difference"`
-	Points         int    `json:"points"`
+	TeamID         uint           `json:"team_id"`
+	TeamName       string         `json:"team_name"`
+	Played         int            `json:"played"`
+	Won            int            `json:"won"`
+	Drawn          int            `json:"drawn"`
+	Lost           int            `json:"lost"`
+	GoalsFor       int            `json:"goals_for"`
+	GoalsAgainst   int            `json:"goals_against"`
+	GoalDifference int            `json:"goal_difference"`
+	Points         int            `json:"points"`
+	Remaining      int            `json:"remaining"`  // Unplayed fixtures, differs between teams in odd-sized leagues
+	DecidedBy      TiebreakerRule `json:"decided_by"` // Rule that ranked the team above ahead of this one; empty for the leader or if level
+	Qualification  string         `json:"qualification"`
+
+	// Races for the top places, where the table decides them
+	TitleRace         *PlaceRace `json:"title_race"`
+	QualificationRace *PlaceRace `json:"qualification_race"`
+}
+
+// PlaceRace is where a team stands in the race for the top places of its table, on points alone
+type PlaceRace struct {
+	Places       int  `json:"places"`
+	Clinched     bool `json:"clinched"`      // Sure to finish in the places whatever the remaining results
+	Eliminated   bool `json:"eliminated"`    // Can't reach the places even winning every remaining match
+	PointsNeeded *int `json:"points_needed"` // More points that make sure of the places, nil if none do
+	Exact        bool `json:"exact"`         // False if the search gave up: the race may be more settled than it says
 }
 
+// Knockout zones of a single league table; teams outside them have no qualification
+const (
+	QualificationKnockout = "knockout" // Places going straight into the knockout bracket
+	QualificationPlayoff  = "playoff"  // Places going into the knockout play-off
+)
+
 // ChampionshipPrediction represents a team's probability of winning the championship
 type ChampionshipPrediction struct {
 	TeamID     uint    `json:"team_id"`
@@ -21,11 +43,46 @@ type ChampionshipPrediction struct {
 	Percentage float64 `json:"percentage"`
 }
 
+// PredictionResult holds championship predictions and the number of simulated seasons behind them
+type PredictionResult struct {
+	Iterations  int                      `json:"iterations"`
+	Predictions []ChampionshipPrediction `json:"predictions"`
+}
+
+// PositionPrediction holds a team's probability of finishing in every league position and the
+// points it is expected to finish on
+type PositionPrediction struct {
+	TeamID         uint        `json:"team_id"`
+	TeamName       string      `json:"team_name"`
+	Positions      []float64   `json:"positions"` // Percentage for each final position, first place first
+	ExpectedPoints float64     `json:"expected_points"`
+	Points         PointsBands `json:"points"`
+}
+
+// PointsBands are percentiles of a team's final points across the simulated seasons
+type PointsBands struct {
+	P5     int `json:"p5"`
+	P25    int `json:"p25"`
+	Median int `json:"median"`
+	P75    int `json:"p75"`
+	P95    int `json:"p95"`
+}
+
+// PositionPredictionResult holds every team's position predictions and the number of simulated
+// seasons behind them
+type PositionPredictionResult struct {
+	Iterations  int                  `json:"iterations"`
+	Predictions []PositionPrediction `json:"predictions"`
+}
+
 // SimulationState represents the complete state of the simulation
 type SimulationState struct {
-	LeagueState LeagueState              `json:"league_state"`
-	Standings   []TeamStanding           `json:"standings"`
-	CurrentWeek []MatchResult            `json:"current_week_results"`
-	AllMatches  map[int][]MatchResult    `json:"all_matches"`
-	Predictions []ChampionshipPrediction `json:"predictions"`
+	LeagueState          LeagueState              `json:"league_state"`
+	Standings            []TeamStanding           `json:"standings"`
+	CurrentWeek          []MatchResult            `json:"current_week_results"`
+	AllMatches           map[int][]MatchResult    `json:"all_matches"`
+	Predictions          []ChampionshipPrediction `json:"predictions"`
+	PredictionIterations int                      `json:"prediction_iterations"`
+	Live                 *LiveWeek                `json:"live"` // While a week is played live
+	Edit                 *ResultEdit              `json:"edit"` // After a result is entered or cleared by hand
 }
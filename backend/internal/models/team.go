@@ -6,18 +6,81 @@ import (
 
 type Team struct {
 	ID        uint      `gorm:"primaryKey"`
-	Name      string    `gorm:"uniqueIndex;not null"`
+	LeagueID  uint      `gorm:"not null;default:0;uniqueIndex:idx_teams_league_name"`
+	Name      string    `gorm:"not null;uniqueIndex:idx_teams_league_name"`
 	Power     int       `gorm:"not null;default:50"` // Team strength 1-100
+	Attack    int       `gorm:"not null;default:0"`  // Goal-scoring strength 1-100
+	Defence   int       `gorm:"not null;default:0"`  // Goal-preventing strength 1-100
+	Country   string    `gorm:"not null;default:''"` // Teams from the same country are kept apart in the group draw
+	Rating    float64   `gorm:"not null;default:0"`  // Elo rating while the league updates ratings, 0 to play by power
 	CreatedAt time.Time `gorm:"autoCreateTime"`
 	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	// Share of its attack and defence the team can field in the match being played while players
+	// are injured or suspended, and of its strength tiredness leaves it; 0 for full strength. Not
+	// stored.
+	AttackAvailability  float64 `gorm:"-"`
+	DefenceAvailability float64 `gorm:"-"`
+	Freshness           float64 `gorm:"-"`
 }
 
 // DefaultTeams returns the 4 seeded teams with their power ratings
 func DefaultTeams() []Team {
-	return []Team{
-		{Name: "Chelsea", Power: 85},
-		{Name: "Arsenal", Power: 80},
-		{Name: "Manchester City", Power: 90},
-		{Name: "Liverpool", Power: 82},
+	teams := []Team{
+		{Name: "Chelsea", Country: "England", Power: 85},
+		{Name: "Arsenal", Country: "England", Power: 80},
+		{Name: "Manchester City", Country: "England", Power: 90},
+		{Name: "Liverpool", Country: "England", Power: 82},
+	}
+	for i := range teams {
+		teams[i].FillStrengths()
+	}
+	return teams
+}
+
+// FillStrengths completes a team's strengths: a missing attack or defence is set to the power,
+// and a missing power to the rounded average of attack and defence
+func (t *Team) FillStrengths() {
+	if t.Power == 0 {
+		t.Power = (t.Attack + t.Defence + 1) / 2
+	}
+	if t.Attack == 0 {
+		t.Attack = t.Power
+	}
+	if t.Defence == 0 {
+		t.Defence = t.Power
+	}
+}
+
+// AttackStrength returns the team's attack, or its power when it has none
+func (t *Team) AttackStrength() int {
+	if t.Attack > 0 {
+		return t.Attack
+	}
+	return t.Power
+}
+
+// DefenceStrength returns the team's defence, or its power when it has none
+func (t *Team) DefenceStrength() int {
+	if t.Defence > 0 {
+		return t.Defence
+	}
+	return t.Power
+}
+
+// MatchStrength returns the share of its attack and defence the team plays the match at, after
+// missing players and tiredness; 1 at full strength
+func (t *Team) MatchStrength() (attack, defence float64) {
+	attack, defence = 1, 1
+	if t.AttackAvailability > 0 {
+		attack = t.AttackAvailability
+	}
+	if t.DefenceAvailability > 0 {
+		defence = t.DefenceAvailability
+	}
+	if t.Freshness > 0 {
+		attack *= t.Freshness
+		defence *= t.Freshness
 	}
+	return attack, defence
 }
@@ -59,3 +59,27 @@ func TestTeamPowerRatings(t *testing.T) {
 		t.Logf("Note: Manchester City expected to have highest power, but %s has %d", maxTeam, maxPower)
 	}
 }
+
+func TestFillStrengths(t *testing.T) {
+	tests := []struct {
+		name     string
+		team     Team
+		expected Team
+	}{
+		{"Power only", Team{Power: 70}, Team{Power: 70, Attack: 70, Defence: 70}},
+		{"Attack and defence", Team{Attack: 90, Defence: 61}, Team{Power: 76, Attack: 90, Defence: 61}},
+		{"Power and defence", Team{Power: 70, Defence: 88}, Team{Power: 70, Attack: 70, Defence: 88}},
+	}
+	for _, tt := range tests {
+		tt.team.FillStrengths()
+		if tt.team != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.expected, tt.team)
+		}
+	}
+
+	for _, team := range DefaultTeams() {
+		if team.Attack != team.Power || team.Defence != team.Power {
+			t.Errorf("Expected %s to attack and defend at its power, got %+v", team.Name, team)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package models
+
+import (
+	"strings"
+)
+
+// TiebreakerRule is one step of a league's tiebreaker chain, applied in order to teams level on points
+type TiebreakerRule string
+
+const (
+	// TiebreakerPoints is never part of a chain; it reports that two teams were separated on points
+	TiebreakerPoints TiebreakerRule = "points"
+
+	// Head-to-head rules only count the matches played between the tied teams (a mini-league)
+	TiebreakerHeadToHeadPoints         TiebreakerRule = "head_to_head_points"
+	TiebreakerHeadToHeadGoalDifference TiebreakerRule = "head_to_head_goal_difference"
+	TiebreakerHeadToHeadGoalsFor       TiebreakerRule = "head_to_head_goals_for"
+	TiebreakerHeadToHeadAwayGoals      TiebreakerRule = "head_to_head_away_goals"
+
+	// Overall rules count every played match
+	TiebreakerGoalDifference TiebreakerRule = "goal_difference"
+	TiebreakerGoalsFor       TiebreakerRule = "goals_for"
+	TiebreakerAwayGoals      TiebreakerRule = "away_goals"
+	TiebreakerWins           TiebreakerRule = "wins"
+	TiebreakerAwayWins       TiebreakerRule = "away_wins"
+
+	// TiebreakerDrawingOfLots ranks the remaining teams by a draw derived from the league seed
+	TiebreakerDrawingOfLots TiebreakerRule = "drawing_of_lots"
+)
+
+// IsHeadToHead reports whether the rule only looks at matches between the tied teams
+func (r TiebreakerRule) IsHeadToHead() bool {
+	return strings.HasPrefix(string(r), "head_to_head_")
+}
+
+// Valid reports whether the rule can be part of a tiebreaker chain
+func (r TiebreakerRule) Valid() bool {
+	switch r {
+	case TiebreakerHeadToHeadPoints, TiebreakerHeadToHeadGoalDifference, TiebreakerHeadToHeadGoalsFor,
+		TiebreakerHeadToHeadAwayGoals, TiebreakerGoalDifference, TiebreakerGoalsFor, TiebreakerAwayGoals,
+		TiebreakerWins, TiebreakerAwayWins, TiebreakerDrawingOfLots:
+		return true
+	}
+	return false
+}
+
+// Tiebreaker presets selectable per league
+const (
+	TiebreakerPresetPremierLeague  = "premier_league"
+	TiebreakerPresetUEFAGroupStage = "uefa_group_stage"
+	TiebreakerPresetCustom         = "custom"
+
+	DefaultTiebreakerPreset = TiebreakerPresetPremierLeague
+)
+
+// TiebreakerPresets maps each preset to its chain
+var TiebreakerPresets = map[string][]TiebreakerRule{
+	// Goal difference and goals scored first, then the head-to-head record
+	TiebreakerPresetPremierLeague: {
+		TiebreakerGoalDifference,
+		TiebreakerGoalsFor,
+		TiebreakerHeadToHeadPoints,
+		TiebreakerHeadToHeadAwayGoals,
+		TiebreakerDrawingOfLots,
+	},
+	// Head-to-head mini-league first, then overall goal difference, goals, away goals and wins
+	TiebreakerPresetUEFAGroupStage: {
+		TiebreakerHeadToHeadPoints,
+		TiebreakerHeadToHeadGoalDifference,
+		TiebreakerHeadToHeadGoalsFor,
+		TiebreakerHeadToHeadAwayGoals,
+		TiebreakerGoalDifference,
+		TiebreakerGoalsFor,
+		TiebreakerAwayGoals,
+		TiebreakerWins,
+		TiebreakerAwayWins,
+		TiebreakerDrawingOfLots,
+	},
+}
+
+// ParseTiebreakerRules splits a comma-separated chain as stored on the league state
+func ParseTiebreakerRules(value string) []TiebreakerRule {
+	if value == "" {
+		return nil
+	}
+	parts := strings.Split(value, ",")
+	rules := make([]TiebreakerRule, len(parts))
+	for i, part := range parts {
+		rules[i] = TiebreakerRule(strings.TrimSpace(part))
+	}
+	return rules
+}
+
+// FormatTiebreakerRules joins a chain into its stored comma-separated form
+func FormatTiebreakerRules(rules []TiebreakerRule) string {
+	parts := make([]string, len(rules))
+	for i, rule := range rules {
+		parts[i] = string(rule)
+	}
+	return strings.Join(parts, ",")
+}
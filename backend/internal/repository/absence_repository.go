@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+type AbsenceRepository interface {
+	CreateBatch(absences []models.Absence) error
+	FindAll(leagueID uint) ([]models.Absence, error)
+	DeleteByMatch(leagueID, matchID uint) error
+	DeleteAll(leagueID uint) error
+}
+
+type absenceRepository struct {
+	db *gorm.DB
+}
+
+func NewAbsenceRepository(db *gorm.DB) AbsenceRepository {
+	return &absenceRepository{db: db}
+}
+
+func (r *absenceRepository) CreateBatch(absences []models.Absence) error {
+	if len(absences) == 0 {
+		return nil
+	}
+	return r.db.Create(&absences).Error
+}
+
+// FindAll returns the league's absences in the order they happened
+func (r *absenceRepository) FindAll(leagueID uint) ([]models.Absence, error) {
+	var absences []models.Absence
+	err := r.db.Where("league_id = ?", leagueID).Order("week, match_id, id").Find(&absences).Error
+	return absences, err
+}
+
+func (r *absenceRepository) DeleteByMatch(leagueID, matchID uint) error {
+	return r.db.Where("league_id = ? AND match_id = ?", leagueID, matchID).Delete(&models.Absence{}).Error
+}
+
+func (r *absenceRepository) DeleteAll(leagueID uint) error {
+	return r.db.Where("league_id = ?", leagueID).Delete(&models.Absence{}).Error
+}
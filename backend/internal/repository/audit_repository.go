@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+type AuditRepository interface {
+	Create(entry *models.AuditEntry, delta *models.AuditDelta) error
+	FindAll(leagueID uint) ([]models.AuditEntry, error)
+	FindDelta(entryID uint) (*models.AuditDelta, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+// Create appends the entry to the log, together with the rows undo and redo put back. Undo and
+// redo entries are stored without them.
+func (r *auditRepository) Create(entry *models.AuditEntry, delta *models.AuditDelta) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		if delta == nil {
+			return nil
+		}
+		delta.EntryID = entry.ID
+		return tx.Create(delta).Error
+	})
+}
+
+// FindAll returns the league's log, oldest entry first
+func (r *auditRepository) FindAll(leagueID uint) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	err := r.db.Where("league_id = ?", leagueID).Order("id").Find(&entries).Error
+	return entries, err
+}
+
+func (r *auditRepository) FindDelta(entryID uint) (*models.AuditDelta, error) {
+	var delta models.AuditDelta
+	err := r.db.First(&delta, entryID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delta, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type GroupRepository interface {
+	CreateBatch(entries []models.GroupEntry) error
+	FindAll(leagueID uint) ([]models.GroupEntry, error)
+	DeleteAll(leagueID uint) error
+}
+
+type groupRepository struct {
+	db *gorm.DB
+}
+
+func NewGroupRepository(db *gorm.DB) GroupRepository {
+	return &groupRepository{db: db}
+}
+
+func (r *groupRepository) CreateBatch(entries []models.GroupEntry) error {
+	return r.db.Omit(clause.Associations).Create(&entries).Error
+}
+
+// FindAll returns the league's group entries ordered by group and pot
+func (r *groupRepository) FindAll(leagueID uint) ([]models.GroupEntry, error) {
+	var entries []models.GroupEntry
+	err := r.db.Preload("Team").Where("league_id = ?", leagueID).Order("group_name, pot").Find(&entries).Error
+	return entries, err
+}
+
+func (r *groupRepository) DeleteAll(leagueID uint) error {
+	return r.db.Where("league_id = ?", leagueID).Delete(&models.GroupEntry{}).Error
+}
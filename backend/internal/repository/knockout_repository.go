@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type KnockoutRepository interface {
+	CreateTies(ties []models.KnockoutTie) error
+	FindAll(leagueID uint) ([]models.KnockoutTie, error)
+	FindByID(leagueID, id uint) (*models.KnockoutTie, error)
+	Update(tie *models.KnockoutTie) error
+	DeleteAll(leagueID uint) error
+}
+
+type knockoutRepository struct {
+	db *gorm.DB
+}
+
+func NewKnockoutRepository(db *gorm.DB) KnockoutRepository {
+	return &knockoutRepository{db: db}
+}
+
+// preloadTie loads a tie's teams and its legs in order
+func preloadTie(db *gorm.DB) *gorm.DB {
+	return db.Preload("TeamA").Preload("TeamB").
+		Preload("Matches", func(db *gorm.DB) *gorm.DB {
+			return db.Order("week")
+		}).
+		Preload("Matches.HomeTeam").Preload("Matches.AwayTeam")
+}
+
+func (r *knockoutRepository) CreateTies(ties []models.KnockoutTie) error {
+	return r.db.Omit(clause.Associations).Create(&ties).Error
+}
+
+func (r *knockoutRepository) FindAll(leagueID uint) ([]models.KnockoutTie, error) {
+	var ties []models.KnockoutTie
+	err := preloadTie(r.db).Where("league_id = ?", leagueID).Order("round, slot").Find(&ties).Error
+	return ties, err
+}
+
+func (r *knockoutRepository) FindByID(leagueID, id uint) (*models.KnockoutTie, error) {
+	var tie models.KnockoutTie
+	err := preloadTie(r.db).Where("league_id = ?", leagueID).First(&tie, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tie, nil
+}
+
+func (r *knockoutRepository) Update(tie *models.KnockoutTie) error {
+	return r.db.Omit(clause.Associations).Save(tie).Error
+}
+
+func (r *knockoutRepository) DeleteAll(leagueID uint) error {
+	return r.db.Where("league_id = ?", leagueID).Delete(&models.KnockoutTie{}).Error
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+type LeagueRepository interface {
+	Create(league *models.League) error
+	FindAll() ([]models.League, error)
+	FindByID(id uint) (*models.League, error)
+	FindByName(name string) (*models.League, error)
+	GetDefault() (*models.League, error)
+	Delete(id uint) error
+}
+
+type leagueRepository struct {
+	db *gorm.DB
+}
+
+func NewLeagueRepository(db *gorm.DB) LeagueRepository {
+	return &leagueRepository{db: db}
+}
+
+func (r *leagueRepository) Create(league *models.League) error {
+	return r.db.Create(league).Error
+}
+
+func (r *leagueRepository) FindAll() ([]models.League, error) {
+	var leagues []models.League
+	err := r.db.Order("id").Find(&leagues).Error
+	return leagues, err
+}
+
+func (r *leagueRepository) FindByID(id uint) (*models.League, error) {
+	var league models.League
+	err := r.db.First(&league, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &league, nil
+}
+
+func (r *leagueRepository) FindByName(name string) (*models.League, error) {
+	var league models.League
+	err := r.db.Where("name = ?", name).First(&league).Error
+	if err != nil {
+		return nil, err
+	}
+	return &league, nil
+}
+
+// GetDefault returns the league served by the un-nested routes, creating it on first use
+func (r *leagueRepository) GetDefault() (*models.League, error) {
+	var league models.League
+	err := r.db.Where(models.League{Name: models.DefaultLeagueName}).FirstOrCreate(&league).Error
+	if err != nil {
+		return nil, err
+	}
+	return &league, nil
+}
+
+// Delete removes a league together with everything it owns
+func (r *leagueRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		entries := tx.Model(&models.AuditEntry{}).Select("id").Where("league_id = ?", id)
+		if err := tx.Where("entry_id IN (?)", entries).Delete(&models.AuditDelta{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.AuditEntry{}).Error; err != nil {
+			return err
+		}
+		seasons := tx.Model(&models.Season{}).Select("id").Where("league_id = ?", id)
+		if err := tx.Where("season_id IN (?)", seasons).Delete(&models.SeasonMatch{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("season_id IN (?)", seasons).Delete(&models.SeasonStanding{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.Season{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.MatchEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.Absence{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.Match{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.KnockoutTie{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.GroupEntry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.Player{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.TeamRating{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.Team{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", id).Delete(&models.LeagueState{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.League{}, id).Error
+	})
+}
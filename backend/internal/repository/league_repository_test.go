@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+// recordingPool is a connection that records the statements run on it instead of running them
+type recordingPool struct {
+	statements []string
+}
+
+func (p *recordingPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, nil
+}
+
+func (p *recordingPool) ExecContext(_ context.Context, query string, _ ...interface{}) (sql.Result, error) {
+	p.statements = append(p.statements, query)
+	return driverResult{}, nil
+}
+
+func (p *recordingPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+
+func (p *recordingPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (p *recordingPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &recordingTx{p}, nil
+}
+
+// recordingTx is a transaction on a recordingPool
+type recordingTx struct {
+	*recordingPool
+}
+
+func (*recordingTx) Commit() error   { return nil }
+func (*recordingTx) Rollback() error { return nil }
+
+type driverResult struct{}
+
+func (driverResult) LastInsertId() (int64, error) { return 0, nil }
+func (driverResult) RowsAffected() (int64, error) { return 0, nil }
+
+func TestLeagueDelete(t *testing.T) {
+	pool := &recordingPool{}
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{ConnPool: pool})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := NewLeagueRepository(db).Delete(3); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Every table holding the league's rows is cleared, rows referring to a team before the teams
+	deleted := make(map[string]int)
+	for i, statement := range pool.statements {
+		table := strings.Fields(strings.TrimPrefix(statement, "DELETE FROM "))[0]
+		deleted[strings.Trim(table, "`\"")] = i + 1
+	}
+	tables := []string{"audit_delta", "audit_entries", "season_matches", "season_standings", "seasons",
+		"match_events", "absences", "matches", "knockout_ties", "group_entries", "players", "team_ratings",
+		"teams", "league_states", "leagues"}
+	for _, table := range tables {
+		if deleted[table] == 0 {
+			t.Errorf("Expected %s cleared, got %v", table, pool.statements)
+		}
+	}
+	for _, table := range []string{"match_events", "absences", "matches", "knockout_ties", "group_entries", "players", "team_ratings"} {
+		if deleted[table] > deleted["teams"] {
+			t.Errorf("Expected %s cleared before the teams", table)
+		}
+	}
+}
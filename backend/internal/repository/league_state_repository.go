@@ -1,15 +1,21 @@
 package repository
 
 import (
+	"errors"
+	"math/rand"
+
 	"github.com/zahidcakici/champions-league/internal/models"
 	"gorm.io/gorm"
 )
 
+// ErrStaleState is returned when a league state is updated from a copy that has since changed
+var ErrStaleState = errors.New("league state changed since it was read")
+
 type LeagueStateRepository interface {
-	Get() (*models.LeagueState, error)
+	Get(leagueID uint) (*models.LeagueState, error)
 	Create(state *models.LeagueState) error
 	Update(state *models.LeagueState) error
-	Reset() error
+	Reset(leagueID uint) error
 }
 
 type leagueStateRepository struct {
@@ -20,23 +26,9 @@ func NewLeagueStateRepository(db *gorm.DB) LeagueStateRepository {
 	return &leagueStateRepository{db: db}
 }
 
-func (r *leagueStateRepository) Get() (*models.LeagueState, error) {
+func (r *leagueStateRepository) Get(leagueID uint) (*models.LeagueState, error) {
 	var state models.LeagueState
-	err := r.db.First(&state).Error
-	if err == gorm.ErrRecordNotFound {
-		// Create default state
-		state = models.LeagueState{
-			CurrentWeek:     0,
-			TotalWeeks:      6,
-			FixturesCreated: false,
-			Started:         false,
-			Completed:       false,
-		}
-		if createErr := r.db.Create(&state).Error; createErr != nil {
-			return nil, createErr
-		}
-		return &state, nil
-	}
+	err := r.db.Where("league_id = ?", leagueID).First(&state).Error
 	if err != nil {
 		return nil, err
 	}
@@ -47,10 +39,30 @@ func (r *leagueStateRepository) Create(state *models.LeagueState) error {
 	return r.db.Create(state).Error
 }
 
+// Update saves the state if it is still at the version it was read at, moving it to the next
+// version; otherwise it returns ErrStaleState and saves nothing
 func (r *leagueStateRepository) Update(state *models.LeagueState) error {
-	return r.db.Save(state).Error
+	version := state.Version
+	state.Version++
+	result := r.db.Model(state).Where("version = ?", version).Select("*").Updates(state)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrStaleState
+	}
+	if result.Error != nil {
+		state.Version = version
+	}
+	return result.Error
 }
 
-func (r *leagueStateRepository) Reset() error {
-	return r.db.Exec("DELETE FROM league_states").Error
+// Reset clears the league's progress and draws a new seed, keeping its configuration
+func (r *leagueStateRepository) Reset(leagueID uint) error {
+	return r.db.Model(&models.LeagueState{}).Where("league_id = ?", leagueID).Updates(map[string]interface{}{
+		"current_week":     0,
+		"total_weeks":      6,
+		"fixtures_created": false,
+		"started":          false,
+		"completed":        false,
+		"seed":             rand.Int63n(models.MaxSeed),
+		"version":          gorm.Expr("version + 1"),
+	}).Error
 }
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+type MatchEventRepository interface {
+	CreateBatch(events []models.MatchEvent) error
+	FindByMatch(leagueID, matchID uint) ([]models.MatchEvent, error)
+	FindByMatches(leagueID uint, matchIDs []uint) ([]models.MatchEvent, error)
+	FindByType(leagueID uint, eventType string) ([]models.MatchEvent, error)
+	DeleteByMatch(leagueID, matchID uint) error
+	DeleteAll(leagueID uint) error
+}
+
+type matchEventRepository struct {
+	db *gorm.DB
+}
+
+func NewMatchEventRepository(db *gorm.DB) MatchEventRepository {
+	return &matchEventRepository{db: db}
+}
+
+func (r *matchEventRepository) CreateBatch(events []models.MatchEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.Create(&events).Error
+}
+
+// FindByMatch returns a match's events in the order they happened
+func (r *matchEventRepository) FindByMatch(leagueID, matchID uint) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	err := r.db.Where("league_id = ? AND match_id = ?", leagueID, matchID).Order("sequence").Find(&events).Error
+	return events, err
+}
+
+// FindByMatches returns the events of several matches, each match's in the order they happened
+func (r *matchEventRepository) FindByMatches(leagueID uint, matchIDs []uint) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	if len(matchIDs) == 0 {
+		return events, nil
+	}
+	err := r.db.Where("league_id = ? AND match_id IN ?", leagueID, matchIDs).Order("match_id, sequence").Find(&events).Error
+	return events, err
+}
+
+func (r *matchEventRepository) FindByType(leagueID uint, eventType string) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	err := r.db.Where("league_id = ? AND type = ?", leagueID, eventType).Order("match_id, sequence").Find(&events).Error
+	return events, err
+}
+
+func (r *matchEventRepository) DeleteByMatch(leagueID, matchID uint) error {
+	return r.db.Where("league_id = ? AND match_id = ?", leagueID, matchID).Delete(&models.MatchEvent{}).Error
+}
+
+func (r *matchEventRepository) DeleteAll(leagueID uint) error {
+	return r.db.Where("league_id = ?", leagueID).Delete(&models.MatchEvent{}).Error
+}
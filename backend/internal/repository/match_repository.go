@@ -8,13 +8,13 @@ import (
 type MatchRepository interface {
 	Create(match *models.Match) error
 	CreateBatch(matches []models.Match) error
-	FindAll() ([]models.Match, error)
-	FindByID(id uint) (*models.Match, error)
-	FindByWeek(week int) ([]models.Match, error)
-	FindPlayedMatches() ([]models.Match, error)
+	FindAll(leagueID uint) ([]models.Match, error)
+	FindByID(leagueID, id uint) (*models.Match, error)
+	FindByWeek(leagueID uint, week int) ([]models.Match, error)
+	FindPlayedMatches(leagueID uint) ([]models.Match, error)
 	Update(match *models.Match) error
-	DeleteAll() error
-	GetMaxWeek() (int, error)
+	DeleteAll(leagueID uint) error
+	GetMaxWeek(leagueID uint) (int, error)
 }
 
 type matchRepository struct {
@@ -33,30 +33,30 @@ func (r *matchRepository) CreateBatch(matches []models.Match) error {
 	return r.db.Create(&matches).Error
 }
 
-func (r *matchRepository) FindAll() ([]models.Match, error) {
+func (r *matchRepository) FindAll(leagueID uint) ([]models.Match, error) {
 	var matches []models.Match
-	err := r.db.Preload("HomeTeam").Preload("AwayTeam").Order("week, id").Find(&matches).Error
+	err := r.db.Preload("HomeTeam").Preload("AwayTeam").Where("league_id = ?", leagueID).Order("week, id").Find(&matches).Error
 	return matches, err
 }
 
-func (r *matchRepository) FindByID(id uint) (*models.Match, error) {
+func (r *matchRepository) FindByID(leagueID, id uint) (*models.Match, error) {
 	var match models.Match
-	err := r.db.Preload("HomeTeam").Preload("AwayTeam").First(&match, id).Error
+	err := r.db.Preload("HomeTeam").Preload("AwayTeam").Where("league_id = ?", leagueID).First(&match, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &match, nil
 }
 
-func (r *matchRepository) FindByWeek(week int) ([]models.Match, error) {
+func (r *matchRepository) FindByWeek(leagueID uint, week int) ([]models.Match, error) {
 	var matches []models.Match
-	err := r.db.Preload("HomeTeam").Preload("AwayTeam").Where("week = ?", week).Order("id").Find(&matches).Error
+	err := r.db.Preload("HomeTeam").Preload("AwayTeam").Where("league_id = ? AND week = ?", leagueID, week).Order("id").Find(&matches).Error
 	return matches, err
 }
 
-func (r *matchRepository) FindPlayedMatches() ([]models.Match, error) {
+func (r *matchRepository) FindPlayedMatches(leagueID uint) ([]models.Match, error) {
 	var matches []models.Match
-	err := r.db.Preload("HomeTeam").Preload("AwayTeam").Where("played = ?", true).Order("week, id").Find(&matches).Error
+	err := r.db.Preload("HomeTeam").Preload("AwayTeam").Where("league_id = ? AND played = ?", leagueID, true).Order("week, id").Find(&matches).Error
 	return matches, err
 }
 
@@ -64,12 +64,12 @@ func (r *matchRepository) Update(match *models.Match) error {
 	return r.db.Save(match).Error
 }
 
-func (r *matchRepository) DeleteAll() error {
-	return r.db.Exec("DELETE FROM matches").Error
+func (r *matchRepository) DeleteAll(leagueID uint) error {
+	return r.db.Where("league_id = ?", leagueID).Delete(&models.Match{}).Error
 }
 
-func (r *matchRepository) GetMaxWeek() (int, error) {
+func (r *matchRepository) GetMaxWeek(leagueID uint) (int, error) {
 	var maxWeek int
-	err := r.db.Model(&models.Match{}).Select("COALESCE(MAX(week), 0)").Scan(&maxWeek).Error
+	err := r.db.Model(&models.Match{}).Where("league_id = ?", leagueID).Select("COALESCE(MAX(week), 0)").Scan(&maxWeek).Error
 	return maxWeek, err
 }
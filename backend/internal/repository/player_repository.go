@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+type PlayerRepository interface {
+	Create(player *models.Player) error
+	FindAll(leagueID uint) ([]models.Player, error)
+	FindByTeam(leagueID, teamID uint) ([]models.Player, error)
+	FindByID(leagueID, id uint) (*models.Player, error)
+	Update(player *models.Player) error
+	Delete(leagueID, id uint) error
+}
+
+type playerRepository struct {
+	db *gorm.DB
+}
+
+func NewPlayerRepository(db *gorm.DB) PlayerRepository {
+	return &playerRepository{db: db}
+}
+
+func (r *playerRepository) Create(player *models.Player) error {
+	return r.db.Create(player).Error
+}
+
+func (r *playerRepository) FindAll(leagueID uint) ([]models.Player, error) {
+	var players []models.Player
+	err := r.db.Where("league_id = ?", leagueID).Order("team_id, shirt_number").Find(&players).Error
+	return players, err
+}
+
+// FindByTeam returns a team's squad by shirt number
+func (r *playerRepository) FindByTeam(leagueID, teamID uint) ([]models.Player, error) {
+	var players []models.Player
+	err := r.db.Where("league_id = ? AND team_id = ?", leagueID, teamID).Order("shirt_number").Find(&players).Error
+	return players, err
+}
+
+func (r *playerRepository) FindByID(leagueID, id uint) (*models.Player, error) {
+	var player models.Player
+	err := r.db.Where("league_id = ?", leagueID).First(&player, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+func (r *playerRepository) Update(player *models.Player) error {
+	return r.db.Save(player).Error
+}
+
+func (r *playerRepository) Delete(leagueID, id uint) error {
+	return r.db.Where("league_id = ?", leagueID).Delete(&models.Player{}, id).Error
+}
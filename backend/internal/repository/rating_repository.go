@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+type RatingRepository interface {
+	CreateBatch(ratings []models.TeamRating) error
+	FindByTeam(leagueID, teamID uint) ([]models.TeamRating, error)
+	DeleteAll(leagueID uint) error
+}
+
+type ratingRepository struct {
+	db *gorm.DB
+}
+
+func NewRatingRepository(db *gorm.DB) RatingRepository {
+	return &ratingRepository{db: db}
+}
+
+func (r *ratingRepository) CreateBatch(ratings []models.TeamRating) error {
+	if len(ratings) == 0 {
+		return nil
+	}
+	return r.db.Create(&ratings).Error
+}
+
+// FindByTeam returns a team's rating history ordered by week
+func (r *ratingRepository) FindByTeam(leagueID, teamID uint) ([]models.TeamRating, error) {
+	var ratings []models.TeamRating
+	err := r.db.Where("league_id = ? AND team_id = ?", leagueID, teamID).Order("week").Find(&ratings).Error
+	return ratings, err
+}
+
+func (r *ratingRepository) DeleteAll(leagueID uint) error {
+	return r.db.Where("league_id = ?", leagueID).Delete(&models.TeamRating{}).Error
+}
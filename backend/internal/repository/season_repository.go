@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+type SeasonRepository interface {
+	Create(season *models.Season) error
+	Replace(season *models.Season) error
+	FindAll(leagueID uint) ([]models.Season, error)
+	FindByID(leagueID, id uint) (*models.Season, error)
+	FindMatches(seasonID uint) ([]models.SeasonMatch, error)
+	FindAllStandings(leagueID uint) ([]models.SeasonStanding, error)
+	FindMatchesBetween(leagueID, teamAID, teamBID uint) ([]models.SeasonMatch, error)
+	Count(leagueID uint) (int64, error)
+}
+
+type seasonRepository struct {
+	db *gorm.DB
+}
+
+func NewSeasonRepository(db *gorm.DB) SeasonRepository {
+	return &seasonRepository{db: db}
+}
+
+// Create stores the season together with its standings and matches
+func (r *seasonRepository) Create(season *models.Season) error {
+	return r.db.Create(season).Error
+}
+
+// Replace rewrites an archived season, replacing its standings and matches with the season's
+func (r *seasonRepository) Replace(season *models.Season) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("season_id = ?", season.ID).Delete(&models.SeasonStanding{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("season_id = ?", season.ID).Delete(&models.SeasonMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Save(season).Error
+	})
+}
+
+func (r *seasonRepository) FindAll(leagueID uint) ([]models.Season, error) {
+	var seasons []models.Season
+	err := r.db.Where("league_id = ?", leagueID).Order("number").Find(&seasons).Error
+	return seasons, err
+}
+
+func (r *seasonRepository) FindByID(leagueID, id uint) (*models.Season, error) {
+	var season models.Season
+	err := r.db.Preload("Standings", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position")
+	}).Where("league_id = ?", leagueID).First(&season, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &season, nil
+}
+
+func (r *seasonRepository) FindMatches(seasonID uint) ([]models.SeasonMatch, error) {
+	var matches []models.SeasonMatch
+	err := r.db.Where("season_id = ?", seasonID).Order("week, id").Find(&matches).Error
+	return matches, err
+}
+
+func (r *seasonRepository) FindAllStandings(leagueID uint) ([]models.SeasonStanding, error) {
+	var standings []models.SeasonStanding
+	err := r.db.Joins("JOIN seasons ON seasons.id = season_standings.season_id").
+		Where("seasons.league_id = ?", leagueID).
+		Order("season_standings.season_id, season_standings.position").
+		Find(&standings).Error
+	return standings, err
+}
+
+func (r *seasonRepository) FindMatchesBetween(leagueID, teamAID, teamBID uint) ([]models.SeasonMatch, error) {
+	var matches []models.SeasonMatch
+	err := r.db.Joins("JOIN seasons ON seasons.id = season_matches.season_id").
+		Where("seasons.league_id = ?", leagueID).
+		Where("(season_matches.home_team_id = ? AND season_matches.away_team_id = ?) OR (season_matches.home_team_id = ? AND season_matches.away_team_id = ?)",
+			teamAID, teamBID, teamBID, teamAID).
+		Order("season_matches.season_id, season_matches.week, season_matches.id").
+		Find(&matches).Error
+	return matches, err
+}
+
+func (r *seasonRepository) Count(leagueID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Season{}).Where("league_id = ?", leagueID).Count(&count).Error
+	return count, err
+}
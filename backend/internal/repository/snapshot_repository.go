@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"reflect"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SnapshotRepository reads what a league owns at once, and writes back the rows that changed
+type SnapshotRepository interface {
+	Take(leagueID uint, tables models.SnapshotTables) (*models.LeagueSnapshot, error)
+	Apply(leagueID uint, from, to *models.LeagueSnapshot) error
+}
+
+type snapshotRepository struct {
+	db *gorm.DB
+}
+
+func NewSnapshotRepository(db *gorm.DB) SnapshotRepository {
+	return &snapshotRepository{db: db}
+}
+
+// Take reads the league's state and the given tables in one transaction
+func (r *snapshotRepository) Take(leagueID uint, tables models.SnapshotTables) (*models.LeagueSnapshot, error) {
+	var snapshot models.LeagueSnapshot
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var states []models.LeagueState
+		if err := tx.Where("league_id = ?", leagueID).Limit(1).Find(&states).Error; err != nil {
+			return err
+		}
+		if len(states) > 0 {
+			snapshot.State = &states[0]
+		}
+
+		owned := []struct {
+			table models.SnapshotTables
+			rows  interface{}
+		}{
+			{models.SnapshotTeams, &snapshot.Teams},
+			{models.SnapshotPlayers, &snapshot.Players},
+			{models.SnapshotMatches, &snapshot.Matches},
+			{models.SnapshotMatchEvents, &snapshot.MatchEvents},
+			{models.SnapshotAbsences, &snapshot.Absences},
+			{models.SnapshotTies, &snapshot.Ties},
+			{models.SnapshotGroups, &snapshot.Groups},
+			{models.SnapshotRatings, &snapshot.Ratings},
+			{models.SnapshotSeasons, &snapshot.Seasons},
+		}
+		for _, owned := range owned {
+			if tables&owned.table == 0 {
+				continue
+			}
+			if err := tx.Where("league_id = ?", leagueID).Order("id").Find(owned.rows).Error; err != nil {
+				return err
+			}
+		}
+		if tables&models.SnapshotSeasons == 0 {
+			return nil
+		}
+		seasons := tx.Model(&models.Season{}).Select("id").Where("league_id = ?", leagueID)
+		if err := tx.Where("season_id IN (?)", seasons).Order("id").Find(&snapshot.SeasonStandings).Error; err != nil {
+			return err
+		}
+		return tx.Where("season_id IN (?)", seasons).Order("id").Find(&snapshot.SeasonMatches).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// snapshotTables lists a snapshot's rows by table, every table after those it refers to
+func snapshotTables(snapshot *models.LeagueSnapshot) []interface{} {
+	states := []models.LeagueState{}
+	if snapshot.State != nil {
+		states = append(states, *snapshot.State)
+	}
+	return []interface{}{
+		states, snapshot.Teams, snapshot.Players, snapshot.Groups, snapshot.Ties, snapshot.Matches,
+		snapshot.MatchEvents, snapshot.Absences, snapshot.Ratings, snapshot.Seasons, snapshot.SeasonStandings,
+		snapshot.SeasonMatches,
+	}
+}
+
+// Apply turns the league's rows in from into those in to, keeping every row's ID: rows only in
+// from are deleted, rows only in to inserted and the others updated. Rows in neither are left
+// alone. The league state moves on to a new version so that requests that read it before fail
+// to save.
+func (r *snapshotRepository) Apply(leagueID uint, from, to *models.LeagueSnapshot) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var version int
+		if err := tx.Model(&models.LeagueState{}).Where("league_id = ?", leagueID).
+			Pluck("version", &version).Error; err != nil {
+			return err
+		}
+
+		fromTables, toTables := snapshotTables(from), snapshotTables(to)
+		for i := len(fromTables) - 1; i >= 0; i-- {
+			removed, _ := splitRows(toTables[i], fromTables[i])
+			if len(removed) == 0 {
+				continue
+			}
+			model := reflect.New(reflect.TypeOf(fromTables[i]).Elem()).Interface()
+			if err := tx.Where("id IN ?", removed).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		if to.State != nil {
+			if err := writeState(tx, from.State, *to.State, version+1); err != nil {
+				return err
+			}
+		} else if err := tx.Model(&models.LeagueState{}).Where("league_id = ?", leagueID).
+			UpdateColumn("version", version+1).Error; err != nil {
+			return err
+		}
+
+		for i := 1; i < len(toTables); i++ {
+			if err := writeRows(tx, fromTables[i], toTables[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// writeState writes the league state at the given version, over the state in from if it's the same row
+func writeState(tx *gorm.DB, from *models.LeagueState, state models.LeagueState, version int) error {
+	state.Version = version
+	if from != nil && from.ID == state.ID {
+		return tx.Model(&state).Select("*").UpdateColumns(&state).Error
+	}
+
+	// Create writes a column's default in place of a zero value, which the update then puts back
+	// from the untouched copy
+	created := state
+	if err := tx.Create(&created).Error; err != nil {
+		return err
+	}
+	return tx.Model(&created).Select("*").UpdateColumns(&state).Error
+}
+
+// writeRows writes a table's rows in to, inserting those not in from and updating the others
+func writeRows(tx *gorm.DB, from, to interface{}) error {
+	_, existing := splitRows(from, to)
+	rows := reflect.ValueOf(to)
+	added := reflect.MakeSlice(rows.Type(), 0, rows.Len())
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		if !existing[uint(row.FieldByName("ID").Uint())] {
+			added = reflect.Append(added, row)
+			continue
+		}
+		update := row.Addr().Interface()
+		if err := tx.Model(update).Select("*").Omit(clause.Associations).UpdateColumns(update).Error; err != nil {
+			return err
+		}
+	}
+
+	return createAll(tx, added.Interface())
+}
+
+// createAll inserts a slice of rows as they are, without their relations
+func createAll(tx *gorm.DB, rows interface{}) error {
+	if reflect.ValueOf(rows).Len() == 0 {
+		return nil
+	}
+	return tx.Omit(clause.Associations).CreateInBatches(rows, 500).Error
+}
+
+// splitRows returns the IDs of the rows of one slice missing from the other, and the IDs of those in both
+func splitRows(rows, others interface{}) (missing []uint, both map[uint]bool) {
+	ids := func(slice interface{}) map[uint]bool {
+		value := reflect.ValueOf(slice)
+		set := make(map[uint]bool, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			set[uint(value.Index(i).FieldByName("ID").Uint())] = true
+		}
+		return set
+	}
+	kept, other := ids(rows), ids(others)
+	both = make(map[uint]bool)
+	for id := range other {
+		if kept[id] {
+			both[id] = true
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return missing, both
+}
@@ -7,13 +7,15 @@ import (
 
 type TeamRepository interface {
 	Create(team *models.Team) error
-	FindAll() ([]models.Team, error)
-	FindByID(id uint) (*models.Team, error)
-	FindByName(name string) (*models.Team, error)
-	Count() (int64, error)
-	Delete(id uint) error
-	DeleteAll() error
-	SeedDefault() error
+	FindAll(leagueID uint) ([]models.Team, error)
+	FindByID(leagueID, id uint) (*models.Team, error)
+	FindByName(leagueID uint, name string) (*models.Team, error)
+	Count(leagueID uint) (int64, error)
+	UpdateRating(leagueID, id uint, rating float64) error
+	Update(team *models.Team) error
+	Delete(leagueID, id uint) error
+	DeleteAll(leagueID uint) error
+	SeedDefault(leagueID uint) error
 }
 
 type teamRepository struct {
@@ -28,46 +30,72 @@ func (r *teamRepository) Create(team *models.Team) error {
 	return r.db.Create(team).Error
 }
 
-func (r *teamRepository) FindAll() ([]models.Team, error) {
+func (r *teamRepository) FindAll(leagueID uint) ([]models.Team, error) {
 	var teams []models.Team
-	err := r.db.Order("id").Find(&teams).Error
+	err := r.db.Where("league_id = ?", leagueID).Order("id").Find(&teams).Error
 	return teams, err
 }
 
-func (r *teamRepository) FindByID(id uint) (*models.Team, error) {
+func (r *teamRepository) FindByID(leagueID, id uint) (*models.Team, error) {
 	var team models.Team
-	err := r.db.First(&team, id).Error
+	err := r.db.Where("league_id = ?", leagueID).First(&team, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &team, nil
 }
 
-func (r *teamRepository) FindByName(name string) (*models.Team, error) {
+func (r *teamRepository) FindByName(leagueID uint, name string) (*models.Team, error) {
 	var team models.Team
-	err := r.db.Where("name = ?", name).First(&team).Error
+	err := r.db.Where("league_id = ? AND name = ?", leagueID, name).First(&team).Error
 	if err != nil {
 		return nil, err
 	}
 	return &team, nil
 }
 
-func (r *teamRepository) Count() (int64, error) {
+func (r *teamRepository) Count(leagueID uint) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Team{}).Count(&count).Error
+	err := r.db.Model(&models.Team{}).Where("league_id = ?", leagueID).Count(&count).Error
 	return count, err
 }
 
-func (r *teamRepository) Delete(id uint) error {
-	return r.db.Delete(&models.Team{}, id).Error
+func (r *teamRepository) UpdateRating(leagueID, id uint, rating float64) error {
+	return r.db.Model(&models.Team{}).Where("league_id = ? AND id = ?", leagueID, id).Update("rating", rating).Error
 }
 
-func (r *teamRepository) DeleteAll() error {
-	return r.db.Exec("DELETE FROM teams").Error
+func (r *teamRepository) Update(team *models.Team) error {
+	return r.db.Save(team).Error
 }
 
-func (r *teamRepository) SeedDefault() error {
-	count, err := r.Count()
+// Delete deletes a team with its squad and their absences
+func (r *teamRepository) Delete(leagueID, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("league_id = ? AND team_id = ?", leagueID, id).Delete(&models.Absence{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ? AND team_id = ?", leagueID, id).Delete(&models.Player{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("league_id = ?", leagueID).Delete(&models.Team{}, id).Error
+	})
+}
+
+// DeleteAll deletes every team of the league with their squads and their absences
+func (r *teamRepository) DeleteAll(leagueID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("league_id = ?", leagueID).Delete(&models.Absence{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("league_id = ?", leagueID).Delete(&models.Player{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("league_id = ?", leagueID).Delete(&models.Team{}).Error
+	})
+}
+
+func (r *teamRepository) SeedDefault(leagueID uint) error {
+	count, err := r.Count(leagueID)
 	if err != nil {
 		return err
 	}
@@ -78,6 +106,7 @@ func (r *teamRepository) SeedDefault() error {
 
 	teams := models.DefaultTeams()
 	for _, team := range teams {
+		team.LeagueID = leagueID
 		if err := r.Create(&team); err != nil {
 			return err
 		}
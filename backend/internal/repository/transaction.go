@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"gorm.io/gorm"
+)
+
+// Repositories holds a repository of each kind, all working through the same connection
+type Repositories struct {
+	Leagues      LeagueRepository
+	Teams        TeamRepository
+	Matches      MatchRepository
+	LeagueStates LeagueStateRepository
+	Seasons      SeasonRepository
+	Knockout     KnockoutRepository
+	Groups       GroupRepository
+	Ratings      RatingRepository
+	MatchEvents  MatchEventRepository
+	Players      PlayerRepository
+	Absences     AbsenceRepository
+}
+
+func NewRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Leagues:      NewLeagueRepository(db),
+		Teams:        NewTeamRepository(db),
+		Matches:      NewMatchRepository(db),
+		LeagueStates: NewLeagueStateRepository(db),
+		Seasons:      NewSeasonRepository(db),
+		Knockout:     NewKnockoutRepository(db),
+		Groups:       NewGroupRepository(db),
+		Ratings:      NewRatingRepository(db),
+		MatchEvents:  NewMatchEventRepository(db),
+		Players:      NewPlayerRepository(db),
+		Absences:     NewAbsenceRepository(db),
+	}
+}
+
+// Transactor runs work that spans several repositories in one database transaction
+type Transactor interface {
+	// Transaction calls fn with repositories working in a new transaction, committed if fn
+	// returns nil and rolled back otherwise
+	Transaction(fn func(repos Repositories) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) Transaction(fn func(repos Repositories) error) error {
+	return t.db.Transaction(func(tx *gorm.DB) error {
+		return fn(NewRepositories(tx))
+	})
+}
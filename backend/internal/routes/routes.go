@@ -3,43 +3,121 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/zahidcakici/champions-league/internal/handlers"
+	"github.com/zahidcakici/champions-league/internal/models"
 )
 
 func Setup(
 	app *fiber.App,
+	leagueHandler *handlers.LeagueHandler,
 	teamHandler *handlers.TeamHandler,
 	fixtureHandler *handlers.FixtureHandler,
 	simulationHandler *handlers.SimulationHandler,
 	standingsHandler *handlers.StandingsHandler,
+	seasonHandler *handlers.SeasonHandler,
+	knockoutHandler *handlers.KnockoutHandler,
+	playerHandler *handlers.PlayerHandler,
+	eventHandler *handlers.EventHandler,
+	auditHandler *handlers.AuditHandler,
 ) {
 	api := app.Group("/api")
 
+	// League routes
+	leagues := api.Group("/leagues")
+	leagues.Get("/", leagueHandler.GetAllLeagues)
+	leagues.Post("/", leagueHandler.CreateLeague)
+	leagues.Get("/:leagueId", leagueHandler.ResolveLeague, leagueHandler.GetLeague)
+	leagues.Delete("/:leagueId", leagueHandler.ResolveLeague, leagueHandler.DeleteLeague)
+
+	// League-scoped routes, nested under a league and aliased at /api for the default league
+	league := leagues.Group("/:leagueId")
+	setupLeagueRoutes(league, leagueHandler.ResolveLeague, teamHandler, fixtureHandler, simulationHandler, standingsHandler, seasonHandler, knockoutHandler, playerHandler, eventHandler, auditHandler)
+	setupLeagueRoutes(api, leagueHandler.ResolveDefaultLeague, teamHandler, fixtureHandler, simulationHandler, standingsHandler, seasonHandler, knockoutHandler, playerHandler, eventHandler, auditHandler)
+
+	// Health check
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"status": "ok"})
+	})
+}
+
+// setupLeagueRoutes registers every league-scoped route on router, resolving the league with resolve first
+func setupLeagueRoutes(
+	router fiber.Router,
+	resolve fiber.Handler,
+	teamHandler *handlers.TeamHandler,
+	fixtureHandler *handlers.FixtureHandler,
+	simulationHandler *handlers.SimulationHandler,
+	standingsHandler *handlers.StandingsHandler,
+	seasonHandler *handlers.SeasonHandler,
+	knockoutHandler *handlers.KnockoutHandler,
+	playerHandler *handlers.PlayerHandler,
+	eventHandler *handlers.EventHandler,
+	auditHandler *handlers.AuditHandler,
+) {
+	// Every change to the league is recorded in its audit log
+	track := auditHandler.Track
+
 	// Team routes
-	teams := api.Group("/teams")
-	teams.Get("/", teamHandler.GetAllTeams)
-	teams.Post("/", teamHandler.CreateTeam)
-	teams.Delete("/:id", teamHandler.DeleteTeam)
+	teams := router.Group("/teams")
+	teams.Get("/", resolve, teamHandler.GetAllTeams)
+	teams.Post("/", resolve, track(models.AuditCreateTeam), teamHandler.CreateTeam)
+	teams.Post("/ratings/fit", resolve, track(models.AuditFitRatings), teamHandler.FitRatings)
+	teams.Delete("/:id", resolve, track(models.AuditDeleteTeam), teamHandler.DeleteTeam)
+	teams.Get("/:id/ratings", resolve, teamHandler.GetRatingHistory)
+	teams.Get("/:id/players", resolve, playerHandler.GetSquad)
+	teams.Post("/:id/players", resolve, track(models.AuditCreatePlayer), playerHandler.CreatePlayer)
+	teams.Put("/:id/players/:playerId", resolve, track(models.AuditUpdatePlayer), playerHandler.UpdatePlayer)
+	teams.Delete("/:id/players/:playerId", resolve, track(models.AuditDeletePlayer), playerHandler.DeletePlayer)
 
 	// Fixture routes
-	fixtures := api.Group("/fixtures")
-	fixtures.Get("/", fixtureHandler.GetAllFixtures)
-	fixtures.Get("/:week", fixtureHandler.GetFixturesByWeek)
-	fixtures.Post("/generate", fixtureHandler.GenerateFixtures)
+	fixtures := router.Group("/fixtures")
+	fixtures.Get("/", resolve, fixtureHandler.GetAllFixtures)
+	fixtures.Get("/:week", resolve, fixtureHandler.GetFixturesByWeek)
+	fixtures.Get("/:week/availability", resolve, fixtureHandler.GetWeekAvailability)
+	fixtures.Get("/:id/odds", resolve, fixtureHandler.GetMatchOdds)
+	fixtures.Post("/generate", resolve, track(models.AuditGenerateFixtures), fixtureHandler.GenerateFixtures)
+	fixtures.Put("/match/:id/date", resolve, track(models.AuditRescheduleMatch), fixtureHandler.RescheduleMatch)
 
 	// Simulation routes
-	simulation := api.Group("/simulation")
-	simulation.Get("/state", simulationHandler.GetState)
-	simulation.Post("/play-week", simulationHandler.PlayNextWeek)
-	simulation.Post("/play-all", simulationHandler.PlayAllWeeks)
-	simulation.Put("/match/:id", simulationHandler.UpdateMatchResult)
-	simulation.Post("/reset", simulationHandler.ResetSimulation)
+	simulation := router.Group("/simulation")
+	simulation.Get("/state", resolve, simulationHandler.GetState)
+	simulation.Post("/play-week", resolve, track(models.AuditPlayWeek), simulationHandler.PlayNextWeek)
+	simulation.Post("/play-week/live", resolve, simulationHandler.PlayLiveWeek)
+	simulation.Post("/play-all", resolve, track(models.AuditPlayAll), simulationHandler.PlayAllWeeks)
+	simulation.Put("/match/:id", resolve, track(models.AuditUpdateResult), simulationHandler.UpdateMatchResult)
+	simulation.Delete("/match/:id", resolve, track(models.AuditClearResult), simulationHandler.ClearMatchResult)
+	simulation.Get("/match/:id/timeline", resolve, simulationHandler.GetMatchTimeline)
+	simulation.Get("/week/:week/timeline", resolve, simulationHandler.GetWeekTimelines)
+	simulation.Put("/settings", resolve, track(models.AuditUpdateSettings), simulationHandler.UpdateSettings)
+	simulation.Post("/reset", resolve, track(models.AuditReset), simulationHandler.ResetSimulation)
+	simulation.Post("/undo", resolve, auditHandler.Undo)
+	simulation.Post("/redo", resolve, auditHandler.Redo)
 
 	// Standings routes
-	api.Get("/standings", standingsHandler.GetStandings)
-	api.Get("/predictions", standingsHandler.GetPredictions)
+	router.Get("/standings", resolve, standingsHandler.GetStandings)
+	router.Get("/standings/groups", resolve, standingsHandler.GetGroupStandings)
+	router.Get("/standings/scorers", resolve, playerHandler.GetTopScorers)
+	router.Get("/standings/assists", resolve, playerHandler.GetTopAssists)
+	router.Get("/predictions", resolve, standingsHandler.GetPredictions)
+	router.Get("/predictions/positions", resolve, standingsHandler.GetPositionPredictions)
 
-	// Health check
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{"status": "ok"})
-	})
+	// Season history routes
+	seasons := router.Group("/seasons")
+	seasons.Get("/", resolve, seasonHandler.GetSeasons)
+	seasons.Get("/all-time", resolve, seasonHandler.GetAllTimeTable)
+	seasons.Get("/head-to-head", resolve, seasonHandler.GetHeadToHead)
+	seasons.Get("/:id", resolve, seasonHandler.GetSeason)
+	seasons.Get("/:id/standings", resolve, seasonHandler.GetSeasonStandings)
+	seasons.Get("/:id/matches", resolve, seasonHandler.GetSeasonMatches)
+
+	// Knockout routes
+	knockout := router.Group("/knockout")
+	knockout.Get("/", resolve, knockoutHandler.GetBracket)
+	knockout.Get("/ties/:id", resolve, knockoutHandler.GetTie)
+
+	// Audit log
+	router.Get("/audit", resolve, auditHandler.GetLog)
+
+	// Live event routes
+	router.Get("/events", resolve, eventHandler.StreamEvents)
+	router.Get("/events/ws", resolve, eventHandler.StreamEventsWebSocket)
 }
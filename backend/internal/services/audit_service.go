@@ -0,0 +1,482 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+)
+
+var (
+	ErrActionInProgress = errors.New("another action on the league, such as a week played live, is still in progress")
+	ErrNothingToUndo    = errors.New("there is no action to undo")
+	ErrNothingToRedo    = errors.New("there is no undone action to redo")
+)
+
+type AuditService interface {
+	// Track runs an action on a league and, if the league changed, appends it to the league's
+	// audit log with what it changed. An action that fails after saving part of its work is
+	// recorded too, so that undo never skips over what it saved.
+	Track(leagueID uint, action, actor string, run func()) error
+	// Begin is Track for an action that finishes after start returns, such as a live week: the
+	// action is recorded when finish is called, and until then no other action can be taken on
+	// the league. finish must be called once start has reported success; a start that fails is
+	// recorded straight away if it changed the league.
+	Begin(leagueID uint, action, actor string, start func(finish func() error) bool) error
+	// GetLog returns the league's audit log, newest first; a positive limit keeps that many entries
+	GetLog(leagueID uint, limit int) ([]models.AuditEntry, error)
+	// Undo puts the league back as it was before the latest action not undone yet
+	Undo(leagueID uint, actor string) (*models.AuditEntry, error)
+	// Redo reapplies the latest action undone, as long as no other action was taken since
+	Redo(leagueID uint, actor string) (*models.AuditEntry, error)
+	// Remove deletes a league with remove, then drops what is kept in memory about it. A league
+	// with an action in progress, such as a week played live, can't be removed.
+	Remove(leagueID uint, remove func() error) error
+}
+
+// auditTables lists the tables each action can change besides the state, which are all the
+// snapshots before and after it read. Actions left out can change any table.
+var auditTables = map[string]models.SnapshotTables{
+	models.AuditCreateTeam:       models.SnapshotTeams,
+	models.AuditDeleteTeam:       models.SnapshotTeams | models.SnapshotPlayers | models.SnapshotAbsences,
+	models.AuditFitRatings:       models.SnapshotTeams | models.SnapshotRatings,
+	models.AuditCreatePlayer:     models.SnapshotPlayers,
+	models.AuditUpdatePlayer:     models.SnapshotPlayers,
+	models.AuditDeletePlayer:     models.SnapshotPlayers,
+	models.AuditGenerateFixtures: models.SnapshotMatches | models.SnapshotGroups,
+	models.AuditRescheduleMatch:  models.SnapshotMatches,
+}
+
+// actionTables returns the tables an action can change
+func actionTables(action string) models.SnapshotTables {
+	if tables, ok := auditTables[action]; ok {
+		return tables
+	}
+	return models.SnapshotAll
+}
+
+// auditLeague serializes the audited actions on one league
+type auditLeague struct {
+	sync.Mutex
+	pending bool // An action begun with Begin hasn't finished yet
+}
+
+type auditService struct {
+	auditRepo    repository.AuditRepository
+	snapshotRepo repository.SnapshotRepository
+
+	mu      sync.Mutex
+	leagues map[uint]*auditLeague
+}
+
+func NewAuditService(auditRepo repository.AuditRepository, snapshotRepo repository.SnapshotRepository) AuditService {
+	return &auditService{
+		auditRepo:    auditRepo,
+		snapshotRepo: snapshotRepo,
+		leagues:      make(map[uint]*auditLeague),
+	}
+}
+
+// league returns the lock of a league's audited actions
+func (s *auditService) league(leagueID uint) *auditLeague {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	league, ok := s.leagues[leagueID]
+	if !ok {
+		league = &auditLeague{}
+		s.leagues[leagueID] = league
+	}
+	return league
+}
+
+func (s *auditService) Remove(leagueID uint, remove func() error) error {
+	league := s.league(leagueID)
+	league.Lock()
+	defer league.Unlock()
+
+	if league.pending {
+		return ErrActionInProgress
+	}
+	if err := remove(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.leagues, leagueID)
+	return nil
+}
+
+func (s *auditService) Track(leagueID uint, action, actor string, run func()) error {
+	league := s.league(leagueID)
+	league.Lock()
+	defer league.Unlock()
+
+	if league.pending {
+		return ErrActionInProgress
+	}
+	before, err := s.snapshotRepo.Take(leagueID, actionTables(action))
+	if err != nil {
+		return err
+	}
+	run()
+	return s.record(leagueID, action, actor, before)
+}
+
+func (s *auditService) Begin(leagueID uint, action, actor string, start func(finish func() error) bool) error {
+	league := s.league(leagueID)
+	league.Lock()
+	defer league.Unlock()
+
+	if league.pending {
+		return ErrActionInProgress
+	}
+	before, err := s.snapshotRepo.Take(leagueID, actionTables(action))
+	if err != nil {
+		return err
+	}
+
+	league.pending = true
+	finish := func() error {
+		league.Lock()
+		defer league.Unlock()
+		league.pending = false
+		return s.record(leagueID, action, actor, before)
+	}
+	if !start(finish) {
+		league.pending = false
+		return s.record(leagueID, action, actor, before)
+	}
+	return nil
+}
+
+// record appends an action to the log if the league changed since before
+func (s *auditService) record(leagueID uint, action, actor string, before *models.LeagueSnapshot) error {
+	after, err := s.snapshotRepo.Take(leagueID, actionTables(action))
+	if err != nil {
+		return err
+	}
+	changes := diffSnapshots(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	entry := &models.AuditEntry{LeagueID: leagueID, Action: action, Actor: actor, Changes: changes}
+	return s.auditRepo.Create(entry, deltaSnapshots(before, after))
+}
+
+// deltaSnapshots keeps the rows that differ between two snapshots of a league, as they were in
+// each
+func deltaSnapshots(before, after *models.LeagueSnapshot) *models.AuditDelta {
+	delta := &models.AuditDelta{}
+	if !sameRow(before.State, after.State) {
+		delta.Before.State, delta.After.State = before.State, after.State
+	}
+	delta.Before.Teams, delta.After.Teams = changedRows(before.Teams, after.Teams)
+	delta.Before.Players, delta.After.Players = changedRows(before.Players, after.Players)
+	delta.Before.Matches, delta.After.Matches = changedRows(before.Matches, after.Matches)
+	delta.Before.MatchEvents, delta.After.MatchEvents = changedRows(before.MatchEvents, after.MatchEvents)
+	delta.Before.Absences, delta.After.Absences = changedRows(before.Absences, after.Absences)
+	delta.Before.Ties, delta.After.Ties = changedRows(before.Ties, after.Ties)
+	delta.Before.Groups, delta.After.Groups = changedRows(before.Groups, after.Groups)
+	delta.Before.Ratings, delta.After.Ratings = changedRows(before.Ratings, after.Ratings)
+	delta.Before.Seasons, delta.After.Seasons = changedRows(before.Seasons, after.Seasons)
+	delta.Before.SeasonStandings, delta.After.SeasonStandings = changedRows(before.SeasonStandings, after.SeasonStandings)
+	delta.Before.SeasonMatches, delta.After.SeasonMatches = changedRows(before.SeasonMatches, after.SeasonMatches)
+	return delta
+}
+
+// changedRows returns the rows of one table that differ between before and after, matched by
+// ID: those removed or changed as they were, and those added or changed as they became
+func changedRows[T any](before, after []T) (was, now []T) {
+	byID := make(map[uint]*T, len(before))
+	for i := range before {
+		byID[rowID(&before[i])] = &before[i]
+	}
+	kept := make(map[uint]bool, len(after))
+	for i := range after {
+		id := rowID(&after[i])
+		kept[id] = true
+		row, ok := byID[id]
+		if ok && sameRow(row, &after[i]) {
+			continue
+		}
+		if ok {
+			was = append(was, *row)
+		}
+		now = append(now, after[i])
+	}
+	for i := range before {
+		if !kept[rowID(&before[i])] {
+			was = append(was, before[i])
+		}
+	}
+	return was, now
+}
+
+// rowID returns the ID field of a row
+func rowID(row any) uint {
+	return uint(reflect.ValueOf(row).Elem().FieldByName("ID").Uint())
+}
+
+// sameRow reports whether two rows encode to the same JSON
+func sameRow(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+func (s *auditService) GetLog(leagueID uint, limit int) ([]models.AuditEntry, error) {
+	entries, err := s.auditRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	undone := make(map[uint]bool)
+	for _, entry := range entries {
+		switch {
+		case entry.TargetID == nil:
+		case entry.Action == models.AuditUndo:
+			undone[*entry.TargetID] = true
+		case entry.Action == models.AuditRedo:
+			undone[*entry.TargetID] = false
+		}
+	}
+
+	log := make([]models.AuditEntry, 0, len(entries))
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		entry.Undone = undone[entry.ID]
+		log = append(log, entry)
+	}
+	if limit > 0 && len(log) > limit {
+		log = log[:limit]
+	}
+	return log, nil
+}
+
+func (s *auditService) Undo(leagueID uint, actor string) (*models.AuditEntry, error) {
+	return s.revisit(leagueID, models.AuditUndo, actor)
+}
+
+func (s *auditService) Redo(leagueID uint, actor string) (*models.AuditEntry, error) {
+	return s.revisit(leagueID, models.AuditRedo, actor)
+}
+
+// revisit undoes or redoes the action on top of the undo or redo stack, writing back the rows it
+// changed as they were before or after it
+func (s *auditService) revisit(leagueID uint, action, actor string) (*models.AuditEntry, error) {
+	league := s.league(leagueID)
+	league.Lock()
+	defer league.Unlock()
+
+	if league.pending {
+		return nil, ErrActionInProgress
+	}
+	entries, err := s.auditRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	done, undone := auditStacks(entries)
+	stack, empty := done, ErrNothingToUndo
+	if action == models.AuditRedo {
+		stack, empty = undone, ErrNothingToRedo
+	}
+	if len(stack) == 0 {
+		return nil, empty
+	}
+	target := stack[len(stack)-1]
+
+	delta, err := s.auditRepo.FindDelta(target)
+	if err != nil {
+		return nil, err
+	}
+	from, to := &delta.After, &delta.Before
+	if action == models.AuditRedo {
+		from, to = to, from
+	}
+	if err := s.snapshotRepo.Apply(leagueID, from, to); err != nil {
+		return nil, err
+	}
+	entry := &models.AuditEntry{
+		LeagueID: leagueID,
+		Action:   action,
+		Actor:    actor,
+		TargetID: &target,
+		Changes:  diffSnapshots(from, to),
+	}
+	if err := s.auditRepo.Create(entry, nil); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// auditStacks replays a league's log, oldest entry first, into the IDs of the actions that can be
+// undone and those that can be redone, the next one last. Taking a new action empties the redo
+// stack.
+func auditStacks(entries []models.AuditEntry) (done, undone []uint) {
+	for _, entry := range entries {
+		switch entry.Action {
+		case models.AuditUndo:
+			if len(done) > 0 {
+				undone = append(undone, done[len(done)-1])
+				done = done[:len(done)-1]
+			}
+		case models.AuditRedo:
+			if len(undone) > 0 {
+				done = append(done, undone[len(undone)-1])
+				undone = undone[:len(undone)-1]
+			}
+		default:
+			done = append(done, entry.ID)
+			undone = nil
+		}
+	}
+	return done, undone
+}
+
+// auditIgnoredFields are left out of audit changes: timestamps, the state's version, the
+// relations loaded with a row and team strength worked out for a single match
+var auditIgnoredFields = map[string]bool{
+	"id": true, "leagueId": true, "createdAt": true, "updatedAt": true, "version": true,
+	"homeTeam": true, "awayTeam": true, "teamA": true, "teamB": true, "team": true, "matches": true,
+	"standings": true, "attackAvailability": true, "defenceAvailability": true, "freshness": true,
+}
+
+// diffSnapshots returns the rows that differ between two snapshots of a league
+func diffSnapshots(before, after *models.LeagueSnapshot) []models.AuditChange {
+	states := func(snapshot *models.LeagueSnapshot) []models.LeagueState {
+		if snapshot.State == nil {
+			return nil
+		}
+		return []models.LeagueState{*snapshot.State}
+	}
+	kinds := []struct {
+		kind          string
+		before, after interface{}
+	}{
+		{models.AuditKindState, states(before), states(after)},
+		{models.AuditKindTeam, before.Teams, after.Teams},
+		{models.AuditKindPlayer, before.Players, after.Players},
+		{models.AuditKindMatch, before.Matches, after.Matches},
+		{models.AuditKindTie, before.Ties, after.Ties},
+		{models.AuditKindGroup, before.Groups, after.Groups},
+		{models.AuditKindSeason, before.Seasons, after.Seasons},
+	}
+
+	var changes []models.AuditChange
+	for _, kind := range kinds {
+		changes = append(changes, diffRows(kind.kind, auditRows(kind.before), auditRows(kind.after))...)
+	}
+	return changes
+}
+
+// diffRows compares rows of one kind by ID
+func diffRows(kind string, before, after map[uint]map[string]any) []models.AuditChange {
+	ids := make([]uint, 0, len(before)+len(after))
+	for id := range before {
+		ids = append(ids, id)
+	}
+	for id := range after {
+		if _, ok := before[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	var changes []models.AuditChange
+	for _, id := range ids {
+		old, had := before[id]
+		row, has := after[id]
+		if !had || !has {
+			changes = append(changes, models.AuditChange{Kind: kind, ID: id, Before: old, After: row})
+			continue
+		}
+
+		changed := models.AuditChange{Kind: kind, ID: id, Before: map[string]any{}, After: map[string]any{}}
+		for field, value := range row {
+			if !reflect.DeepEqual(old[field], value) {
+				changed.Before[field], changed.After[field] = old[field], value
+			}
+		}
+		if len(changed.After) > 0 {
+			changes = append(changes, changed)
+		}
+	}
+	return changes
+}
+
+// auditRows turns a slice of rows into their fields by ID, as they encode to JSON with nested
+// fields flattened into dotted names, every name in camelCase like the rest of the API
+func auditRows(rows interface{}) map[uint]map[string]any {
+	data, err := json.Marshal(rows)
+	if err != nil {
+		return nil
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+
+	byID := make(map[uint]map[string]any, len(decoded))
+	for _, row := range decoded {
+		fields := make(map[string]any)
+		var id uint
+		for name, value := range row {
+			name = camelCase(name)
+			if name == "id" {
+				if number, ok := value.(float64); ok {
+					id = uint(number)
+				}
+			}
+			if !auditIgnoredFields[name] {
+				flattenField(fields, name, value)
+			}
+		}
+		byID[id] = fields
+	}
+	return byID
+}
+
+// flattenField adds a field to fields, the fields of a nested object under dotted names
+func flattenField(fields map[string]any, name string, value any) {
+	nested, ok := value.(map[string]any)
+	if !ok {
+		fields[name] = value
+		return
+	}
+	for field, value := range nested {
+		flattenField(fields, name+"."+camelCase(field), value)
+	}
+}
+
+// camelCase turns a JSON name such as home_score, or a Go field name such as LeagueID, into
+// camelCase: homeScore, leagueId
+func camelCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	upper := false
+	for i, r := range runes {
+		switch {
+		case r == '_':
+			upper = true
+			continue
+		case unicode.IsUpper(r):
+			// A capital starts a word after a lower-case letter, or ends a run of capitals
+			upper = i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+		}
+		if upper && b.Len() > 0 {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteRune(unicode.ToLower(r))
+		}
+		upper = false
+	}
+	return b.String()
+}
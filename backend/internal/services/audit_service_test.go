@@ -0,0 +1,304 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+// mockAuditRepository implements repository.AuditRepository for testing
+type mockAuditRepository struct {
+	entries []models.AuditEntry
+	deltas  map[uint]models.AuditDelta
+}
+
+func (m *mockAuditRepository) Create(entry *models.AuditEntry, delta *models.AuditDelta) error {
+	entry.ID = uint(len(m.entries) + 1)
+	m.entries = append(m.entries, *entry)
+	if delta != nil {
+		delta.EntryID = entry.ID
+		m.deltas[entry.ID] = *delta
+	}
+	return nil
+}
+
+func (m *mockAuditRepository) FindAll(leagueID uint) ([]models.AuditEntry, error) {
+	var entries []models.AuditEntry
+	for _, entry := range m.entries {
+		if entry.LeagueID == leagueID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *mockAuditRepository) FindDelta(entryID uint) (*models.AuditDelta, error) {
+	delta, ok := m.deltas[entryID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &delta, nil
+}
+
+// mockSnapshotRepository implements repository.SnapshotRepository for testing, holding a single
+// league that tests change directly
+type mockSnapshotRepository struct {
+	league models.LeagueSnapshot
+	taken  []models.SnapshotTables // The tables of every snapshot taken
+}
+
+func (m *mockSnapshotRepository) Take(leagueID uint, tables models.SnapshotTables) (*models.LeagueSnapshot, error) {
+	m.taken = append(m.taken, tables)
+	var copied models.LeagueSnapshot
+	data, _ := json.Marshal(&m.league)
+	err := json.Unmarshal(data, &copied)
+	return &copied, err
+}
+
+func (m *mockSnapshotRepository) Apply(leagueID uint, from, to *models.LeagueSnapshot) error {
+	if to.State != nil {
+		state := *to.State
+		m.league.State = &state
+	} else if from.State != nil {
+		m.league.State = nil
+	}
+	m.league.Teams = applyRows(m.league.Teams, from.Teams, to.Teams)
+	m.league.Players = applyRows(m.league.Players, from.Players, to.Players)
+	m.league.Matches = applyRows(m.league.Matches, from.Matches, to.Matches)
+	return nil
+}
+
+// applyRows removes the rows of from missing from to, and writes those in to over the rows with
+// their IDs, adding the others
+func applyRows[T any](rows, from, to []T) []T {
+	removed := make(map[uint]bool)
+	for i := range from {
+		removed[rowID(&from[i])] = true
+	}
+	written := make(map[uint]T)
+	for i := range to {
+		delete(removed, rowID(&to[i]))
+		written[rowID(&to[i])] = to[i]
+	}
+
+	var applied []T
+	for i := range rows {
+		id := rowID(&rows[i])
+		if row, ok := written[id]; ok {
+			applied = append(applied, row)
+			delete(written, id)
+		} else if !removed[id] {
+			applied = append(applied, rows[i])
+		}
+	}
+	for i := range to {
+		if _, ok := written[rowID(&to[i])]; ok {
+			applied = append(applied, to[i])
+		}
+	}
+	return applied
+}
+
+func newAuditedLeague() (AuditService, *mockSnapshotRepository, *mockAuditRepository) {
+	snapshotRepo := &mockSnapshotRepository{league: models.LeagueSnapshot{
+		State: &models.LeagueState{ID: 1, LeagueID: 1, TotalWeeks: 6},
+		Teams: []models.Team{{ID: 1, LeagueID: 1, Name: "Chelsea", Power: 85}},
+	}}
+	auditRepo := &mockAuditRepository{deltas: make(map[uint]models.AuditDelta)}
+	return NewAuditService(auditRepo, snapshotRepo), snapshotRepo, auditRepo
+}
+
+func TestAuditTrack(t *testing.T) {
+	service, league, auditRepo := newAuditedLeague()
+
+	err := service.Track(1, models.AuditCreateTeam, "alice", func() {
+		league.league.Teams = append(league.league.Teams, models.Team{ID: 2, LeagueID: 1, Name: "Arsenal", Power: 80})
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(auditRepo.entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(auditRepo.entries))
+	}
+	entry := auditRepo.entries[0]
+	if entry.Action != models.AuditCreateTeam || entry.Actor != "alice" || len(entry.Changes) != 1 {
+		t.Fatalf("Expected alice's team creation with 1 change, got %+v", entry)
+	}
+	change := entry.Changes[0]
+	if change.Kind != models.AuditKindTeam || change.ID != 2 || change.Before != nil || change.After["name"] != "Arsenal" {
+		t.Errorf("Expected Arsenal added, got %+v", change)
+	}
+
+	// Only the rows the action changed are kept for undo and redo
+	delta := auditRepo.deltas[entry.ID]
+	if delta.Before.State != nil || len(delta.Before.Teams) != 0 || len(delta.After.Teams) != 1 ||
+		delta.After.Teams[0].Name != "Arsenal" {
+		t.Errorf("Expected only Arsenal stored, got %+v", delta)
+	}
+
+	// Snapshots only read the tables the action can change
+	for _, tables := range league.taken {
+		if tables != models.SnapshotTeams {
+			t.Errorf("Expected only the teams read, got %b", tables)
+		}
+	}
+
+	// Actions that change nothing are left out
+	_ = service.Track(1, models.AuditUpdateSettings, "alice", func() {})
+	if len(auditRepo.entries) != 1 {
+		t.Fatalf("Expected still 1 entry, got %d", len(auditRepo.entries))
+	}
+
+	// Updated rows only list the fields that changed
+	_ = service.Track(1, models.AuditPlayWeek, "bob", func() {
+		league.league.State.CurrentWeek = 1
+		league.league.State.Version++
+	})
+	change = auditRepo.entries[1].Changes[0]
+	if len(change.Before) != 1 || change.Before["currentWeek"] != 0.0 || change.After["currentWeek"] != 1.0 {
+		t.Errorf("Expected only the current week to change, got %+v", change)
+	}
+}
+
+func TestAuditUndoRedo(t *testing.T) {
+	service, league, _ := newAuditedLeague()
+	_ = service.Track(1, models.AuditCreateTeam, "alice", func() {
+		league.league.Teams = append(league.league.Teams, models.Team{ID: 2, LeagueID: 1, Name: "Arsenal", Power: 80})
+	})
+	_ = service.Track(1, models.AuditPlayWeek, "bob", func() {
+		league.league.State.CurrentWeek = 1
+	})
+
+	undo, err := service.Undo(1, "carol")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *undo.TargetID != 2 || undo.Actor != "carol" || league.league.State.CurrentWeek != 0 || len(league.league.Teams) != 2 {
+		t.Fatalf("Expected the week undone and the team kept, got %+v in week %d with %d teams", undo,
+			league.league.State.CurrentWeek, len(league.league.Teams))
+	}
+	if _, err := service.Undo(1, "carol"); err != nil || len(league.league.Teams) != 1 {
+		t.Fatalf("Expected the team creation undone, got %v with %d teams", err, len(league.league.Teams))
+	}
+	if _, err := service.Undo(1, "carol"); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Expected ErrNothingToUndo, got %v", err)
+	}
+
+	redo, err := service.Redo(1, "dave")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *redo.TargetID != 1 || len(league.league.Teams) != 2 || league.league.State.CurrentWeek != 0 {
+		t.Errorf("Expected only the team creation redone, got %+v", redo)
+	}
+	log, _ := service.GetLog(1, 0)
+	if len(log) != 5 || log[0].Action != models.AuditRedo || !log[3].Undone || log[4].Undone {
+		t.Errorf("Expected the week still undone in the log, got %+v", log)
+	}
+
+	// A new action can't be followed by a redo of what was undone before it
+	_ = service.Track(1, models.AuditUpdateSettings, "alice", func() {
+		league.league.State.Seed = 7
+	})
+	if _, err := service.Redo(1, "dave"); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Expected ErrNothingToRedo, got %v", err)
+	}
+	if log, _ := service.GetLog(1, 2); len(log) != 2 || log[0].Action != models.AuditUpdateSettings {
+		t.Errorf("Expected the 2 latest entries, got %+v", log)
+	}
+}
+
+func TestAuditBegin(t *testing.T) {
+	service, league, auditRepo := newAuditedLeague()
+
+	var finish func() error
+	err := service.Begin(1, models.AuditPlayLiveWeek, "alice", func(f func() error) bool {
+		finish = f
+		return true
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Nothing else can happen to the league until the action finishes
+	if err := service.Track(1, models.AuditReset, "bob", func() {}); !errors.Is(err, ErrActionInProgress) {
+		t.Errorf("Expected ErrActionInProgress, got %v", err)
+	}
+	if _, err := service.Undo(1, "bob"); !errors.Is(err, ErrActionInProgress) {
+		t.Errorf("Expected ErrActionInProgress, got %v", err)
+	}
+
+	league.league.State.CurrentWeek = 1
+	if err := finish(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].Action != models.AuditPlayLiveWeek {
+		t.Fatalf("Expected the live week recorded, got %+v", auditRepo.entries)
+	}
+	if _, err := service.Undo(1, "bob"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if league.league.State.CurrentWeek != 0 {
+		t.Errorf("Expected the league back at kick-off, got week %d", league.league.State.CurrentWeek)
+	}
+
+	// A start that fails leaves the league free
+	_ = service.Begin(1, models.AuditPlayLiveWeek, "alice", func(func() error) bool { return false })
+	if err := service.Track(1, models.AuditReset, "bob", func() {}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestAuditTrackFailedAction(t *testing.T) {
+	service, league, auditRepo := newAuditedLeague()
+
+	// An action that saves part of its work before failing is still recorded, and undone whole
+	_ = service.Track(1, models.AuditPlayAll, "alice", func() {
+		league.league.State.CurrentWeek = 2
+	})
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].Action != models.AuditPlayAll {
+		t.Fatalf("Expected the partly played weeks recorded, got %+v", auditRepo.entries)
+	}
+	if _, err := service.Undo(1, "bob"); err != nil || league.league.State.CurrentWeek != 0 {
+		t.Errorf("Expected the weeks undone, got %v in week %d", err, league.league.State.CurrentWeek)
+	}
+}
+
+func TestAuditRemove(t *testing.T) {
+	service, _, _ := newAuditedLeague()
+	_ = service.Track(1, models.AuditReset, "alice", func() {})
+
+	// A league playing a week live is kept
+	var finish func() error
+	_ = service.Begin(1, models.AuditPlayLiveWeek, "alice", func(f func() error) bool {
+		finish = f
+		return true
+	})
+	removed := false
+	remove := func() error {
+		removed = true
+		return nil
+	}
+	if err := service.Remove(1, remove); !errors.Is(err, ErrActionInProgress) || removed {
+		t.Errorf("Expected ErrActionInProgress without removing, got %v", err)
+	}
+	_ = finish()
+
+	// A failed delete keeps what is known about the league
+	if err := service.Remove(1, func() error { return errors.New("delete failed") }); err == nil {
+		t.Error("Expected the delete's error")
+	}
+	if _, ok := service.(*auditService).leagues[1]; !ok {
+		t.Error("Expected the league kept after a failed delete")
+	}
+
+	if err := service.Remove(1, remove); err != nil || !removed {
+		t.Fatalf("Expected the league removed, got %v", err)
+	}
+	if leagues := service.(*auditService).leagues; len(leagues) != 0 {
+		t.Errorf("Expected the deleted league dropped, got %v", leagues)
+	}
+}
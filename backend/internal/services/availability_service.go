@@ -0,0 +1,306 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+)
+
+const (
+	// absenceSeedPart derives the seed injury lengths are drawn from off the match seed, keeping
+	// the score and timeline draws as they were
+	absenceSeedPart = 2
+	// minAvailability is the least of its attack or defence a depleted team still plays at
+	minAvailability = 0.5
+)
+
+type AvailabilityService interface {
+	GetWeekAvailability(leagueID uint, week int) ([]models.TeamAvailability, error)
+	RecordAbsences(leagueID uint, match *models.Match, events []models.MatchEvent) error
+	ClearMatch(leagueID, matchID uint) error
+	Clear(leagueID uint) error
+}
+
+type availabilityService struct {
+	playerRepo  repository.PlayerRepository
+	absenceRepo repository.AbsenceRepository
+	eventRepo   repository.MatchEventRepository
+	matchRepo   repository.MatchRepository
+	teamRepo    repository.TeamRepository
+	leagueRepo  repository.LeagueStateRepository
+}
+
+func NewAvailabilityService(
+	playerRepo repository.PlayerRepository,
+	absenceRepo repository.AbsenceRepository,
+	eventRepo repository.MatchEventRepository,
+	matchRepo repository.MatchRepository,
+	teamRepo repository.TeamRepository,
+	leagueRepo repository.LeagueStateRepository,
+) AvailabilityService {
+	return &availabilityService{
+		playerRepo:  playerRepo,
+		absenceRepo: absenceRepo,
+		eventRepo:   eventRepo,
+		matchRepo:   matchRepo,
+		teamRepo:    teamRepo,
+		leagueRepo:  leagueRepo,
+	}
+}
+
+// GetWeekAvailability returns every team's squad for a week: the players it can pick, those
+// missing through injury or suspension and the share of its attack and defence it is left with,
+// and how tired its earlier matches leave it. Teams without a squad never miss players.
+func (s *availabilityService) GetWeekAvailability(leagueID uint, week int) ([]models.TeamAvailability, error) {
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.playerRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	absences, err := s.absenceRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.matchRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	squads := make(map[uint][]models.Player)
+	playersByID := make(map[uint]models.Player, len(players))
+	for _, player := range players {
+		squads[player.TeamID] = append(squads[player.TeamID], player)
+		playersByID[player.ID] = player
+	}
+	teamWeeks := make(map[uint][]int)
+	matchDates := make(map[uint]*time.Time)
+	for _, match := range matches {
+		teamWeeks[match.HomeTeamID] = append(teamWeeks[match.HomeTeamID], match.Week)
+		teamWeeks[match.AwayTeamID] = append(teamWeeks[match.AwayTeamID], match.Week)
+		if match.Week == week {
+			matchDates[match.HomeTeamID] = match.Date
+			matchDates[match.AwayTeamID] = match.Date
+		}
+	}
+	for _, weeks := range teamWeeks {
+		sort.Ints(weeks)
+	}
+
+	// Collect who misses the week, skipping players who have left the squad since
+	missing := make(map[uint][]models.PlayerAbsence)
+	for _, absence := range absences {
+		player, ok := playersByID[absence.PlayerID]
+		if !ok || player.TeamID != absence.TeamID {
+			continue
+		}
+		out, returnWeek := absentIn(absence, week, teamWeeks[absence.TeamID])
+		if !out {
+			continue
+		}
+		missing[absence.TeamID] = append(missing[absence.TeamID], models.PlayerAbsence{
+			Absence:    absence,
+			PlayerName: player.Name,
+			Position:   player.Position,
+			ReturnWeek: returnWeek,
+		})
+	}
+
+	report := make([]models.TeamAvailability, len(teams))
+	for i, team := range teams {
+		squad := squads[team.ID]
+		out := make(map[uint]bool)
+		for _, absence := range missing[team.ID] {
+			out[absence.PlayerID] = true
+		}
+		available := make([]models.Player, 0, len(squad))
+		for _, player := range squad {
+			if !out[player.ID] {
+				available = append(available, player)
+			}
+		}
+
+		attack, defence := squadAvailability(available, squad)
+		report[i] = models.TeamAvailability{
+			TeamID:              team.ID,
+			TeamName:            team.Name,
+			Week:                week,
+			SquadSize:           len(squad),
+			Available:           available,
+			Absences:            missing[team.ID],
+			AttackAvailability:  attack,
+			DefenceAvailability: defence,
+			MatchDate:           matchDates[team.ID],
+			Freshness:           1,
+		}
+		if report[i].Absences == nil {
+			report[i].Absences = []models.PlayerAbsence{}
+		}
+		if date := matchDates[team.ID]; date != nil {
+			fatigue, restDays := teamFatigue(&state.Fatigue, matches, team.ID, *date)
+			report[i].RestDays = restDays
+			report[i].Freshness = freshness(&state.Fatigue, fatigue)
+		}
+	}
+	return report, nil
+}
+
+// absentIn tells whether an absence keeps the player out of a week, and the first week they can
+// play again. An injury lasts a number of weeks; a suspension a number of the team's matches, so
+// its return week is 0 while the team's fixtures run out before it is served.
+func absentIn(absence models.Absence, week int, teamWeeks []int) (bool, int) {
+	if absence.Reason == models.AbsenceInjury {
+		returnWeek := absence.Week + absence.Length + 1
+		return absence.Week < week && week < returnWeek, returnWeek
+	}
+
+	later := teamWeeks[sort.SearchInts(teamWeeks, absence.Week+1):]
+	served := sort.SearchInts(later, week)
+	returnWeek := 0
+	if len(later) >= absence.Length {
+		returnWeek = later[absence.Length-1] + 1
+	}
+	return absence.Week < week && served < absence.Length, returnWeek
+}
+
+// squadAvailability compares the team sheet the available players make with the full squad's:
+// the attack by the ratings of the midfielders and forwards (slots 6-11), the defence by those
+// of the goalkeeper, defenders and midfielders (slots 1-8), to three decimals
+func squadAvailability(available, squad []models.Player) (float64, float64) {
+	full, fielded := models.TeamSheet(squad), models.TeamSheet(available)
+	share := func(first, last int) float64 {
+		total := sheetRating(full, first, last)
+		if total == 0 {
+			return 1
+		}
+		return math.Round(max(sheetRating(fielded, first, last)/total, minAvailability)*1000) / 1000
+	}
+	return share(6, 11), share(1, 8)
+}
+
+// sheetRating adds up the ratings of the players in a range of team-sheet slots
+func sheetRating(sheet []*models.Player, first, last int) float64 {
+	total := 0
+	for slot := first; slot <= last; slot++ {
+		if player := sheet[slot-1]; player != nil {
+			total += player.Rating
+		}
+	}
+	return float64(total)
+}
+
+// RecordAbsences records the injuries and suspensions a simulated match leaves its players
+// with: injured players are out for 1 to models.MaxInjuryWeeks weeks, a straight red card bans
+// the player for models.RedCardBan matches, a second booking for models.SecondYellowBan and
+// every models.YellowCardsPerBan-th yellow card of the season for models.YellowCardBan. Only
+// slots filled from the squad count.
+func (s *availabilityService) RecordAbsences(leagueID uint, match *models.Match, events []models.MatchEvent) error {
+	if match.Seed == nil {
+		return nil
+	}
+	rng := rand.New(rand.NewSource(deriveSeed(*match.Seed, absenceSeedPart)))
+
+	var absences []models.Absence
+	absence := func(event models.MatchEvent, reason, detail string, length int) {
+		absences = append(absences, models.Absence{
+			LeagueID: leagueID,
+			TeamID:   event.TeamID,
+			PlayerID: event.PlayerID,
+			MatchID:  match.ID,
+			Week:     match.Week,
+			Reason:   reason,
+			Detail:   detail,
+			Length:   length,
+		})
+	}
+
+	var booked []models.MatchEvent
+	for _, event := range events {
+		if event.PlayerID == 0 {
+			continue
+		}
+		switch event.Type {
+		case models.MatchEventInjury:
+			absence(event, models.AbsenceInjury, "", 1+rng.Intn(models.MaxInjuryWeeks))
+		case models.MatchEventRedCard:
+			if event.Detail == models.MatchEventDetailSecondYellow {
+				absence(event, models.AbsenceSuspension, models.SuspensionSecondYellow, models.SecondYellowBan)
+			} else {
+				absence(event, models.AbsenceSuspension, models.SuspensionRedCard, models.RedCardBan)
+			}
+		case models.MatchEventYellowCard:
+			booked = append(booked, event)
+		}
+	}
+
+	// Bookings count towards a ban unless they added up to a red card in the match
+	if len(booked) > 0 {
+		season, err := s.countedYellows(leagueID)
+		if err != nil {
+			return err
+		}
+		thisMatch := countYellows(events)
+		for _, event := range booked {
+			total := season[event.PlayerID]
+			before := total - thisMatch[event.PlayerID]
+			if thisMatch[event.PlayerID] > 0 && total/models.YellowCardsPerBan > before/models.YellowCardsPerBan {
+				absence(event, models.AbsenceSuspension, models.SuspensionYellowCards, models.YellowCardBan)
+				thisMatch[event.PlayerID] = 0
+			}
+		}
+	}
+	return s.absenceRepo.CreateBatch(absences)
+}
+
+// countedYellows counts every player's yellow cards this season that count towards a ban
+func (s *availabilityService) countedYellows(leagueID uint) (map[uint]int, error) {
+	yellows, err := s.eventRepo.FindByType(leagueID, models.MatchEventYellowCard)
+	if err != nil {
+		return nil, err
+	}
+	reds, err := s.eventRepo.FindByType(leagueID, models.MatchEventRedCard)
+	if err != nil {
+		return nil, err
+	}
+	return countYellows(append(yellows, reds...)), nil
+}
+
+// countYellows counts the players' yellow cards among the events, leaving out a player's
+// bookings in a match where they were sent off for a second one
+func countYellows(events []models.MatchEvent) map[uint]int {
+	type booking struct{ matchID, playerID uint }
+	sentOff := make(map[booking]bool)
+	for _, event := range events {
+		if event.Type == models.MatchEventRedCard && event.Detail == models.MatchEventDetailSecondYellow {
+			sentOff[booking{event.MatchID, event.PlayerID}] = true
+		}
+	}
+
+	counts := make(map[uint]int)
+	for _, event := range events {
+		if event.Type == models.MatchEventYellowCard && event.PlayerID != 0 && !sentOff[booking{event.MatchID, event.PlayerID}] {
+			counts[event.PlayerID]++
+		}
+	}
+	return counts
+}
+
+// ClearMatch forgets the absences a match caused, once its result is entered by hand
+func (s *availabilityService) ClearMatch(leagueID, matchID uint) error {
+	return s.absenceRepo.DeleteByMatch(leagueID, matchID)
+}
+
+// Clear forgets every absence of the season
+func (s *availabilityService) Clear(leagueID uint) error {
+	return s.absenceRepo.DeleteAll(leagueID)
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// mockAbsenceRepository implements repository.AbsenceRepository for testing
+type mockAbsenceRepository struct {
+	absences []models.Absence
+}
+
+func (m *mockAbsenceRepository) CreateBatch(absences []models.Absence) error {
+	for _, absence := range absences {
+		absence.ID = uint(len(m.absences) + 1)
+		m.absences = append(m.absences, absence)
+	}
+	return nil
+}
+
+func (m *mockAbsenceRepository) FindAll(_ uint) ([]models.Absence, error) {
+	return append([]models.Absence(nil), m.absences...), nil
+}
+
+func (m *mockAbsenceRepository) DeleteByMatch(_, matchID uint) error {
+	var kept []models.Absence
+	for _, absence := range m.absences {
+		if absence.MatchID != matchID {
+			kept = append(kept, absence)
+		}
+	}
+	m.absences = kept
+	return nil
+}
+
+func (m *mockAbsenceRepository) DeleteAll(_ uint) error {
+	m.absences = nil
+	return nil
+}
+
+func TestAbsentIn(t *testing.T) {
+	// The team has a bye in week 4
+	teamWeeks := []int{1, 2, 3, 5, 6}
+
+	tests := []struct {
+		name       string
+		absence    models.Absence
+		out        []int
+		returnWeek int
+	}{
+		{
+			name:       "injury counts weeks",
+			absence:    models.Absence{Week: 2, Reason: models.AbsenceInjury, Length: 2},
+			out:        []int{3, 4},
+			returnWeek: 5,
+		},
+		{
+			name:       "suspension counts the team's matches",
+			absence:    models.Absence{Week: 2, Reason: models.AbsenceSuspension, Length: 2},
+			out:        []int{3, 4, 5},
+			returnWeek: 6,
+		},
+		{
+			name:       "suspension outlasting the fixtures",
+			absence:    models.Absence{Week: 5, Reason: models.AbsenceSuspension, Length: 3},
+			out:        []int{6, 7},
+			returnWeek: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := make(map[int]bool)
+			for _, week := range tt.out {
+				out[week] = true
+			}
+			for week := 1; week <= 7; week++ {
+				absent, returnWeek := absentIn(tt.absence, week, teamWeeks)
+				if absent != out[week] {
+					t.Errorf("Week %d: expected absent %v, got %v", week, out[week], absent)
+				}
+				if returnWeek != tt.returnWeek {
+					t.Errorf("Expected return week %d, got %d", tt.returnWeek, returnWeek)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordAbsences(t *testing.T) {
+	eventRepo := &mockMatchEventRepository{}
+	absenceRepo := &mockAbsenceRepository{}
+	service := NewAvailabilityService(&mockPlayerRepository{}, absenceRepo, eventRepo, &mockMatchRepository{}, &mockTeamRepository{}, &mockLeagueStateRepository{})
+
+	// Player 4 was booked in four earlier matches, once alongside a sending-off that doesn't count
+	for match := uint(1); match <= 5; match++ {
+		_ = eventRepo.CreateBatch([]models.MatchEvent{{MatchID: match, Type: models.MatchEventYellowCard, TeamID: 1, PlayerID: 4}})
+	}
+	_ = eventRepo.CreateBatch([]models.MatchEvent{
+		{MatchID: 5, Type: models.MatchEventYellowCard, TeamID: 1, PlayerID: 4},
+		{MatchID: 5, Type: models.MatchEventRedCard, TeamID: 1, PlayerID: 4, Detail: models.MatchEventDetailSecondYellow},
+	})
+
+	seed := int64(7)
+	match := &models.Match{ID: 6, Week: 6, Seed: &seed}
+	events := []models.MatchEvent{
+		{MatchID: 6, Type: models.MatchEventRedCard, TeamID: 1, PlayerID: 1},
+		{MatchID: 6, Type: models.MatchEventYellowCard, TeamID: 1, PlayerID: 2},
+		{MatchID: 6, Type: models.MatchEventYellowCard, TeamID: 1, PlayerID: 2},
+		{MatchID: 6, Type: models.MatchEventRedCard, TeamID: 1, PlayerID: 2, Detail: models.MatchEventDetailSecondYellow},
+		{MatchID: 6, Type: models.MatchEventInjury, TeamID: 2, PlayerID: 3},
+		{MatchID: 6, Type: models.MatchEventYellowCard, TeamID: 1, PlayerID: 4},
+		{MatchID: 6, Type: models.MatchEventInjury, TeamID: 2}, // Empty slot
+	}
+	_ = eventRepo.CreateBatch(events)
+
+	if err := service.RecordAbsences(1, match, events); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := map[uint]struct {
+		reason, detail string
+		length         int
+	}{
+		1: {models.AbsenceSuspension, models.SuspensionRedCard, models.RedCardBan},
+		2: {models.AbsenceSuspension, models.SuspensionSecondYellow, models.SecondYellowBan},
+		4: {models.AbsenceSuspension, models.SuspensionYellowCards, models.YellowCardBan},
+	}
+	if len(absenceRepo.absences) != len(want)+1 {
+		t.Fatalf("Expected %d absences, got %+v", len(want)+1, absenceRepo.absences)
+	}
+	for _, absence := range absenceRepo.absences {
+		if absence.MatchID != 6 || absence.Week != 6 || absence.LeagueID != 1 {
+			t.Errorf("Expected an absence from match 6 in week 6, got %+v", absence)
+		}
+		if absence.PlayerID == 3 {
+			if absence.Reason != models.AbsenceInjury || absence.Length < 1 || absence.Length > models.MaxInjuryWeeks {
+				t.Errorf("Expected an injury of 1 to %d weeks, got %+v", models.MaxInjuryWeeks, absence)
+			}
+			continue
+		}
+		expected, ok := want[absence.PlayerID]
+		if !ok || absence.Reason != expected.reason || absence.Detail != expected.detail || absence.Length != expected.length {
+			t.Errorf("Player %d: expected %+v, got %+v", absence.PlayerID, expected, absence)
+		}
+	}
+
+	// The draw of injury lengths is seeded by the match
+	injury := func() int {
+		for _, absence := range absenceRepo.absences {
+			if absence.PlayerID == 3 {
+				return absence.Length
+			}
+		}
+		return 0
+	}
+	first := injury()
+	_ = service.ClearMatch(1, 6)
+	_ = service.RecordAbsences(1, match, events)
+	if again := injury(); again != first {
+		t.Errorf("Expected the same injury length, got %d and %d", first, again)
+	}
+}
+
+func TestWeekAvailability(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	playerRepo := fullSquads(2)
+	absenceRepo := &mockAbsenceRepository{}
+	matchRepo := &mockMatchRepository{matches: []models.Match{
+		{ID: 1, Week: 1, HomeTeamID: 1, AwayTeamID: 2},
+		{ID: 2, Week: 2, HomeTeamID: 2, AwayTeamID: 1},
+		{ID: 3, Week: 3, HomeTeamID: 1, AwayTeamID: 2},
+	}}
+	service := NewAvailabilityService(playerRepo, absenceRepo, &mockMatchEventRepository{}, matchRepo, teamRepo, &mockLeagueStateRepository{})
+
+	// Team 1 loses its star number 9 to a ban and its goalkeeper to an injury
+	_ = absenceRepo.CreateBatch([]models.Absence{
+		{LeagueID: 1, TeamID: 1, PlayerID: 9, MatchID: 1, Week: 1, Reason: models.AbsenceSuspension, Detail: models.SuspensionRedCard, Length: 1},
+		{LeagueID: 1, TeamID: 1, PlayerID: 1, MatchID: 1, Week: 1, Reason: models.AbsenceInjury, Length: 2},
+	})
+
+	report, err := service.GetWeekAvailability(1, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(report) != 4 {
+		t.Fatalf("Expected a report for all 4 teams, got %d", len(report))
+	}
+
+	depleted := report[0]
+	if depleted.SquadSize != 18 || len(depleted.Available) != 16 || len(depleted.Absences) != 2 {
+		t.Fatalf("Expected 16 of 18 players available, got %+v", depleted)
+	}
+	// The reserve goalkeeper is as good as the first choice, unlike the number 9's stand-in
+	if depleted.AttackAvailability >= 1 || depleted.DefenceAvailability != 1 {
+		t.Errorf("Expected only a weaker attack, got %.3f and %.3f", depleted.AttackAvailability, depleted.DefenceAvailability)
+	}
+	if depleted.Absences[0].PlayerName != "Player 1-9" || depleted.Absences[0].ReturnWeek != 3 {
+		t.Errorf("Expected the number 9 back in week 3, got %+v", depleted.Absences[0])
+	}
+
+	// A full squad plays at full strength, as does a team without one
+	for _, team := range report[1:] {
+		if team.AttackAvailability != 1 || team.DefenceAvailability != 1 || len(team.Absences) != 0 {
+			t.Errorf("Team %d: expected full strength, got %+v", team.TeamID, team)
+		}
+	}
+
+	// The ban is served in week 2, while the injury lasts into week 3
+	report, _ = service.GetWeekAvailability(1, 3)
+	if len(report[0].Absences) != 1 || report[0].Absences[0].Reason != models.AbsenceInjury {
+		t.Errorf("Expected only the injury left in week 3, got %+v", report[0].Absences)
+	}
+	if report[0].AttackAvailability != 1 {
+		t.Errorf("Expected the attack back at full strength, got %.3f", report[0].AttackAvailability)
+	}
+}
+
+func TestAbsencesWeakenTeams(t *testing.T) {
+	home := &models.Team{ID: 1, Power: 80, Attack: 80, Defence: 80}
+	away := &models.Team{ID: 2, Power: 80, Attack: 80, Defence: 80}
+	depleted := *home
+	depleted.AttackAvailability, depleted.DefenceAvailability = 0.8, 0.9
+
+	for _, engine := range []MatchEngine{defaultMatchEngine, &eloEngine{params: models.DefaultMatchEngineParams()}} {
+		fullHome, fullAway := engine.ExpectedGoals(home, away, false)
+		weakHome, weakAway := engine.ExpectedGoals(&depleted, away, false)
+		if weakHome >= fullHome || weakAway <= fullAway {
+			t.Errorf("%T: expected the depleted team to score less and concede more, got %.2f-%.2f against %.2f-%.2f",
+				engine, weakHome, weakAway, fullHome, fullAway)
+		}
+	}
+}
+
+func TestSimulationRecordsAbsences(t *testing.T) {
+	playerRepo := fullSquads(4)
+	absenceRepo := &mockAbsenceRepository{}
+	service, matchRepo, _ := newSeededLeagueWithSquads(t, 42, playerRepo, &mockMatchEventRepository{}, absenceRepo)
+	if _, err := service.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(absenceRepo.absences) == 0 {
+		t.Fatal("Expected a season to leave some players injured or suspended")
+	}
+
+	// Entering a result by hand drops the absences the simulated match caused
+	matchID := absenceRepo.absences[0].MatchID
+	if _, err := service.UpdateMatchResult(1, matchID, 1, 1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, absence := range absenceRepo.absences {
+		if absence.MatchID == matchID {
+			t.Errorf("Expected the absences of match %d cleared, got %+v", matchID, absence)
+		}
+	}
+
+	if err := service.ResetSimulation(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(absenceRepo.absences) != 0 || len(matchRepo.matches) != 0 {
+		t.Errorf("Expected a reset to clear the absences, got %+v", absenceRepo.absences)
+	}
+}
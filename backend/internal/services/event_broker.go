@@ -0,0 +1,180 @@
+package services
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+const (
+	// eventHistorySize is the number of recent events kept per league for subscribers who reconnect
+	eventHistorySize = 256
+	// subscriberBuffer is the number of events a subscriber can fall behind before it is dropped
+	subscriberBuffer = 64
+)
+
+// EventBroker pushes league events to their subscribers
+type EventBroker interface {
+	Publish(leagueID uint, eventType string, data interface{}) error
+	Subscribe(leagueID uint, lastEventID string) *EventSubscription
+	// Drop forgets a deleted league's events and closes its subscriptions
+	Drop(leagueID uint)
+}
+
+// EventSubscription is a subscriber's feed of a league's events. Events is closed once the
+// subscription is closed, or when the subscriber falls more than its buffer behind; it can then
+// subscribe again from the last event it saw.
+type EventSubscription struct {
+	Events <-chan models.LeagueEvent
+
+	broker *eventBroker
+	feed   chan models.LeagueEvent
+	league uint
+}
+
+// Close ends the subscription
+func (s *EventSubscription) Close() {
+	s.broker.unsubscribe(s)
+}
+
+// leagueEvents is the event stream of one league
+type leagueEvents struct {
+	lastID      uint64
+	history     []models.LeagueEvent // Oldest first
+	subscribers map[*EventSubscription]struct{}
+}
+
+type eventBroker struct {
+	epoch string // Starts every event ID, telling this process's events from those of earlier ones
+
+	mu      sync.Mutex
+	leagues map[uint]*leagueEvents
+}
+
+func NewEventBroker() EventBroker {
+	return &eventBroker{
+		epoch:   strconv.FormatInt(time.Now().UnixNano(), 36),
+		leagues: make(map[uint]*leagueEvents),
+	}
+}
+
+// eventID returns the ID of a league's event by its number: the broker's epoch, a dash and the number
+func (b *eventBroker) eventID(number uint64) string {
+	return b.epoch + "-" + strconv.FormatUint(number, 10)
+}
+
+// eventNumber returns the number of an event ID, and false if the ID isn't one of this broker's
+func (b *eventBroker) eventNumber(id string) (uint64, bool) {
+	number, ok := strings.CutPrefix(id, b.epoch+"-")
+	if !ok {
+		return 0, false
+	}
+	parsed, err := strconv.ParseUint(number, 10, 64)
+	return parsed, err == nil
+}
+
+// league returns the event stream of a league, starting it if it has none yet. The caller holds mu.
+func (b *eventBroker) league(leagueID uint) *leagueEvents {
+	league, ok := b.leagues[leagueID]
+	if !ok {
+		league = &leagueEvents{subscribers: make(map[*EventSubscription]struct{})}
+		b.leagues[leagueID] = league
+	}
+	return league
+}
+
+// Publish sends an event with data as its JSON payload to every subscriber of the league. A
+// subscriber whose buffer is full is dropped rather than holding up the others.
+func (b *eventBroker) Publish(leagueID uint, eventType string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	league := b.league(leagueID)
+	league.lastID++
+	event := models.LeagueEvent{ID: b.eventID(league.lastID), LeagueID: leagueID, Type: eventType, Data: payload}
+	league.history = append(league.history, event)
+	if len(league.history) > eventHistorySize {
+		league.history = league.history[len(league.history)-eventHistorySize:]
+	}
+
+	for subscriber := range league.subscribers {
+		select {
+		case subscriber.feed <- event:
+		default:
+			delete(league.subscribers, subscriber)
+			close(subscriber.feed)
+		}
+	}
+	return nil
+}
+
+// Subscribe starts a feed of the league's events. With a lastEventID the events after it are
+// replayed first; when they are no longer all kept, or the ID is from before a restart, a resync
+// event comes first instead, telling the subscriber to refetch the state.
+func (b *eventBroker) Subscribe(leagueID uint, lastEventID string) *EventSubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	league := b.league(leagueID)
+	var replay []models.LeagueEvent
+	if lastEventID != "" {
+		oldest := league.lastID + 1 - uint64(len(league.history)) // Number of the oldest event kept
+		last, ok := b.eventNumber(lastEventID)
+		if !ok || last > league.lastID || last+1 < oldest {
+			replay = []models.LeagueEvent{{
+				ID:       b.eventID(league.lastID),
+				LeagueID: leagueID,
+				Type:     models.LeagueEventResync,
+				Data:     []byte("null"),
+			}}
+		} else {
+			replay = league.history[last+1-oldest:]
+		}
+	}
+
+	feed := make(chan models.LeagueEvent, subscriberBuffer+len(replay))
+	for _, event := range replay {
+		feed <- event
+	}
+	subscription := &EventSubscription{Events: feed, broker: b, feed: feed, league: leagueID}
+	league.subscribers[subscription] = struct{}{}
+	return subscription
+}
+
+// unsubscribe drops a subscription, closing its feed unless Publish or Drop already has
+func (b *eventBroker) unsubscribe(subscription *EventSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	league, ok := b.leagues[subscription.league]
+	if !ok {
+		return
+	}
+	if _, ok := league.subscribers[subscription]; ok {
+		delete(league.subscribers, subscription)
+		close(subscription.feed)
+	}
+}
+
+func (b *eventBroker) Drop(leagueID uint) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	league, ok := b.leagues[leagueID]
+	if !ok {
+		return
+	}
+	for subscriber := range league.subscribers {
+		close(subscriber.feed)
+	}
+	delete(b.leagues, leagueID)
+}
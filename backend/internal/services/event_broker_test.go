@@ -0,0 +1,146 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// drain returns the events waiting in a subscription, and whether its feed is still open
+func drain(subscription *EventSubscription) ([]models.LeagueEvent, bool) {
+	var events []models.LeagueEvent
+	for {
+		select {
+		case event, ok := <-subscription.Events:
+			if !ok {
+				return events, false
+			}
+			events = append(events, event)
+		default:
+			return events, true
+		}
+	}
+}
+
+// eventID returns the ID of a broker's event by its number
+func eventID(broker EventBroker, number uint64) string {
+	return broker.(*eventBroker).eventID(number)
+}
+
+func TestEventBrokerPublish(t *testing.T) {
+	broker := NewEventBroker()
+	first := broker.Subscribe(1, "")
+	other := broker.Subscribe(2, "")
+
+	if err := broker.Publish(1, models.LeagueEventResults, map[string]int{"week": 1}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second := broker.Subscribe(1, "")
+	if err := broker.Publish(1, models.LeagueEventStandings, []int{1, 2}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	events, open := drain(first)
+	if !open || len(events) != 2 {
+		t.Fatalf("Expected both events, got %+v", events)
+	}
+	if events[0].ID != eventID(broker, 1) || events[0].Type != models.LeagueEventResults || string(events[0].Data) != `{"week":1}` {
+		t.Errorf("Expected the results event first, got %+v", events[0])
+	}
+	if events[1].ID != eventID(broker, 2) || string(events[1].Data) != `[1,2]` {
+		t.Errorf("Expected the standings event second, got %+v", events[1])
+	}
+
+	// Subscribers only get what is published after they join, and only for their league
+	if events, _ := drain(second); len(events) != 1 || events[0].ID != eventID(broker, 2) {
+		t.Errorf("Expected only the standings event, got %+v", events)
+	}
+	if events, _ := drain(other); len(events) != 0 {
+		t.Errorf("Expected no events for another league, got %+v", events)
+	}
+
+	second.Close()
+	second.Close()
+	if _, open := drain(second); open {
+		t.Error("Expected a closed subscription to close its feed")
+	}
+}
+
+func TestEventBrokerReplay(t *testing.T) {
+	broker := NewEventBroker()
+	for range eventHistorySize + 10 {
+		if err := broker.Publish(1, models.LeagueEventResults, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	last := uint64(eventHistorySize + 10)
+
+	// A reconnect within the history gets exactly what it missed
+	events, _ := drain(broker.Subscribe(1, eventID(broker, last-3)))
+	if len(events) != 3 || events[0].ID != eventID(broker, last-2) || events[2].ID != eventID(broker, last) {
+		t.Errorf("Expected the last three events, got %+v", events)
+	}
+	if events, _ := drain(broker.Subscribe(1, eventID(broker, last))); len(events) != 0 {
+		t.Errorf("Expected nothing missed, got %+v", events)
+	}
+	if events, _ := drain(broker.Subscribe(1, eventID(broker, 11))); len(events) != eventHistorySize-1 {
+		t.Errorf("Expected %d events replayed from the oldest kept, got %d", eventHistorySize-1, len(events))
+	}
+
+	// Events no longer kept, IDs past the last event and IDs from before a restart call for a resync
+	restarted := NewEventBroker()
+	for range 20 {
+		_ = restarted.Publish(1, models.LeagueEventResults, nil)
+	}
+	for _, lastID := range []string{eventID(broker, 9), eventID(broker, last+5), eventID(restarted, 12), "12", "garbage"} {
+		events, _ := drain(broker.Subscribe(1, lastID))
+		if len(events) != 1 || events[0].Type != models.LeagueEventResync || events[0].ID != eventID(broker, last) {
+			t.Errorf("Last event %s: expected a resync at %d, got %+v", lastID, last, events)
+		}
+	}
+	if events, _ := drain(broker.Subscribe(2, eventID(broker, 4))); len(events) != 1 || events[0].Type != models.LeagueEventResync || events[0].ID != eventID(broker, 0) {
+		t.Errorf("Expected a resync for a league without events, got %+v", events)
+	}
+}
+
+func TestEventBrokerSlowSubscriber(t *testing.T) {
+	broker := NewEventBroker()
+	slow := broker.Subscribe(1, "")
+	for range subscriberBuffer + 1 {
+		if err := broker.Publish(1, models.LeagueEventResults, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	events, open := drain(slow)
+	if open || len(events) != subscriberBuffer {
+		t.Fatalf("Expected the slow subscriber dropped after %d events, got %d (open %v)", subscriberBuffer, len(events), open)
+	}
+	slow.Close()
+
+	// It picks up where it left off
+	events, _ = drain(broker.Subscribe(1, events[len(events)-1].ID))
+	if len(events) != 1 || events[0].ID != eventID(broker, subscriberBuffer+1) {
+		t.Errorf("Expected the event it missed, got %+v", events)
+	}
+}
+
+func TestEventBrokerDrop(t *testing.T) {
+	broker := NewEventBroker()
+	subscription := broker.Subscribe(1, "")
+	other := broker.Subscribe(2, "")
+	_ = broker.Publish(1, models.LeagueEventResults, nil)
+
+	// A deleted league's subscribers are let go and its history is forgotten
+	broker.Drop(1)
+	if events, open := drain(subscription); open || len(events) != 1 {
+		t.Errorf("Expected the feed closed after its last event, got %+v (open %v)", events, open)
+	}
+	subscription.Close()
+	if _, open := drain(other); !open {
+		t.Error("Expected other leagues' subscriptions kept")
+	}
+	if _, ok := broker.(*eventBroker).leagues[1]; ok {
+		t.Error("Expected the league's events forgotten")
+	}
+}
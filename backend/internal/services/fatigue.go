@@ -0,0 +1,53 @@
+package services
+
+import (
+	"math"
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+const (
+	// extraTimeMinutes are added to the 90 of a match that went to extra time
+	extraTimeMinutes = 30
+	hoursPerDay      = 24
+)
+
+// teamFatigue returns the tiredness a team takes into a match on date from its earlier played
+// matches: each match's minutes over 90, halved every recovery half-life days since. It also
+// returns the days since the team's last match, 0 without one.
+func teamFatigue(params *models.FatigueParams, history []models.Match, teamID uint, date time.Time) (float64, int) {
+	halfLife := params.RecoveryHalfLife
+	if halfLife <= 0 {
+		halfLife = models.DefaultFatigueParams().RecoveryHalfLife
+	}
+
+	fatigue, restDays := 0.0, 0
+	for i := range history {
+		match := &history[i]
+		if !match.Played || match.Date == nil || !match.Date.Before(date) ||
+			match.HomeTeamID != teamID && match.AwayTeamID != teamID {
+			continue
+		}
+
+		days := date.Sub(*match.Date).Hours() / hoursPerDay
+		minutes := 90.0
+		if match.HomeExtraTimeScore != nil {
+			minutes += extraTimeMinutes
+		}
+		fatigue += minutes / 90 * math.Pow(0.5, days/halfLife)
+		if restDays == 0 || int(days) < restDays {
+			restDays = int(days)
+		}
+	}
+	return fatigue, restDays
+}
+
+// freshness returns the share of its strength a team keeps with the given tiredness, to three
+// decimals; 1 while the league doesn't model fatigue
+func freshness(params *models.FatigueParams, fatigue float64) float64 {
+	if !params.Enabled {
+		return 1
+	}
+	return math.Round((1-min(params.Penalty*fatigue, params.MaxPenalty))*1000) / 1000
+}
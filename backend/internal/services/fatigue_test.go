@@ -0,0 +1,158 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+func TestTeamFatigue(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2025, 8, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	extraTime := 1
+	history := []models.Match{
+		{HomeTeamID: 1, AwayTeamID: 2, Played: true, Date: day(1)},
+		{HomeTeamID: 3, AwayTeamID: 1, Played: true, Date: day(5), HomeExtraTimeScore: &extraTime, AwayExtraTimeScore: &extraTime},
+		{HomeTeamID: 1, AwayTeamID: 4, Played: false, Date: day(6)},
+		{HomeTeamID: 2, AwayTeamID: 3, Played: true, Date: day(7)},
+	}
+	params := models.DefaultFatigueParams()
+
+	// Six days after the first match and two after 120 minutes in the second
+	fatigue, restDays := teamFatigue(&params, history, 1, *day(7))
+	want := math.Pow(0.5, 3) + 120.0/90*math.Pow(0.5, 1)
+	if math.Abs(fatigue-want) > 1e-9 || restDays != 2 {
+		t.Errorf("Expected fatigue %.3f after 2 days of rest, got %.3f after %d", want, fatigue, restDays)
+	}
+
+	if fatigue, restDays := teamFatigue(&params, history, 4, *day(7)); fatigue != 0 || restDays != 0 {
+		t.Errorf("Expected a team without matches to be rested, got %.3f after %d days", fatigue, restDays)
+	}
+
+	if fresh := freshness(&params, fatigue); fresh != 1 {
+		t.Errorf("Expected full strength while fatigue is off, got %.3f", fresh)
+	}
+	params.Enabled = true
+	if fresh := freshness(&params, fatigue); fresh != math.Round((1-0.1*want)*1000)/1000 {
+		t.Errorf("Expected to lose a tenth of the fatigue, got %.3f", fresh)
+	}
+	if fresh := freshness(&params, 10); fresh != 1-params.MaxPenalty {
+		t.Errorf("Expected tiredness capped at %.2f, got %.3f", params.MaxPenalty, fresh)
+	}
+}
+
+func TestFixtureSchedule(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	matchRepo := &mockMatchRepository{}
+	leagueRepo := &mockLeagueStateRepository{state: &models.LeagueState{LeagueID: 1, Seed: 42, MatchdayInterval: 7}}
+	fixtures := newFixtureService(teamRepo, matchRepo, leagueRepo, &mockGroupRepository{})
+	simulation, _ := newSeededLeague(t, 42)
+
+	start := time.Date(2025, 8, 16, 0, 0, 0, 0, time.UTC)
+	interval := 3
+	state, _ := leagueRepo.Get(1)
+	if err := updateSchedule(state, &start, &interval); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	_ = leagueRepo.Update(state)
+
+	matches, err := fixtures.GenerateFixtures(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, match := range matches {
+		if want := start.AddDate(0, 0, (match.Week-1)*interval); match.Date == nil || !match.Date.Equal(want) {
+			t.Errorf("Week %d: expected %s, got %v", match.Week, want.Format(time.DateOnly), match.Date)
+		}
+	}
+
+	// The schedule is fixed once fixtures exist
+	if _, err := simulation.UpdateSettings(1, models.LeagueSettings{MatchdayInterval: &interval}); !errors.Is(err, ErrScheduleLocked) {
+		t.Error("Expected an error changing the schedule after fixtures are generated")
+	}
+
+	moved := start.AddDate(0, 0, 1)
+	match, err := fixtures.RescheduleMatch(1, matches[0].ID, moved)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !match.Date.Equal(moved) || match.Week != matches[0].Week {
+		t.Errorf("Expected the match moved to %s in its week, got %+v", moved.Format(time.DateOnly), match)
+	}
+
+	score := 1
+	matchRepo.matches[0].Played, matchRepo.matches[0].HomeScore, matchRepo.matches[0].AwayScore = true, &score, &score
+	if _, err := fixtures.RescheduleMatch(1, matches[0].ID, moved); !errors.Is(err, ErrMatchAlreadyPlayed) {
+		t.Errorf("Expected ErrMatchAlreadyPlayed, got %v", err)
+	}
+
+	// A match stays in its week: from the week's date up to the day before the next week's
+	second := matches[len(matches)-1]
+	for _, date := range []time.Time{start.AddDate(1, 0, 0), start.AddDate(0, 0, (second.Week-1)*interval-1),
+		start.AddDate(0, 0, second.Week*interval)} {
+		if _, err := fixtures.RescheduleMatch(1, second.ID, date); !errors.Is(err, ErrDateOutsideWeek) {
+			t.Errorf("%s: expected ErrDateOutsideWeek, got %v", date.Format(time.DateOnly), err)
+		}
+	}
+	if _, err := fixtures.RescheduleMatch(1, second.ID, start.AddDate(0, 0, second.Week*interval-1)); err != nil {
+		t.Errorf("Expected the last day of the week allowed, got %v", err)
+	}
+}
+
+func TestFatigueWeakensCongestedTeams(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	matchRepo := &mockMatchRepository{}
+	leagueRepo := &mockLeagueStateRepository{state: &models.LeagueState{
+		LeagueID: 1, Seed: 42, MatchdayInterval: 7, Fatigue: models.DefaultFatigueParams(),
+	}}
+	leagueRepo.state.Fatigue.Enabled = true
+	fixtures := newFixtureService(teamRepo, matchRepo, leagueRepo, &mockGroupRepository{})
+	availability := NewAvailabilityService(&mockPlayerRepository{}, &mockAbsenceRepository{}, &mockMatchEventRepository{},
+		matchRepo, teamRepo, leagueRepo)
+
+	matches, err := fixtures.GenerateFixtures(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Push one week 1 match back to two days before week 2: half of that match still in its legs
+	congested := matches[0]
+	if _, err := fixtures.RescheduleMatch(1, congested.ID, matches[0].Date.AddDate(0, 0, 5)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	score := 0
+	for i := range matchRepo.matches {
+		if matchRepo.matches[i].Week == 1 {
+			matchRepo.matches[i].Played, matchRepo.matches[i].HomeScore, matchRepo.matches[i].AwayScore = true, &score, &score
+		}
+	}
+
+	report, err := availability.GetWeekAvailability(1, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, team := range report {
+		tired := team.TeamID == congested.HomeTeamID || team.TeamID == congested.AwayTeamID
+		switch {
+		case tired && (team.RestDays != 2 || team.Freshness != 0.95):
+			t.Errorf("Team %d: expected a tired team after 2 days of rest, got %+v", team.TeamID, team)
+		case !tired && (team.RestDays != 7 || team.Freshness < 0.99 || team.Freshness >= 1):
+			t.Errorf("Team %d: expected a nearly rested team after 7 days, got %+v", team.TeamID, team)
+		}
+	}
+
+	// A tired team is played at the strength it has left
+	home := teamRepo.teams[0]
+	fieldTeam(&home, &models.TeamAvailability{Freshness: 0.9})
+	attack, defence := home.MatchStrength()
+	if attack != 0.9 || defence != 0.9 {
+		t.Errorf("Expected a tenth off both attack and defence, got %.2f and %.2f", attack, defence)
+	}
+}
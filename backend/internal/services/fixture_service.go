@@ -2,82 +2,219 @@ package services
 
 import (
 	"errors"
+	"math/rand"
+	"time"
 
 	"github.com/zahidcakici/champions-league/internal/models"
 	"github.com/zahidcakici/champions-league/internal/repository"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrMatchAlreadyPlayed = errors.New("only unplayed matches can be rescheduled")
+	ErrDateOutsideWeek    = errors.New("a match can only be moved within its week, before the next week's date")
+)
+
+// byeTeamID is the phantom team added to odd-sized leagues; whoever is drawn against it rests that week
+const byeTeamID uint = 0
+
 type FixtureService interface {
-	GenerateFixtures() ([]models.Match, error)
-	GetAllFixtures() ([]models.Match, error)
-	GetFixturesByWeek(week int) ([]models.Match, error)
+	GenerateFixtures(leagueID uint) ([]models.Match, error)
+	GetAllFixtures(leagueID uint) ([]models.Match, error)
+	GetFixturesByWeek(leagueID uint, week int) (*models.WeekFixtures, error)
+	RescheduleMatch(leagueID, matchID uint, date time.Time) (*models.Match, error)
+}
+
+// LiveWeeks tells which leagues are playing a week live
+type LiveWeeks interface {
+	GetLiveWeek(leagueID uint) *models.LiveWeek
 }
 
 type fixtureService struct {
 	teamRepo   repository.TeamRepository
 	matchRepo  repository.MatchRepository
 	leagueRepo repository.LeagueStateRepository
+	groupRepo  repository.GroupRepository
+	transactor repository.Transactor
+	live       LiveWeeks
 }
 
 func NewFixtureService(
 	teamRepo repository.TeamRepository,
 	matchRepo repository.MatchRepository,
 	leagueRepo repository.LeagueStateRepository,
+	groupRepo repository.GroupRepository,
+	transactor repository.Transactor,
+	live LiveWeeks,
 ) FixtureService {
 	return &fixtureService{
 		teamRepo:   teamRepo,
 		matchRepo:  matchRepo,
 		leagueRepo: leagueRepo,
+		groupRepo:  groupRepo,
+		transactor: transactor,
+		live:       live,
+	}
+}
+
+// transaction runs fn with a fixture service whose repositories work in one transaction. A
+// league state changed by another request in the meantime rolls it back with ErrStateChanged.
+func (s *fixtureService) transaction(fn func(tx *fixtureService) error) error {
+	err := s.transactor.Transaction(func(repos repository.Repositories) error {
+		return fn(&fixtureService{
+			teamRepo:   repos.Teams,
+			matchRepo:  repos.Matches,
+			leagueRepo: repos.LeagueStates,
+			groupRepo:  repos.Groups,
+		})
+	})
+	if errors.Is(err, repository.ErrStaleState) {
+		return ErrStateChanged
 	}
+	return err
 }
 
-func (s *fixtureService) GenerateFixtures() ([]models.Match, error) {
+// GenerateFixtures draws the groups and saves the fixtures together with the league state in one
+// transaction, so of two requests generating fixtures at once only the first is kept
+func (s *fixtureService) GenerateFixtures(leagueID uint) ([]models.Match, error) {
 	// Check if fixtures already exist
-	state, err := s.leagueRepo.Get()
+	state, err := s.leagueRepo.Get(leagueID)
 	if err != nil {
 		return nil, err
 	}
 
 	if state.FixturesCreated {
-		return s.matchRepo.FindAll()
+		return s.matchRepo.FindAll(leagueID)
 	}
 
-	// Get all teams
-	teams, err := s.teamRepo.FindAll()
+	err = s.transaction(func(tx *fixtureService) error {
+		return tx.generateFixtures(leagueID, state)
+	})
 	if err != nil {
 		return nil, err
 	}
+	return s.matchRepo.FindAll(leagueID)
+}
+
+// generateFixtures generates and saves the league's fixtures
+func (s *fixtureService) generateFixtures(leagueID uint, state *models.LeagueState) error {
+	// Get all teams
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return err
+	}
 
 	if len(teams) < 2 {
-		return nil, errors.New("need at least 2 teams to generate fixtures")
+		return errors.New("need at least 2 teams to generate fixtures")
+	}
+	if len(teams) < state.KnockoutQualifiers() {
+		return errors.New("not enough teams for the knockout stage")
 	}
 
-	// Generate round-robin fixtures (home and away)
-	matches := s.generateRoundRobin(teams)
+	// Generate round-robin fixtures (home and away), within each group for a group stage, or the
+	// Swiss league phase
+	var matches []models.Match
+	switch {
+	case state.Groups > 0:
+		matches, err = s.generateGroupStage(state, teams)
+	case state.Format == models.LeagueFormatSwiss:
+		matches, err = s.generateLeaguePhase(state, teams)
+	default:
+		matches = s.generateRoundRobin(teams)
+		for i := range matches {
+			matches[i].Stage = models.MatchStageLeague
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	// Schedule the weeks from the league's start date, today unless chosen before
+	if state.StartDate == nil {
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		state.StartDate = &today
+	}
+	for i := range matches {
+		matches[i].LeagueID = leagueID
+		matches[i].Date = state.WeekDate(matches[i].Week)
+	}
 
 	// Save matches
 	if err := s.matchRepo.CreateBatch(matches); err != nil {
-		return nil, err
+		return err
 	}
 
 	// Update league state
 	state.FixturesCreated = true
-	state.TotalWeeks = len(matches) / (len(teams) / 2)
-	if err := s.leagueRepo.Update(state); err != nil {
+	state.TotalWeeks = totalWeeks(matches) + state.KnockoutWeeks()
+	return s.leagueRepo.Update(state)
+}
+
+// generateGroupStage draws the groups and gives each group its own double round-robin; all groups
+// play on the same weeks
+func (s *fixtureService) generateGroupStage(state *models.LeagueState, teams []models.Team) ([]models.Match, error) {
+	// Week 0 of the league seed: drawn before any match is simulated
+	rng := rand.New(rand.NewSource(deriveSeed(state.Seed, 0)))
+	entries, err := drawGroups(rng, teams, state.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.groupRepo.CreateBatch(entries); err != nil {
+		return nil, err
+	}
+
+	var matches []models.Match
+	for start := 0; start < len(entries); start += models.GroupSize {
+		group := entries[start : start+models.GroupSize]
+		groupTeams := make([]models.Team, len(group))
+		for i := range group {
+			groupTeams[i] = group[i].Team
+		}
+
+		for _, match := range s.generateRoundRobin(groupTeams) {
+			match.Stage = models.MatchStageGroup
+			match.Group = group[0].Group
+			matches = append(matches, match)
+		}
+	}
+	return matches, nil
+}
+
+// generateLeaguePhase draws a Swiss-system league phase: eight weeks in which each team plays
+// two different opponents from each of four pots, one at home and one away
+func (s *fixtureService) generateLeaguePhase(state *models.LeagueState, teams []models.Team) ([]models.Match, error) {
+	// Week 0 of the league seed, like the group draw
+	rng := rand.New(rand.NewSource(deriveSeed(state.Seed, 0)))
+	matches, err := drawLeaguePhase(rng, teams)
+	if err != nil {
 		return nil, err
 	}
 
-	return s.matchRepo.FindAll()
+	for i := range matches {
+		matches[i].Stage = models.MatchStageLeague
+	}
+	return matches, nil
+}
+
+// totalWeeks returns the number of weeks a fixture list spans
+func totalWeeks(matches []models.Match) int {
+	weeks := 0
+	for _, m := range matches {
+		weeks = max(weeks, m.Week)
+	}
+	return weeks
 }
 
 // generateRoundRobin creates a round-robin schedule where each team plays every other team
 // twice (home and away). Uses the circle method for fair scheduling.
 func (s *fixtureService) generateRoundRobin(teams []models.Team) []models.Match {
-	n := len(teams)
+	// Odd leagues get a bye slot, so the circle always has an even number of places
+	n := len(teams) + len(teams)%2
 
-	// For n teams, we have n-1 rounds in single round-robin
+	// For n places, we have n-1 rounds in single round-robin
 	// For double round-robin (home and away), we have 2*(n-1) rounds
-	// Each round has n/2 matches
+	// Each round has n/2 matches, minus the one against the bye slot
 
 	// First half: each team plays every other team once
 	firstHalfMatches := s.generateSingleRoundRobin(teams)
@@ -101,17 +238,21 @@ func (s *fixtureService) generateRoundRobin(teams []models.Team) []models.Match
 	return matches
 }
 
-// generateSingleRoundRobin creates matches where each team plays every other team once
+// generateSingleRoundRobin creates matches where each team plays every other team once.
+// With an odd number of teams a phantom bye slot is added; the team paired with it rests that round.
 func (s *fixtureService) generateSingleRoundRobin(teams []models.Team) []models.Match {
-	n := len(teams)
-	var matches []models.Match
-
 	// Create a copy of team IDs for rotation
-	teamIDs := make([]uint, n)
-	for i, t := range teams {
-		teamIDs[i] = t.ID
+	teamIDs := make([]uint, 0, len(teams)+1)
+	for _, t := range teams {
+		teamIDs = append(teamIDs, t.ID)
+	}
+	if len(teamIDs)%2 == 1 {
+		teamIDs = append(teamIDs, byeTeamID)
 	}
 
+	n := len(teamIDs)
+	var matches []models.Match
+
 	// Circle method: fix first team, rotate others
 	for round := 0; round < n-1; round++ {
 		week := round + 1
@@ -120,6 +261,10 @@ func (s *fixtureService) generateSingleRoundRobin(teams []models.Team) []models.
 			home := i
 			away := n - 1 - i
 
+			if teamIDs[home] == byeTeamID || teamIDs[away] == byeTeamID {
+				continue
+			}
+
 			// Alternate home/away for fairness
 			if round%2 == 0 {
 				matches = append(matches, models.Match{
@@ -149,10 +294,93 @@ func (s *fixtureService) generateSingleRoundRobin(teams []models.Team) []models.
 	return matches
 }
 
-func (s *fixtureService) GetAllFixtures() ([]models.Match, error) {
-	return s.matchRepo.FindAll()
+func (s *fixtureService) GetAllFixtures(leagueID uint) ([]models.Match, error) {
+	return s.matchRepo.FindAll(leagueID)
+}
+
+// GetFixturesByWeek returns the week's matches together with the teams resting that week
+func (s *fixtureService) GetFixturesByWeek(leagueID uint, week int) (*models.WeekFixtures, error) {
+	matches, err := s.matchRepo.FindByWeek(leagueID, week)
+	if err != nil {
+		return nil, err
+	}
+
+	fixtures := &models.WeekFixtures{
+		Week:     week,
+		Matches:  matches,
+		ByeTeams: []models.Team{},
+	}
+	// Only league weeks have byes; teams missing from a knockout week are out or already through
+	if len(matches) == 0 || matches[0].IsKnockout() {
+		return fixtures, nil
+	}
+
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	playing := make(map[uint]bool, len(matches)*2)
+	for _, m := range matches {
+		playing[m.HomeTeamID] = true
+		playing[m.AwayTeamID] = true
+	}
+	for _, team := range teams {
+		if !playing[team.ID] {
+			fixtures.ByeTeams = append(fixtures.ByeTeams, team)
+		}
+	}
+
+	return fixtures, nil
+}
+
+// RescheduleMatch moves an unplayed match to another day in its week, from the week's date up to
+// the day before the next week's. Only the rest between matches changes, which matters once the
+// league models fatigue. The match and the league state are saved together, so a week played at
+// the same time either sees the new date or fails on the state's version; while a week is played
+// live the match can't be moved.
+func (s *fixtureService) RescheduleMatch(leagueID, matchID uint, date time.Time) (*models.Match, error) {
+	if s.live.GetLiveWeek(leagueID) != nil {
+		return nil, ErrWeekLive
+	}
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	var match *models.Match
+	err = s.transaction(func(tx *fixtureService) error {
+		match, err = tx.rescheduleMatch(leagueID, matchID, state, date)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
 }
 
-func (s *fixtureService) GetFixturesByWeek(week int) ([]models.Match, error) {
-	return s.matchRepo.FindByWeek(week)
+// rescheduleMatch moves the match and saves it with the league state
+func (s *fixtureService) rescheduleMatch(leagueID, matchID uint, state *models.LeagueState, date time.Time) (*models.Match, error) {
+	match, err := s.matchRepo.FindByID(leagueID, matchID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if match.Played {
+		return nil, ErrMatchAlreadyPlayed
+	}
+
+	day := date.UTC().Truncate(24 * time.Hour)
+	if start := state.WeekDate(match.Week); start != nil {
+		if day.Before(*start) || !day.Before(*state.WeekDate(match.Week + 1)) {
+			return nil, ErrDateOutsideWeek
+		}
+	}
+	match.Date = &day
+	if err := s.matchRepo.Update(match); err != nil {
+		return nil, err
+	}
+	return match, s.leagueRepo.Update(state)
 }
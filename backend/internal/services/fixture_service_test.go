@@ -1,11 +1,34 @@
 package services
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
 )
 
+// newFixtureService builds a fixture service whose transactions run straight on the repositories
+func newFixtureService(
+	teamRepo repository.TeamRepository,
+	matchRepo repository.MatchRepository,
+	leagueRepo repository.LeagueStateRepository,
+	groupRepo repository.GroupRepository,
+) FixtureService {
+	transactor := &mockTransactor{repos: repository.Repositories{
+		Teams: teamRepo, Matches: matchRepo, LeagueStates: leagueRepo, Groups: groupRepo,
+	}}
+	return NewFixtureService(teamRepo, matchRepo, leagueRepo, groupRepo, transactor, noLiveWeeks{})
+}
+
+// noLiveWeeks is LiveWeeks for leagues that never play a week live
+type noLiveWeeks struct{}
+
+func (noLiveWeeks) GetLiveWeek(uint) *models.LiveWeek {
+	return nil
+}
+
 func TestGenerateSingleRoundRobin(t *testing.T) {
 	service := &fixtureService{}
 
@@ -133,3 +156,160 @@ func TestMatchesSpreadAcrossWeeks(t *testing.T) {
 		}
 	}
 }
+
+func TestGenerateRoundRobinOddTeams(t *testing.T) {
+	service := &fixtureService{}
+
+	for _, n := range []int{3, 5, 7} {
+		t.Run(fmt.Sprintf("%d teams", n), func(t *testing.T) {
+			teams := make([]models.Team, n)
+			for i := range teams {
+				teams[i] = models.Team{ID: uint(i + 1), Name: fmt.Sprintf("Team %d", i+1), Power: 70}
+			}
+
+			matches := service.generateRoundRobin(teams)
+
+			// Every ordered pair plays exactly once: n*(n-1) matches over 2*n weeks
+			if len(matches) != n*(n-1) {
+				t.Errorf("Expected %d matches, got %d", n*(n-1), len(matches))
+			}
+			if weeks := totalWeeks(matches); weeks != 2*n {
+				t.Errorf("Expected %d weeks, got %d", 2*n, weeks)
+			}
+
+			pairs := make(map[[2]uint]int)
+			weekTeams := make(map[int]map[uint]bool)
+			for _, m := range matches {
+				if m.HomeTeamID == byeTeamID || m.AwayTeamID == byeTeamID {
+					t.Fatalf("Bye slot should never appear in a match: %+v", m)
+				}
+				pairs[[2]uint{m.HomeTeamID, m.AwayTeamID}]++
+				if weekTeams[m.Week] == nil {
+					weekTeams[m.Week] = make(map[uint]bool)
+				}
+				if weekTeams[m.Week][m.HomeTeamID] || weekTeams[m.Week][m.AwayTeamID] {
+					t.Errorf("A team plays twice in week %d", m.Week)
+				}
+				weekTeams[m.Week][m.HomeTeamID] = true
+				weekTeams[m.Week][m.AwayTeamID] = true
+			}
+
+			for _, home := range teams {
+				for _, away := range teams {
+					if home.ID != away.ID && pairs[[2]uint{home.ID, away.ID}] != 1 {
+						t.Errorf("%d vs %d should be played once at home, played %d",
+							home.ID, away.ID, pairs[[2]uint{home.ID, away.ID}])
+					}
+				}
+			}
+
+			// Exactly one team rests each week and every team rests twice
+			byes := make(map[uint]int)
+			for week, playing := range weekTeams {
+				if len(playing) != n-1 {
+					t.Errorf("Week %d should have %d teams playing, has %d", week, n-1, len(playing))
+				}
+				for _, team := range teams {
+					if !playing[team.ID] {
+						byes[team.ID]++
+					}
+				}
+			}
+			for _, team := range teams {
+				if byes[team.ID] != 2 {
+					t.Errorf("Team %d should have 2 byes, has %d", team.ID, byes[team.ID])
+				}
+			}
+		})
+	}
+}
+
+func TestGetFixturesByWeekReportsByes(t *testing.T) {
+	teamRepo := &mockTeamRepository{teams: []models.Team{
+		{ID: 1, Name: "Team A", Power: 80},
+		{ID: 2, Name: "Team B", Power: 75},
+		{ID: 3, Name: "Team C", Power: 70},
+	}}
+	matchRepo := &mockMatchRepository{}
+	service := newFixtureService(teamRepo, matchRepo, &mockLeagueStateRepository{}, &mockGroupRepository{})
+
+	if _, err := service.GenerateFixtures(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	fixtures, err := service.GetFixturesByWeek(1, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(fixtures.Matches) != 1 || len(fixtures.ByeTeams) != 1 {
+		t.Fatalf("Expected 1 match and 1 bye in week 1, got %d and %d", len(fixtures.Matches), len(fixtures.ByeTeams))
+	}
+
+	resting := fixtures.ByeTeams[0].ID
+	if resting == fixtures.Matches[0].HomeTeamID || resting == fixtures.Matches[0].AwayTeamID {
+		t.Errorf("Team %d cannot both play and rest in week 1", resting)
+	}
+}
+
+func TestGenerateFixturesStateChanged(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	leagueRepo := &mockLeagueStateRepository{}
+	transactor := &mockTransactor{repos: repository.Repositories{
+		Teams: teamRepo, Matches: &mockMatchRepository{}, LeagueStates: leagueRepo, Groups: &mockGroupRepository{},
+	}}
+	service := NewFixtureService(teamRepo, &mockMatchRepository{}, leagueRepo, &mockGroupRepository{}, transactor, noLiveWeeks{})
+
+	// Another request generates the fixtures while this one is drawing them
+	transactor.before = func() { leagueRepo.state.Version++ }
+	if _, err := service.GenerateFixtures(1); !errors.Is(err, ErrStateChanged) {
+		t.Errorf("Expected ErrStateChanged, got %v", err)
+	}
+}
+
+// liveWeeks is LiveWeeks for a league playing the given week live, none if nil
+type liveWeeks struct {
+	week *models.LiveWeek
+}
+
+func (l *liveWeeks) GetLiveWeek(uint) *models.LiveWeek {
+	return l.week
+}
+
+func TestRescheduleMatchConflicts(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	matchRepo := &mockMatchRepository{}
+	leagueRepo := &mockLeagueStateRepository{}
+	transactor := &mockTransactor{repos: repository.Repositories{
+		Teams: teamRepo, Matches: matchRepo, LeagueStates: leagueRepo, Groups: &mockGroupRepository{},
+	}}
+	live := &liveWeeks{}
+	service := NewFixtureService(teamRepo, matchRepo, leagueRepo, &mockGroupRepository{}, transactor, live)
+	matches, err := service.GenerateFixtures(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	match := matches[0]
+
+	// Moving a match moves the league to a new version, so a week read before can't be saved
+	version := leagueRepo.state.Version
+	if _, err := service.RescheduleMatch(1, match.ID, *match.Date); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if leagueRepo.state.Version != version+1 {
+		t.Errorf("Expected version %d, got %d", version+1, leagueRepo.state.Version)
+	}
+
+	// A week played in the meantime keeps the old date
+	transactor.before = func() { leagueRepo.state.Version++ }
+	if _, err := service.RescheduleMatch(1, match.ID, *match.Date); !errors.Is(err, ErrStateChanged) {
+		t.Errorf("Expected ErrStateChanged, got %v", err)
+	}
+	transactor.before = nil
+
+	live.week = &models.LiveWeek{Week: 1}
+	if _, err := service.RescheduleMatch(1, match.ID, *match.Date); !errors.Is(err, ErrWeekLive) {
+		t.Errorf("Expected ErrWeekLive, got %v", err)
+	}
+}
@@ -0,0 +1,210 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// groupQualifiers is the number of teams from each group that reach the knockout stage
+const groupQualifiers = 2
+
+// drawGroups draws the teams into groups of four. Teams are split into four pots by power, the
+// strongest in pot 1, and drawn pot by pot in random order; each team goes to the first group
+// without a team from its pot or its country that still leaves a valid draw for everyone else.
+func drawGroups(rng *rand.Rand, teams []models.Team, groups int) ([]models.GroupEntry, error) {
+	if groups < 1 || len(teams) != groups*models.GroupSize {
+		return nil, errors.New("the group stage needs exactly four teams per group")
+	}
+
+	// Pots by power, strongest first
+	sorted := sortByPower(teams)
+
+	perCountry := make(map[string]int)
+	for _, team := range sorted {
+		if team.Country != "" {
+			perCountry[team.Country]++
+			if perCountry[team.Country] > groups {
+				return nil, errors.New("too many teams from " + team.Country + " to keep them in separate groups")
+			}
+		}
+	}
+
+	// Draw order: pot by pot, shuffled within each pot
+	for pot := 0; pot < models.GroupSize; pot++ {
+		potTeams := sorted[pot*groups : (pot+1)*groups]
+		rng.Shuffle(len(potTeams), func(i, j int) {
+			potTeams[i], potTeams[j] = potTeams[j], potTeams[i]
+		})
+	}
+
+	draw := &groupDraw{
+		order:      sorted,
+		groups:     groups,
+		assignment: make([]int, len(sorted)),
+		filled:     make([]int, groups),
+		countries:  make([]map[string]bool, groups),
+	}
+	for g := range draw.countries {
+		draw.countries[g] = make(map[string]bool)
+	}
+	if !draw.place(0) {
+		return nil, errors.New("no group draw keeps teams from the same country apart")
+	}
+
+	entries := make([]models.GroupEntry, len(sorted))
+	for i, team := range sorted {
+		entries[i] = models.GroupEntry{
+			LeagueID: team.LeagueID,
+			Group:    models.GroupName(draw.assignment[i]),
+			Pot:      i/groups + 1,
+			TeamID:   team.ID,
+			Team:     team,
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Group != entries[j].Group {
+			return entries[i].Group < entries[j].Group
+		}
+		return entries[i].Pot < entries[j].Pot
+	})
+	return entries, nil
+}
+
+// sortByPower returns a copy of the teams, strongest first; pots are consecutive runs of it
+func sortByPower(teams []models.Team) []models.Team {
+	sorted := make([]models.Team, len(teams))
+	copy(sorted, teams)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Power > sorted[j].Power
+	})
+	return sorted
+}
+
+// groupDraw is the state of a group draw in progress
+type groupDraw struct {
+	order      []models.Team     // Teams in draw order, pot by pot
+	groups     int               // Number of groups
+	assignment []int             // Group of each drawn team
+	filled     []int             // Teams in each group so far
+	countries  []map[string]bool // Countries already in each group
+}
+
+// place draws order[i:], backtracking when a placement leaves no valid draw for the teams after it
+func (d *groupDraw) place(i int) bool {
+	if i == len(d.order) {
+		return true
+	}
+
+	team := &d.order[i]
+	pot := i / d.groups
+	for g := 0; g < d.groups; g++ {
+		// Pots are drawn in order, so a group without a team from this pot has exactly pot teams
+		if d.filled[g] != pot || (team.Country != "" && d.countries[g][team.Country]) {
+			continue
+		}
+
+		d.assignment[i] = g
+		d.filled[g]++
+		if team.Country != "" {
+			d.countries[g][team.Country] = true
+		}
+		if d.place(i + 1) {
+			return true
+		}
+		d.filled[g]--
+		delete(d.countries[g], team.Country)
+	}
+	return false
+}
+
+// calculateGroupTables splits a ranked table into the group tables, re-ranking each group with
+// the chain among its own teams only
+func calculateGroupTables(
+	entries []models.GroupEntry,
+	table []models.TeamStanding,
+	scores []matchScore,
+	chain tiebreakChain,
+) []models.GroupTable {
+	index := make(map[uint]int, len(table))
+	for i := range table {
+		index[table[i].TeamID] = i
+	}
+
+	var tables []models.GroupTable
+	for _, entry := range entries {
+		if len(tables) == 0 || tables[len(tables)-1].Group != entry.Group {
+			tables = append(tables, models.GroupTable{Group: entry.Group})
+		}
+		if i, ok := index[entry.TeamID]; ok {
+			group := &tables[len(tables)-1]
+			group.Standings = append(group.Standings, table[i])
+		}
+	}
+
+	for i := range tables {
+		rankStandings(tables[i].Standings, scores, chain)
+	}
+	return tables
+}
+
+// groupKnockoutEntrants seeds the group winners (ranked among themselves) ahead of the runners-up
+// and returns them in bracket order, so every first-round tie is a winner against a runner-up.
+// A winner drawn against the runner-up of its own group swaps opponents with another tie.
+func groupKnockoutEntrants(tables []models.GroupTable, scores []matchScore, chain tiebreakChain) []seededTeam {
+	groupOf := make(map[uint]string)
+	var finishers [groupQualifiers][]models.TeamStanding
+	for _, group := range tables {
+		for place := 0; place < groupQualifiers && place < len(group.Standings); place++ {
+			finishers[place] = append(finishers[place], group.Standings[place])
+			groupOf[group.Standings[place].TeamID] = group.Group
+		}
+	}
+
+	var seeded []uint
+	for place := range finishers {
+		rankStandings(finishers[place], scores, chain)
+		for _, standing := range finishers[place] {
+			seeded = append(seeded, standing.TeamID)
+		}
+	}
+
+	order := bracketOrder(len(seeded))
+	entrants := make([]seededTeam, len(order))
+	for i, seed := range order {
+		entrants[i] = seededTeam{teamID: seeded[seed-1], seed: seed}
+	}
+
+	// Pairs are (2k, 2k+1); the runner-up is the one with the higher seed number
+	runnerUp := func(pair int) int {
+		if entrants[2*pair].seed > entrants[2*pair+1].seed {
+			return 2 * pair
+		}
+		return 2*pair + 1
+	}
+	sameGroup := func(pair int) bool {
+		return groupOf[entrants[2*pair].teamID] == groupOf[entrants[2*pair+1].teamID]
+	}
+
+	pairs := len(entrants) / 2
+	for pair := 0; pair < pairs; pair++ {
+		if !sameGroup(pair) {
+			continue
+		}
+		for other := 0; other < pairs; other++ {
+			if other == pair {
+				continue
+			}
+			a, b := runnerUp(pair), runnerUp(other)
+			entrants[a], entrants[b] = entrants[b], entrants[a]
+			if !sameGroup(pair) && !sameGroup(other) {
+				break
+			}
+			entrants[a], entrants[b] = entrants[b], entrants[a]
+		}
+	}
+
+	return entrants
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+)
+
+// mockGroupRepository implements repository.GroupRepository for testing
+type mockGroupRepository struct {
+	entries []models.GroupEntry
+}
+
+func (m *mockGroupRepository) CreateBatch(entries []models.GroupEntry) error {
+	for i := range entries {
+		entries[i].ID = uint(len(m.entries) + 1)
+		m.entries = append(m.entries, entries[i])
+	}
+	return nil
+}
+
+func (m *mockGroupRepository) FindAll(_ uint) ([]models.GroupEntry, error) {
+	return m.entries, nil
+}
+
+func (m *mockGroupRepository) DeleteAll(_ uint) error {
+	m.entries = nil
+	return nil
+}
+
+// groupTeams returns 4*groups teams with descending power, two per country
+func groupTeams(groups int) []models.Team {
+	teams := make([]models.Team, groups*models.GroupSize)
+	for i := range teams {
+		teams[i] = models.Team{
+			ID:      uint(i + 1),
+			Name:    fmt.Sprintf("Team %d", i+1),
+			Power:   100 - i,
+			Country: fmt.Sprintf("Country %d", i/2),
+		}
+	}
+	return teams
+}
+
+func TestDrawGroups(t *testing.T) {
+	teams := groupTeams(4)
+	powers := make(map[uint]int, len(teams))
+	countries := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		powers[team.ID] = team.Power
+		countries[team.ID] = team.Country
+	}
+
+	for seed := int64(0); seed < 50; seed++ {
+		entries, err := drawGroups(rand.New(rand.NewSource(seed)), teams, 4)
+		if err != nil {
+			t.Fatalf("Seed %d: expected no error, got %v", seed, err)
+		}
+		if len(entries) != len(teams) {
+			t.Fatalf("Seed %d: expected %d entries, got %d", seed, len(teams), len(entries))
+		}
+
+		pots := make(map[string]map[int]bool)
+		seen := make(map[string]map[string]bool)
+		for _, entry := range entries {
+			if pots[entry.Group] == nil {
+				pots[entry.Group] = make(map[int]bool)
+				seen[entry.Group] = make(map[string]bool)
+			}
+			if pots[entry.Group][entry.Pot] {
+				t.Fatalf("Seed %d: group %s has two teams from pot %d", seed, entry.Group, entry.Pot)
+			}
+			pots[entry.Group][entry.Pot] = true
+
+			if seen[entry.Group][countries[entry.TeamID]] {
+				t.Fatalf("Seed %d: group %s has two teams from %s", seed, entry.Group, countries[entry.TeamID])
+			}
+			seen[entry.Group][countries[entry.TeamID]] = true
+
+			// Pot 1 holds the four strongest teams, pot 4 the four weakest
+			if expected := (100-powers[entry.TeamID])/4 + 1; entry.Pot != expected {
+				t.Errorf("Seed %d: team %d should be in pot %d, got %d", seed, entry.TeamID, expected, entry.Pot)
+			}
+		}
+		if len(pots) != 4 {
+			t.Fatalf("Seed %d: expected 4 groups, got %d", seed, len(pots))
+		}
+	}
+}
+
+func TestDrawGroupsImpossible(t *testing.T) {
+	// The default teams are all English, so they can't share a group
+	if _, err := drawGroups(rand.New(rand.NewSource(1)), models.DefaultTeams(), 1); err == nil {
+		t.Error("Expected an error when a country has more teams than there are groups")
+	}
+
+	if _, err := drawGroups(rand.New(rand.NewSource(1)), groupTeams(2)[:7], 2); err == nil {
+		t.Error("Expected an error when the teams don't fill the groups")
+	}
+}
+
+func TestGroupKnockoutEntrants(t *testing.T) {
+	standing := func(id uint, points int) models.TeamStanding {
+		return models.TeamStanding{TeamID: id, Points: points}
+	}
+	tables := []models.GroupTable{
+		{Group: "A", Standings: []models.TeamStanding{standing(1, 12), standing(2, 10)}},
+		{Group: "B", Standings: []models.TeamStanding{standing(3, 15), standing(4, 9)}},
+	}
+
+	entrants := groupKnockoutEntrants(tables, nil, presetChain(models.TiebreakerPresetPremierLeague))
+
+	// Winners are seeded 1-2 by points, runners-up 3-4; B's winner would meet B's runner-up, so runners-up swap
+	expected := []seededTeam{{3, 1}, {2, 3}, {1, 2}, {4, 4}}
+	if len(entrants) != len(expected) {
+		t.Fatalf("Expected %d entrants, got %d", len(expected), len(entrants))
+	}
+	for i := range expected {
+		if entrants[i] != expected[i] {
+			t.Errorf("Entrant %d: expected %+v, got %+v", i, expected[i], entrants[i])
+		}
+	}
+}
+
+func TestGroupStageFeedsKnockout(t *testing.T) {
+	teamRepo := &mockTeamRepository{teams: groupTeams(2)}
+	teams := make(map[uint]models.Team)
+	for _, team := range teamRepo.teams {
+		teams[team.ID] = team
+	}
+	matchRepo := &mockMatchRepository{teams: teams}
+	leagueRepo := &mockLeagueStateRepository{state: &models.LeagueState{LeagueID: 1, Seed: 5}}
+	groupRepo := &mockGroupRepository{}
+	knockoutRepo := &mockKnockoutRepository{matchRepo: matchRepo}
+	seasonRepo, ratingRepo, eventRepo := &mockSeasonRepository{}, &mockRatingRepository{}, &mockMatchEventRepository{}
+	playerRepo, absenceRepo := &mockPlayerRepository{}, &mockAbsenceRepository{}
+	seasons := NewSeasonService(matchRepo, teamRepo, leagueRepo, seasonRepo, knockoutRepo)
+	knockout := NewKnockoutService(matchRepo, teamRepo, leagueRepo, knockoutRepo, groupRepo)
+	availability := NewAvailabilityService(playerRepo, absenceRepo, eventRepo, matchRepo, teamRepo, leagueRepo)
+	transactor := &mockTransactor{repos: repository.Repositories{
+		Teams: teamRepo, Matches: matchRepo, LeagueStates: leagueRepo, Seasons: seasonRepo, Knockout: knockoutRepo,
+		Groups: groupRepo, Ratings: ratingRepo, MatchEvents: eventRepo, Players: playerRepo, Absences: absenceRepo,
+	}}
+	ratings := NewRatingService(teamRepo, matchRepo, leagueRepo, ratingRepo, transactor)
+	simulation := NewSimulationService(matchRepo, teamRepo, leagueRepo, groupRepo, seasons, knockout, ratings, eventRepo,
+		availability, transactor)
+	standings := NewStandingsService(matchRepo, teamRepo, leagueRepo, knockoutRepo, groupRepo)
+
+	groups := 2
+	state, err := simulation.UpdateSettings(1, models.LeagueSettings{Groups: &groups})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if state.KnockoutTeams != 4 {
+		t.Fatalf("Expected the top two of each group in the knockout stage, got %d teams", state.KnockoutTeams)
+	}
+
+	if _, err := newFixtureService(teamRepo, matchRepo, leagueRepo, groupRepo).GenerateFixtures(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Each group of four plays a double round-robin over the same six weeks
+	groupOf := make(map[uint]string)
+	for _, entry := range groupRepo.entries {
+		groupOf[entry.TeamID] = entry.Group
+	}
+	if len(matchRepo.matches) != 2*12 {
+		t.Fatalf("Expected 24 group matches, got %d", len(matchRepo.matches))
+	}
+	for _, match := range matchRepo.matches {
+		if match.Stage != models.MatchStageGroup || match.Week > 6 ||
+			groupOf[match.HomeTeamID] != match.Group || groupOf[match.AwayTeamID] != match.Group {
+			t.Fatalf("Unexpected group match %+v", match)
+		}
+	}
+
+	if _, err := simulation.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	tables, err := standings.GetGroupStandings(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(tables) != 2 || tables[0].Group != "A" || tables[1].Group != "B" {
+		t.Fatalf("Expected groups A and B, got %+v", tables)
+	}
+
+	qualified := make(map[uint]bool)
+	for _, table := range tables {
+		if len(table.Standings) != models.GroupSize || table.Standings[0].Played != 6 {
+			t.Fatalf("Unexpected table for group %s: %+v", table.Group, table.Standings)
+		}
+		qualified[table.Standings[0].TeamID] = true
+		qualified[table.Standings[1].TeamID] = true
+	}
+
+	semis := knockoutRepo.ties[:2]
+	for _, tie := range semis {
+		if !qualified[tie.TeamAID] || !qualified[tie.TeamBID] {
+			t.Errorf("Semi-final %d-%d has a team that didn't finish in the top two", tie.TeamAID, tie.TeamBID)
+		}
+		if groupOf[tie.TeamAID] == groupOf[tie.TeamBID] {
+			t.Errorf("Semi-final %d-%d pairs teams from the same group", tie.TeamAID, tie.TeamBID)
+		}
+	}
+
+	final, _ := leagueRepo.Get(1)
+	if !final.Completed || len(knockoutRepo.ties) != 3 || knockoutRepo.ties[2].WinnerID == nil {
+		t.Errorf("Expected the knockout stage to be played through to the final")
+	}
+}
@@ -0,0 +1,592 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+	"gorm.io/gorm"
+)
+
+const (
+	// extraTimeFraction scales 90-minute expected goals down to 30 minutes of extra time
+	extraTimeFraction = 30.0 / 90.0
+	// penaltyConversion is the chance of scoring a shootout penalty
+	penaltyConversion = 0.75
+	// shootoutKicks is the number of kicks each side takes before sudden death
+	shootoutKicks = 5
+)
+
+var ErrTieNotFound = errors.New("knockout tie not found")
+
+type KnockoutService interface {
+	GetBracket(leagueID uint) (*models.KnockoutBracket, error)
+	GetTie(leagueID, tieID uint) (*models.KnockoutTie, error)
+	DecideTie(leagueID uint, engine MatchEngine, rng *rand.Rand, decider *models.Match) (*models.KnockoutTie, error)
+	SaveTie(tie *models.KnockoutTie) error
+	Advance(leagueID uint, state *models.LeagueState) error
+	Clear(leagueID uint) error
+}
+
+type knockoutService struct {
+	matchRepo    repository.MatchRepository
+	teamRepo     repository.TeamRepository
+	leagueRepo   repository.LeagueStateRepository
+	knockoutRepo repository.KnockoutRepository
+	groupRepo    repository.GroupRepository
+}
+
+func NewKnockoutService(
+	matchRepo repository.MatchRepository,
+	teamRepo repository.TeamRepository,
+	leagueRepo repository.LeagueStateRepository,
+	knockoutRepo repository.KnockoutRepository,
+	groupRepo repository.GroupRepository,
+) KnockoutService {
+	return &knockoutService{
+		matchRepo:    matchRepo,
+		teamRepo:     teamRepo,
+		leagueRepo:   leagueRepo,
+		knockoutRepo: knockoutRepo,
+		groupRepo:    groupRepo,
+	}
+}
+
+func (s *knockoutService) GetBracket(leagueID uint) (*models.KnockoutBracket, error) {
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	ties, err := s.knockoutRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	bracket := &models.KnockoutBracket{
+		Teams:  state.KnockoutTeams,
+		Rounds: []models.KnockoutRound{},
+	}
+
+	// Ties come ordered by round and slot; the play-off, if any, is round 1
+	firstRound := 1
+	if state.HasPlayoff() {
+		firstRound = 2
+	}
+	for _, tie := range ties {
+		if len(bracket.Rounds) == 0 || bracket.Rounds[len(bracket.Rounds)-1].Round != tie.Round {
+			name := models.KnockoutPlayoffName
+			if tie.Round >= firstRound {
+				name = models.KnockoutRoundName(state.KnockoutTeams >> (tie.Round - firstRound))
+			}
+			bracket.Rounds = append(bracket.Rounds, models.KnockoutRound{
+				Round: tie.Round,
+				Name:  name,
+			})
+		}
+		round := &bracket.Rounds[len(bracket.Rounds)-1]
+		round.Ties = append(round.Ties, tie)
+
+		if tie.SingleLeg && tie.Round == state.KnockoutRounds() {
+			bracket.ChampionID = tie.WinnerID
+		}
+	}
+
+	return bracket, nil
+}
+
+func (s *knockoutService) GetTie(leagueID, tieID uint) (*models.KnockoutTie, error) {
+	tie, err := s.knockoutRepo.FindByID(leagueID, tieID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTieNotFound
+	}
+	return tie, err
+}
+
+// DecideTie decides the tie of a just-played deciding match (second leg or final) whose regular
+// time score is already set: on aggregate, else after extra time, else on penalties. Extra time
+// and penalties are recorded on the match and draw from the match's own random source. The
+// decided tie is returned for SaveTie, nil if the match decides none.
+func (s *knockoutService) DecideTie(leagueID uint, engine MatchEngine, rng *rand.Rand, decider *models.Match) (*models.KnockoutTie, error) {
+	if decider.TieID == nil || decider.Leg == 1 {
+		return nil, nil
+	}
+
+	tie, err := s.knockoutRepo.FindByID(leagueID, *decider.TieID)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregateA, aggregateB := 0, 0
+	for i := range tie.Matches {
+		leg := &tie.Matches[i]
+		if leg.ID == decider.ID {
+			continue
+		}
+		if !leg.Played || leg.HomeScore == nil || leg.AwayScore == nil {
+			return nil, errors.New("first leg has not been played")
+		}
+		a, b := tieGoals(tie, leg.HomeTeamID, *leg.HomeScore, *leg.AwayScore)
+		aggregateA += a
+		aggregateB += b
+	}
+	a, b := tieGoals(tie, decider.HomeTeamID, *decider.HomeScore, *decider.AwayScore)
+	aggregateA += a
+	aggregateB += b
+
+	tie.DecidedBy = models.TieDecidedByAggregate
+	winnerIsA := aggregateA > aggregateB
+	if aggregateA == aggregateB {
+		homeExtra, awayExtra := extraTimeGoals(rng, engine, &decider.HomeTeam, &decider.AwayTeam, decider.Neutral)
+		decider.HomeExtraTimeScore = &homeExtra
+		decider.AwayExtraTimeScore = &awayExtra
+		a, b = tieGoals(tie, decider.HomeTeamID, homeExtra, awayExtra)
+		aggregateA += a
+		aggregateB += b
+		tie.DecidedBy = models.TieDecidedByExtraTime
+		winnerIsA = aggregateA > aggregateB
+
+		if aggregateA == aggregateB {
+			// Shootout goals don't count towards the aggregate
+			homePens, awayPens := penaltyShootout(rng)
+			decider.HomePenalties = &homePens
+			decider.AwayPenalties = &awayPens
+			tie.DecidedBy = models.TieDecidedByPenalties
+			pensA, pensB := tieGoals(tie, decider.HomeTeamID, homePens, awayPens)
+			winnerIsA = pensA > pensB
+		}
+	}
+
+	tie.TeamAAggregate = aggregateA
+	tie.TeamBAggregate = aggregateB
+	winner := tie.TeamBID
+	if winnerIsA {
+		winner = tie.TeamAID
+	}
+	tie.WinnerID = &winner
+	return tie, nil
+}
+
+// SaveTie saves a tie decided by DecideTie
+func (s *knockoutService) SaveTie(tie *models.KnockoutTie) error {
+	return s.knockoutRepo.Update(tie)
+}
+
+// tieGoals maps a match score to the tie's team A and team B
+func tieGoals(tie *models.KnockoutTie, homeTeamID uint, homeGoals, awayGoals int) (int, int) {
+	if homeTeamID == tie.TeamAID {
+		return homeGoals, awayGoals
+	}
+	return awayGoals, homeGoals
+}
+
+// Advance draws the next knockout round once the week just played completes the previous one:
+// the first round after the last league week, then each round after its deciding week. Play-off
+// winners join the teams that qualified directly.
+func (s *knockoutService) Advance(leagueID uint, state *models.LeagueState) error {
+	if state.KnockoutTeams == 0 {
+		return nil
+	}
+
+	leagueWeeks := state.LeagueWeeks()
+	rounds := state.KnockoutRounds()
+
+	if state.CurrentWeek == leagueWeeks {
+		entrants, err := s.qualifiedTeams(leagueID, state, nil)
+		if err != nil {
+			return err
+		}
+		return s.drawRound(leagueID, state, 1, rounds, entrants)
+	}
+
+	offset := state.CurrentWeek - leagueWeeks
+	round := (offset + 1) / 2
+	if offset <= 0 || offset%2 == 1 || round >= rounds {
+		// Still in the league, between legs, or the final has been played
+		return nil
+	}
+
+	ties, err := s.knockoutRepo.FindAll(leagueID)
+	if err != nil {
+		return err
+	}
+
+	var winners []seededTeam
+	for _, tie := range ties {
+		if tie.Round != round {
+			continue
+		}
+		if tie.WinnerID == nil {
+			return errors.New("knockout round has undecided ties")
+		}
+		if *tie.WinnerID == tie.TeamAID {
+			winners = append(winners, seededTeam{teamID: tie.TeamAID, seed: tie.TeamASeed})
+		} else {
+			winners = append(winners, seededTeam{teamID: tie.TeamBID, seed: tie.TeamBSeed})
+		}
+	}
+
+	if round == 1 && state.HasPlayoff() {
+		if winners, err = s.qualifiedTeams(leagueID, state, winners); err != nil {
+			return err
+		}
+	}
+
+	return s.drawRound(leagueID, state, round+1, rounds, winners)
+}
+
+// qualifiedTeams returns the teams of the first knockout round in bracket order: the play-off
+// teams, or, once the play-off winners are known, the teams that qualified directly and them
+func (s *knockoutService) qualifiedTeams(leagueID uint, state *models.LeagueState, playoffWinners []seededTeam) ([]seededTeam, error) {
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.matchRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := s.groupRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := leagueTiebreakChain(state)
+	plan := knockoutPlan{teams: state.KnockoutTeams, playoff: state.HasPlayoff(), chain: chain, groups: groups}
+	table := calculateStandings(teams, matches, chain)
+	if playoffWinners != nil {
+		return plan.bracketEntrants(table, playoffWinners), nil
+	}
+	entrants := plan.entrants(table, playedScores(matches))
+	if entrants == nil {
+		return nil, errors.New("not enough teams for the knockout stage")
+	}
+	return entrants, nil
+}
+
+// drawRound creates a round's ties and matches from its entrants in bracket order, pairing
+// entrants 2k and 2k+1. Two-legged ties start at the lower seed; the final is a single match
+// at a neutral venue. The matches are scheduled on their weeks' dates.
+func (s *knockoutService) drawRound(leagueID uint, state *models.LeagueState, round, rounds int, entrants []seededTeam) error {
+	singleLeg := round == rounds
+	ties := make([]models.KnockoutTie, len(entrants)/2)
+	for slot := range ties {
+		a, b := entrants[2*slot], entrants[2*slot+1]
+		if b.seed < a.seed {
+			a, b = b, a
+		}
+		ties[slot] = models.KnockoutTie{
+			LeagueID:  leagueID,
+			Round:     round,
+			Slot:      slot,
+			TeamAID:   a.teamID,
+			TeamBID:   b.teamID,
+			TeamASeed: a.seed,
+			TeamBSeed: b.seed,
+			SingleLeg: singleLeg,
+		}
+	}
+
+	if err := s.knockoutRepo.CreateTies(ties); err != nil {
+		return err
+	}
+
+	firstWeek := state.LeagueWeeks() + 2*(round-1) + 1
+	var matches []models.Match
+	for i := range ties {
+		tie := &ties[i]
+		if singleLeg {
+			matches = append(matches, knockoutMatch(leagueID, tie, firstWeek, 0, tie.TeamAID, tie.TeamBID))
+			continue
+		}
+		matches = append(matches,
+			knockoutMatch(leagueID, tie, firstWeek, 1, tie.TeamBID, tie.TeamAID),
+			knockoutMatch(leagueID, tie, firstWeek+1, 2, tie.TeamAID, tie.TeamBID),
+		)
+	}
+	for i := range matches {
+		matches[i].Date = state.WeekDate(matches[i].Week)
+	}
+
+	return s.matchRepo.CreateBatch(matches)
+}
+
+// knockoutMatch builds one match of a tie; the single-leg final (leg 0) is played on neutral ground
+func knockoutMatch(leagueID uint, tie *models.KnockoutTie, week, leg int, homeTeamID, awayTeamID uint) models.Match {
+	return models.Match{
+		LeagueID:   leagueID,
+		Week:       week,
+		HomeTeamID: homeTeamID,
+		AwayTeamID: awayTeamID,
+		Stage:      models.MatchStageKnockout,
+		TieID:      &tie.ID,
+		Leg:        leg,
+		Neutral:    leg == 0,
+	}
+}
+
+func (s *knockoutService) Clear(leagueID uint) error {
+	return s.knockoutRepo.DeleteAll(leagueID)
+}
+
+// seededTeam is a team in the bracket with its seed (1 is the best)
+type seededTeam struct {
+	teamID uint
+	seed   int
+}
+
+// bracketOrder lists the seeds of a bracket of n teams in slot order so that, if the higher
+// seed always wins, seeds 1 and 2 only meet in the final: 1 8 4 5 2 7 3 6 for eight teams
+func bracketOrder(n int) []int {
+	order := []int{1}
+	for size := 2; size <= n; size *= 2 {
+		next := make([]int, 0, size)
+		for _, seed := range order {
+			next = append(next, seed, size+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// extraTimeGoals plays 30 minutes of extra time
+func extraTimeGoals(rng *rand.Rand, engine MatchEngine, homeTeam, awayTeam *models.Team, neutral bool) (int, int) {
+	homeExpectedGoals, awayExpectedGoals := engine.ExpectedGoals(homeTeam, awayTeam, neutral)
+
+	homeGoals := generateGoalsPoisson(rng, homeExpectedGoals*extraTimeFraction)
+	awayGoals := generateGoalsPoisson(rng, awayExpectedGoals*extraTimeFraction)
+
+	return homeGoals, awayGoals
+}
+
+// penaltyShootout plays a shootout: five kicks each, stopping once one side can no longer be
+// caught, then sudden death. It never ends level.
+func penaltyShootout(rng *rand.Rand) (int, int) {
+	home, away := 0, 0
+	for kick := 1; kick <= shootoutKicks; kick++ {
+		if rng.Float64() < penaltyConversion {
+			home++
+		}
+		if home > away+shootoutKicks-kick+1 || away > home+shootoutKicks-kick {
+			return home, away
+		}
+		if rng.Float64() < penaltyConversion {
+			away++
+		}
+		if home > away+shootoutKicks-kick || away > home+shootoutKicks-kick {
+			return home, away
+		}
+	}
+
+	for home == away {
+		if rng.Float64() < penaltyConversion {
+			home++
+		}
+		if rng.Float64() < penaltyConversion {
+			away++
+		}
+	}
+	return home, away
+}
+
+// simulateTieWinner plays out the rest of a tie between a (the higher seed, hosting the second
+// leg) and b, keeping the legs already played, and returns the winner's ID
+func simulateTieWinner(rng *rand.Rand, engine MatchEngine, a, b *models.Team, singleLeg bool, played []models.Match) uint {
+	type leg struct {
+		number     int
+		home, away *models.Team
+	}
+	legs := []leg{{1, b, a}, {2, a, b}}
+	if singleLeg {
+		legs = []leg{{0, a, b}}
+	}
+
+	aggregateA, aggregateB := 0, 0
+	for _, l := range legs {
+		var homeGoals, awayGoals int
+		if m := findPlayedLeg(played, l.number); m != nil {
+			homeGoals, awayGoals = *m.HomeScore, *m.AwayScore
+		} else {
+			homeGoals, awayGoals = engine.Simulate(rng, l.home, l.away, singleLeg)
+		}
+
+		if l.home.ID == a.ID {
+			aggregateA, aggregateB = aggregateA+homeGoals, aggregateB+awayGoals
+		} else {
+			aggregateA, aggregateB = aggregateA+awayGoals, aggregateB+homeGoals
+		}
+	}
+
+	if aggregateA == aggregateB {
+		// Extra time and penalties in the deciding match, hosted by a unless neutral
+		extraA, extraB := extraTimeGoals(rng, engine, a, b, singleLeg)
+		aggregateA, aggregateB = aggregateA+extraA, aggregateB+extraB
+		if aggregateA == aggregateB {
+			aggregateA, aggregateB = penaltyShootout(rng)
+		}
+	}
+
+	if aggregateA > aggregateB {
+		return a.ID
+	}
+	return b.ID
+}
+
+// findPlayedLeg returns the played leg with the given number, or nil
+func findPlayedLeg(matches []models.Match, leg int) *models.Match {
+	for i := range matches {
+		m := &matches[i]
+		if m.Leg == leg && m.Played && m.HomeScore != nil && m.AwayScore != nil {
+			return m
+		}
+	}
+	return nil
+}
+
+// knockoutPlan is the knockout stage as seen by the championship predictions
+type knockoutPlan struct {
+	teams   int                  // Bracket size, 0 without a knockout stage
+	playoff bool                 // Whether the bottom half of the bracket comes through a play-off
+	engine  MatchEngine          // Simulates the knockout matches
+	chain   tiebreakChain        // Ranks group winners and runners-up for seeding
+	groups  []models.GroupEntry  // Group draw, empty without a group stage
+	current []models.KnockoutTie // Ties of the latest drawn round, in slot order
+}
+
+// newKnockoutPlan keeps the latest drawn round of the given ties (ordered by round and slot)
+func newKnockoutPlan(state *models.LeagueState, groups []models.GroupEntry, ties []models.KnockoutTie) knockoutPlan {
+	plan := knockoutPlan{
+		teams:   state.KnockoutTeams,
+		playoff: state.HasPlayoff(),
+		engine:  newMatchEngine(state),
+		chain:   leagueTiebreakChain(state),
+		groups:  groups,
+	}
+	for _, tie := range ties {
+		if len(plan.current) > 0 && tie.Round != plan.current[0].Round {
+			plan.current = plan.current[:0]
+		}
+		plan.current = append(plan.current, tie)
+	}
+	return plan
+}
+
+// qualifiers returns the number of teams the table sends to the knockout stage
+func (p *knockoutPlan) qualifiers() int {
+	if p.playoff {
+		return p.teams + p.teams/2
+	}
+	return p.teams
+}
+
+// entrants returns the teams of the first knockout round from the final ranked table, in bracket
+// order: the top of the table, the top two of each group, or the play-off places. Play-off ties
+// pair the best play-off team with the worst and so on. Returns nil if there are too few teams.
+func (p *knockoutPlan) entrants(table []models.TeamStanding, scores []matchScore) []seededTeam {
+	if len(table) < p.qualifiers() {
+		return nil
+	}
+	if len(p.groups) > 0 {
+		tables := calculateGroupTables(p.groups, table, scores, p.chain)
+		return groupKnockoutEntrants(tables, scores, p.chain)
+	}
+
+	if p.playoff {
+		first, last := p.teams/2+1, p.qualifiers()
+		entrants := make([]seededTeam, 0, p.teams)
+		for seed := first; seed < first+p.teams/2; seed++ {
+			entrants = append(entrants,
+				seededTeam{teamID: table[seed-1].TeamID, seed: seed},
+				seededTeam{teamID: table[first+last-seed-1].TeamID, seed: first + last - seed},
+			)
+		}
+		return entrants
+	}
+
+	entrants := make([]seededTeam, 0, p.teams)
+	for _, seed := range bracketOrder(p.teams) {
+		entrants = append(entrants, seededTeam{teamID: table[seed-1].TeamID, seed: seed})
+	}
+	return entrants
+}
+
+// bracketEntrants returns the bracket after the play-off in bracket order: the top half of the
+// table keeps its places, and the winner of each play-off tie (in slot order) takes the seed of
+// the better-placed team in it
+func (p *knockoutPlan) bracketEntrants(table []models.TeamStanding, playoffWinners []seededTeam) []seededTeam {
+	direct := p.teams / 2
+	bySeed := make([]uint, p.teams)
+	for i := 0; i < direct; i++ {
+		bySeed[i] = table[i].TeamID
+	}
+	for slot, winner := range playoffWinners {
+		bySeed[direct+slot] = winner.teamID
+	}
+
+	entrants := make([]seededTeam, 0, p.teams)
+	for _, seed := range bracketOrder(p.teams) {
+		entrants = append(entrants, seededTeam{teamID: bySeed[seed-1], seed: seed})
+	}
+	return entrants
+}
+
+// champion returns the simulated season's champion: the top of the table, or the winner of the
+// bracket played out from the table (or from the ties already drawn), play-off included
+func (p *knockoutPlan) champion(
+	rng *rand.Rand,
+	table []models.TeamStanding,
+	scores []matchScore,
+	teamsByID map[uint]*models.Team,
+) uint {
+	if p.teams == 0 || len(table) < p.qualifiers() {
+		return table[0].TeamID
+	}
+
+	var entrants []seededTeam
+	if len(p.current) == 0 {
+		entrants = p.entrants(table, scores)
+		if p.playoff {
+			entrants = p.bracketEntrants(table, simulateRound(rng, p.engine, entrants, teamsByID))
+		}
+	} else {
+		for i := range p.current {
+			tie := &p.current[i]
+			winner := seededTeam{teamID: tie.TeamAID, seed: tie.TeamASeed}
+			if tie.WinnerID != nil && *tie.WinnerID == tie.TeamBID {
+				winner = seededTeam{teamID: tie.TeamBID, seed: tie.TeamBSeed}
+			} else if tie.WinnerID == nil &&
+				simulateTieWinner(rng, p.engine, teamsByID[tie.TeamAID], teamsByID[tie.TeamBID], tie.SingleLeg, tie.Matches) == tie.TeamBID {
+				winner = seededTeam{teamID: tie.TeamBID, seed: tie.TeamBSeed}
+			}
+			entrants = append(entrants, winner)
+		}
+		if p.playoff && p.current[0].Round == 1 {
+			entrants = p.bracketEntrants(table, entrants)
+		}
+	}
+
+	for len(entrants) > 1 {
+		entrants = simulateRound(rng, p.engine, entrants, teamsByID)
+	}
+	return entrants[0].teamID
+}
+
+// simulateRound plays out a round of entrants in bracket order and returns the winners in slot
+// order; a round of two is the single-leg final
+func simulateRound(rng *rand.Rand, engine MatchEngine, entrants []seededTeam, teamsByID map[uint]*models.Team) []seededTeam {
+	winners := make([]seededTeam, len(entrants)/2)
+	for slot := range winners {
+		a, b := entrants[2*slot], entrants[2*slot+1]
+		if b.seed < a.seed {
+			a, b = b, a
+		}
+		winners[slot] = b
+		if simulateTieWinner(rng, engine, teamsByID[a.teamID], teamsByID[b.teamID], len(entrants) == 2, nil) == a.teamID {
+			winners[slot] = a
+		}
+	}
+	return winners
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+// mockKnockoutRepository implements repository.KnockoutRepository for testing, loading each
+// tie's legs from the match repository
+type mockKnockoutRepository struct {
+	ties      []models.KnockoutTie
+	matchRepo *mockMatchRepository
+}
+
+func (m *mockKnockoutRepository) withLegs(tie models.KnockoutTie) models.KnockoutTie {
+	tie.TeamA = m.matchRepo.teams[tie.TeamAID]
+	tie.TeamB = m.matchRepo.teams[tie.TeamBID]
+	tie.Matches = nil
+	for _, match := range m.matchRepo.matches {
+		if match.TieID != nil && *match.TieID == tie.ID {
+			tie.Matches = append(tie.Matches, m.matchRepo.withTeams(match))
+		}
+	}
+	return tie
+}
+
+func (m *mockKnockoutRepository) CreateTies(ties []models.KnockoutTie) error {
+	for i := range ties {
+		ties[i].ID = uint(len(m.ties) + 1)
+		m.ties = append(m.ties, ties[i])
+	}
+	return nil
+}
+
+func (m *mockKnockoutRepository) FindAll(_ uint) ([]models.KnockoutTie, error) {
+	ties := make([]models.KnockoutTie, len(m.ties))
+	for i, tie := range m.ties {
+		ties[i] = m.withLegs(tie)
+	}
+	return ties, nil
+}
+
+func (m *mockKnockoutRepository) FindByID(_, id uint) (*models.KnockoutTie, error) {
+	for _, tie := range m.ties {
+		if tie.ID == id {
+			found := m.withLegs(tie)
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockKnockoutRepository) Update(tie *models.KnockoutTie) error {
+	for i := range m.ties {
+		if m.ties[i].ID == tie.ID {
+			m.ties[i] = *tie
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *mockKnockoutRepository) DeleteAll(_ uint) error {
+	m.ties = nil
+	return nil
+}
+
+func TestBracketOrder(t *testing.T) {
+	tests := map[int][]int{
+		2:  {1, 2},
+		4:  {1, 4, 2, 3},
+		8:  {1, 8, 4, 5, 2, 7, 3, 6},
+		16: {1, 16, 8, 9, 4, 13, 5, 12, 2, 15, 7, 10, 3, 14, 6, 11},
+	}
+	for n, expected := range tests {
+		if got := bracketOrder(n); !reflect.DeepEqual(got, expected) {
+			t.Errorf("bracketOrder(%d): expected %v, got %v", n, expected, got)
+		}
+	}
+}
+
+func TestKnockoutWeeks(t *testing.T) {
+	tests := map[int]int{0: 0, 2: 1, 4: 3, 8: 5, 16: 7}
+	for teams, expected := range tests {
+		if got := models.KnockoutWeeks(teams); got != expected {
+			t.Errorf("KnockoutWeeks(%d): expected %d, got %d", teams, expected, got)
+		}
+	}
+
+	for _, teams := range []int{1, 3, 6, 128} {
+		if models.ValidKnockoutTeams(teams) {
+			t.Errorf("Expected %d knockout teams to be invalid", teams)
+		}
+	}
+}
+
+func TestPenaltyShootoutNeverLevel(t *testing.T) {
+	rng := rand.New(rand.NewSource(42))
+	for range 10000 {
+		home, away := penaltyShootout(rng)
+		if home == away {
+			t.Fatalf("Shootout ended level at %d-%d", home, away)
+		}
+	}
+}
+
+func TestDecideTie(t *testing.T) {
+	teams := map[uint]models.Team{
+		1: {ID: 1, Name: "A", Power: 80},
+		2: {ID: 2, Name: "B", Power: 80},
+	}
+
+	tests := []struct {
+		name      string
+		firstLeg  [2]int // B at home
+		secondLeg [2]int // A at home
+		decidedBy string
+	}{
+		{"aggregate", [2]int{1, 0}, [2]int{3, 1}, models.TieDecidedByAggregate},
+		{"level aggregate goes to extra time or penalties", [2]int{2, 1}, [2]int{1, 0}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matchRepo := &mockMatchRepository{teams: teams}
+			knockoutRepo := &mockKnockoutRepository{matchRepo: matchRepo}
+			service := NewKnockoutService(matchRepo, nil, nil, knockoutRepo, nil)
+
+			tie := models.KnockoutTie{TeamAID: 1, TeamBID: 2, TeamASeed: 1, TeamBSeed: 2}
+			if err := knockoutRepo.CreateTies([]models.KnockoutTie{tie}); err != nil {
+				t.Fatal(err)
+			}
+			tie.ID = 1
+
+			first := knockoutMatch(1, &tie, 1, 1, 2, 1)
+			first.HomeScore, first.AwayScore, first.Played = intPtr(tt.firstLeg[0]), intPtr(tt.firstLeg[1]), true
+			second := knockoutMatch(1, &tie, 2, 2, 1, 2)
+			if err := matchRepo.CreateBatch([]models.Match{first, second}); err != nil {
+				t.Fatal(err)
+			}
+
+			decider := matchRepo.withTeams(matchRepo.matches[1])
+			decider.HomeScore, decider.AwayScore, decider.Played = intPtr(tt.secondLeg[0]), intPtr(tt.secondLeg[1]), true
+			decided, err := service.DecideTie(1, defaultMatchEngine, rand.New(rand.NewSource(3)), &decider)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if knockoutRepo.ties[0].WinnerID != nil {
+				t.Fatal("Expected the tie saved only by SaveTie")
+			}
+			if err := service.SaveTie(decided); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			settled := knockoutRepo.ties[0]
+			if settled.WinnerID == nil {
+				t.Fatal("Expected the tie to have a winner")
+			}
+			if tt.decidedBy != "" && settled.DecidedBy != tt.decidedBy {
+				t.Errorf("Expected tie decided by %s, got %s", tt.decidedBy, settled.DecidedBy)
+			}
+			if settled.DecidedBy == models.TieDecidedByAggregate && *settled.WinnerID != 1 {
+				t.Errorf("Expected team A to win 4-2 on aggregate, got %d", *settled.WinnerID)
+			}
+
+			switch settled.DecidedBy {
+			case models.TieDecidedByAggregate:
+				if decider.HomeExtraTimeScore != nil {
+					t.Error("Expected no extra time when the aggregate decides")
+				}
+			case models.TieDecidedByExtraTime:
+				if decider.HomeExtraTimeScore == nil || decider.HomePenalties != nil {
+					t.Error("Expected extra time without penalties")
+				}
+			case models.TieDecidedByPenalties:
+				if decider.HomePenalties == nil || *decider.HomePenalties == *decider.AwayPenalties {
+					t.Error("Expected a decisive shootout")
+				}
+			}
+		})
+	}
+}
+
+func TestPlayAllWeeksThroughKnockout(t *testing.T) {
+	service, matchRepo, seasonRepo := newSeededLeagueWithSeasons(t, 42)
+
+	knockoutTeams := 4
+	state, err := service.UpdateSettings(1, models.LeagueSettings{KnockoutTeams: &knockoutTeams})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if state.TotalWeeks != 9 || state.LeagueWeeks() != 6 {
+		t.Fatalf("Expected 6 league weeks and 9 in total, got %d/%d", state.LeagueWeeks(), state.TotalWeeks)
+	}
+
+	if _, err := service.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The league phase is untouched by the knockout stage
+	expected := []string{
+		"1-4 1-2", "2-3 1-1", "3-1 3-3", "2-4 1-0", "1-2 3-1", "3-4 5-4",
+		"4-1 0-2", "3-2 2-2", "1-3 1-0", "4-2 2-1", "2-1 0-2", "4-3 1-4",
+	}
+	// Two semi-finals over two legs and the final
+	if len(matchRepo.matches) != len(expected)+5 {
+		t.Fatalf("Expected %d matches, got %d", len(expected)+5, len(matchRepo.matches))
+	}
+	for i, want := range expected {
+		if got := scoreline(&matchRepo.matches[i]); got != want {
+			t.Errorf("League match %d: expected %s, got %s", i+1, want, got)
+		}
+	}
+
+	for _, match := range matchRepo.matches[len(expected):] {
+		if !match.Played || !match.IsKnockout() || match.Week <= 6 {
+			t.Errorf("Unexpected knockout match %+v", match)
+		}
+		if match.Leg == 0 && (!match.Neutral || match.Week != 9) {
+			t.Errorf("Expected the final on neutral ground in week 9, got %+v", match)
+		}
+	}
+
+	season := seasonRepo.seasons[0]
+	bracket, err := service.(*simulationService).knockout.GetBracket(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(bracket.Rounds) != 2 || len(bracket.Rounds[0].Ties) != 2 || bracket.Rounds[1].Name != "Final" {
+		t.Fatalf("Unexpected bracket %+v", bracket.Rounds)
+	}
+
+	// Semi-finals pair 1st with 4th and 2nd with 3rd
+	table := season.Standings
+	semis := bracket.Rounds[0].Ties
+	if semis[0].TeamAID != table[0].TeamID || semis[0].TeamBID != table[3].TeamID ||
+		semis[1].TeamAID != table[1].TeamID || semis[1].TeamBID != table[2].TeamID {
+		t.Errorf("Semi-finals not seeded from the table: %+v", semis)
+	}
+
+	if bracket.ChampionID == nil || season.ChampionTeamID == nil || *season.ChampionTeamID != *bracket.ChampionID {
+		t.Errorf("Expected the final's winner to be the season champion")
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLeagueNotFound     = errors.New("league not found")
+	ErrDefaultLeagueInUse = errors.New("the default league cannot be deleted")
+)
+
+type LeagueService interface {
+	GetAllLeagues() ([]models.League, error)
+	GetLeague(id uint) (*models.League, error)
+	GetDefaultLeague() (*models.League, error)
+	CreateLeague(name string, seed *int64) (*models.League, error)
+	DeleteLeague(id uint) error
+}
+
+type leagueService struct {
+	leagueRepo      repository.LeagueRepository
+	leagueStateRepo repository.LeagueStateRepository
+	teamRepo        repository.TeamRepository
+
+	mu            sync.Mutex
+	defaultLeague *models.League // Once set up
+}
+
+func NewLeagueService(
+	leagueRepo repository.LeagueRepository,
+	leagueStateRepo repository.LeagueStateRepository,
+	teamRepo repository.TeamRepository,
+) LeagueService {
+	return &leagueService{
+		leagueRepo:      leagueRepo,
+		leagueStateRepo: leagueStateRepo,
+		teamRepo:        teamRepo,
+	}
+}
+
+func (s *leagueService) GetAllLeagues() ([]models.League, error) {
+	// Make sure the default league always shows up
+	if _, err := s.GetDefaultLeague(); err != nil {
+		return nil, err
+	}
+	return s.leagueRepo.FindAll()
+}
+
+func (s *leagueService) GetLeague(id uint) (*models.League, error) {
+	league, err := s.leagueRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLeagueNotFound
+	}
+	return league, err
+}
+
+// GetDefaultLeague returns the default league, creating and setting it up on first use
+func (s *leagueService) GetDefaultLeague() (*models.League, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.defaultLeague != nil {
+		return s.defaultLeague, nil
+	}
+
+	league, err := s.leagueRepo.GetDefault()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.setUp(league.ID, nil); err != nil {
+		return nil, err
+	}
+	s.defaultLeague = league
+	return league, nil
+}
+
+// setUp gives a league its state and the default teams if it has none yet. It runs before anything
+// is recorded in the league's audit log, so undo never goes back past it.
+func (s *leagueService) setUp(leagueID uint, seed *int64) error {
+	if _, err := s.leagueStateRepo.Get(leagueID); errors.Is(err, gorm.ErrRecordNotFound) {
+		state := &models.LeagueState{
+			LeagueID:         leagueID,
+			TotalWeeks:       6,
+			Seed:             rand.Int63n(models.MaxSeed),
+			TiebreakerPreset: models.DefaultTiebreakerPreset,
+			FutureEdits:      models.FutureEditsReject,
+		}
+		if seed != nil {
+			state.Seed = *seed
+		}
+		if err := s.leagueStateRepo.Create(state); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+	return s.teamRepo.SeedDefault(leagueID)
+}
+
+// CreateLeague creates a league with its own state and the default teams
+func (s *leagueService) CreateLeague(name string, seed *int64) (*models.League, error) {
+	if _, err := s.leagueRepo.FindByName(name); err == nil {
+		return nil, errors.New("a league with this name already exists")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	league := &models.League{Name: name}
+	if err := s.leagueRepo.Create(league); err != nil {
+		return nil, err
+	}
+
+	if err := s.setUp(league.ID, seed); err != nil {
+		return nil, err
+	}
+	return league, nil
+}
+
+func (s *leagueService) DeleteLeague(id uint) error {
+	league, err := s.GetLeague(id)
+	if err != nil {
+		return err
+	}
+	if league.Name == models.DefaultLeagueName {
+		return ErrDefaultLeagueInUse
+	}
+	return s.leagueRepo.Delete(id)
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+// mockLeagueRepository implements repository.LeagueRepository for testing
+type mockLeagueRepository struct {
+	leagues []models.League
+}
+
+func (m *mockLeagueRepository) Create(league *models.League) error {
+	league.ID = uint(len(m.leagues) + 1)
+	m.leagues = append(m.leagues, *league)
+	return nil
+}
+
+func (m *mockLeagueRepository) FindAll() ([]models.League, error) {
+	return m.leagues, nil
+}
+
+func (m *mockLeagueRepository) FindByID(id uint) (*models.League, error) {
+	for i := range m.leagues {
+		if m.leagues[i].ID == id {
+			return &m.leagues[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockLeagueRepository) FindByName(name string) (*models.League, error) {
+	for i := range m.leagues {
+		if m.leagues[i].Name == name {
+			return &m.leagues[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockLeagueRepository) GetDefault() (*models.League, error) {
+	if league, err := m.FindByName(models.DefaultLeagueName); err == nil {
+		return league, nil
+	}
+	league := &models.League{Name: models.DefaultLeagueName}
+	return league, m.Create(league)
+}
+
+func (m *mockLeagueRepository) Delete(id uint) error {
+	return nil
+}
+
+// missingStateRepository is a mockLeagueStateRepository whose leagues have no state until one is created
+type missingStateRepository struct {
+	mockLeagueStateRepository
+}
+
+func (m *missingStateRepository) Get(leagueID uint) (*models.LeagueState, error) {
+	if m.state == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return m.mockLeagueStateRepository.Get(leagueID)
+}
+
+func TestCreateLeagueSetsUp(t *testing.T) {
+	stateRepo := &missingStateRepository{}
+	teamRepo := &mockTeamRepository{}
+	service := NewLeagueService(&mockLeagueRepository{}, stateRepo, teamRepo)
+
+	seed := int64(99)
+	league, err := service.CreateLeague("Mini League", &seed)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The state and default teams exist before anything can be recorded in the league's log
+	state := stateRepo.state
+	if state == nil || state.LeagueID != league.ID || state.Seed != 99 || state.TotalWeeks != 6 {
+		t.Errorf("Expected the league's state with seed 99, got %+v", state)
+	}
+	if len(teamRepo.teams) != len(models.DefaultTeams()) {
+		t.Errorf("Expected the default teams, got %d", len(teamRepo.teams))
+	}
+}
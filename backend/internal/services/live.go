@@ -0,0 +1,153 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+var ErrWeekLive = errors.New("a week is being played live; wait for the final whistle")
+
+// regularMinutes is the match time a live week's duration stands for
+const regularMinutes = 90
+
+// LiveWatcher follows a week played out in real time: the kick-off, each goal as it goes in, then
+// the week's results once they are saved, or the error that kept them from being saved
+type LiveWatcher interface {
+	KickOff(leagueID uint, week *models.LiveWeek)
+	Goal(leagueID uint, week int, match *models.LiveMatch, goal *models.MatchEvent)
+	FullTime(leagueID uint, matches []models.Match, err error)
+}
+
+// liveRun is a live week with when each of its matches ends
+type liveRun struct {
+	week *models.LiveWeek
+	ends []float64 // Match minute each match ends, extra time and stoppages included
+	end  float64   // Match minute the last match ends
+}
+
+// at returns how long after kick-off a match minute comes in real time
+func (r *liveRun) at(minute float64) time.Duration {
+	return time.Duration(float64(r.week.Duration) * minute / regularMinutes)
+}
+
+// liveGoal is a goal of a live week, minute being the match minute it comes at
+type liveGoal struct {
+	match  int // Index in the live week's matches
+	minute float64
+	event  models.MatchEvent
+}
+
+// PlayLiveWeek plays the next week out over duration of real time for its 90 minutes (extra time
+// running on at the same pace), reporting the goals to watcher as they go in. The results are the
+// ones PlayNextWeek would give, and are saved only once the last match ends. Until then the
+// league's other results, settings and weeks can't be changed.
+func (s *simulationService) PlayLiveWeek(leagueID uint, seed *int64, duration time.Duration, watcher LiveWatcher) (*models.LiveWeek, error) {
+	if s.GetLiveWeek(leagueID) != nil {
+		return nil, ErrWeekLive
+	}
+
+	// The week is simulated without holding liveMu, which guards the live weeks of every league.
+	// Should the league change before the final whistle, saving the week fails on its version.
+	play, err := s.simulateWeek(leagueID, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &liveRun{week: &models.LiveWeek{Week: play.week, Duration: duration}}
+	var goals []liveGoal
+	for _, match := range play.matches {
+		events, ok := play.timelines[match.ID]
+		if !ok {
+			continue
+		}
+		index := len(run.week.Matches)
+		run.week.Matches = append(run.week.Matches, models.LiveMatch{
+			MatchID:      match.ID,
+			HomeTeamID:   match.HomeTeamID,
+			HomeTeamName: match.HomeTeam.Name,
+			AwayTeamID:   match.AwayTeamID,
+			AwayTeamName: match.AwayTeam.Name,
+		})
+
+		// Stoppage time runs on from the end of its period, so a match's clock never goes back
+		clock := 0.0
+		for _, event := range events {
+			clock = max(clock, float64(event.Minute+event.AddedTime))
+			if event.Type == models.MatchEventGoal {
+				goals = append(goals, liveGoal{match: index, minute: clock, event: event})
+			}
+		}
+		run.ends = append(run.ends, clock)
+		run.end = max(run.end, clock)
+	}
+	sort.SliceStable(goals, func(i, j int) bool {
+		return goals[i].minute < goals[j].minute
+	})
+
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+
+	if _, ok := s.live[leagueID]; ok {
+		return nil, ErrWeekLive
+	}
+	run.week.StartedAt = time.Now()
+	s.live[leagueID] = run
+	kickOff := s.liveSnapshot(run)
+	go s.playLive(leagueID, play, run, goals, watcher)
+	return kickOff, nil
+}
+
+// playLive plays a live week's goals at their time, then saves the week at the final whistle
+func (s *simulationService) playLive(leagueID uint, play *weekPlay, run *liveRun, goals []liveGoal, watcher LiveWatcher) {
+	s.liveMu.Lock()
+	kickOff := s.liveSnapshot(run)
+	s.liveMu.Unlock()
+	watcher.KickOff(leagueID, kickOff)
+
+	for i := range goals {
+		goal := &goals[i]
+		time.Sleep(time.Until(run.week.StartedAt.Add(run.at(goal.minute))))
+
+		s.liveMu.Lock()
+		match := &run.week.Matches[goal.match]
+		match.HomeScore, match.AwayScore = goal.event.HomeScore, goal.event.AwayScore
+		score := *match
+		s.liveMu.Unlock()
+		watcher.Goal(leagueID, run.week.Week, &score, &goal.event)
+	}
+	time.Sleep(time.Until(run.week.StartedAt.Add(run.at(run.end))))
+
+	matches, err := s.saveWeek(leagueID, play)
+	s.liveMu.Lock()
+	delete(s.live, leagueID)
+	s.liveMu.Unlock()
+	watcher.FullTime(leagueID, matches, err)
+}
+
+// GetLiveWeek returns the week a league is playing live with the scores so far, nil if none is
+func (s *simulationService) GetLiveWeek(leagueID uint) *models.LiveWeek {
+	s.liveMu.Lock()
+	defer s.liveMu.Unlock()
+
+	run, ok := s.live[leagueID]
+	if !ok {
+		return nil
+	}
+	return s.liveSnapshot(run)
+}
+
+// liveSnapshot copies a live week as it stands. The caller holds liveMu.
+func (s *simulationService) liveSnapshot(run *liveRun) *models.LiveWeek {
+	week := *run.week
+	week.Matches = append([]models.LiveMatch(nil), run.week.Matches...)
+
+	minute := min(float64(time.Since(week.StartedAt))/float64(week.Duration)*regularMinutes, run.end)
+	week.Minute = int(minute)
+	for i := range week.Matches {
+		week.Matches[i].Finished = minute >= run.ends[i]
+	}
+	return &week
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// recordingWatcher keeps what a live week reports
+type recordingWatcher struct {
+	mu      sync.Mutex
+	kickOff *models.LiveWeek
+	goals   []models.MatchEvent
+	scores  []models.LiveMatch
+	matches []models.Match
+	err     error
+	done    chan struct{}
+}
+
+func (w *recordingWatcher) KickOff(_ uint, week *models.LiveWeek) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.kickOff = week
+}
+
+func (w *recordingWatcher) Goal(_ uint, _ int, match *models.LiveMatch, goal *models.MatchEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.goals = append(w.goals, *goal)
+	w.scores = append(w.scores, *match)
+}
+
+func (w *recordingWatcher) FullTime(_ uint, matches []models.Match, err error) {
+	w.matches, w.err = matches, err
+	close(w.done)
+}
+
+func TestPlayLiveWeek(t *testing.T) {
+	instant, instantRepo := newSeededLeague(t, 42)
+	if _, err := instant.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	service, matchRepo := newSeededLeague(t, 42)
+	watcher := &recordingWatcher{done: make(chan struct{})}
+	week, err := service.PlayLiveWeek(1, nil, 300*time.Millisecond, watcher)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if week.Week != 1 || len(week.Matches) != 2 || week.Matches[0].HomeScore != 0 {
+		t.Errorf("Expected week 1 to kick off at 0-0, got %+v", week)
+	}
+
+	// Nothing is saved, nor can anything else change, until the final whistle
+	for _, match := range matchRepo.matches {
+		if match.Played {
+			t.Fatalf("Expected no results saved before the final whistle, got %+v", match)
+		}
+	}
+	if _, err := service.PlayNextWeek(1, nil); !errors.Is(err, ErrWeekLive) {
+		t.Errorf("Expected ErrWeekLive playing another week, got %v", err)
+	}
+	if _, err := service.PlayLiveWeek(1, nil, time.Second, watcher); !errors.Is(err, ErrWeekLive) {
+		t.Errorf("Expected ErrWeekLive starting another live week, got %v", err)
+	}
+	if _, err := service.UpdateMatchResult(1, matchRepo.matches[0].ID, 1, 0); !errors.Is(err, ErrWeekLive) {
+		t.Errorf("Expected ErrWeekLive editing a result, got %v", err)
+	}
+	if err := service.ResetSimulation(1); !errors.Is(err, ErrWeekLive) {
+		t.Errorf("Expected ErrWeekLive resetting, got %v", err)
+	}
+	if live := service.GetLiveWeek(1); live == nil || live.Week != 1 {
+		t.Errorf("Expected week 1 live, got %+v", live)
+	}
+
+	select {
+	case <-watcher.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the final whistle")
+	}
+	if watcher.err != nil {
+		t.Fatalf("Expected the week saved, got %v", watcher.err)
+	}
+	if service.GetLiveWeek(1) != nil {
+		t.Error("Expected no week live after the final whistle")
+	}
+
+	// The week ends as it would have played instantly, each goal reported with the score after it
+	goals := 0
+	for i := range instantRepo.matches {
+		want, got := &instantRepo.matches[i], &matchRepo.matches[i]
+		if want.Played != got.Played || (want.Played && scoreline(want) != scoreline(got)) {
+			t.Errorf("Match %d: expected %+v, got %+v", want.ID, want, got)
+		}
+		if want.Week == 1 {
+			goals += *want.HomeScore + *want.AwayScore
+		}
+	}
+	if watcher.kickOff == nil || len(watcher.goals) != goals || len(watcher.matches) != 2 {
+		t.Fatalf("Expected a kick-off, %d goals and 2 results, got %v, %d and %d", goals, watcher.kickOff != nil,
+			len(watcher.goals), len(watcher.matches))
+	}
+	for i, goal := range watcher.goals {
+		if score := watcher.scores[i]; score.HomeScore != goal.HomeScore || score.AwayScore != goal.AwayScore {
+			t.Errorf("Expected the live score to follow the goals, got %+v for %+v", score, goal)
+		}
+	}
+}
+
+// blockingMatchRepository holds up reading a week's fixtures until released
+type blockingMatchRepository struct {
+	*mockMatchRepository
+	reading, release chan struct{}
+}
+
+func (m *blockingMatchRepository) FindByWeek(leagueID uint, week int) ([]models.Match, error) {
+	close(m.reading)
+	<-m.release
+	return m.mockMatchRepository.FindByWeek(leagueID, week)
+}
+
+func TestPlayLiveWeekSimulatesUnlocked(t *testing.T) {
+	service, matchRepo := newSeededLeague(t, 42)
+	blocking := &blockingMatchRepository{matchRepo, make(chan struct{}), make(chan struct{})}
+	service.(*simulationService).matchRepo = blocking
+
+	watcher := &recordingWatcher{done: make(chan struct{})}
+	started := make(chan error)
+	go func() {
+		_, err := service.PlayLiveWeek(1, nil, 10*time.Millisecond, watcher)
+		started <- err
+	}()
+	<-blocking.reading
+
+	// Other leagues' live weeks can be looked up while this one is being simulated
+	looked := make(chan *models.LiveWeek)
+	go func() { looked <- service.GetLiveWeek(2) }()
+	select {
+	case live := <-looked:
+		if live != nil {
+			t.Errorf("Expected no live week in league 2, got %+v", live)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the live week lookup not to wait for the simulation")
+	}
+
+	close(blocking.release)
+	if err := <-started; err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	select {
+	case <-watcher.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the final whistle")
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrFutureMatch     = errors.New("match is after the current week; allow future edits in the settings to enter it early")
+	ErrMatchNotPlayed  = errors.New("match has not been played")
+	ErrSeasonCompleted = errors.New("a completed season's results can be edited but not cleared; reset the league to replay it")
+)
+
+// UpdateMatchResult enters a league match's result by hand, replacing a simulated one. A match
+// after the current week can only be entered if the league allows future edits; it then counts as
+// played, and its week only plays the other matches. The current week moves on over the weeks
+// the edit leaves fully played, which can end the league phase or the league.
+func (s *simulationService) UpdateMatchResult(leagueID, matchID uint, homeScore, awayScore int) (*models.ResultEdit, error) {
+	state, match, err := s.editableMatch(leagueID, matchID)
+	if err != nil {
+		return nil, err
+	}
+
+	prePlayed := match.Week > state.CurrentWeek
+	if prePlayed && state.FutureEdits != models.FutureEditsAllow {
+		return nil, ErrFutureMatch
+	}
+
+	match.HomeScore = &homeScore
+	match.AwayScore = &awayScore
+	match.Played = true
+	match.Seed = nil
+
+	edit, err := s.saveEdit(leagueID, state, match)
+	if err != nil {
+		return nil, err
+	}
+	edit.PrePlayed = prePlayed
+	return edit, nil
+}
+
+// ClearMatchResult puts a played league match back to unplayed, to be simulated again when its
+// week comes. The current week moves back to the last week still fully played.
+func (s *simulationService) ClearMatchResult(leagueID, matchID uint) (*models.ResultEdit, error) {
+	state, match, err := s.editableMatch(leagueID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if !match.Played {
+		return nil, ErrMatchNotPlayed
+	}
+	// The finished season has been archived, and would be archived again once replayed
+	if state.Completed {
+		return nil, ErrSeasonCompleted
+	}
+
+	match.HomeScore = nil
+	match.AwayScore = nil
+	match.Played = false
+	match.Seed = nil
+
+	edit, err := s.saveEdit(leagueID, state, match)
+	if err != nil {
+		return nil, err
+	}
+	edit.Cleared = true
+	return edit, nil
+}
+
+// editableMatch returns a league match whose result can be changed by hand, with its league state
+func (s *simulationService) editableMatch(leagueID, matchID uint) (*models.LeagueState, *models.Match, error) {
+	if s.GetLiveWeek(leagueID) != nil {
+		return nil, nil, ErrWeekLive
+	}
+
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	match, err := s.matchRepo.FindByID(leagueID, matchID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if match.IsKnockout() {
+		return nil, nil, errors.New("knockout results cannot be edited")
+	}
+	if state.KnockoutStarted() {
+		return nil, nil, errors.New("league results are locked once the knockout bracket is drawn")
+	}
+	return state, match, nil
+}
+
+// saveEdit saves a match whose result was changed by hand in one transaction, together with the
+// league state it leads to and everything that follows from it
+func (s *simulationService) saveEdit(leagueID uint, state *models.LeagueState, match *models.Match) (*models.ResultEdit, error) {
+	edit := &models.ResultEdit{Match: *match, PreviousWeek: state.CurrentWeek}
+	completed := state.Completed
+	err := s.transaction(func(tx *simulationService) error {
+		if err := tx.matchRepo.Update(match); err != nil {
+			return err
+		}
+
+		// The simulated timeline no longer matches the result, nor do the absences it caused
+		if err := tx.eventRepo.DeleteByMatch(leagueID, match.ID); err != nil {
+			return err
+		}
+		if err := tx.availability.ClearMatch(leagueID, match.ID); err != nil {
+			return err
+		}
+
+		week, err := tx.playedWeeks(leagueID, state)
+		if err != nil {
+			return err
+		}
+		state.CurrentWeek = week
+		state.Started = state.Started || match.Played
+		state.Completed = week >= state.TotalWeeks
+
+		// The edit may have played the last matches of the league phase
+		if week > edit.PreviousWeek {
+			if err := tx.knockout.Advance(leagueID, state); err != nil {
+				return err
+			}
+			edit.KnockoutDrawn = state.KnockoutStarted()
+		}
+
+		// The state is saved even when unchanged, so the edit fails if a week was played since
+		// it was read
+		if err := tx.leagueRepo.Update(state); err != nil {
+			return err
+		}
+
+		// Replay the ratings with the new result
+		if err := tx.ratings.UpdateRatings(leagueID, state); err != nil {
+			return err
+		}
+
+		// Keep a record of a season the edit finished, and keep the record of a season finished
+		// before in line with its results
+		switch {
+		case state.Completed && !completed:
+			_, err = tx.seasons.ArchiveSeason(leagueID)
+		case completed:
+			_, err = tx.seasons.RearchiveSeason(leagueID)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	edit.CurrentWeek = state.CurrentWeek
+	edit.Completed = state.Completed && !completed
+	return edit, nil
+}
+
+// playedWeeks returns the last week of the league phase up to which every match has been played
+func (s *simulationService) playedWeeks(leagueID uint, state *models.LeagueState) (int, error) {
+	matches, err := s.matchRepo.FindAll(leagueID)
+	if err != nil {
+		return 0, err
+	}
+
+	scheduled := make(map[int]bool)
+	unplayed := make(map[int]bool)
+	for _, match := range matches {
+		scheduled[match.Week] = true
+		if !match.Played {
+			unplayed[match.Week] = true
+		}
+	}
+
+	week := 0
+	for week < state.LeagueWeeks() && scheduled[week+1] && !unplayed[week+1] {
+		week++
+	}
+	return week, nil
+}
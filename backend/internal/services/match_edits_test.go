@@ -0,0 +1,169 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// weekMatches returns the IDs of a week's matches
+func weekMatches(t *testing.T, matchRepo *mockMatchRepository, week int) []uint {
+	t.Helper()
+
+	matches, _ := matchRepo.FindByWeek(1, week)
+	if len(matches) == 0 {
+		t.Fatalf("Expected matches in week %d", week)
+	}
+	ids := make([]uint, len(matches))
+	for i := range matches {
+		ids[i] = matches[i].ID
+	}
+	return ids
+}
+
+func TestUpdateMatchResultFutureEdits(t *testing.T) {
+	service, matchRepo := newSeededLeague(t, 42)
+	if _, err := service.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Matches after the current week are rejected by default
+	week3 := weekMatches(t, matchRepo, 3)
+	if _, err := service.UpdateMatchResult(1, week3[0], 2, 0); !errors.Is(err, ErrFutureMatch) {
+		t.Fatalf("Expected ErrFutureMatch, got %v", err)
+	}
+
+	allow := models.FutureEditsAllow
+	if _, err := service.UpdateSettings(1, models.LeagueSettings{FutureEdits: &allow}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, id := range week3 {
+		edit, err := service.UpdateMatchResult(1, id, 2, 0)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if !edit.PrePlayed || edit.PreviousWeek != 1 || edit.CurrentWeek != 1 {
+			t.Errorf("Expected a pre-played match leaving week 1 current, got %+v", edit)
+		}
+	}
+
+	// Playing week 2 moves past week 3, whose results were all entered ahead of time
+	if _, err := service.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	state, _ := service.GetCurrentState(1)
+	if state.LeagueState.CurrentWeek != 3 {
+		t.Errorf("Expected week 3 current, got %d", state.LeagueState.CurrentWeek)
+	}
+	for _, id := range week3 {
+		match, _ := matchRepo.FindByID(1, id)
+		if *match.HomeScore != 2 || *match.AwayScore != 0 || match.Seed != nil {
+			t.Errorf("Expected the entered result kept, got %+v", match)
+		}
+	}
+}
+
+func TestUpdateMatchResultCompletesLeague(t *testing.T) {
+	service, matchRepo, seasonRepo := newSeededLeagueWithSeasons(t, 42)
+	allow := models.FutureEditsAllow
+	if _, err := service.UpdateSettings(1, models.LeagueSettings{FutureEdits: &allow}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for range 5 {
+		if _, err := service.PlayNextWeek(1, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	week6 := weekMatches(t, matchRepo, 6)
+	edit, err := service.UpdateMatchResult(1, week6[0], 1, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !edit.PrePlayed || edit.Completed {
+		t.Errorf("Expected the first result to leave the league running, got %+v", edit)
+	}
+
+	edit, err = service.UpdateMatchResult(1, week6[1], 0, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if edit.CurrentWeek != 6 || !edit.Completed || len(seasonRepo.seasons) != 1 {
+		t.Fatalf("Expected the last result to complete and archive the league, got %+v and %d seasons",
+			edit, len(seasonRepo.seasons))
+	}
+
+	// Editing a completed league keeps it completed, updating its archived season in place
+	edit, err = service.UpdateMatchResult(1, week6[1], 1, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if edit.Completed || edit.CurrentWeek != 6 || len(seasonRepo.seasons) != 1 {
+		t.Errorf("Expected nothing else to change, got %+v and %d seasons", edit, len(seasonRepo.seasons))
+	}
+	season := seasonRepo.seasons[0]
+	edited, _ := matchRepo.FindByID(1, week6[1])
+	found := false
+	for _, match := range season.Matches {
+		if match.Week == 6 && match.HomeTeamID == edited.HomeTeamID && match.AwayTeamID == edited.AwayTeamID {
+			found = match.HomeScore == 1 && match.AwayScore == 3
+		}
+	}
+	if season.ID != 1 || season.Number != 1 || !found {
+		t.Errorf("Expected season 1 to hold the edited 1-3, got %+v", season)
+	}
+	goals, archivedGoals := 0, 0
+	for _, match := range matchRepo.matches {
+		goals += *match.HomeScore + *match.AwayScore
+	}
+	for _, standing := range season.Standings {
+		archivedGoals += standing.GoalsFor
+	}
+	if archivedGoals != goals {
+		t.Errorf("Expected the archived table to count %d goals, got %d", goals, archivedGoals)
+	}
+	if _, err := service.ClearMatchResult(1, week6[1]); !errors.Is(err, ErrSeasonCompleted) {
+		t.Errorf("Expected ErrSeasonCompleted, got %v", err)
+	}
+}
+
+func TestClearMatchResult(t *testing.T) {
+	service, matchRepo := newSeededLeague(t, 7)
+	for range 3 {
+		if _, err := service.PlayNextWeek(1, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	week2 := weekMatches(t, matchRepo, 2)
+	kept, _ := matchRepo.FindByID(1, week2[1])
+	edit, err := service.ClearMatchResult(1, week2[0])
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !edit.Cleared || edit.PreviousWeek != 3 || edit.CurrentWeek != 1 {
+		t.Errorf("Expected the current week back to 1, got %+v", edit)
+	}
+	match, _ := matchRepo.FindByID(1, week2[0])
+	if match.Played || match.HomeScore != nil || match.AwayScore != nil {
+		t.Errorf("Expected the match unplayed, got %+v", match)
+	}
+	if _, err := service.ClearMatchResult(1, week2[0]); !errors.Is(err, ErrMatchNotPlayed) {
+		t.Errorf("Expected ErrMatchNotPlayed, got %v", err)
+	}
+
+	// Replaying week 2 only plays the cleared match, and moves on over week 3 again
+	played, err := service.PlayNextWeek(1, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(played) != 2 || played[1].ID != kept.ID || scoreline(&played[1]) != scoreline(kept) {
+		t.Errorf("Expected week 2 replayed with %s kept, got %+v", scoreline(kept), played)
+	}
+	match, _ = matchRepo.FindByID(1, week2[0])
+	state, _ := service.GetCurrentState(1)
+	if !match.Played || state.LeagueState.CurrentWeek != 3 {
+		t.Errorf("Expected the match played and week 3 current, got %+v in week %d", match, state.LeagueState.CurrentWeek)
+	}
+}
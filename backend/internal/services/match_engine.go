@@ -0,0 +1,363 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+const (
+	// eloBaseRating and eloPointsPerPower turn a team's power into an Elo rating
+	eloBaseRating     = 1000.0
+	eloPointsPerPower = 10.0
+	// eloScoreAttempts bounds the redraws of a score that doesn't match the drawn outcome
+	eloScoreAttempts = 100
+)
+
+// MatchEngine simulates the 90 minutes of a match between two teams
+type MatchEngine interface {
+	// ExpectedGoals returns both teams' expected goals over 90 minutes
+	ExpectedGoals(homeTeam, awayTeam *models.Team, neutral bool) (float64, float64)
+	// Simulate draws the score after 90 minutes; a neutral venue has no home advantage
+	Simulate(rng *rand.Rand, homeTeam, awayTeam *models.Team, neutral bool) (int, int)
+	// ScoreProbabilities returns the probability of every score Simulate can draw, indexed by
+	// home then away goals up to the goal cap
+	ScoreProbabilities(homeTeam, awayTeam *models.Team, neutral bool) [][]float64
+}
+
+// newMatchEngine returns the league's match engine with its parameters
+func newMatchEngine(state *models.LeagueState) MatchEngine {
+	params := state.EngineParams
+
+	// Leagues that predate the engine settings have no parameters yet
+	defaults := models.DefaultMatchEngineParams()
+	if params.HomeAdvantage <= 0 {
+		params.HomeAdvantage = defaults.HomeAdvantage
+	}
+	if params.BaseGoals <= 0 {
+		params.BaseGoals = defaults.BaseGoals
+	}
+	if params.MaxGoals <= 0 {
+		params.MaxGoals = defaults.MaxGoals
+	}
+
+	switch state.Engine {
+	case models.MatchEngineDixonColes:
+		return &dixonColesEngine{params: params}
+	case models.MatchEngineElo:
+		return &eloEngine{params: params}
+	case models.MatchEngineMinute:
+		return &minuteEngine{params: params}
+	default:
+		return &poissonEngine{params: params}
+	}
+}
+
+// defaultMatchEngine is the power-ratio Poisson engine with the default parameters
+var defaultMatchEngine MatchEngine = &poissonEngine{params: models.DefaultMatchEngineParams()}
+
+// poissonEngine draws each team's goals independently from a Poisson distribution whose mean
+// is the team's share of the combined power, with the home team's power scaled up
+type poissonEngine struct {
+	params models.MatchEngineParams
+}
+
+func (e *poissonEngine) ExpectedGoals(homeTeam, awayTeam *models.Team, neutral bool) (float64, float64) {
+	return powerRatioGoals(&e.params, homeTeam, awayTeam, neutral)
+}
+
+func (e *poissonEngine) Simulate(rng *rand.Rand, homeTeam, awayTeam *models.Team, neutral bool) (int, int) {
+	homeExpectedGoals, awayExpectedGoals := e.ExpectedGoals(homeTeam, awayTeam, neutral)
+
+	homeGoals := poissonGoals(rng, homeExpectedGoals, e.params.MaxGoals)
+	awayGoals := poissonGoals(rng, awayExpectedGoals, e.params.MaxGoals)
+
+	return homeGoals, awayGoals
+}
+
+func (e *poissonEngine) ScoreProbabilities(homeTeam, awayTeam *models.Team, neutral bool) [][]float64 {
+	homeExpectedGoals, awayExpectedGoals := e.ExpectedGoals(homeTeam, awayTeam, neutral)
+	return poissonGrid(homeExpectedGoals, awayExpectedGoals, e.params.MaxGoals)
+}
+
+// powerRatioGoals splits twice the base expected goals between each side's attack and the
+// opposing defence, with the home team's attack and defence scaled up
+func powerRatioGoals(params *models.MatchEngineParams, homeTeam, awayTeam *models.Team, neutral bool) (float64, float64) {
+	homeAdvantage := params.HomeAdvantage
+	if neutral {
+		homeAdvantage = 1
+	}
+
+	// Calculate effective strengths
+	homeAttack := teamAttack(homeTeam) * homeAdvantage
+	homeDefence := teamDefence(homeTeam) * homeAdvantage
+	awayAttack := teamAttack(awayTeam)
+	awayDefence := teamDefence(awayTeam)
+
+	// Expected goals = base * (own attack relative to attack plus opposing defence)
+	// This means stronger defences reduce your expected goals
+	homeExpectedGoals := params.BaseGoals * 2 * (homeAttack / (homeAttack + awayDefence))
+	awayExpectedGoals := params.BaseGoals * 2 * (awayAttack / (awayAttack + homeDefence))
+
+	return homeExpectedGoals, awayExpectedGoals
+}
+
+// dixonColesEngine uses the power-ratio expected goals but draws the score from the Dixon-Coles
+// joint distribution: independent Poisson probabilities with the 0-0, 1-0, 0-1 and 1-1 scores
+// reweighted by rho (negative rho makes low-scoring draws more likely)
+type dixonColesEngine struct {
+	params models.MatchEngineParams
+}
+
+func (e *dixonColesEngine) ExpectedGoals(homeTeam, awayTeam *models.Team, neutral bool) (float64, float64) {
+	return powerRatioGoals(&e.params, homeTeam, awayTeam, neutral)
+}
+
+func (e *dixonColesEngine) Simulate(rng *rand.Rand, homeTeam, awayTeam *models.Team, neutral bool) (int, int) {
+	homeExpectedGoals, awayExpectedGoals := e.ExpectedGoals(homeTeam, awayTeam, neutral)
+	grid := dixonColesGrid(homeExpectedGoals, awayExpectedGoals, e.params.Rho, e.params.MaxGoals)
+
+	total := 0.0
+	for _, row := range grid {
+		for _, p := range row {
+			total += p
+		}
+	}
+
+	// Inverse transform over the scores up to the cap
+	target := rng.Float64() * total
+	for home, row := range grid {
+		for away, p := range row {
+			if target < p {
+				return home, away
+			}
+			target -= p
+		}
+	}
+	return e.params.MaxGoals, e.params.MaxGoals
+}
+
+func (e *dixonColesEngine) ScoreProbabilities(homeTeam, awayTeam *models.Team, neutral bool) [][]float64 {
+	homeExpectedGoals, awayExpectedGoals := e.ExpectedGoals(homeTeam, awayTeam, neutral)
+	grid := dixonColesGrid(homeExpectedGoals, awayExpectedGoals, e.params.Rho, e.params.MaxGoals)
+
+	total := 0.0
+	for _, row := range grid {
+		for _, p := range row {
+			total += p
+		}
+	}
+	for _, row := range grid {
+		for away := range row {
+			row[away] /= total
+		}
+	}
+	return grid
+}
+
+// dixonColesGrid returns the unnormalized probability of every score up to maxGoals each
+func dixonColesGrid(lambda, mu, rho float64, maxGoals int) [][]float64 {
+	home := poissonProbabilities(lambda, maxGoals)
+	away := poissonProbabilities(mu, maxGoals)
+
+	grid := make([][]float64, maxGoals+1)
+	for x := range grid {
+		grid[x] = make([]float64, maxGoals+1)
+		for y := range grid[x] {
+			grid[x][y] = dixonColesTau(x, y, lambda, mu, rho) * home[x] * away[y]
+		}
+	}
+	return grid
+}
+
+// dixonColesTau is the Dixon-Coles correction of a score, floored at zero for extreme rho
+func dixonColesTau(x, y int, lambda, mu, rho float64) float64 {
+	tau := 1.0
+	switch {
+	case x == 0 && y == 0:
+		tau = 1 - lambda*mu*rho
+	case x == 0 && y == 1:
+		tau = 1 + lambda*rho
+	case x == 1 && y == 0:
+		tau = 1 + mu*rho
+	case x == 1 && y == 1:
+		tau = 1 - rho
+	}
+	return math.Max(tau, 0)
+}
+
+// poissonProbabilities returns P(k) for k = 0..maxGoals of a Poisson distribution with mean lambda
+func poissonProbabilities(lambda float64, maxGoals int) []float64 {
+	probabilities := make([]float64, maxGoals+1)
+	p := math.Exp(-lambda)
+	for k := range probabilities {
+		probabilities[k] = p
+		p *= lambda / float64(k+1)
+	}
+	return probabilities
+}
+
+// poissonGrid returns the probability of every score with independent Poisson goals capped at
+// maxGoals, as poissonGoals draws them: the chance of more goals than the cap goes to the cap
+func poissonGrid(lambda, mu float64, maxGoals int) [][]float64 {
+	home := cappedPoissonProbabilities(lambda, maxGoals)
+	away := cappedPoissonProbabilities(mu, maxGoals)
+
+	grid := make([][]float64, maxGoals+1)
+	for x := range grid {
+		grid[x] = make([]float64, maxGoals+1)
+		for y := range grid[x] {
+			grid[x][y] = home[x] * away[y]
+		}
+	}
+	return grid
+}
+
+// cappedPoissonProbabilities returns P(min(k, maxGoals)) for a Poisson distribution with mean lambda
+func cappedPoissonProbabilities(lambda float64, maxGoals int) []float64 {
+	probabilities := poissonProbabilities(max(lambda, 0), maxGoals)
+	below := 0.0
+	for _, p := range probabilities[:maxGoals] {
+		below += p
+	}
+	probabilities[maxGoals] = max(1-below, 0)
+	return probabilities
+}
+
+// eloEngine first draws the outcome from the Elo win expectancy of the home team, E = 1 / (1 +
+// 10^(-d/400)) with d the rating difference plus the home advantage: a draw with probability
+// drawRate * 4E(1-E), which peaks between equal teams, and a home win with E minus half of that.
+// The score is then drawn from Poisson goals split by E until it matches the outcome.
+type eloEngine struct {
+	params models.MatchEngineParams
+}
+
+// expectancy returns the home team's Elo win expectancy
+func (e *eloEngine) expectancy(homeTeam, awayTeam *models.Team, neutral bool) float64 {
+	difference := matchRating(homeTeam) - matchRating(awayTeam)
+	if !neutral {
+		difference += e.params.EloHomeAdvantage
+	}
+	return eloExpectancy(difference)
+}
+
+func (e *eloEngine) ExpectedGoals(homeTeam, awayTeam *models.Team, neutral bool) (float64, float64) {
+	expectancy := e.expectancy(homeTeam, awayTeam, neutral)
+	return e.params.BaseGoals * 2 * expectancy, e.params.BaseGoals * 2 * (1 - expectancy)
+}
+
+func (e *eloEngine) Simulate(rng *rand.Rand, homeTeam, awayTeam *models.Team, neutral bool) (int, int) {
+	expectancy := e.expectancy(homeTeam, awayTeam, neutral)
+	draw := e.params.EloDrawRate * 4 * expectancy * (1 - expectancy)
+	homeWin := expectancy - draw/2
+
+	// Outcome as the sign of the goal difference
+	outcome := -1
+	if u := rng.Float64(); u < homeWin {
+		outcome = 1
+	} else if u < homeWin+draw {
+		outcome = 0
+	}
+
+	homeExpectedGoals := e.params.BaseGoals * 2 * expectancy
+	awayExpectedGoals := e.params.BaseGoals * 2 * (1 - expectancy)
+	for range eloScoreAttempts {
+		homeGoals := poissonGoals(rng, homeExpectedGoals, e.params.MaxGoals)
+		awayGoals := poissonGoals(rng, awayExpectedGoals, e.params.MaxGoals)
+		if sign(homeGoals-awayGoals) == outcome {
+			return homeGoals, awayGoals
+		}
+	}
+
+	// The smallest score with the outcome
+	return max(outcome, 0), max(-outcome, 0)
+}
+
+// ScoreProbabilities weights the Poisson scores of each outcome to the outcome's probability, as
+// redrawing until the score matches does; the rare run of redraws that never matches ends on the
+// outcome's smallest score
+func (e *eloEngine) ScoreProbabilities(homeTeam, awayTeam *models.Team, neutral bool) [][]float64 {
+	expectancy := e.expectancy(homeTeam, awayTeam, neutral)
+	draw := e.params.EloDrawRate * 4 * expectancy * (1 - expectancy)
+	homeWin := expectancy - draw/2
+	outcomes := map[int]float64{1: homeWin, 0: draw, -1: 1 - homeWin - draw}
+
+	grid := poissonGrid(e.params.BaseGoals*2*expectancy, e.params.BaseGoals*2*(1-expectancy), e.params.MaxGoals)
+	matching := make(map[int]float64, len(outcomes))
+	for home, row := range grid {
+		for away, p := range row {
+			matching[sign(home-away)] += p
+		}
+	}
+	for home, row := range grid {
+		for away, p := range row {
+			if outcome := sign(home - away); matching[outcome] > 0 {
+				row[away] = outcomes[outcome] * p / matching[outcome] * (1 - math.Pow(1-matching[outcome], eloScoreAttempts))
+			}
+		}
+	}
+	for outcome, p := range outcomes {
+		grid[max(outcome, 0)][max(-outcome, 0)] += p * math.Pow(1-matching[outcome], eloScoreAttempts)
+	}
+	return grid
+}
+
+// eloExpectancy returns the expected score (1 for a win, 0.5 for a draw) of the side rated
+// difference points higher
+func eloExpectancy(difference float64) float64 {
+	return 1 / (1 + math.Pow(10, -difference/400))
+}
+
+// eloRating returns a team's current Elo rating, derived from its power until rating updates
+// move it
+func eloRating(team *models.Team) float64 {
+	if team.Rating > 0 {
+		return team.Rating
+	}
+	return powerRating(team)
+}
+
+// matchRating returns the Elo rating a team plays at, less the power missing players and
+// tiredness take away
+func matchRating(team *models.Team) float64 {
+	attack, defence := team.MatchStrength()
+	return eloRating(team) - eloPointsPerPower*float64(team.Power)*(1-(attack+defence)/2)
+}
+
+// powerRating derives a team's Elo rating from its power
+func powerRating(team *models.Team) float64 {
+	return eloBaseRating + eloPointsPerPower*float64(team.Power)
+}
+
+// teamAttack and teamDefence return the strengths the power-ratio engines play a team at: its
+// attack and defence, moved by the power its Elo rating has gained or lost once rating updates
+// move it and scaled down by missing players and tiredness, but never below 1
+func teamAttack(team *models.Team) float64 {
+	attack, _ := team.MatchStrength()
+	return max((float64(team.AttackStrength())+ratingShift(team))*attack, 1)
+}
+
+func teamDefence(team *models.Team) float64 {
+	_, defence := team.MatchStrength()
+	return max((float64(team.DefenceStrength())+ratingShift(team))*defence, 1)
+}
+
+// ratingShift returns the power a team's Elo rating has gained or lost from its starting rating
+func ratingShift(team *models.Team) float64 {
+	if team.Rating > 0 {
+		return (team.Rating - powerRating(team)) / eloPointsPerPower
+	}
+	return 0
+}
+
+// sign returns -1, 0 or 1
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
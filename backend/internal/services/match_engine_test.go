@@ -0,0 +1,199 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+func TestNewMatchEngine(t *testing.T) {
+	tests := map[string]MatchEngine{
+		"":                           &poissonEngine{},
+		models.MatchEnginePoisson:    &poissonEngine{},
+		models.MatchEngineDixonColes: &dixonColesEngine{},
+		models.MatchEngineElo:        &eloEngine{},
+	}
+	for name, expected := range tests {
+		engine := newMatchEngine(&models.LeagueState{Engine: name})
+		if got, want := engineName(engine), engineName(expected); got != want {
+			t.Errorf("Engine %q: expected %s, got %s", name, want, got)
+		}
+	}
+
+	// Missing parameters fall back to the defaults
+	engine := newMatchEngine(&models.LeagueState{}).(*poissonEngine)
+	if engine.params.HomeAdvantage != 1.1 || engine.params.BaseGoals != 1.5 || engine.params.MaxGoals != 7 {
+		t.Errorf("Expected default parameters, got %+v", engine.params)
+	}
+}
+
+func engineName(engine MatchEngine) string {
+	switch engine.(type) {
+	case *poissonEngine:
+		return "poisson"
+	case *dixonColesEngine:
+		return "dixon_coles"
+	case *eloEngine:
+		return "elo"
+	}
+	return "unknown"
+}
+
+func TestDixonColesGrid(t *testing.T) {
+	independent := dixonColesGrid(1.4, 1.1, 0, 7)
+	corrected := dixonColesGrid(1.4, 1.1, -0.1, 7)
+
+	// Negative rho moves probability from 1-0 and 0-1 to 0-0 and 1-1
+	if corrected[0][0] <= independent[0][0] || corrected[1][1] <= independent[1][1] {
+		t.Error("Expected more 0-0 and 1-1 draws with negative rho")
+	}
+	if corrected[1][0] >= independent[1][0] || corrected[0][1] >= independent[0][1] {
+		t.Error("Expected fewer 1-0 and 0-1 results with negative rho")
+	}
+	if corrected[2][1] != independent[2][1] {
+		t.Error("Expected scores above 1-1 to be unchanged")
+	}
+}
+
+func TestMatchEnginesBetweenEqualTeams(t *testing.T) {
+	params := models.DefaultMatchEngineParams()
+	engines := map[string]MatchEngine{
+		models.MatchEnginePoisson:    &poissonEngine{params: params},
+		models.MatchEngineDixonColes: &dixonColesEngine{params: params},
+		models.MatchEngineElo:        &eloEngine{params: params},
+	}
+	team := &models.Team{ID: 1, Power: 80}
+
+	for name, engine := range engines {
+		rng := rand.New(rand.NewSource(1))
+		iterations := 20000
+		goals, homeWins, awayWins, draws := 0, 0, 0, 0
+		for range iterations {
+			home, away := engine.Simulate(rng, team, team, true)
+			if home < 0 || away < 0 || home > params.MaxGoals || away > params.MaxGoals {
+				t.Fatalf("%s: score %d-%d out of range", name, home, away)
+			}
+			goals += home + away
+			switch {
+			case home > away:
+				homeWins++
+			case home < away:
+				awayWins++
+			default:
+				draws++
+			}
+		}
+
+		// On neutral ground equal teams are evenly matched
+		if perTeam := float64(goals) / float64(2*iterations); math.Abs(perTeam-params.BaseGoals) > 0.1 {
+			t.Errorf("%s: expected about %.1f goals per team, got %.2f", name, params.BaseGoals, perTeam)
+		}
+		if diff := math.Abs(float64(homeWins-awayWins)) / float64(iterations); diff > 0.02 {
+			t.Errorf("%s: expected as many wins for both sides, got %d and %d", name, homeWins, awayWins)
+		}
+		if name == models.MatchEngineElo {
+			if rate := float64(draws) / float64(iterations); math.Abs(rate-params.EloDrawRate) > 0.02 {
+				t.Errorf("elo: expected a draw rate of about %.2f, got %.3f", params.EloDrawRate, rate)
+			}
+		}
+	}
+}
+
+func TestMatchEnginesHomeAdvantage(t *testing.T) {
+	params := models.DefaultMatchEngineParams()
+	team := &models.Team{ID: 1, Power: 80}
+
+	for _, engine := range []MatchEngine{
+		&poissonEngine{params: params},
+		&dixonColesEngine{params: params},
+		&eloEngine{params: params},
+	} {
+		home, away := engine.ExpectedGoals(team, team, false)
+		if home <= away {
+			t.Errorf("%s: expected the home side to expect more goals, got %.2f and %.2f", engineName(engine), home, away)
+		}
+		home, away = engine.ExpectedGoals(team, team, true)
+		if home != away {
+			t.Errorf("%s: expected no home advantage on neutral ground, got %.2f and %.2f", engineName(engine), home, away)
+		}
+	}
+}
+
+func TestPlayAllWeeksWithEachEngine(t *testing.T) {
+	results := make(map[string][]string)
+	for _, name := range []string{models.MatchEnginePoisson, models.MatchEngineDixonColes, models.MatchEngineElo} {
+		service, matchRepo := newSeededLeague(t, 42)
+		engine := name
+		state, err := service.UpdateSettings(1, models.LeagueSettings{Engine: &engine})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if state.Engine != name {
+			t.Fatalf("Expected engine %s, got %s", name, state.Engine)
+		}
+
+		if _, err := service.PlayAllWeeks(1, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		for i := range matchRepo.matches {
+			results[name] = append(results[name], scoreline(&matchRepo.matches[i]))
+		}
+	}
+
+	// The default engine still plays the golden season
+	if results[models.MatchEnginePoisson][0] != "1-4 1-2" {
+		t.Errorf("Expected the Poisson engine to keep the golden results, got %v", results[models.MatchEnginePoisson])
+	}
+	if slices.Equal(results[models.MatchEnginePoisson], results[models.MatchEngineElo]) {
+		t.Error("Expected the Elo engine to play a different season from the same seed")
+	}
+}
+
+func TestUpdateSettingsEngineParams(t *testing.T) {
+	service, _ := newSeededLeague(t, 1)
+
+	rho, drawRate := -0.2, 0.3
+	state, err := service.UpdateSettings(1, models.LeagueSettings{
+		EngineParams: &models.MatchEngineSettings{Rho: &rho, EloDrawRate: &drawRate},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if state.EngineParams.Rho != rho || state.EngineParams.EloDrawRate != drawRate {
+		t.Errorf("Expected the parameters to be updated, got %+v", state.EngineParams)
+	}
+
+	invalid := 0.0
+	if _, err := service.UpdateSettings(1, models.LeagueSettings{
+		EngineParams: &models.MatchEngineSettings{BaseGoals: &invalid},
+	}); err == nil {
+		t.Error("Expected an error for zero base goals")
+	}
+
+	unknown := "coin_toss"
+	if _, err := service.UpdateSettings(1, models.LeagueSettings{Engine: &unknown}); err == nil {
+		t.Error("Expected an error for an unknown engine")
+	}
+}
+
+func TestPowerRatioGoalsAttackAgainstDefence(t *testing.T) {
+	params := models.DefaultMatchEngineParams()
+	average := &models.Team{Power: 80, Attack: 80, Defence: 80}
+	open := &models.Team{Power: 80, Attack: 95, Defence: 65}
+
+	// Equal attack and defence play exactly like the power alone
+	home, away := powerRatioGoals(&params, average, &models.Team{Power: 80}, false)
+	if math.Abs(home-1.5*2*88/168) > 1e-12 || math.Abs(away-1.5*2*80/168) > 1e-12 {
+		t.Errorf("Expected the power-ratio expected goals, got %.3f and %.3f", home, away)
+	}
+
+	// An open team scores more and concedes more than an average one of the same power
+	openHome, openAway := powerRatioGoals(&params, open, average, true)
+	evenHome, evenAway := powerRatioGoals(&params, average, average, true)
+	if openHome <= evenHome || openAway <= evenAway {
+		t.Errorf("Expected more goals at both ends, got %.2f-%.2f against %.2f-%.2f", openHome, openAway, evenHome, evenAway)
+	}
+}
@@ -0,0 +1,427 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+const (
+	// Events per team over 90 minutes
+	yellowCardsPerMatch  = 1.8
+	straightRedsPerMatch = 0.04
+	injuriesPerMatch     = 0.25
+	// assistRate is the share of goals with an assist
+	assistRate = 0.7
+	// minPlayers is the fewest players a team is left with; it doesn't lose anyone below that
+	minPlayers = 7
+	// manDownFactor and manUpFactor scale a team's scoring rate on the minute-by-minute engine
+	// for each player it has fewer or more than its opponent
+	manDownFactor = 0.75
+	manUpFactor   = 1.2
+	// timelineSeedPart derives the seed of the timeline built around a drawn score
+	timelineSeedPart = 1
+)
+
+// scorerWeights and assistWeights make forwards score and midfielders set up most goals
+var (
+	scorerWeights = map[string]float64{
+		models.PositionGoalkeeper: 0.01,
+		models.PositionDefender:   0.12,
+		models.PositionMidfielder: 0.3,
+		models.PositionForward:    0.57,
+	}
+	assistWeights = map[string]float64{
+		models.PositionGoalkeeper: 0.02,
+		models.PositionDefender:   0.2,
+		models.PositionMidfielder: 0.45,
+		models.PositionForward:    0.33,
+	}
+)
+
+// timelineEngine is a match engine that plays the 90 minutes event by event, so the score is
+// whatever the goals on the timeline add up to
+type timelineEngine interface {
+	MatchEngine
+	// PlayTimeline plays the 90 minutes onto the timeline with the timeline's random source
+	PlayTimeline(timeline *matchTimeline, homeTeam, awayTeam *models.Team, neutral bool)
+}
+
+// minuteEngine plays a match minute by minute. Each side scores in a minute with its
+// power-ratio expected goals spread over the minutes played, less for each player it has fewer
+// than its opponent and more for each player it has more, around cards, injuries and
+// substitutions.
+type minuteEngine struct {
+	params models.MatchEngineParams
+}
+
+func (e *minuteEngine) ExpectedGoals(homeTeam, awayTeam *models.Team, neutral bool) (float64, float64) {
+	return powerRatioGoals(&e.params, homeTeam, awayTeam, neutral)
+}
+
+func (e *minuteEngine) Simulate(rng *rand.Rand, homeTeam, awayTeam *models.Team, neutral bool) (int, int) {
+	timeline := newMatchTimeline(rng, homeTeam.ID, awayTeam.ID, nil, nil)
+	e.PlayTimeline(timeline, homeTeam, awayTeam, neutral)
+	return timeline.score(false)
+}
+
+// ScoreProbabilities approximates the minute-by-minute goals with capped Poisson goals of the same
+// means, which they come close to while both sides keep eleven players
+func (e *minuteEngine) ScoreProbabilities(homeTeam, awayTeam *models.Team, neutral bool) [][]float64 {
+	homeExpectedGoals, awayExpectedGoals := e.ExpectedGoals(homeTeam, awayTeam, neutral)
+	return poissonGrid(homeExpectedGoals, awayExpectedGoals, e.params.MaxGoals)
+}
+
+func (e *minuteEngine) PlayTimeline(timeline *matchTimeline, homeTeam, awayTeam *models.Team, neutral bool) {
+	homeExpectedGoals, awayExpectedGoals := e.ExpectedGoals(homeTeam, awayTeam, neutral)
+
+	ticks := timeline.regularTicks()
+	rates := [2]float64{homeExpectedGoals / float64(len(ticks)), awayExpectedGoals / float64(len(ticks))}
+	timeline.play(ticks, func(side, _ int) int {
+		if timeline.sides[side].goals >= e.params.MaxGoals {
+			return 0
+		}
+		if timeline.rng.Float64() < rates[side]*timeline.manpowerFactor(side) {
+			return 1
+		}
+		return 0
+	})
+}
+
+// playMatch plays a match's 90 minutes with the engine, the players of both team sheets (see
+// models.TeamSheet) taking part. Engines that don't play event by event draw the score first,
+// and the timeline is built around it with its own seed derived from the match seed, so the
+// score is the same as without a timeline.
+func playMatch(
+	engine MatchEngine, rng *rand.Rand, seed int64, homeTeam, awayTeam *models.Team, homeSheet, awaySheet []*models.Player, neutral bool,
+) *matchTimeline {
+	if engine, ok := engine.(timelineEngine); ok {
+		timeline := newMatchTimeline(rng, homeTeam.ID, awayTeam.ID, homeSheet, awaySheet)
+		engine.PlayTimeline(timeline, homeTeam, awayTeam, neutral)
+		return timeline
+	}
+
+	homeGoals, awayGoals := engine.Simulate(rng, homeTeam, awayTeam, neutral)
+	timelineRNG := rand.New(rand.NewSource(deriveSeed(seed, timelineSeedPart)))
+	timeline := newMatchTimeline(timelineRNG, homeTeam.ID, awayTeam.ID, homeSheet, awaySheet)
+	timeline.playGoals(timeline.regularTicks(), homeGoals, awayGoals)
+	return timeline
+}
+
+// matchTimeline plays out a match minute by minute, keeping track of who is on the pitch
+type matchTimeline struct {
+	rng    *rand.Rand
+	sides  [2]timelineSide // Home, then away
+	events []models.MatchEvent
+}
+
+// timelineSide is one team's side of a match
+type timelineSide struct {
+	teamID        uint
+	sheet         []*models.Player // Players by slot - 1, nil for empty slots
+	onPitch       []int            // Slots of the players on the pitch
+	bench         []int            // Slots of the substitutes yet to come on
+	booked        map[int]bool
+	substitutions int
+	planned       []int // Minutes of the second half with a planned substitution
+	goals         int
+}
+
+// timelineTick is one minute of the match clock
+type timelineTick struct {
+	period string
+	minute int
+	added  int
+}
+
+func newMatchTimeline(rng *rand.Rand, homeTeamID, awayTeamID uint, homeSheet, awaySheet []*models.Player) *matchTimeline {
+	timeline := &matchTimeline{rng: rng}
+	sheets := [2][]*models.Player{homeSheet, awaySheet}
+	for i, teamID := range []uint{homeTeamID, awayTeamID} {
+		side := timelineSide{teamID: teamID, sheet: sheets[i], booked: make(map[int]bool)}
+		for slot := 1; slot <= models.LineupSize+models.BenchSize; slot++ {
+			if slot <= models.LineupSize {
+				side.onPitch = append(side.onPitch, slot)
+			} else {
+				side.bench = append(side.bench, slot)
+			}
+		}
+
+		// Two to four changes in the second half, on top of any forced by injuries
+		for range 2 + rng.Intn(3) {
+			side.planned = append(side.planned, 46+rng.Intn(40))
+		}
+		slices.Sort(side.planned)
+		timeline.sides[i] = side
+	}
+	return timeline
+}
+
+// regularTicks returns the minutes of both halves with their stoppage time, up to three minutes
+// in the first half and one to six in the second
+func (t *matchTimeline) regularTicks() []timelineTick {
+	ticks := periodTicks(models.MatchPeriodFirstHalf, 1, 45, t.rng.Intn(4))
+	return append(ticks, periodTicks(models.MatchPeriodSecondHalf, 46, 90, 1+t.rng.Intn(6))...)
+}
+
+// periodTicks returns the minutes from first to last followed by the added minutes
+func periodTicks(period string, first, last, added int) []timelineTick {
+	var ticks []timelineTick
+	for minute := first; minute <= last; minute++ {
+		ticks = append(ticks, timelineTick{period: period, minute: minute})
+	}
+	for extra := 1; extra <= added; extra++ {
+		ticks = append(ticks, timelineTick{period: period, minute: last, added: extra})
+	}
+	return ticks
+}
+
+// playGoals plays the ticks with the given goals spread over them at random
+func (t *matchTimeline) playGoals(ticks []timelineTick, homeGoals, awayGoals int) {
+	schedule := make([][2]int, len(ticks))
+	for side, goals := range []int{homeGoals, awayGoals} {
+		for range goals {
+			schedule[t.rng.Intn(len(ticks))][side]++
+		}
+	}
+	t.play(ticks, func(side, tick int) int {
+		return schedule[tick][side]
+	})
+}
+
+// play plays the ticks: in each minute each side scores the goals returned by goals, then may
+// pick up cards and injuries and make planned substitutions. The whistles are blown at the end
+// of every period.
+func (t *matchTimeline) play(ticks []timelineTick, goals func(side, tick int) int) {
+	for i, tick := range ticks {
+		for side := range t.sides {
+			for range goals(side, i) {
+				t.goal(tick, side)
+			}
+			t.discipline(tick, side)
+			t.injury(tick, side)
+			t.plannedSubstitution(tick, side)
+		}
+
+		if i == len(ticks)-1 || ticks[i+1].period != tick.period {
+			t.whistle(tick)
+		}
+	}
+}
+
+// playExtraTime adds extra time with the given goals, 15 minutes each way with up to a minute
+// and up to two of stoppage time
+func (t *matchTimeline) playExtraTime(homeGoals, awayGoals int) {
+	ticks := periodTicks(models.MatchPeriodExtraTime, 91, 105, t.rng.Intn(2))
+	ticks = append(ticks, periodTicks(models.MatchPeriodExtraTime, 106, 120, t.rng.Intn(3))...)
+	t.playGoals(ticks, homeGoals, awayGoals)
+}
+
+// score returns the goals of the 90 minutes, or of extra time
+func (t *matchTimeline) score(extraTime bool) (int, int) {
+	return models.TimelineScore(t.events, t.sides[0].teamID, extraTime)
+}
+
+// manpowerFactor scales a side's scoring rate by the difference in players on the pitch
+func (t *matchTimeline) manpowerFactor(side int) float64 {
+	difference := len(t.sides[side].onPitch) - len(t.sides[1-side].onPitch)
+	if difference < 0 {
+		return math.Pow(manDownFactor, float64(-difference))
+	}
+	return math.Pow(manUpFactor, float64(difference))
+}
+
+func (t *matchTimeline) goal(tick timelineTick, side int) {
+	scorer := t.pick(side, scorerWeights, 0)
+	assist := 0
+	if t.rng.Float64() < assistRate {
+		assist = t.pick(side, assistWeights, scorer)
+	}
+
+	t.sides[side].goals++
+	t.record(tick, models.MatchEvent{Type: models.MatchEventGoal, TeamID: t.sides[side].teamID, Slot: scorer, RelatedSlot: assist})
+}
+
+// discipline books a random outfield player, sending the player off for a second booking, and
+// now and then shows a straight red card
+func (t *matchTimeline) discipline(tick timelineTick, side int) {
+	s := &t.sides[side]
+	if t.rng.Float64() < yellowCardsPerMatch/90 {
+		player := t.pickOutfield(side)
+		if player != 0 && (!s.booked[player] || len(s.onPitch) > minPlayers) {
+			t.record(tick, models.MatchEvent{Type: models.MatchEventYellowCard, TeamID: s.teamID, Slot: player})
+			if s.booked[player] {
+				t.sendOff(tick, side, player, models.MatchEventDetailSecondYellow)
+			}
+			s.booked[player] = true
+		}
+	}
+	if t.rng.Float64() < straightRedsPerMatch/90 && len(s.onPitch) > minPlayers {
+		if player := t.pickOutfield(side); player != 0 {
+			t.sendOff(tick, side, player, "")
+		}
+	}
+}
+
+func (t *matchTimeline) sendOff(tick timelineTick, side, player int, detail string) {
+	s := &t.sides[side]
+	s.onPitch = slices.DeleteFunc(s.onPitch, func(slot int) bool { return slot == player })
+	t.record(tick, models.MatchEvent{Type: models.MatchEventRedCard, TeamID: s.teamID, Slot: player, Detail: detail})
+}
+
+// injury injures a random player on the pitch, who is replaced while the team has substitutions
+// left and otherwise leaves the team a player short
+func (t *matchTimeline) injury(tick timelineTick, side int) {
+	s := &t.sides[side]
+	if t.rng.Float64() >= injuriesPerMatch/90 {
+		return
+	}
+	player := s.onPitch[t.rng.Intn(len(s.onPitch))]
+
+	switch {
+	case s.substitutions < models.MaxSubstitutions && len(s.bench) > 0:
+		t.record(tick, models.MatchEvent{Type: models.MatchEventInjury, TeamID: s.teamID, Slot: player})
+		t.substitute(tick, side, player, models.MatchEventDetailInjury)
+	case len(s.onPitch) > minPlayers:
+		s.onPitch = slices.DeleteFunc(s.onPitch, func(slot int) bool { return slot == player })
+		t.record(tick, models.MatchEvent{
+			Type: models.MatchEventInjury, TeamID: s.teamID, Slot: player, Detail: models.MatchEventDetailNoSubstitute,
+		})
+	}
+}
+
+// plannedSubstitution makes the substitutions planned for this minute of the second half,
+// taking off a random outfield player
+func (t *matchTimeline) plannedSubstitution(tick timelineTick, side int) {
+	s := &t.sides[side]
+	if tick.period != models.MatchPeriodSecondHalf || tick.added > 0 {
+		return
+	}
+	for len(s.planned) > 0 && s.planned[0] == tick.minute {
+		s.planned = s.planned[1:]
+		if s.substitutions >= models.MaxSubstitutions || len(s.bench) == 0 {
+			continue
+		}
+		if player := t.pickOutfield(side); player != 0 {
+			t.substitute(tick, side, player, "")
+		}
+	}
+}
+
+// substitute replaces a player with a substitute in the same position, or any substitute when
+// there is none left
+func (t *matchTimeline) substitute(tick timelineTick, side, player int, detail string) {
+	s := &t.sides[side]
+	index := slices.IndexFunc(s.bench, func(slot int) bool {
+		return models.SlotPosition(slot) == models.SlotPosition(player)
+	})
+	if index < 0 {
+		index = t.rng.Intn(len(s.bench))
+	}
+	substitute := s.bench[index]
+	s.bench = slices.Delete(s.bench, index, index+1)
+
+	s.onPitch[slices.Index(s.onPitch, player)] = substitute
+	s.substitutions++
+	t.record(tick, models.MatchEvent{
+		Type: models.MatchEventSubstitution, TeamID: s.teamID, Slot: player, RelatedSlot: substitute, Detail: detail,
+	})
+}
+
+// pick draws a player on the pitch other than exclude, weighted by position and rating; it
+// returns 0 when there is nobody to pick
+func (t *matchTimeline) pick(side int, weights map[string]float64, exclude int) int {
+	s := &t.sides[side]
+	total := 0.0
+	for _, slot := range s.onPitch {
+		if slot != exclude {
+			total += s.weight(slot, weights)
+		}
+	}
+
+	target := t.rng.Float64() * total
+	for _, slot := range s.onPitch {
+		if slot == exclude {
+			continue
+		}
+		if target < s.weight(slot, weights) {
+			return slot
+		}
+		target -= s.weight(slot, weights)
+	}
+	return 0
+}
+
+// weight is the chance of a slot's player being picked: the weight of the slot's position scaled
+// by the player's rating
+func (s *timelineSide) weight(slot int, weights map[string]float64) float64 {
+	return weights[models.SlotPosition(slot)] * float64(s.rating(slot)) / models.DefaultPlayerRating
+}
+
+// rating returns the rating of the slot's player, or the default rating for an empty slot
+func (s *timelineSide) rating(slot int) int {
+	if player := s.player(slot); player != nil {
+		return player.Rating
+	}
+	return models.DefaultPlayerRating
+}
+
+// player returns the slot's player, or nil for an empty slot
+func (s *timelineSide) player(slot int) *models.Player {
+	if slot < 1 || slot > len(s.sheet) {
+		return nil
+	}
+	return s.sheet[slot-1]
+}
+
+// pickOutfield draws an outfield player on the pitch, or 0 when there is none
+func (t *matchTimeline) pickOutfield(side int) int {
+	var outfield []int
+	for _, slot := range t.sides[side].onPitch {
+		if models.SlotPosition(slot) != models.PositionGoalkeeper {
+			outfield = append(outfield, slot)
+		}
+	}
+	if len(outfield) == 0 {
+		return 0
+	}
+	return outfield[t.rng.Intn(len(outfield))]
+}
+
+// whistle ends a period: half-time, full time or the end of extra time
+func (t *matchTimeline) whistle(tick timelineTick) {
+	event := models.MatchEvent{Type: models.MatchEventFullTime}
+	switch {
+	case tick.period == models.MatchPeriodFirstHalf:
+		event.Type = models.MatchEventHalfTime
+	case tick.period == models.MatchPeriodExtraTime && tick.minute == 120:
+		event.Type = models.MatchEventExtraTimeEnd
+	case tick.period == models.MatchPeriodExtraTime:
+		return
+	}
+	t.record(tick, event)
+}
+
+// record appends an event at the tick with the score once it has happened
+func (t *matchTimeline) record(tick timelineTick, event models.MatchEvent) {
+	event.Sequence = len(t.events) + 1
+	event.Period = tick.period
+	event.Minute = tick.minute
+	event.AddedTime = tick.added
+	event.HomeScore = t.sides[0].goals
+	event.AwayScore = t.sides[1].goals
+	for i := range t.sides {
+		if t.sides[i].teamID != event.TeamID || event.TeamID == 0 {
+			continue
+		}
+		if player := t.sides[i].player(event.Slot); player != nil {
+			event.PlayerID = player.ID
+		}
+		if player := t.sides[i].player(event.RelatedSlot); player != nil {
+			event.RelatedPlayerID = player.ID
+		}
+	}
+	t.events = append(t.events, event)
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// mockMatchEventRepository implements repository.MatchEventRepository for testing
+type mockMatchEventRepository struct {
+	events []models.MatchEvent
+}
+
+func (m *mockMatchEventRepository) CreateBatch(events []models.MatchEvent) error {
+	m.events = append(m.events, events...)
+	return nil
+}
+
+func (m *mockMatchEventRepository) FindByMatch(_, matchID uint) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	for _, event := range m.events {
+		if event.MatchID == matchID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockMatchEventRepository) FindByMatches(_ uint, matchIDs []uint) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	for _, id := range matchIDs {
+		found, _ := m.FindByMatch(0, id)
+		events = append(events, found...)
+	}
+	return events, nil
+}
+
+func (m *mockMatchEventRepository) FindByType(_ uint, eventType string) ([]models.MatchEvent, error) {
+	var events []models.MatchEvent
+	for _, event := range m.events {
+		if event.Type == eventType {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockMatchEventRepository) DeleteByMatch(_, matchID uint) error {
+	var kept []models.MatchEvent
+	for _, event := range m.events {
+		if event.MatchID != matchID {
+			kept = append(kept, event)
+		}
+	}
+	m.events = kept
+	return nil
+}
+
+func (m *mockMatchEventRepository) DeleteAll(_ uint) error {
+	m.events = nil
+	return nil
+}
+
+// checkTimeline checks that a played match's timeline adds up to its score and never breaks the
+// laws of the game
+func checkTimeline(t *testing.T, timeline *models.MatchTimeline) {
+	t.Helper()
+	match := &timeline.Match
+
+	home, away := models.TimelineScore(timeline.Events, match.HomeTeamID, false)
+	if home != *match.HomeScore || away != *match.AwayScore {
+		t.Fatalf("Match %d: expected goals adding up to %d-%d, got %d-%d", match.ID, *match.HomeScore, *match.AwayScore, home, away)
+	}
+	if timeline.HalfTimeHomeScore == nil || *timeline.HalfTimeHomeScore > home || *timeline.HalfTimeAwayScore > away {
+		t.Errorf("Match %d: expected a half-time score within the final score", match.ID)
+	}
+
+	substitutions := make(map[uint]int)
+	dismissals := make(map[uint]int)
+	fullTime := false
+	for i, event := range timeline.Events {
+		if event.Sequence != i+1 {
+			t.Fatalf("Match %d: expected event %d to have sequence %d, got %d", match.ID, i, i+1, event.Sequence)
+		}
+		if i > 0 {
+			previous := timeline.Events[i-1]
+			if event.Minute < previous.Minute || event.Minute == previous.Minute && event.AddedTime < previous.AddedTime {
+				t.Fatalf("Match %d: expected events in order, got %+v after %+v", match.ID, event, previous)
+			}
+		}
+
+		switch event.Type {
+		case models.MatchEventGoal:
+			if event.Slot == 0 || event.Slot == event.RelatedSlot {
+				t.Errorf("Match %d: expected a scorer and a different assist, got %+v", match.ID, event)
+			}
+		case models.MatchEventSubstitution:
+			substitutions[event.TeamID]++
+			if event.RelatedSlot <= models.LineupSize {
+				t.Errorf("Match %d: expected a substitute coming on, got %+v", match.ID, event)
+			}
+		case models.MatchEventRedCard:
+			dismissals[event.TeamID]++
+		case models.MatchEventInjury:
+			if event.Detail == models.MatchEventDetailNoSubstitute {
+				dismissals[event.TeamID]++
+			}
+		case models.MatchEventFullTime:
+			fullTime = true
+			if event.HomeScore != home || event.AwayScore != away {
+				t.Errorf("Match %d: expected full time at %d-%d, got %+v", match.ID, home, away, event)
+			}
+		}
+	}
+	if !fullTime {
+		t.Errorf("Match %d: expected a full-time whistle", match.ID)
+	}
+	for teamID := range substitutions {
+		if substitutions[teamID] > models.MaxSubstitutions {
+			t.Errorf("Match %d: expected at most %d substitutions, got %d", match.ID, models.MaxSubstitutions, substitutions[teamID])
+		}
+	}
+	for teamID := range dismissals {
+		if models.LineupSize-dismissals[teamID] < minPlayers {
+			t.Errorf("Match %d: expected at least %d players, got %d", match.ID, minPlayers, models.LineupSize-dismissals[teamID])
+		}
+	}
+}
+
+func TestMatchTimelines(t *testing.T) {
+	for _, engine := range []string{models.MatchEnginePoisson, models.MatchEngineMinute} {
+		service, matchRepo := newSeededLeague(t, 42)
+		if _, err := service.UpdateSettings(1, models.LeagueSettings{Engine: &engine}); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if _, err := service.PlayAllWeeks(1, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		timelines, err := service.GetWeekTimelines(1, 1)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if len(timelines) != 2 {
+			t.Fatalf("%s: expected 2 timelines in week 1, got %d", engine, len(timelines))
+		}
+		for i := range matchRepo.matches {
+			timeline, err := service.GetMatchTimeline(1, matchRepo.matches[i].ID)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			checkTimeline(t, timeline)
+		}
+	}
+}
+
+func TestMatchTimelineDressingKeepsResults(t *testing.T) {
+	// The timeline is built from its own seed, so the score is the engine's draw from the match seed
+	service, matchRepo := newSeededLeague(t, 42)
+	if _, err := service.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	engine := defaultMatchEngine
+	for i, match := range matchRepo.matches[:2] {
+		played := matchRepo.withTeams(match)
+		rng := rand.New(rand.NewSource(deriveSeed(42, 1, int64(i))))
+		home, away := engine.Simulate(rng, &played.HomeTeam, &played.AwayTeam, false)
+		if home != *match.HomeScore || away != *match.AwayScore {
+			t.Errorf("Match %d: expected %d-%d, got %d-%d", match.ID, home, away, *match.HomeScore, *match.AwayScore)
+		}
+	}
+}
+
+func TestUpdateMatchResultClearsTimeline(t *testing.T) {
+	service, matchRepo := newSeededLeague(t, 42)
+	if _, err := service.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	matchID := matchRepo.matches[0].ID
+	if _, err := service.UpdateMatchResult(1, matchID, 4, 4); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	timeline, err := service.GetMatchTimeline(1, matchID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(timeline.Events) != 0 || timeline.HalfTimeHomeScore != nil {
+		t.Errorf("Expected no timeline for an edited result, got %+v", timeline)
+	}
+
+	// The other match of the week keeps its timeline
+	timeline, _ = service.GetMatchTimeline(1, matchRepo.matches[1].ID)
+	if len(timeline.Events) == 0 {
+		t.Error("Expected the other match to keep its timeline")
+	}
+}
+
+func TestMatchTimelineExtraTime(t *testing.T) {
+	timeline := newMatchTimeline(rand.New(rand.NewSource(3)), 1, 2, nil, nil)
+	timeline.playGoals(timeline.regularTicks(), 1, 1)
+	timeline.playExtraTime(2, 0)
+
+	if home, away := timeline.score(false); home != 1 || away != 1 {
+		t.Errorf("Expected 1-1 after 90 minutes, got %d-%d", home, away)
+	}
+	if home, away := timeline.score(true); home != 2 || away != 0 {
+		t.Errorf("Expected 2-0 in extra time, got %d-%d", home, away)
+	}
+	for _, event := range timeline.events {
+		if event.IsGoal(true) && (event.Minute < 91 || event.Minute > 120) {
+			t.Errorf("Expected extra-time goals between 91 and 120 minutes, got %+v", event)
+		}
+	}
+	last := timeline.events[len(timeline.events)-1]
+	if last.Type != models.MatchEventExtraTimeEnd || last.HomeScore != 3 || last.AwayScore != 1 {
+		t.Errorf("Expected extra time to end at 3-1, got %+v", last)
+	}
+}
+
+func TestMinuteEngineExpectedGoals(t *testing.T) {
+	engine := &minuteEngine{params: models.DefaultMatchEngineParams()}
+	home := &models.Team{ID: 1, Power: 80, Attack: 80, Defence: 80}
+	away := &models.Team{ID: 2, Power: 70, Attack: 70, Defence: 70}
+	expectedHome, expectedAway := engine.ExpectedGoals(home, away, false)
+
+	rng := rand.New(rand.NewSource(11))
+	const n = 4000
+	totalHome, totalAway := 0, 0
+	for range n {
+		h, a := engine.Simulate(rng, home, away, false)
+		totalHome += h
+		totalAway += a
+	}
+
+	// Red cards shift the rates a little either way, so the means stay close to expected
+	if math.Abs(float64(totalHome)/n-expectedHome) > 0.1 || math.Abs(float64(totalAway)/n-expectedAway) > 0.1 {
+		t.Errorf("Expected mean goals near %.2f-%.2f, got %.2f-%.2f",
+			expectedHome, expectedAway, float64(totalHome)/n, float64(totalAway)/n)
+	}
+}
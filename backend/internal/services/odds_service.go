@@ -0,0 +1,134 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+	"gorm.io/gorm"
+)
+
+var ErrMatchNotUpcoming = errors.New("odds are only available for unplayed matches")
+
+// likelyScoreCount is the number of most likely scores in a match's odds
+const likelyScoreCount = 5
+
+type OddsService interface {
+	GetMatchOdds(leagueID, matchID uint) (*models.MatchOdds, error)
+	GetFixtureOdds(leagueID uint, matches []models.Match) (map[uint]models.MatchOdds, error)
+}
+
+type oddsService struct {
+	matchRepo    repository.MatchRepository
+	leagueRepo   repository.LeagueStateRepository
+	availability AvailabilityService
+}
+
+func NewOddsService(
+	matchRepo repository.MatchRepository,
+	leagueRepo repository.LeagueStateRepository,
+	availability AvailabilityService,
+) OddsService {
+	return &oddsService{
+		matchRepo:    matchRepo,
+		leagueRepo:   leagueRepo,
+		availability: availability,
+	}
+}
+
+// GetMatchOdds returns the odds of an unplayed match
+func (s *oddsService) GetMatchOdds(leagueID, matchID uint) (*models.MatchOdds, error) {
+	match, err := s.matchRepo.FindByID(leagueID, matchID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if match.Played {
+		return nil, ErrMatchNotUpcoming
+	}
+
+	odds, err := s.GetFixtureOdds(leagueID, []models.Match{*match})
+	if err != nil {
+		return nil, err
+	}
+	result := odds[match.ID]
+	return &result, nil
+}
+
+// GetFixtureOdds returns the odds of the unplayed matches among the given ones by match ID. Each
+// side is played at the strength its week's absences and tiredness leave it, as simulating the
+// week would.
+func (s *oddsService) GetFixtureOdds(leagueID uint, matches []models.Match) (map[uint]models.MatchOdds, error) {
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	engine := newMatchEngine(state)
+
+	odds := make(map[uint]models.MatchOdds)
+	squads := make(map[int]map[uint]*models.TeamAvailability)
+	for _, match := range matches {
+		if match.Played {
+			continue
+		}
+		if _, ok := squads[match.Week]; !ok {
+			availability, err := s.availability.GetWeekAvailability(leagueID, match.Week)
+			if err != nil {
+				return nil, err
+			}
+			squads[match.Week] = make(map[uint]*models.TeamAvailability, len(availability))
+			for i := range availability {
+				squads[match.Week][availability[i].TeamID] = &availability[i]
+			}
+		}
+
+		homeTeam, awayTeam := match.HomeTeam, match.AwayTeam
+		fieldTeam(&homeTeam, squads[match.Week][match.HomeTeamID])
+		fieldTeam(&awayTeam, squads[match.Week][match.AwayTeamID])
+		odds[match.ID] = matchOdds(match.ID, engine.ScoreProbabilities(&homeTeam, &awayTeam, match.Neutral))
+	}
+	return odds, nil
+}
+
+// matchOdds sums a match's score probabilities up into its odds, rounded to three decimals
+func matchOdds(matchID uint, grid [][]float64) models.MatchOdds {
+	odds := models.MatchOdds{MatchID: matchID}
+	var scores []models.ScoreProbability
+	for home, row := range grid {
+		for away, p := range row {
+			switch sign(home - away) {
+			case 1:
+				odds.HomeWin += p
+			case 0:
+				odds.Draw += p
+			default:
+				odds.AwayWin += p
+			}
+			if home+away > 2 {
+				odds.Over25 += p
+			}
+			odds.HomeExpectedGoals += float64(home) * p
+			odds.AwayExpectedGoals += float64(away) * p
+			scores = append(scores, models.ScoreProbability{HomeGoals: home, AwayGoals: away, Probability: p})
+		}
+	}
+	odds.Under25 = 1 - odds.Over25
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].Probability > scores[j].Probability
+	})
+	odds.LikelyScores = scores[:min(likelyScoreCount, len(scores))]
+	rounded := []*float64{&odds.HomeWin, &odds.Draw, &odds.AwayWin, &odds.HomeExpectedGoals,
+		&odds.AwayExpectedGoals, &odds.Over25, &odds.Under25}
+	for i := range odds.LikelyScores {
+		rounded = append(rounded, &odds.LikelyScores[i].Probability)
+	}
+	for _, p := range rounded {
+		*p = math.Round(*p*1000) / 1000
+	}
+	return odds
+}
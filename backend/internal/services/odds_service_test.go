@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+func TestScoreProbabilities(t *testing.T) {
+	home := &models.Team{ID: 1, Power: 85, Attack: 88, Defence: 80}
+	away := &models.Team{ID: 2, Power: 70, Attack: 68, Defence: 74}
+	params := models.DefaultMatchEngineParams()
+	params.MaxGoals = 4
+
+	// The minute-by-minute engine is only approximated
+	engines := []MatchEngine{&poissonEngine{params: params}, &dixonColesEngine{params: params}, &eloEngine{params: params}}
+	for _, engine := range engines {
+		grid := engine.ScoreProbabilities(home, away, false)
+
+		draws := 200000
+		counts := make([][]int, len(grid))
+		for i := range counts {
+			counts[i] = make([]int, len(grid[i]))
+		}
+		rng := rand.New(rand.NewSource(42))
+		for range draws {
+			homeGoals, awayGoals := engine.Simulate(rng, home, away, false)
+			counts[homeGoals][awayGoals]++
+		}
+
+		total := 0.0
+		for x, row := range grid {
+			for y, p := range row {
+				total += p
+				if frequency := float64(counts[x][y]) / float64(draws); math.Abs(frequency-p) > 0.005 {
+					t.Errorf("%s %d-%d: expected a frequency near %.4f, got %.4f", engineName(engine), x, y, p, frequency)
+				}
+			}
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("%s: expected the probabilities to add up to 1, got %.6f", engineName(engine), total)
+		}
+	}
+}
+
+func TestMatchOdds(t *testing.T) {
+	odds := matchOdds(1, [][]float64{
+		{0.1, 0.05, 0.05},
+		{0.2, 0.1, 0.05},
+		{0.15, 0.2, 0.1},
+	})
+
+	if odds.HomeWin != 0.55 || odds.Draw != 0.3 || odds.AwayWin != 0.15 {
+		t.Errorf("Expected 0.55/0.3/0.15, got %.3f/%.3f/%.3f", odds.HomeWin, odds.Draw, odds.AwayWin)
+	}
+	if odds.Over25 != 0.35 || odds.Under25 != 0.65 {
+		t.Errorf("Expected 0.35 over and 0.65 under 2.5 goals, got %.3f and %.3f", odds.Over25, odds.Under25)
+	}
+	if odds.HomeExpectedGoals != 1.25 || odds.AwayExpectedGoals != 0.75 {
+		t.Errorf("Expected 1.25 and 0.75 expected goals, got %.3f and %.3f", odds.HomeExpectedGoals, odds.AwayExpectedGoals)
+	}
+
+	// Most likely first, ties in score order
+	want := []struct {
+		home, away  int
+		probability float64
+	}{{1, 0, 0.2}, {2, 1, 0.2}, {2, 0, 0.15}, {0, 0, 0.1}, {1, 1, 0.1}}
+	if len(odds.LikelyScores) != len(want) {
+		t.Fatalf("Expected %d likely scores, got %+v", len(want), odds.LikelyScores)
+	}
+	for i, score := range want {
+		got := odds.LikelyScores[i]
+		if got.HomeGoals != score.home || got.AwayGoals != score.away || got.Probability != score.probability {
+			t.Errorf("Score %d: expected %d-%d at %.2f, got %+v", i+1, score.home, score.away, score.probability, got)
+		}
+	}
+}
+
+func TestGetMatchOdds(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	teams := make(map[uint]models.Team)
+	for _, team := range teamRepo.teams {
+		teams[team.ID] = team
+	}
+	matchRepo := &mockMatchRepository{teams: teams}
+	leagueRepo := &mockLeagueStateRepository{state: &models.LeagueState{LeagueID: 1, Seed: 42}}
+	availability := NewAvailabilityService(&mockPlayerRepository{}, &mockAbsenceRepository{}, &mockMatchEventRepository{},
+		matchRepo, teamRepo, leagueRepo)
+	service := NewOddsService(matchRepo, leagueRepo, availability)
+
+	matches, err := newFixtureService(teamRepo, matchRepo, leagueRepo, &mockGroupRepository{}).GenerateFixtures(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	odds, err := service.GetMatchOdds(1, matches[0].ID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if outcomes := odds.HomeWin + odds.Draw + odds.AwayWin; math.Abs(outcomes-1) > 0.002 {
+		t.Errorf("Expected the outcomes to add up to 1, got %.3f", outcomes)
+	}
+	home, away := teams[matches[0].HomeTeamID], teams[matches[0].AwayTeamID]
+	lambda, mu := defaultMatchEngine.ExpectedGoals(&home, &away, false)
+	if math.Abs(odds.HomeExpectedGoals-lambda) > 0.01 || math.Abs(odds.AwayExpectedGoals-mu) > 0.01 {
+		t.Errorf("Expected %.3f and %.3f expected goals, got %.3f and %.3f", lambda, mu, odds.HomeExpectedGoals, odds.AwayExpectedGoals)
+	}
+
+	// Only unplayed matches have odds
+	score := 1
+	matchRepo.matches[0].Played, matchRepo.matches[0].HomeScore, matchRepo.matches[0].AwayScore = true, &score, &score
+	if _, err := service.GetMatchOdds(1, matches[0].ID); !errors.Is(err, ErrMatchNotUpcoming) {
+		t.Errorf("Expected ErrMatchNotUpcoming, got %v", err)
+	}
+	all, err := service.GetFixtureOdds(1, matchRepo.matches)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := all[matches[0].ID]; ok || len(all) != len(matches)-1 {
+		t.Errorf("Expected odds for the %d unplayed matches, got %d", len(matches)-1, len(all))
+	}
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPlayerNotFound     = errors.New("player not found")
+	ErrShirtNumberTaken   = errors.New("shirt number already taken in this squad")
+	errUnknownLeaderboard = errors.New("unknown leaderboard")
+)
+
+// Leaderboards of player statistics
+const (
+	LeaderboardGoals   = "goals"
+	LeaderboardAssists = "assists"
+)
+
+type PlayerService interface {
+	GetSquad(leagueID, teamID uint) ([]models.Player, error)
+	CreatePlayer(leagueID, teamID uint, player models.Player) (*models.Player, error)
+	UpdatePlayer(leagueID, teamID, playerID uint, update models.PlayerUpdate) (*models.Player, error)
+	DeletePlayer(leagueID, teamID, playerID uint) error
+	GetLeaderboard(leagueID uint, leaderboard string, limit int) ([]models.PlayerStats, error)
+}
+
+type playerService struct {
+	playerRepo repository.PlayerRepository
+	teamRepo   repository.TeamRepository
+	eventRepo  repository.MatchEventRepository
+}
+
+func NewPlayerService(
+	playerRepo repository.PlayerRepository,
+	teamRepo repository.TeamRepository,
+	eventRepo repository.MatchEventRepository,
+) PlayerService {
+	return &playerService{playerRepo: playerRepo, teamRepo: teamRepo, eventRepo: eventRepo}
+}
+
+func (s *playerService) GetSquad(leagueID, teamID uint) ([]models.Player, error) {
+	if _, err := s.findTeam(leagueID, teamID); err != nil {
+		return nil, err
+	}
+	return s.playerRepo.FindByTeam(leagueID, teamID)
+}
+
+func (s *playerService) CreatePlayer(leagueID, teamID uint, player models.Player) (*models.Player, error) {
+	if _, err := s.findTeam(leagueID, teamID); err != nil {
+		return nil, err
+	}
+
+	player.ID = 0
+	player.LeagueID = leagueID
+	player.TeamID = teamID
+	if err := s.checkShirtNumber(&player); err != nil {
+		return nil, err
+	}
+	if err := s.playerRepo.Create(&player); err != nil {
+		return nil, err
+	}
+	return &player, nil
+}
+
+// UpdatePlayer changes a player of the team. Squads can change at any time; the player's goals
+// and assists so far stay theirs.
+func (s *playerService) UpdatePlayer(leagueID, teamID, playerID uint, update models.PlayerUpdate) (*models.Player, error) {
+	player, err := s.findPlayer(leagueID, teamID, playerID)
+	if err != nil {
+		return nil, err
+	}
+
+	update.Apply(player)
+	if err := s.checkShirtNumber(player); err != nil {
+		return nil, err
+	}
+	if err := s.playerRepo.Update(player); err != nil {
+		return nil, err
+	}
+	return player, nil
+}
+
+// DeletePlayer removes a player from the team. Their goals and assists drop off the
+// leaderboards, while past timelines keep the player's ID.
+func (s *playerService) DeletePlayer(leagueID, teamID, playerID uint) error {
+	if _, err := s.findPlayer(leagueID, teamID, playerID); err != nil {
+		return err
+	}
+	return s.playerRepo.Delete(leagueID, playerID)
+}
+
+// GetLeaderboard ranks the league's players by goals or assists this season, the other count
+// breaking ties and then the name. Players without any are left out; a positive limit keeps
+// only the top of the board.
+func (s *playerService) GetLeaderboard(leagueID uint, leaderboard string, limit int) ([]models.PlayerStats, error) {
+	if leaderboard != LeaderboardGoals && leaderboard != LeaderboardAssists {
+		return nil, errUnknownLeaderboard
+	}
+
+	goals, err := s.eventRepo.FindByType(leagueID, models.MatchEventGoal)
+	if err != nil {
+		return nil, err
+	}
+	players, err := s.playerRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	teamNames := make(map[uint]string, len(teams))
+	for _, team := range teams {
+		teamNames[team.ID] = team.Name
+	}
+	stats := make(map[uint]*models.PlayerStats, len(players))
+	for _, player := range players {
+		stats[player.ID] = &models.PlayerStats{
+			PlayerID:    player.ID,
+			PlayerName:  player.Name,
+			TeamID:      player.TeamID,
+			TeamName:    teamNames[player.TeamID],
+			Position:    player.Position,
+			ShirtNumber: player.ShirtNumber,
+		}
+	}
+
+	// Each goal counts to its scorer and to the player who set it up
+	for _, goal := range goals {
+		if stat, ok := stats[goal.PlayerID]; ok {
+			stat.Goals++
+		}
+		if stat, ok := stats[goal.RelatedPlayerID]; ok {
+			stat.Assists++
+		}
+	}
+
+	board := make([]models.PlayerStats, 0, len(stats))
+	for _, stat := range stats {
+		if leaderboard == LeaderboardGoals && stat.Goals > 0 || leaderboard == LeaderboardAssists && stat.Assists > 0 {
+			board = append(board, *stat)
+		}
+	}
+	sort.Slice(board, func(i, j int) bool {
+		a, b := board[i], board[j]
+		first, second := [2]int{a.Goals, a.Assists}, [2]int{b.Goals, b.Assists}
+		if leaderboard == LeaderboardAssists {
+			first, second = [2]int{a.Assists, a.Goals}, [2]int{b.Assists, b.Goals}
+		}
+		if first != second {
+			return first[0] > second[0] || first[0] == second[0] && first[1] > second[1]
+		}
+		if a.PlayerName != b.PlayerName {
+			return a.PlayerName < b.PlayerName
+		}
+		return a.PlayerID < b.PlayerID
+	})
+
+	if limit > 0 && len(board) > limit {
+		board = board[:limit]
+	}
+	return board, nil
+}
+
+func (s *playerService) findTeam(leagueID, teamID uint) (*models.Team, error) {
+	team, err := s.teamRepo.FindByID(leagueID, teamID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTeamNotFound
+	}
+	return team, err
+}
+
+// findPlayer returns a player of the team
+func (s *playerService) findPlayer(leagueID, teamID, playerID uint) (*models.Player, error) {
+	if _, err := s.findTeam(leagueID, teamID); err != nil {
+		return nil, err
+	}
+
+	player, err := s.playerRepo.FindByID(leagueID, playerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || err == nil && player.TeamID != teamID {
+		return nil, ErrPlayerNotFound
+	}
+	return player, err
+}
+
+// checkShirtNumber makes sure no teammate wears the player's number
+func (s *playerService) checkShirtNumber(player *models.Player) error {
+	squad, err := s.playerRepo.FindByTeam(player.LeagueID, player.TeamID)
+	if err != nil {
+		return err
+	}
+	for _, teammate := range squad {
+		if teammate.ID != player.ID && teammate.ShirtNumber == player.ShirtNumber {
+			return ErrShirtNumberTaken
+		}
+	}
+	return nil
+}
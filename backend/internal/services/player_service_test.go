@@ -0,0 +1,196 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// mockPlayerRepository implements repository.PlayerRepository for testing
+type mockPlayerRepository struct {
+	players []models.Player
+}
+
+func (m *mockPlayerRepository) Create(player *models.Player) error {
+	player.ID = uint(len(m.players) + 1)
+	m.players = append(m.players, *player)
+	return nil
+}
+
+func (m *mockPlayerRepository) FindAll(_ uint) ([]models.Player, error) {
+	return append([]models.Player(nil), m.players...), nil
+}
+
+func (m *mockPlayerRepository) FindByTeam(_, teamID uint) ([]models.Player, error) {
+	var players []models.Player
+	for _, player := range m.players {
+		if player.TeamID == teamID {
+			players = append(players, player)
+		}
+	}
+	return players, nil
+}
+
+func (m *mockPlayerRepository) FindByID(_, id uint) (*models.Player, error) {
+	for _, player := range m.players {
+		if player.ID == id {
+			return &player, nil
+		}
+	}
+	return nil, errors.New("player not found")
+}
+
+func (m *mockPlayerRepository) Update(player *models.Player) error {
+	for i := range m.players {
+		if m.players[i].ID == player.ID {
+			m.players[i] = *player
+			return nil
+		}
+	}
+	return errors.New("player not found")
+}
+
+func (m *mockPlayerRepository) Delete(_, id uint) error {
+	for i := range m.players {
+		if m.players[i].ID == id {
+			m.players = append(m.players[:i], m.players[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// fullSquads gives every team a full match-day squad, its number 9 the best-rated forward
+func fullSquads(teams int) *mockPlayerRepository {
+	positions := []string{
+		models.PositionGoalkeeper, models.PositionDefender, models.PositionDefender, models.PositionDefender,
+		models.PositionDefender, models.PositionMidfielder, models.PositionMidfielder, models.PositionMidfielder,
+		models.PositionForward, models.PositionForward, models.PositionForward,
+		models.PositionGoalkeeper, models.PositionDefender, models.PositionDefender, models.PositionMidfielder,
+		models.PositionMidfielder, models.PositionForward, models.PositionForward,
+	}
+	repo := &mockPlayerRepository{}
+	for team := 1; team <= teams; team++ {
+		for i, position := range positions {
+			rating := 60
+			if i == 8 {
+				rating = 95
+			}
+			_ = repo.Create(&models.Player{
+				LeagueID:    1,
+				TeamID:      uint(team),
+				Name:        fmt.Sprintf("Player %d-%d", team, i+1),
+				Position:    position,
+				Rating:      rating,
+				ShirtNumber: i + 1,
+			})
+		}
+	}
+	return repo
+}
+
+func TestPlayerCRUD(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	playerRepo := &mockPlayerRepository{}
+	service := NewPlayerService(playerRepo, teamRepo, &mockMatchEventRepository{})
+
+	player, err := service.CreatePlayer(1, 3, models.Player{Name: "Striker", Position: models.PositionForward, Rating: 90, ShirtNumber: 9})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if player.TeamID != 3 || player.LeagueID != 1 {
+		t.Errorf("Expected the player in team 3 of league 1, got %+v", player)
+	}
+
+	// Shirt numbers are unique within a squad, not across squads
+	if _, err := service.CreatePlayer(1, 3, models.Player{Name: "Copy", Position: models.PositionForward, Rating: 50, ShirtNumber: 9}); !errors.Is(err, ErrShirtNumberTaken) {
+		t.Errorf("Expected ErrShirtNumberTaken, got %v", err)
+	}
+	if _, err := service.CreatePlayer(1, 1, models.Player{Name: "Other", Position: models.PositionForward, Rating: 50, ShirtNumber: 9}); err != nil {
+		t.Errorf("Expected another team to use number 9, got %v", err)
+	}
+
+	rating := 93
+	updated, err := service.UpdatePlayer(1, 3, player.ID, models.PlayerUpdate{Rating: &rating})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if updated.Rating != 93 || updated.Name != "Striker" {
+		t.Errorf("Expected only the rating to change, got %+v", updated)
+	}
+
+	// A player can only be reached through their own team
+	if _, err := service.UpdatePlayer(1, 1, player.ID, models.PlayerUpdate{Rating: &rating}); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+	if err := service.DeletePlayer(1, 1, player.ID); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("Expected ErrPlayerNotFound, got %v", err)
+	}
+
+	if err := service.DeletePlayer(1, 3, player.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	squad, _ := service.GetSquad(1, 3)
+	if len(squad) != 0 {
+		t.Errorf("Expected an empty squad, got %+v", squad)
+	}
+}
+
+func TestLeaderboards(t *testing.T) {
+	playerRepo := fullSquads(4)
+	eventRepo := &mockMatchEventRepository{}
+	simulation, matchRepo, _ := newSeededLeagueWithSquads(t, 42, playerRepo, eventRepo, &mockAbsenceRepository{})
+	if _, err := simulation.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Squads only decide who scores, not the results, while the stand-ins for absent players are
+	// as good as them
+	plain, plainMatches := newSeededLeague(t, 42)
+	if _, err := plain.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i := range matchRepo.matches {
+		if scoreline(&matchRepo.matches[i]) != scoreline(&plainMatches.matches[i]) {
+			t.Fatalf("Expected the same results with squads, got %s and %s",
+				scoreline(&matchRepo.matches[i]), scoreline(&plainMatches.matches[i]))
+		}
+	}
+
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	service := NewPlayerService(playerRepo, teamRepo, eventRepo)
+	scorers, err := service.GetLeaderboard(1, LeaderboardGoals, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Every goal of the season is credited, as the squads fill every slot
+	goals, credited := 0, 0
+	for _, match := range matchRepo.matches {
+		goals += *match.HomeScore + *match.AwayScore
+	}
+	for i, line := range scorers {
+		credited += line.Goals
+		if i > 0 && line.Goals > scorers[i-1].Goals {
+			t.Errorf("Expected scorers by goals, got %+v", scorers)
+		}
+	}
+	if credited != goals {
+		t.Errorf("Expected %d goals credited, got %d", goals, credited)
+	}
+	if scorers[0].Position != models.PositionForward || scorers[0].TeamName == "" {
+		t.Errorf("Expected a forward with a team at the top, got %+v", scorers[0])
+	}
+
+	assists, err := service.GetLeaderboard(1, LeaderboardAssists, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(assists) != 3 || assists[0].Assists < assists[2].Assists {
+		t.Errorf("Expected the top three by assists, got %+v", assists)
+	}
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// raceSearchNodes bounds the outcomes a single race search looks at before it gives up, leaving
+// the place neither clinched nor lost and its points needed an upper bound, and the race marked
+// inexact
+const raceSearchNodes = 10000
+
+// markRaces works out each team's race for the top places of its table from the remaining league
+// matches between the table's teams: for first place when title is set, and for the top
+// qualifiers places when there are any. Races are decided on points, which are all that can be
+// known ahead: a rival finishing level counts against clinching a place and for staying in the
+// race for it. Once the table has no matches left, its places are final.
+func markRaces(table []models.TeamStanding, matches []models.Match, title bool, qualifiers int) {
+	index := make(map[uint]int, len(table))
+	points := make([]int, len(table))
+	for i, standing := range table {
+		index[standing.TeamID] = i
+		points[i] = standing.Points
+	}
+
+	var remaining [][2]int
+	for _, match := range matches {
+		home, okHome := index[match.HomeTeamID]
+		away, okAway := index[match.AwayTeamID]
+		if !match.Played && !match.IsKnockout() && okHome && okAway {
+			remaining = append(remaining, [2]int{home, away})
+		}
+	}
+
+	for i := range table {
+		if title {
+			table[i].TitleRace = placeRace(points, remaining, i, 1)
+		}
+		if qualifiers > 0 && qualifiers < len(table) {
+			table[i].QualificationRace = placeRace(points, remaining, i, qualifiers)
+		}
+	}
+}
+
+// placeRace works out the race of the team at position team for the top places of the table
+func placeRace(points []int, remaining [][2]int, team, places int) *models.PlaceRace {
+	race := &models.PlaceRace{Places: places, Exact: true}
+	if len(remaining) == 0 {
+		race.Clinched = team < places
+		race.Eliminated = !race.Clinched
+		if race.Clinched {
+			race.PointsNeeded = new(int)
+		}
+		return race
+	}
+
+	search := newRaceSearch(points, remaining, team, places)
+	defer func() { race.Exact = !search.gaveUp }()
+	race.Clinched = !search.canOvertake(0)
+	if race.Clinched {
+		race.PointsNeeded = new(int)
+		return race
+	}
+	race.Eliminated = !search.canStayAlive()
+	if race.Eliminated {
+		return race
+	}
+
+	// More points only ever help, so the fewest that guarantee the place can be bisected
+	most := 3 * search.left[team]
+	if search.canOvertake(most) {
+		return race
+	}
+	low, high := 0, most
+	for high-low > 1 {
+		mid := (low + high) / 2
+		if search.canOvertake(mid) {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	race.PointsNeeded = &high
+	return race
+}
+
+// raceSearch looks through the outcomes of the remaining matches for one team's race, every
+// match a home win, a draw or an away win. The search gives up after raceSearchNodes outcomes,
+// answering as if the outcome it looks for exists so that nothing is claimed it didn't prove, and
+// remembers it gave up.
+type raceSearch struct {
+	points    []int
+	remaining [][2]int
+	team      int
+	places    int
+	left      []int // Remaining matches of each team
+	nodes     int
+	gaveUp    bool // A search ran out of nodes, so the race may be less settled than it is
+}
+
+func newRaceSearch(points []int, remaining [][2]int, team, places int) *raceSearch {
+	left := make([]int, len(points))
+	for _, match := range remaining {
+		left[match[0]]++
+		left[match[1]]++
+	}
+	return &raceSearch{
+		points:    points,
+		remaining: remaining,
+		team:      team,
+		places:    places,
+		left:      left,
+	}
+}
+
+// split returns the remaining matches of the team and those between its rivals
+func (s *raceSearch) split() ([][2]int, [][2]int) {
+	var own, others [][2]int
+	for _, match := range s.remaining {
+		if match[0] == s.team || match[1] == s.team {
+			own = append(own, match)
+		} else {
+			others = append(others, match)
+		}
+	}
+	return own, others
+}
+
+// canOvertake tells whether the team can take at least earned more points and still have as many
+// rivals as there are places finish level with it or above
+func (s *raceSearch) canOvertake(earned int) bool {
+	s.nodes = 0
+	points := append([]int(nil), s.points...)
+	left := append([]int(nil), s.left...)
+	own, others := s.split()
+
+	// The team's own results, its losses first
+	var playOwn func(i, taken int) bool
+	playOwn = func(i, taken int) bool {
+		if taken+3*(len(own)-i) < earned {
+			return false
+		}
+		if s.nodes++; s.nodes > raceSearchNodes {
+			s.gaveUp = true
+			return true
+		}
+		if s.reachable(points, left, s.points[s.team]+max(taken, earned)) < s.places {
+			return false
+		}
+		if i == len(own) {
+			return s.catchUp(points, left, others, 0, points[s.team])
+		}
+		rival := own[i][0]
+		if rival == s.team {
+			rival = own[i][1]
+		}
+		left[s.team]--
+		left[rival]--
+		defer func() {
+			left[s.team]++
+			left[rival]++
+		}()
+		for _, result := range [][2]int{{0, 3}, {1, 1}, {3, 0}} {
+			points[s.team] += result[0]
+			points[rival] += result[1]
+			found := playOwn(i+1, taken+result[0])
+			points[s.team] -= result[0]
+			points[rival] -= result[1]
+			if found {
+				return true
+			}
+		}
+		return false
+	}
+	return playOwn(0, 0)
+}
+
+// catchUp tells whether the rivals' matches from i on can bring as many rivals as there are
+// places to threshold points or more
+func (s *raceSearch) catchUp(points, left []int, matches [][2]int, i, threshold int) bool {
+	if s.nodes++; s.nodes > raceSearchNodes {
+		s.gaveUp = true
+		return true
+	}
+
+	there := 0
+	for rival := range points {
+		if rival != s.team && points[rival] >= threshold {
+			there++
+		}
+	}
+	if there >= s.places {
+		return true
+	}
+	if i == len(matches) || s.reachable(points, left, threshold) < s.places {
+		return false
+	}
+
+	// A rival still chasing takes every point from one who isn't
+	home, away := matches[i][0], matches[i][1]
+	chasing := func(rival int) bool {
+		return points[rival] < threshold && points[rival]+3*left[rival] >= threshold
+	}
+	results := [][2]int{{3, 0}, {1, 1}, {0, 3}}
+	switch {
+	case chasing(home) && !chasing(away):
+		results = results[:1]
+	case !chasing(home) && chasing(away):
+		results = results[2:]
+	case !chasing(home) && !chasing(away):
+		results = results[:1]
+	case threshold-points[away] > threshold-points[home]:
+		// The side further behind wins first
+		results = [][2]int{{0, 3}, {1, 1}, {3, 0}}
+	}
+
+	left[home]--
+	left[away]--
+	defer func() {
+		left[home]++
+		left[away]++
+	}()
+	for _, result := range results {
+		points[home] += result[0]
+		points[away] += result[1]
+		found := s.catchUp(points, left, matches, i+1, threshold)
+		points[home] -= result[0]
+		points[away] -= result[1]
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// reachable counts the rivals who can still get to threshold points
+func (s *raceSearch) reachable(points, left []int, threshold int) int {
+	count := 0
+	for rival := range points {
+		if rival != s.team && points[rival]+3*left[rival] >= threshold {
+			count++
+		}
+	}
+	return count
+}
+
+// canStayAlive tells whether the team, winning every remaining match, can have fewer rivals than
+// there are places finish above it
+func (s *raceSearch) canStayAlive() bool {
+	s.nodes = 0
+	points := append([]int(nil), s.points...)
+	left := append([]int(nil), s.left...)
+	own, others := s.split()
+	for _, match := range own {
+		points[s.team] += 3
+		left[match[0]]--
+		left[match[1]]--
+	}
+	return s.holdBack(points, left, others, 0, points[s.team])
+}
+
+// holdBack tells whether the rivals' matches from i on can leave fewer rivals than there are
+// places above threshold points
+func (s *raceSearch) holdBack(points, left []int, matches [][2]int, i, threshold int) bool {
+	if s.nodes++; s.nodes > raceSearchNodes {
+		s.gaveUp = true
+		return true
+	}
+
+	above := 0
+	for rival := range points {
+		if rival != s.team && points[rival] > threshold {
+			above++
+		}
+	}
+	if above >= s.places {
+		return false
+	}
+	if i == len(matches) {
+		return true
+	}
+
+	// A rival who can be kept back loses to one who is already above or can't get there
+	home, away := matches[i][0], matches[i][1]
+	open := func(rival int) bool {
+		return points[rival] <= threshold && points[rival]+3*left[rival] > threshold
+	}
+	results := [][2]int{{3, 0}, {1, 1}, {0, 3}}
+	switch {
+	case open(home) && !open(away):
+		results = results[2:]
+	case !open(home) && open(away), !open(home) && !open(away):
+		results = results[:1]
+	case threshold-points[away] > threshold-points[home]:
+		// The side with more room to spare wins first
+		results = [][2]int{{0, 3}, {1, 1}, {3, 0}}
+	}
+
+	left[home]--
+	left[away]--
+	defer func() {
+		left[home]++
+		left[away]++
+	}()
+	for _, result := range results {
+		points[home] += result[0]
+		points[away] += result[1]
+		found := s.holdBack(points, left, matches, i+1, threshold)
+		points[home] -= result[0]
+		points[away] -= result[1]
+		if found {
+			return true
+		}
+	}
+	return false
+}
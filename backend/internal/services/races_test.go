@@ -0,0 +1,205 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+func TestPlaceRace(t *testing.T) {
+	// A has finished on 11 points; B and C can each reach 12, but not both as they play each other
+	points := []int{11, 9, 9, 2}
+	race := placeRace(points, [][2]int{{1, 2}}, 0, 2)
+	if !race.Clinched || race.PointsNeeded == nil || *race.PointsNeeded != 0 {
+		t.Errorf("Expected A sure of the top two, got %+v", race)
+	}
+
+	// Unless B has another match to go
+	remaining := [][2]int{{1, 2}, {3, 1}}
+	if race := placeRace(points, remaining, 0, 2); race.Clinched {
+		t.Errorf("Expected A to be caught by B and C, got %+v", race)
+	}
+	if race := placeRace(points, remaining, 0, 1); race.Clinched || race.Eliminated || race.PointsNeeded != nil {
+		t.Errorf("Expected A still in the title race without a match to make sure of it, got %+v", race)
+	}
+	if race := placeRace(points, remaining, 3, 2); !race.Eliminated {
+		t.Errorf("Expected D out of the top two, got %+v", race)
+	}
+
+	// B needs a draw with C and a win over D to be sure of first place ahead of A's 11
+	race = placeRace(points, remaining, 1, 1)
+	if race.Clinched || race.Eliminated || race.PointsNeeded == nil || *race.PointsNeeded != 4 {
+		t.Errorf("Expected B to need 4 more points for first place, got %+v", race)
+	}
+	if !race.Exact {
+		t.Errorf("Expected a small race worked out exactly, got %+v", race)
+	}
+}
+
+func TestPlaceRaceInexact(t *testing.T) {
+	// Twenty teams level with a whole round-robin to go are too many outcomes to look through
+	points := make([]int, 20)
+	var remaining [][2]int
+	for home := range points {
+		for away := home + 1; away < len(points); away++ {
+			remaining = append(remaining, [2]int{home, away})
+		}
+	}
+	race := placeRace(points, remaining, 0, 8)
+	if race.Exact || race.Clinched || race.Eliminated {
+		t.Errorf("Expected the race left open and marked inexact, got %+v", race)
+	}
+}
+
+func TestPlaceRaceExhaustive(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for round := range 200 {
+		teams := 3 + rng.Intn(3)
+		points := make([]int, teams)
+		for i := range points {
+			points[i] = rng.Intn(10)
+		}
+		var remaining [][2]int
+		for home := range teams {
+			for away := range teams {
+				if home != away && rng.Intn(4) == 0 && len(remaining) < 7 {
+					remaining = append(remaining, [2]int{home, away})
+				}
+			}
+		}
+		if len(remaining) == 0 {
+			continue
+		}
+
+		outcomes := allOutcomes(points, remaining)
+		for team := range teams {
+			for places := 1; places < teams; places++ {
+				got := placeRace(points, remaining, team, places)
+				want := bruteForceRace(outcomes, remaining, team, places)
+				if got.Clinched != want.Clinched || got.Eliminated != want.Eliminated ||
+					(got.PointsNeeded == nil) != (want.PointsNeeded == nil) ||
+					got.PointsNeeded != nil && *got.PointsNeeded != *want.PointsNeeded {
+					t.Fatalf("Round %d, team %d of %v with %v for %d places: expected %s, got %s",
+						round, team, points, remaining, places, describeRace(want), describeRace(got))
+				}
+			}
+		}
+	}
+}
+
+// raceOutcome is one way the remaining matches can end: every team's final points and how many
+// points each team took from its remaining matches
+type raceOutcome struct {
+	points []int
+	earned []int
+}
+
+func allOutcomes(points []int, remaining [][2]int) []raceOutcome {
+	var outcomes []raceOutcome
+	final := append([]int(nil), points...)
+	earned := make([]int, len(points))
+	var play func(i int)
+	play = func(i int) {
+		if i == len(remaining) {
+			outcomes = append(outcomes, raceOutcome{append([]int(nil), final...), append([]int(nil), earned...)})
+			return
+		}
+		home, away := remaining[i][0], remaining[i][1]
+		for _, result := range [][2]int{{3, 0}, {1, 1}, {0, 3}} {
+			final[home], final[away] = final[home]+result[0], final[away]+result[1]
+			earned[home], earned[away] = earned[home]+result[0], earned[away]+result[1]
+			play(i + 1)
+			final[home], final[away] = final[home]-result[0], final[away]-result[1]
+			earned[home], earned[away] = earned[home]-result[0], earned[away]-result[1]
+		}
+	}
+	play(0)
+	return outcomes
+}
+
+func bruteForceRace(outcomes []raceOutcome, remaining [][2]int, team, places int) *models.PlaceRace {
+	rivals := func(outcome raceOutcome, level bool) int {
+		count := 0
+		for rival, p := range outcome.points {
+			if rival != team && (p > outcome.points[team] || level && p == outcome.points[team]) {
+				count++
+			}
+		}
+		return count
+	}
+	sure := func(earned int) bool {
+		for _, outcome := range outcomes {
+			if outcome.earned[team] >= earned && rivals(outcome, true) >= places {
+				return false
+			}
+		}
+		return true
+	}
+
+	race := &models.PlaceRace{Places: places, Eliminated: true}
+	for _, outcome := range outcomes {
+		if rivals(outcome, false) < places {
+			race.Eliminated = false
+		}
+	}
+	race.Clinched = sure(0)
+	if race.Clinched {
+		race.Eliminated = false
+	}
+	matches := 0
+	for _, match := range remaining {
+		if match[0] == team || match[1] == team {
+			matches++
+		}
+	}
+	if race.Eliminated {
+		return race
+	}
+	for earned := 0; earned <= 3*matches; earned++ {
+		if sure(earned) {
+			race.PointsNeeded = &earned
+			break
+		}
+	}
+	return race
+}
+
+func describeRace(race *models.PlaceRace) string {
+	needed := "none"
+	if race.PointsNeeded != nil {
+		needed = fmt.Sprint(*race.PointsNeeded)
+	}
+	return fmt.Sprintf("clinched %v, eliminated %v, points needed %s", race.Clinched, race.Eliminated, needed)
+}
+
+func TestMarkRaces(t *testing.T) {
+	standings := []models.TeamStanding{{TeamID: 1, Points: 6}, {TeamID: 2, Points: 6}, {TeamID: 3, Points: 0}}
+	played := []models.Match{{HomeTeamID: 1, AwayTeamID: 2, Played: true}}
+
+	// A finished table goes by its positions, tiebreakers included
+	markRaces(standings, played, true, 2)
+	if !standings[0].TitleRace.Clinched || !standings[1].TitleRace.Eliminated || !standings[1].QualificationRace.Clinched {
+		t.Errorf("Expected the final places to stand, got %+v and %+v", standings[0].TitleRace, standings[1].TitleRace)
+	}
+
+	// Knockout matches don't count, and only the places asked for are raced for
+	standings[0].TitleRace, standings[1].QualificationRace = nil, nil
+	markRaces(standings, []models.Match{{HomeTeamID: 3, AwayTeamID: 1, Stage: models.MatchStageKnockout}}, false, 3)
+	if standings[0].TitleRace != nil || standings[1].QualificationRace != nil {
+		t.Errorf("Expected no races, got %+v", standings[0])
+	}
+
+	markRaces(standings, []models.Match{{HomeTeamID: 3, AwayTeamID: 1}, {HomeTeamID: 3, AwayTeamID: 2}}, true, 0)
+	if race := standings[2].TitleRace; race.Clinched || race.Eliminated || race.PointsNeeded != nil {
+		t.Errorf("Expected the bottom team to stay in with a chance it can't make sure of, got %+v", race)
+	}
+	if race := standings[0].TitleRace; race.PointsNeeded != nil {
+		t.Errorf("Expected the leader unable to make sure of first place while its rival can win, got %+v", race)
+	}
+	markRaces(standings, []models.Match{{HomeTeamID: 3, AwayTeamID: 1}}, true, 0)
+	if race := standings[0].TitleRace; race.PointsNeeded == nil || *race.PointsNeeded != 1 {
+		t.Errorf("Expected the leader to need a draw, got %+v", race)
+	}
+}
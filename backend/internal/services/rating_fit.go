@@ -0,0 +1,513 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+)
+
+const (
+	// fitMaxIterations bounds the Newton steps of a rating fit
+	fitMaxIterations = 200
+	// fitTolerance is the largest gradient component at which a fit has converged
+	fitTolerance = 1e-8
+)
+
+var (
+	// ErrInvalidFit matches every set of results FitRatings can't fit
+	ErrInvalidFit = errors.New("invalid results to fit")
+
+	ErrSeasonInProgress = errors.New("team strengths can't be changed while a season is in progress")
+)
+
+// fitError is a reason FitRatings can't fit the results it was given; it matches ErrInvalidFit
+type fitError string
+
+func (e fitError) Error() string {
+	return string(e)
+}
+
+func (e fitError) Is(target error) bool {
+	return target == ErrInvalidFit
+}
+
+// fitObservation is a historical result between two teams by their index in the fit
+type fitObservation struct {
+	home, away           int
+	homeGoals, awayGoals int
+	neutral              bool
+}
+
+// powerRatioFit is the maximum likelihood fit of the power-ratio model: with log strengths a and
+// d, log home advantage h and log base goals b, the home side expects 2e^b σ(a_home + h - d_away)
+// goals and the away side 2e^b σ(a_away - d_home - h), σ being the logistic function. That is the
+// engine's ratio attack / (attack + opposing defence) with attack and defence e^a and e^d.
+type powerRatioFit struct {
+	attack, defence []float64 // Log strengths by team index, attacks and defences each summing to zero
+	homeAdvantage   float64   // Log home advantage
+	baseGoals       float64   // Log base goals
+	logLikelihood   float64
+	iterations      int
+	converged       bool
+}
+
+// FitRatings fits attack, defence and home advantage to historical results by maximum likelihood
+// and, unless dryRun is set, writes the strengths onto the teams that played in them. The fitted
+// strengths are scaled so the teams keep their average power.
+func (s *ratingService) FitRatings(leagueID uint, results []models.HistoricalResult, dryRun bool) (*models.RatingFit, error) {
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	if !dryRun && state.Started && !state.Completed {
+		return nil, ErrSeasonInProgress
+	}
+
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]int, len(teams))
+	for i, team := range teams {
+		byName[team.Name] = i
+	}
+
+	// Index the teams in order of appearance
+	var fitted []int // Position in teams of each fitted team
+	index := make(map[int]int)
+	lookup := func(name string) (int, error) {
+		position, ok := byName[name]
+		if !ok {
+			return 0, fitError(fmt.Sprintf("unknown team %q", name))
+		}
+		if i, ok := index[position]; ok {
+			return i, nil
+		}
+		index[position] = len(fitted)
+		fitted = append(fitted, position)
+		return index[position], nil
+	}
+
+	observations := make([]fitObservation, len(results))
+	for i, result := range results {
+		home, err := lookup(result.HomeTeam)
+		if err != nil {
+			return nil, err
+		}
+		away, err := lookup(result.AwayTeam)
+		if err != nil {
+			return nil, err
+		}
+		if home == away {
+			return nil, fitError(result.HomeTeam + " can't play itself")
+		}
+		if result.HomeScore < 0 || result.AwayScore < 0 {
+			return nil, fitError("scores must be non-negative")
+		}
+		observations[i] = fitObservation{
+			home:      home,
+			away:      away,
+			homeGoals: result.HomeScore,
+			awayGoals: result.AwayScore,
+			neutral:   result.Neutral,
+		}
+	}
+
+	if err := checkFittable(len(fitted), observations); err != nil {
+		return nil, err
+	}
+	fit := fitPowerRatioModel(len(fitted), observations)
+
+	// Scale e^a and e^d onto the power scale, keeping the teams' average power
+	powerSum, strengthSum := 0.0, 0.0
+	for i, position := range fitted {
+		powerSum += float64(teams[position].Power)
+		strengthSum += (math.Exp(fit.attack[i]) + math.Exp(fit.defence[i])) / 2
+	}
+	scale := powerSum / strengthSum
+
+	matches := make([]int, len(fitted))
+	for _, observation := range observations {
+		matches[observation.home]++
+		matches[observation.away]++
+	}
+
+	// Leagues that predate the engine settings play with the default home advantage
+	configured := state.EngineParams.HomeAdvantage
+	if configured <= 0 {
+		configured = models.DefaultMatchEngineParams().HomeAdvantage
+	}
+
+	report := &models.RatingFit{
+		Matches:                 len(observations),
+		HomeAdvantage:           math.Exp(fit.homeAdvantage),
+		ConfiguredHomeAdvantage: configured,
+		BaseGoals:               math.Exp(fit.baseGoals),
+		LogLikelihood:           fit.logLikelihood,
+		NullLogLikelihood:       nullLogLikelihood(observations),
+		Deviance:                2 * (saturatedLogLikelihood(observations) - fit.logLikelihood),
+		DegreesOfFreedom:        2*len(observations) - 2*len(fitted),
+		Iterations:              fit.iterations,
+		Converged:               fit.converged,
+		Applied:                 !dryRun,
+	}
+	report.PseudoR2 = 1 - report.LogLikelihood/report.NullLogLikelihood
+
+	updated := make([]models.Team, 0, len(fitted)) // The fitted teams with their new strengths
+	for i, position := range fitted {
+		team := teams[position]
+		teamFit := models.TeamFit{
+			TeamID:          team.ID,
+			TeamName:        team.Name,
+			Matches:         matches[i],
+			Attack:          strengthToPower(scale * math.Exp(fit.attack[i])),
+			Defence:         strengthToPower(scale * math.Exp(fit.defence[i])),
+			PreviousAttack:  team.AttackStrength(),
+			PreviousDefence: team.DefenceStrength(),
+			PreviousPower:   team.Power,
+		}
+		team.Power, team.Attack, team.Defence = 0, teamFit.Attack, teamFit.Defence
+		team.FillStrengths()
+		teamFit.Power = team.Power
+		report.Teams = append(report.Teams, teamFit)
+		updated = append(updated, team)
+	}
+
+	if !dryRun {
+		if err := s.applyFit(leagueID, state, updated); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// applyFit writes the fitted strengths onto the teams and replays the ratings from them, all in
+// one transaction
+func (s *ratingService) applyFit(leagueID uint, state *models.LeagueState, teams []models.Team) error {
+	return s.transactor.Transaction(func(repos repository.Repositories) error {
+		tx := NewRatingService(repos.Teams, repos.Matches, repos.LeagueStates, repos.Ratings, nil)
+		for i := range teams {
+			if err := repos.Teams.Update(&teams[i]); err != nil {
+				return err
+			}
+		}
+
+		// Ratings start from the power, so replay them from the new strengths
+		return tx.UpdateRatings(leagueID, state)
+	})
+}
+
+// checkFittable reports whether the results pin down every team's strengths: all teams must be
+// linked by matches, there must be more scores than free parameters and at least one goal
+func checkFittable(teams int, observations []fitObservation) error {
+	if len(observations) <= teams {
+		return fitError(fmt.Sprintf("%d results are too few to fit %d teams; at least %d are needed", len(observations), teams, teams+1))
+	}
+
+	goals := 0
+	parent := make([]int, teams)
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for _, observation := range observations {
+		parent[find(observation.home)] = find(observation.away)
+		goals += observation.homeGoals + observation.awayGoals
+	}
+
+	if goals == 0 {
+		return fitError("the results need at least one goal")
+	}
+	for i := range parent {
+		if find(i) != find(0) {
+			return fitError("the results must link every team through the matches they played")
+		}
+	}
+	return nil
+}
+
+// fitPowerRatioModel maximizes the log-likelihood of the scores with Levenberg-Marquardt damped
+// Newton steps. Moving every attack and defence together doesn't change the likelihood, and
+// raising every attack against every defence is nearly the same as raising the base goals, so
+// the attacks and the defences each sum to zero: the last team's strengths aren't free but
+// balance the others', and the steps are taken in the free parameters.
+func fitPowerRatioModel(teams int, observations []fitObservation) powerRatioFit {
+	// Parameters: attacks, defences, home advantage, base goals
+	size := 2*teams + 2
+	h, b := 2*teams, 2*teams+1
+	theta := make([]float64, size)
+
+	// Level teams score the base goals each
+	goals := 0
+	for _, observation := range observations {
+		goals += observation.homeGoals + observation.awayGoals
+	}
+	theta[b] = math.Log(float64(goals) / float64(2*len(observations)))
+
+	logLikelihood := func(theta []float64) float64 {
+		total := 0.0
+		for _, observation := range observations {
+			home, away := observationTerms(teams, theta, observation)
+			total += home.logLikelihood(theta) + away.logLikelihood(theta)
+		}
+		return total
+	}
+
+	columns := fitColumns(teams)
+	free := len(columns)
+
+	fit := powerRatioFit{}
+	damping := 1e-3
+	value := logLikelihood(theta)
+	for fit.iterations < fitMaxIterations {
+		fullGradient := make([]float64, size)
+		fullHessian := make([][]float64, size)
+		for i := range fullHessian {
+			fullHessian[i] = make([]float64, size)
+		}
+		for _, observation := range observations {
+			home, away := observationTerms(teams, theta, observation)
+			home.accumulate(theta, fullGradient, fullHessian)
+			away.accumulate(theta, fullGradient, fullHessian)
+		}
+
+		// Project onto the free parameters
+		gradient := make([]float64, free)
+		hessian := make([][]float64, free)
+		for p, column := range columns {
+			hessian[p] = make([]float64, free)
+			for _, i := range column {
+				gradient[p] += i.weight * fullGradient[i.index]
+			}
+			for q, other := range columns {
+				for _, i := range column {
+					for _, j := range other {
+						hessian[p][q] += i.weight * j.weight * fullHessian[i.index][j.index]
+					}
+				}
+			}
+		}
+
+		largest := 0.0
+		for _, g := range gradient {
+			largest = max(largest, math.Abs(g))
+		}
+		if largest < fitTolerance {
+			fit.converged = true
+			break
+		}
+		fit.iterations++
+
+		// Raise the damping until the step improves the likelihood
+		improved := false
+		for !improved && damping < 1e12 {
+			system := make([][]float64, free)
+			for p := range system {
+				system[p] = make([]float64, free)
+				for q := range system[p] {
+					system[p][q] = -hessian[p][q]
+				}
+				system[p][p] += damping
+			}
+			step, ok := solveLinear(system, gradient)
+			if ok {
+				next := append([]float64(nil), theta...)
+				for p, column := range columns {
+					for _, i := range column {
+						next[i.index] += i.weight * step[p]
+					}
+				}
+				if nextValue := logLikelihood(next); nextValue > value {
+					theta, value, improved = next, nextValue, true
+					damping = max(damping/10, 1e-12)
+					continue
+				}
+			}
+			damping *= 10
+		}
+		if !improved {
+			break
+		}
+	}
+
+	fit.attack = theta[:teams]
+	fit.defence = theta[teams : 2*teams]
+	fit.homeAdvantage = theta[h]
+	fit.baseGoals = theta[b]
+	fit.logLikelihood = value
+	return fit
+}
+
+// fitWeight is the weight of a free parameter in one of the model's parameters
+type fitWeight struct {
+	index  int
+	weight float64
+}
+
+// fitColumns returns, for each free parameter of a fit, the model parameters it moves: the
+// attack and defence of every team but the last, each balanced by the last team's, then the
+// home advantage and the base goals
+func fitColumns(teams int) [][]fitWeight {
+	var columns [][]fitWeight
+	for _, offset := range []int{0, teams} {
+		for i := range teams - 1 {
+			columns = append(columns, []fitWeight{{offset + i, 1}, {offset + teams - 1, -1}})
+		}
+	}
+	return append(columns, []fitWeight{{2 * teams, 1}}, []fitWeight{{2*teams + 1, 1}})
+}
+
+// fitTerm is one side's goals in a result: Poisson with mean 2e^b σ(η), η the sum of the
+// parameters at indices weighted by signs
+type fitTerm struct {
+	goals   int
+	indices []int
+	signs   []float64
+	base    int // Index of the log base goals
+}
+
+// observationTerms returns the terms of the home and away goals of a result
+func observationTerms(teams int, theta []float64, observation fitObservation) (fitTerm, fitTerm) {
+	h, b := 2*teams, 2*teams+1
+	home := fitTerm{
+		goals:   observation.homeGoals,
+		indices: []int{observation.home, teams + observation.away},
+		signs:   []float64{1, -1},
+		base:    b,
+	}
+	away := fitTerm{
+		goals:   observation.awayGoals,
+		indices: []int{observation.away, teams + observation.home},
+		signs:   []float64{1, -1},
+		base:    b,
+	}
+	if !observation.neutral {
+		home.indices, home.signs = append(home.indices, h), append(home.signs, 1)
+		away.indices, away.signs = append(away.indices, h), append(away.signs, -1)
+	}
+	return home, away
+}
+
+// mean returns the logistic of the term's linear predictor and its Poisson mean
+func (t *fitTerm) mean(theta []float64) (float64, float64) {
+	eta := 0.0
+	for k, i := range t.indices {
+		eta += t.signs[k] * theta[i]
+	}
+	sigma := 1 / (1 + math.Exp(-eta))
+	return sigma, 2 * math.Exp(theta[t.base]) * sigma
+}
+
+func (t *fitTerm) logLikelihood(theta []float64) float64 {
+	_, lambda := t.mean(theta)
+	return poissonLogProbability(t.goals, lambda)
+}
+
+// accumulate adds the term's gradient and Hessian to the totals. With y goals and mean
+// λ = 2e^b σ(η): ∂/∂η = (1-σ)(y-λ), ∂/∂b = y-λ, ∂²/∂η² = -σ(1-σ)(y-λ) - λ(1-σ)²,
+// ∂²/∂η∂b = -λ(1-σ) and ∂²/∂b² = -λ.
+func (t *fitTerm) accumulate(theta, gradient []float64, hessian [][]float64) {
+	sigma, lambda := t.mean(theta)
+	y := float64(t.goals)
+
+	gradEta := (1 - sigma) * (y - lambda)
+	etaEta := -sigma*(1-sigma)*(y-lambda) - lambda*(1-sigma)*(1-sigma)
+	etaBase := -lambda * (1 - sigma)
+
+	gradient[t.base] += y - lambda
+	hessian[t.base][t.base] -= lambda
+	for k, i := range t.indices {
+		gradient[i] += t.signs[k] * gradEta
+		hessian[i][t.base] += t.signs[k] * etaBase
+		hessian[t.base][i] += t.signs[k] * etaBase
+		for l, j := range t.indices {
+			hessian[i][j] += t.signs[k] * t.signs[l] * etaEta
+		}
+	}
+}
+
+// poissonLogProbability returns log P(k) of a Poisson distribution with mean lambda
+func poissonLogProbability(k int, lambda float64) float64 {
+	logFactorial, _ := math.Lgamma(float64(k + 1))
+	if k == 0 {
+		return -lambda - logFactorial
+	}
+	return float64(k)*math.Log(lambda) - lambda - logFactorial
+}
+
+// nullLogLikelihood is the log-likelihood of every score under one goal rate for all teams
+func nullLogLikelihood(observations []fitObservation) float64 {
+	goals := 0
+	for _, observation := range observations {
+		goals += observation.homeGoals + observation.awayGoals
+	}
+	rate := float64(goals) / float64(2*len(observations))
+
+	total := 0.0
+	for _, observation := range observations {
+		total += poissonLogProbability(observation.homeGoals, rate) + poissonLogProbability(observation.awayGoals, rate)
+	}
+	return total
+}
+
+// saturatedLogLikelihood is the log-likelihood of a model expecting every score exactly
+func saturatedLogLikelihood(observations []fitObservation) float64 {
+	total := 0.0
+	for _, observation := range observations {
+		total += poissonLogProbability(observation.homeGoals, float64(observation.homeGoals)) +
+			poissonLogProbability(observation.awayGoals, float64(observation.awayGoals))
+	}
+	return total
+}
+
+// strengthToPower rounds a fitted strength onto the 1-100 scale
+func strengthToPower(strength float64) int {
+	return min(max(int(math.Round(strength)), 1), 100)
+}
+
+// solveLinear solves a x = b by Gaussian elimination with partial pivoting, reporting false for
+// a singular matrix; a and b are overwritten
+func solveLinear(a [][]float64, b []float64) ([]float64, bool) {
+	n := len(b)
+	b = append([]float64(nil), b...)
+	for col := range n {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-300 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x, true
+}
@@ -0,0 +1,157 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+)
+
+// simulatedResults plays the teams against each other home and away for the given number of
+// seasons with the power-ratio Poisson engine
+func simulatedResults(teams []models.Team, params models.MatchEngineParams, seasons int, seed int64) []models.HistoricalResult {
+	engine := &poissonEngine{params: params}
+	rng := rand.New(rand.NewSource(seed))
+
+	var results []models.HistoricalResult
+	for range seasons {
+		for i := range teams {
+			for j := range teams {
+				if i == j {
+					continue
+				}
+				home, away := engine.Simulate(rng, &teams[i], &teams[j], false)
+				results = append(results, models.HistoricalResult{
+					HomeTeam:  teams[i].Name,
+					AwayTeam:  teams[j].Name,
+					HomeScore: home,
+					AwayScore: away,
+				})
+			}
+		}
+	}
+	return results
+}
+
+func TestFitRatings(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	ratingRepo := &mockRatingRepository{}
+	leagueRepo := &mockLeagueStateRepository{state: &models.LeagueState{LeagueID: 1, EngineParams: models.DefaultMatchEngineParams()}}
+	matchRepo := &mockMatchRepository{}
+	transactions := 0
+	transactor := &mockTransactor{
+		repos:  repository.Repositories{Teams: teamRepo, Matches: matchRepo, LeagueStates: leagueRepo, Ratings: ratingRepo},
+		before: func() { transactions++ },
+	}
+	service := NewRatingService(teamRepo, matchRepo, leagueRepo, ratingRepo, transactor)
+
+	// Results from a world where Arsenal attacks like City but defends poorly, with a stronger
+	// home advantage than the league plays with
+	truth := append([]models.Team(nil), teamRepo.teams...)
+	truth[1].Attack, truth[1].Defence = 90, 60
+	params := models.DefaultMatchEngineParams()
+	params.HomeAdvantage, params.MaxGoals = 1.4, 20
+	results := simulatedResults(truth, params, 60, 7)
+
+	fit, err := service.FitRatings(1, results, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !fit.Converged || fit.Matches != len(results) || fit.Applied {
+		t.Fatalf("Expected a converged dry run over %d matches, got %+v", len(results), fit)
+	}
+	if math.Abs(fit.HomeAdvantage-1.4) > 0.15 || fit.ConfiguredHomeAdvantage != 1.1 {
+		t.Errorf("Expected a home advantage near 1.4 next to the configured 1.1, got %.3f and %.3f",
+			fit.HomeAdvantage, fit.ConfiguredHomeAdvantage)
+	}
+	if math.Abs(fit.BaseGoals-1.5) > 0.15 {
+		t.Errorf("Expected base goals near 1.5, got %.3f", fit.BaseGoals)
+	}
+	if fit.LogLikelihood <= fit.NullLogLikelihood || fit.PseudoR2 <= 0 || fit.Deviance <= 0 {
+		t.Errorf("Expected the fit to beat the null model, got %+v", fit)
+	}
+	if fit.DegreesOfFreedom != 2*len(results)-8 {
+		t.Errorf("Expected %d degrees of freedom, got %d", 2*len(results)-8, fit.DegreesOfFreedom)
+	}
+
+	byName := make(map[string]models.TeamFit)
+	for _, team := range fit.Teams {
+		byName[team.TeamName] = team
+	}
+	arsenal := byName["Arsenal"]
+	if arsenal.Attack-arsenal.Defence < 15 || arsenal.PreviousAttack != 80 {
+		t.Errorf("Expected Arsenal to attack far better than it defends, got %+v", arsenal)
+	}
+	if byName["Manchester City"].Attack <= byName["Liverpool"].Attack {
+		t.Errorf("Expected City to attack better than Liverpool, got %+v", fit.Teams)
+	}
+
+	// A dry run leaves the teams alone
+	if teamRepo.teams[1].Attack != 80 || transactions != 0 {
+		t.Fatalf("Expected a dry run not to change Arsenal, got %+v", teamRepo.teams[1])
+	}
+
+	// Applying keeps the average power and plays the teams at their fitted strengths
+	fit, err = service.FitRatings(1, results, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	total := 0
+	for _, team := range teamRepo.teams {
+		total += team.Power
+	}
+	if !fit.Applied || transactions != 1 || teamRepo.teams[1].Attack != arsenal.Attack || math.Abs(float64(total)-337) > 4 {
+		t.Errorf("Expected the fitted strengths with a total power near 337, got %+v", teamRepo.teams)
+	}
+}
+
+func TestFitRatingsRejectsResults(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	leagueRepo := &mockLeagueStateRepository{state: &models.LeagueState{LeagueID: 1}}
+	service := NewRatingService(teamRepo, &mockMatchRepository{}, leagueRepo, &mockRatingRepository{}, nil)
+
+	result := func(home, away string, homeScore, awayScore int) models.HistoricalResult {
+		return models.HistoricalResult{HomeTeam: home, AwayTeam: away, HomeScore: homeScore, AwayScore: awayScore}
+	}
+	linked := []models.HistoricalResult{
+		result("Chelsea", "Arsenal", 2, 1),
+		result("Arsenal", "Chelsea", 1, 1),
+		result("Chelsea", "Arsenal", 0, 2),
+	}
+
+	tests := []struct {
+		name    string
+		results []models.HistoricalResult
+	}{
+		{"Unknown team", []models.HistoricalResult{result("Chelsea", "Real Madrid", 1, 0)}},
+		{"Team playing itself", []models.HistoricalResult{result("Chelsea", "Chelsea", 1, 0)}},
+		{"Negative score", []models.HistoricalResult{result("Chelsea", "Arsenal", -1, 0)}},
+		{"Too few results", linked[:1]},
+		{"Goalless results", []models.HistoricalResult{
+			result("Chelsea", "Arsenal", 0, 0), result("Arsenal", "Chelsea", 0, 0), result("Chelsea", "Arsenal", 0, 0),
+		}},
+		{"Unlinked teams", append(append([]models.HistoricalResult(nil), linked...),
+			result("Liverpool", "Manchester City", 1, 0), result("Manchester City", "Liverpool", 2, 2),
+			result("Liverpool", "Manchester City", 0, 3)),
+		},
+	}
+	for _, tt := range tests {
+		if _, err := service.FitRatings(1, tt.results, true); !errors.Is(err, ErrInvalidFit) {
+			t.Errorf("%s: expected ErrInvalidFit, got %v", tt.name, err)
+		}
+	}
+
+	// Strengths can't change mid-season, though a dry run is still allowed
+	leagueRepo.state.Started = true
+	if _, err := service.FitRatings(1, linked, false); !errors.Is(err, ErrSeasonInProgress) {
+		t.Errorf("Expected ErrSeasonInProgress, got %v", err)
+	}
+	if _, err := service.FitRatings(1, linked, true); err != nil {
+		t.Errorf("Expected a dry run mid-season to succeed, got %v", err)
+	}
+}
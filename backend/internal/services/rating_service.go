@@ -0,0 +1,196 @@
+package services
+
+import (
+	"cmp"
+	"errors"
+	"math"
+	"slices"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+	"gorm.io/gorm"
+)
+
+type RatingService interface {
+	UpdateRatings(leagueID uint, state *models.LeagueState) error
+	GetRatingHistory(leagueID, teamID uint) (*models.RatingHistory, error)
+	FitRatings(leagueID uint, results []models.HistoricalResult, dryRun bool) (*models.RatingFit, error)
+}
+
+type ratingService struct {
+	teamRepo   repository.TeamRepository
+	matchRepo  repository.MatchRepository
+	leagueRepo repository.LeagueStateRepository
+	ratingRepo repository.RatingRepository
+	transactor repository.Transactor
+}
+
+func NewRatingService(
+	teamRepo repository.TeamRepository,
+	matchRepo repository.MatchRepository,
+	leagueRepo repository.LeagueStateRepository,
+	ratingRepo repository.RatingRepository,
+	transactor repository.Transactor,
+) RatingService {
+	return &ratingService{
+		teamRepo:   teamRepo,
+		matchRepo:  matchRepo,
+		leagueRepo: leagueRepo,
+		ratingRepo: ratingRepo,
+		transactor: transactor,
+	}
+}
+
+// UpdateRatings replays every played match in week order from the teams' power-based ratings
+// and stores the rating of each team after each week, so an edited result is reflected in
+// every later week. With rating updates off the teams go back to playing by power and the
+// history is cleared.
+func (s *ratingService) UpdateRatings(leagueID uint, state *models.LeagueState) error {
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return err
+	}
+	if err := s.ratingRepo.DeleteAll(leagueID); err != nil {
+		return err
+	}
+
+	if !state.RatingUpdates {
+		for _, team := range teams {
+			if team.Rating != 0 {
+				if err := s.teamRepo.UpdateRating(leagueID, team.ID, 0); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	matches, err := s.matchRepo.FindPlayedMatches(leagueID)
+	if err != nil {
+		return err
+	}
+	slices.SortStableFunc(matches, func(a, b models.Match) int {
+		return cmp.Or(cmp.Compare(a.Week, b.Week), cmp.Compare(a.ID, b.ID))
+	})
+
+	ratings := make(map[uint]float64, len(teams))
+	for i := range teams {
+		ratings[teams[i].ID] = powerRating(&teams[i])
+	}
+
+	// Results can be entered ahead of the current week
+	lastWeek := state.CurrentWeek
+	for _, match := range matches {
+		lastWeek = max(lastWeek, match.Week)
+	}
+
+	k := state.RatingK
+	if k <= 0 {
+		k = models.DefaultRatingK
+	}
+
+	history := make([]models.TeamRating, 0, len(teams)*(lastWeek+1))
+	record := func(week int, changes map[uint]float64) {
+		for _, team := range teams {
+			history = append(history, models.TeamRating{
+				LeagueID: leagueID,
+				TeamID:   team.ID,
+				Week:     week,
+				Rating:   ratings[team.ID],
+				Change:   changes[team.ID],
+			})
+		}
+	}
+
+	record(0, nil)
+	next := 0
+	for week := 1; week <= lastWeek; week++ {
+		changes := make(map[uint]float64)
+		for ; next < len(matches) && matches[next].Week == week; next++ {
+			match := &matches[next]
+			home, homeOK := ratings[match.HomeTeamID]
+			away, awayOK := ratings[match.AwayTeamID]
+			if !homeOK || !awayOK || match.HomeScore == nil || match.AwayScore == nil {
+				continue
+			}
+
+			homeAdvantage := state.EngineParams.EloHomeAdvantage
+			if match.Neutral {
+				homeAdvantage = 0
+			}
+			change := eloChange(home-away+homeAdvantage, *match.HomeScore, *match.AwayScore, k)
+			ratings[match.HomeTeamID] += change
+			ratings[match.AwayTeamID] -= change
+			changes[match.HomeTeamID] += change
+			changes[match.AwayTeamID] -= change
+		}
+		record(week, changes)
+	}
+
+	for _, team := range teams {
+		if err := s.teamRepo.UpdateRating(leagueID, team.ID, ratings[team.ID]); err != nil {
+			return err
+		}
+	}
+	return s.ratingRepo.CreateBatch(history)
+}
+
+// eloChange returns the rating the home team gains from a result, and the away team loses: K
+// times the goal margin multiplier times the difference between the result (1 for a win, 0.5
+// for a draw, 0 for a defeat) and the expectancy for the given rating difference
+func eloChange(difference float64, homeGoals, awayGoals int, k float64) float64 {
+	result := 0.5 + float64(sign(homeGoals-awayGoals))/2
+	return k * eloGoalMultiplier(homeGoals-awayGoals) * (result - eloExpectancy(difference))
+}
+
+// eloGoalMultiplier weights a result by its margin like the World Football Elo Ratings: 1 up to
+// one goal, 1.5 for two and (11 + N) / 8 for a margin of N of three or more
+func eloGoalMultiplier(margin int) float64 {
+	if margin < 0 {
+		margin = -margin
+	}
+	switch {
+	case margin <= 1:
+		return 1
+	case margin == 2:
+		return 1.5
+	default:
+		return (11 + float64(margin)) / 8
+	}
+}
+
+func (s *ratingService) GetRatingHistory(leagueID, teamID uint) (*models.RatingHistory, error) {
+	team, err := s.teamRepo.FindByID(leagueID, teamID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrTeamNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	ratings, err := s.ratingRepo.FindByTeam(leagueID, teamID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ratings {
+		ratings[i].Rating = roundRating(ratings[i].Rating)
+		ratings[i].Change = roundRating(ratings[i].Change)
+	}
+
+	return &models.RatingHistory{
+		Team:    *team,
+		Rating:  roundRating(eloRating(team)),
+		Enabled: state.RatingUpdates,
+		Ratings: ratings,
+	}, nil
+}
+
+// roundRating rounds a rating to one decimal for display
+func roundRating(rating float64) float64 {
+	return math.Round(rating*10) / 10
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// mockRatingRepository implements repository.RatingRepository for testing
+type mockRatingRepository struct {
+	ratings []models.TeamRating
+}
+
+func (m *mockRatingRepository) CreateBatch(ratings []models.TeamRating) error {
+	m.ratings = append(m.ratings, ratings...)
+	return nil
+}
+
+func (m *mockRatingRepository) FindByTeam(_, teamID uint) ([]models.TeamRating, error) {
+	var ratings []models.TeamRating
+	for _, rating := range m.ratings {
+		if rating.TeamID == teamID {
+			ratings = append(ratings, rating)
+		}
+	}
+	return ratings, nil
+}
+
+func (m *mockRatingRepository) DeleteAll(_ uint) error {
+	m.ratings = nil
+	return nil
+}
+
+func TestEloChange(t *testing.T) {
+	tests := []struct {
+		name       string
+		difference float64
+		home, away int
+		expected   float64
+	}{
+		{"Draw between equals", 0, 1, 1, 0},
+		{"Narrow win between equals", 0, 1, 0, 10},
+		{"Two-goal win", 0, 2, 0, 15},
+		{"Four-goal defeat", 0, 0, 4, -10 * 15.0 / 8},
+		{"Draw for the favourite", 400, 2, 2, 20 * (0.5 - 10.0/11)},
+	}
+	for _, tt := range tests {
+		if got := eloChange(tt.difference, tt.home, tt.away, 20); math.Abs(got-tt.expected) > 1e-9 {
+			t.Errorf("%s: expected %.3f, got %.3f", tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestUpdateRatings(t *testing.T) {
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	matchRepo := &mockMatchRepository{}
+	ratingRepo := &mockRatingRepository{}
+	service := NewRatingService(teamRepo, matchRepo, &mockLeagueStateRepository{}, ratingRepo, nil)
+
+	score := func(n int) *int { return &n }
+	matchRepo.matches = []models.Match{
+		{ID: 1, Week: 2, HomeTeamID: 1, AwayTeamID: 3, HomeScore: score(1), AwayScore: score(1), Played: true},
+		{ID: 2, Week: 1, HomeTeamID: 2, AwayTeamID: 1, HomeScore: score(0), AwayScore: score(3), Played: true},
+		{ID: 3, Week: 2, HomeTeamID: 2, AwayTeamID: 4},
+	}
+	state := &models.LeagueState{
+		CurrentWeek:   2,
+		RatingUpdates: true,
+		RatingK:       20,
+		EngineParams:  models.DefaultMatchEngineParams(),
+	}
+	if err := service.UpdateRatings(1, state); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Four teams from week 0 to week 2
+	if len(ratingRepo.ratings) != 12 {
+		t.Fatalf("Expected 12 history rows, got %d", len(ratingRepo.ratings))
+	}
+
+	// Chelsea (85) won away at Arsenal (80) by three in week 1, then drew at home to City (90)
+	history, err := service.GetRatingHistory(1, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	week1 := 20 * 14.0 / 8 * (1 - eloExpectancy(1850-1800-65))
+	if history.Ratings[0].Rating != 1850 || math.Abs(history.Ratings[1].Change-roundRating(week1)) > 1e-9 {
+		t.Errorf("Expected 1850 then a gain of %.1f, got %+v", week1, history.Ratings[:2])
+	}
+	if history.Ratings[2].Change >= 0 {
+		t.Errorf("Expected a home draw to cost rating once home advantage lifts Chelsea above City, got %+v", history.Ratings[2])
+	}
+
+	// Ratings only move between teams, and Liverpool's unplayed match leaves it at its power
+	total := 0.0
+	for _, team := range teamRepo.teams {
+		total += team.Rating
+	}
+	if math.Abs(total-(1850+1800+1900+1820)) > 1e-9 || teamRepo.teams[3].Rating != 1820 {
+		t.Errorf("Expected the ratings to sum to the starting ratings, got %+v", teamRepo.teams)
+	}
+
+	// An edited result is replayed from the start
+	matchRepo.matches[1].HomeScore, matchRepo.matches[1].AwayScore = score(3), score(0)
+	if err := service.UpdateRatings(1, state); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if teamRepo.teams[0].Rating >= 1850 || len(ratingRepo.ratings) != 12 {
+		t.Errorf("Expected Chelsea below its starting rating after the edit, got %.1f", teamRepo.teams[0].Rating)
+	}
+
+	// Turning updates off sends the teams back to their power
+	state.RatingUpdates = false
+	if err := service.UpdateRatings(1, state); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, team := range teamRepo.teams {
+		if team.Rating != 0 {
+			t.Errorf("Expected %s to have no rating, got %.1f", team.Name, team.Rating)
+		}
+	}
+	if len(ratingRepo.ratings) != 0 {
+		t.Errorf("Expected the history to be cleared, got %d rows", len(ratingRepo.ratings))
+	}
+}
+
+func TestTeamStrengthFollowsRating(t *testing.T) {
+	team := &models.Team{Power: 80, Attack: 86, Defence: 74}
+	if teamAttack(team) != 86 || teamDefence(team) != 74 || eloRating(team) != 1800 {
+		t.Errorf("Expected an unrated team to play at its strengths, got %.1f, %.1f and %.1f",
+			teamAttack(team), teamDefence(team), eloRating(team))
+	}
+
+	// Rating gained on top of the power moves attack and defence alike
+	team.Rating = 1900
+	if teamAttack(team) != 96 || teamDefence(team) != 84 || eloRating(team) != 1900 {
+		t.Errorf("Expected a team rated 1900 to play ten points stronger, got %.1f, %.1f and %.1f",
+			teamAttack(team), teamDefence(team), eloRating(team))
+	}
+
+	team.Rating = 950
+	if teamAttack(team) != 1 || teamDefence(team) != 1 {
+		t.Errorf("Expected the strengths to bottom out at 1, got %.1f and %.1f", teamAttack(team), teamDefence(team))
+	}
+
+	// A team whose rating has grown expects more goals against the same opponent
+	opponent := &models.Team{Power: 80}
+	before, _ := defaultMatchEngine.ExpectedGoals(&models.Team{Power: 80}, opponent, false)
+	after, _ := defaultMatchEngine.ExpectedGoals(&models.Team{Power: 80, Rating: 1850}, opponent, false)
+	if after <= before {
+		t.Errorf("Expected more goals from the higher rating, got %.2f and %.2f", before, after)
+	}
+}
+
+func TestUpdateSettingsRatingUpdates(t *testing.T) {
+	service, _ := newSeededLeague(t, 42)
+	if _, err := service.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	enabled, k := true, 30.0
+	state, err := service.UpdateSettings(1, models.LeagueSettings{RatingUpdates: &enabled, RatingK: &k})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !state.RatingUpdates || state.RatingK != 30 {
+		t.Errorf("Expected rating updates with K 30, got %v and %.0f", state.RatingUpdates, state.RatingK)
+	}
+
+	invalid := 0.0
+	if _, err := service.UpdateSettings(1, models.LeagueSettings{RatingK: &invalid}); err == nil {
+		t.Error("Expected an error for a zero K-factor")
+	}
+}
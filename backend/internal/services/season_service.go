@@ -0,0 +1,360 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSeasonNotFound = errors.New("season not found")
+	ErrTeamNotFound   = errors.New("team not found")
+)
+
+type SeasonService interface {
+	ArchiveSeason(leagueID uint) (*models.Season, error)
+	RearchiveSeason(leagueID uint) (*models.Season, error)
+	GetSeasons(leagueID uint) ([]models.Season, error)
+	GetSeason(leagueID, seasonID uint) (*models.Season, error)
+	GetSeasonMatches(leagueID, seasonID uint) ([]models.SeasonMatch, error)
+	GetAllTimeTable(leagueID uint) ([]models.AllTimeStanding, error)
+	GetHeadToHead(leagueID, teamAID, teamBID uint) (*models.HeadToHeadRecord, error)
+}
+
+type seasonService struct {
+	matchRepo    repository.MatchRepository
+	teamRepo     repository.TeamRepository
+	leagueRepo   repository.LeagueStateRepository
+	seasonRepo   repository.SeasonRepository
+	knockoutRepo repository.KnockoutRepository
+}
+
+func NewSeasonService(
+	matchRepo repository.MatchRepository,
+	teamRepo repository.TeamRepository,
+	leagueRepo repository.LeagueStateRepository,
+	seasonRepo repository.SeasonRepository,
+	knockoutRepo repository.KnockoutRepository,
+) SeasonService {
+	return &seasonService{
+		matchRepo:    matchRepo,
+		teamRepo:     teamRepo,
+		leagueRepo:   leagueRepo,
+		seasonRepo:   seasonRepo,
+		knockoutRepo: knockoutRepo,
+	}
+}
+
+// ArchiveSeason stores the league's current season: final table with team powers, every played
+// result and the champion (the knockout winner if the league has a knockout stage) when the season
+// is completed. Returns nil when nothing has been played.
+func (s *seasonService) ArchiveSeason(leagueID uint) (*models.Season, error) {
+	season, err := s.currentSeason(leagueID)
+	if season == nil || err != nil {
+		return nil, err
+	}
+
+	count, err := s.seasonRepo.Count(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	season.Number = int(count) + 1
+
+	if err := s.seasonRepo.Create(season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// RearchiveSeason rewrites the league's latest archived season from the current season, for a
+// completed season whose results are edited after it was archived. The season keeps its ID and
+// number. Archives the season if none has been archived yet.
+func (s *seasonService) RearchiveSeason(leagueID uint) (*models.Season, error) {
+	seasons, err := s.seasonRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	if len(seasons) == 0 {
+		return s.ArchiveSeason(leagueID)
+	}
+	archived := seasons[len(seasons)-1]
+
+	season, err := s.currentSeason(leagueID)
+	if season == nil || err != nil {
+		return nil, err
+	}
+	season.ID, season.Number, season.ArchivedAt = archived.ID, archived.Number, archived.ArchivedAt
+
+	if err := s.seasonRepo.Replace(season); err != nil {
+		return nil, err
+	}
+	return season, nil
+}
+
+// currentSeason builds the record of the league's current season, nil when nothing has been played
+func (s *seasonService) currentSeason(leagueID uint) (*models.Season, error) {
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.matchRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	var played []models.Match
+	for _, match := range matches {
+		if match.Played && match.HomeScore != nil && match.AwayScore != nil {
+			played = append(played, match)
+		}
+	}
+	if len(played) == 0 {
+		return nil, nil
+	}
+
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	season := &models.Season{
+		LeagueID:    leagueID,
+		Completed:   state.Completed,
+		WeeksPlayed: state.CurrentWeek,
+		TotalWeeks:  state.TotalWeeks,
+		Seed:        state.Seed,
+		ArchivedAt:  time.Now(),
+	}
+
+	powers := make(map[uint]int, len(teams))
+	for _, team := range teams {
+		powers[team.ID] = team.Power
+	}
+
+	for i, standing := range calculateStandings(teams, matches, leagueTiebreakChain(state)) {
+		season.Standings = append(season.Standings, models.SeasonStanding{
+			Position:       i + 1,
+			TeamID:         standing.TeamID,
+			TeamName:       standing.TeamName,
+			Power:          powers[standing.TeamID],
+			Played:         standing.Played,
+			Won:            standing.Won,
+			Drawn:          standing.Drawn,
+			Lost:           standing.Lost,
+			GoalsFor:       standing.GoalsFor,
+			GoalsAgainst:   standing.GoalsAgainst,
+			GoalDifference: standing.GoalDifference,
+			Points:         standing.Points,
+		})
+	}
+
+	if state.Completed && len(season.Standings) > 0 {
+		champion := season.Standings[0]
+		season.ChampionTeamID = &champion.TeamID
+		season.ChampionName = champion.TeamName
+	}
+	if state.Completed && state.KnockoutTeams > 0 {
+		if err := s.setKnockoutChampion(leagueID, season); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, match := range played {
+		stage := match.Stage
+		if stage == "" {
+			stage = models.MatchStageLeague
+		}
+		season.Matches = append(season.Matches, models.SeasonMatch{
+			Week:         match.Week,
+			Stage:        stage,
+			HomeTeamID:   match.HomeTeamID,
+			AwayTeamID:   match.AwayTeamID,
+			HomeTeamName: match.HomeTeam.Name,
+			AwayTeamName: match.AwayTeam.Name,
+			HomeScore:    *match.HomeScore,
+			AwayScore:    *match.AwayScore,
+			Seed:         match.Seed,
+		})
+	}
+	return season, nil
+}
+
+// setKnockoutChampion makes the winner of the final the season's champion
+func (s *seasonService) setKnockoutChampion(leagueID uint, season *models.Season) error {
+	ties, err := s.knockoutRepo.FindAll(leagueID)
+	if err != nil {
+		return err
+	}
+	if len(ties) == 0 {
+		return nil
+	}
+
+	final := ties[len(ties)-1]
+	if !final.SingleLeg || final.WinnerID == nil {
+		return nil
+	}
+	season.ChampionTeamID = final.WinnerID
+	season.ChampionName = final.TeamA.Name
+	if *final.WinnerID == final.TeamBID {
+		season.ChampionName = final.TeamB.Name
+	}
+	return nil
+}
+
+func (s *seasonService) GetSeasons(leagueID uint) ([]models.Season, error) {
+	return s.seasonRepo.FindAll(leagueID)
+}
+
+func (s *seasonService) GetSeason(leagueID, seasonID uint) (*models.Season, error) {
+	season, err := s.seasonRepo.FindByID(leagueID, seasonID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrSeasonNotFound
+	}
+	return season, err
+}
+
+func (s *seasonService) GetSeasonMatches(leagueID, seasonID uint) ([]models.SeasonMatch, error) {
+	// Make sure the season belongs to the league
+	if _, err := s.GetSeason(leagueID, seasonID); err != nil {
+		return nil, err
+	}
+	return s.seasonRepo.FindMatches(seasonID)
+}
+
+func (s *seasonService) GetAllTimeTable(leagueID uint) ([]models.AllTimeStanding, error) {
+	seasons, err := s.seasonRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	standings, err := s.seasonRepo.FindAllStandings(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	return calculateAllTimeTable(seasons, standings), nil
+}
+
+// calculateAllTimeTable sums every archived season row per team and counts titles from the
+// champions of completed seasons. Standings must be ordered by season so the latest name wins.
+func calculateAllTimeTable(seasons []models.Season, standings []models.SeasonStanding) []models.AllTimeStanding {
+	titles := make(map[uint]int)
+	for _, season := range seasons {
+		if season.ChampionTeamID != nil {
+			titles[*season.ChampionTeamID]++
+		}
+	}
+
+	totals := make(map[uint]*models.AllTimeStanding)
+	var order []uint
+	for _, standing := range standings {
+		total, ok := totals[standing.TeamID]
+		if !ok {
+			total = &models.AllTimeStanding{TeamID: standing.TeamID, Titles: titles[standing.TeamID]}
+			totals[standing.TeamID] = total
+			order = append(order, standing.TeamID)
+		}
+
+		total.TeamName = standing.TeamName
+		total.Seasons++
+		total.Played += standing.Played
+		total.Won += standing.Won
+		total.Drawn += standing.Drawn
+		total.Lost += standing.Lost
+		total.GoalsFor += standing.GoalsFor
+		total.GoalsAgainst += standing.GoalsAgainst
+		total.GoalDifference += standing.GoalDifference
+		total.Points += standing.Points
+	}
+
+	table := make([]models.AllTimeStanding, 0, len(order))
+	for _, teamID := range order {
+		table = append(table, *totals[teamID])
+	}
+
+	sort.SliceStable(table, func(i, j int) bool {
+		if table[i].Points != table[j].Points {
+			return table[i].Points > table[j].Points
+		}
+		if table[i].GoalDifference != table[j].GoalDifference {
+			return table[i].GoalDifference > table[j].GoalDifference
+		}
+		return table[i].GoalsFor > table[j].GoalsFor
+	})
+
+	return table
+}
+
+func (s *seasonService) GetHeadToHead(leagueID, teamAID, teamBID uint) (*models.HeadToHeadRecord, error) {
+	if teamAID == teamBID {
+		return nil, errors.New("head-to-head needs two different teams")
+	}
+
+	matches, err := s.seasonRepo.FindMatchesBetween(leagueID, teamAID, teamBID)
+	if err != nil {
+		return nil, err
+	}
+
+	record := calculateHeadToHead(teamAID, teamBID, matches)
+
+	// Without archived meetings the names come from the current teams
+	if len(matches) == 0 {
+		teamA, err := s.teamRepo.FindByID(leagueID, teamAID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		teamB, err := s.teamRepo.FindByID(leagueID, teamBID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamNotFound
+		}
+		if err != nil {
+			return nil, err
+		}
+		record.TeamAName = teamA.Name
+		record.TeamBName = teamB.Name
+	}
+
+	return record, nil
+}
+
+// calculateHeadToHead tallies the meetings between two teams from team A's point of view
+func calculateHeadToHead(teamAID, teamBID uint, matches []models.SeasonMatch) *models.HeadToHeadRecord {
+	record := &models.HeadToHeadRecord{
+		TeamAID: teamAID,
+		TeamBID: teamBID,
+		Matches: make([]models.SeasonMatch, 0, len(matches)),
+	}
+
+	for _, match := range matches {
+		goalsA, goalsB := match.HomeScore, match.AwayScore
+		nameA, nameB := match.HomeTeamName, match.AwayTeamName
+		if match.HomeTeamID == teamBID {
+			goalsA, goalsB = goalsB, goalsA
+			nameA, nameB = nameB, nameA
+		}
+
+		record.TeamAName, record.TeamBName = nameA, nameB
+		record.Played++
+		record.TeamAGoals += goalsA
+		record.TeamBGoals += goalsB
+		switch {
+		case goalsA > goalsB:
+			record.TeamAWins++
+		case goalsA < goalsB:
+			record.TeamBWins++
+		default:
+			record.Draws++
+		}
+		record.Matches = append(record.Matches, match)
+	}
+
+	return record
+}
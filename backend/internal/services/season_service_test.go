@@ -0,0 +1,215 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"gorm.io/gorm"
+)
+
+// mockSeasonRepository implements repository.SeasonRepository for testing
+type mockSeasonRepository struct {
+	seasons []models.Season
+}
+
+func (m *mockSeasonRepository) Create(season *models.Season) error {
+	season.ID = uint(len(m.seasons) + 1)
+	for i := range season.Standings {
+		season.Standings[i].SeasonID = season.ID
+	}
+	for i := range season.Matches {
+		season.Matches[i].SeasonID = season.ID
+	}
+	m.seasons = append(m.seasons, *season)
+	return nil
+}
+
+func (m *mockSeasonRepository) Replace(season *models.Season) error {
+	for i := range m.seasons {
+		if m.seasons[i].ID == season.ID {
+			m.seasons[i] = *season
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (m *mockSeasonRepository) FindAll(leagueID uint) ([]models.Season, error) {
+	var seasons []models.Season
+	for _, season := range m.seasons {
+		if season.LeagueID == leagueID {
+			seasons = append(seasons, season)
+		}
+	}
+	return seasons, nil
+}
+
+func (m *mockSeasonRepository) FindByID(leagueID, id uint) (*models.Season, error) {
+	for i := range m.seasons {
+		if m.seasons[i].ID == id && m.seasons[i].LeagueID == leagueID {
+			return &m.seasons[i], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *mockSeasonRepository) FindMatches(seasonID uint) ([]models.SeasonMatch, error) {
+	for _, season := range m.seasons {
+		if season.ID == seasonID {
+			return season.Matches, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockSeasonRepository) FindAllStandings(leagueID uint) ([]models.SeasonStanding, error) {
+	var standings []models.SeasonStanding
+	for _, season := range m.seasons {
+		if season.LeagueID == leagueID {
+			standings = append(standings, season.Standings...)
+		}
+	}
+	return standings, nil
+}
+
+func (m *mockSeasonRepository) FindMatchesBetween(leagueID, teamAID, teamBID uint) ([]models.SeasonMatch, error) {
+	var matches []models.SeasonMatch
+	for _, season := range m.seasons {
+		if season.LeagueID != leagueID {
+			continue
+		}
+		for _, match := range season.Matches {
+			if (match.HomeTeamID == teamAID && match.AwayTeamID == teamBID) ||
+				(match.HomeTeamID == teamBID && match.AwayTeamID == teamAID) {
+				matches = append(matches, match)
+			}
+		}
+	}
+	return matches, nil
+}
+
+func (m *mockSeasonRepository) Count(leagueID uint) (int64, error) {
+	seasons, _ := m.FindAll(leagueID)
+	return int64(len(seasons)), nil
+}
+
+func TestSeasonArchivedOnCompletion(t *testing.T) {
+	service, matchRepo, seasonRepo := newSeededLeagueWithSeasons(t, 42)
+
+	if _, err := service.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(seasonRepo.seasons) != 1 {
+		t.Fatalf("Expected 1 archived season, got %d", len(seasonRepo.seasons))
+	}
+
+	season := seasonRepo.seasons[0]
+	if !season.Completed || season.Number != 1 || season.Seed != 42 {
+		t.Errorf("Unexpected season summary: %+v", season)
+	}
+	if len(season.Matches) != len(matchRepo.matches) {
+		t.Errorf("Expected %d archived matches, got %d", len(matchRepo.matches), len(season.Matches))
+	}
+	if len(season.Standings) != 4 {
+		t.Fatalf("Expected 4 archived standings, got %d", len(season.Standings))
+	}
+	if season.ChampionTeamID == nil || *season.ChampionTeamID != season.Standings[0].TeamID {
+		t.Errorf("Expected champion to be the table leader")
+	}
+	for i, standing := range season.Standings {
+		if standing.Position != i+1 || standing.Power == 0 {
+			t.Errorf("Unexpected standing row: %+v", standing)
+		}
+	}
+
+	// Resetting a completed season must not archive it twice
+	if err := service.ResetSimulation(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(seasonRepo.seasons) != 1 {
+		t.Errorf("Expected completed season to be archived once, got %d seasons", len(seasonRepo.seasons))
+	}
+}
+
+func TestSeasonArchivedOnReset(t *testing.T) {
+	service, _, seasonRepo := newSeededLeagueWithSeasons(t, 7)
+
+	// Resetting before anything is played leaves no history
+	if err := service.ResetSimulation(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(seasonRepo.seasons) != 0 {
+		t.Fatalf("Expected no archived season, got %d", len(seasonRepo.seasons))
+	}
+
+	service, _, seasonRepo = newSeededLeagueWithSeasons(t, 7)
+	if _, err := service.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := service.ResetSimulation(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(seasonRepo.seasons) != 1 {
+		t.Fatalf("Expected 1 archived season, got %d", len(seasonRepo.seasons))
+	}
+	season := seasonRepo.seasons[0]
+	if season.Completed || season.ChampionTeamID != nil || season.WeeksPlayed != 1 {
+		t.Errorf("Expected an unfinished season without champion, got %+v", season)
+	}
+	if len(season.Matches) != 2 {
+		t.Errorf("Expected 2 archived matches, got %d", len(season.Matches))
+	}
+}
+
+func TestCalculateAllTimeTable(t *testing.T) {
+	champion := uint(1)
+	seasons := []models.Season{
+		{ID: 1, ChampionTeamID: &champion},
+		{ID: 2},
+	}
+	standings := []models.SeasonStanding{
+		{SeasonID: 1, TeamID: 1, TeamName: "Chelsea", Played: 6, Won: 4, Drawn: 1, Lost: 1, GoalsFor: 10, GoalsAgainst: 5, GoalDifference: 5, Points: 13},
+		{SeasonID: 1, TeamID: 2, TeamName: "Arsenal", Played: 6, Won: 2, Drawn: 1, Lost: 3, GoalsFor: 6, GoalsAgainst: 8, GoalDifference: -2, Points: 7},
+		{SeasonID: 2, TeamID: 2, TeamName: "Arsenal FC", Played: 6, Won: 5, Drawn: 1, Lost: 0, GoalsFor: 12, GoalsAgainst: 3, GoalDifference: 9, Points: 16},
+		{SeasonID: 2, TeamID: 1, TeamName: "Chelsea", Played: 6, Won: 1, Drawn: 1, Lost: 4, GoalsFor: 4, GoalsAgainst: 9, GoalDifference: -5, Points: 4},
+	}
+
+	table := calculateAllTimeTable(seasons, standings)
+
+	if len(table) != 2 {
+		t.Fatalf("Expected 2 rows, got %d", len(table))
+	}
+
+	// Arsenal leads on points (23 vs 17) under its latest name
+	if table[0].TeamID != 2 || table[0].TeamName != "Arsenal FC" || table[0].Points != 23 {
+		t.Errorf("Expected Arsenal FC first with 23 points, got %+v", table[0])
+	}
+	if table[0].Seasons != 2 || table[0].Played != 12 || table[0].GoalDifference != 7 || table[0].Titles != 0 {
+		t.Errorf("Unexpected totals for Arsenal FC: %+v", table[0])
+	}
+	if table[1].TeamID != 1 || table[1].Points != 17 || table[1].Titles != 1 {
+		t.Errorf("Expected Chelsea second with 17 points and 1 title, got %+v", table[1])
+	}
+}
+
+func TestCalculateHeadToHead(t *testing.T) {
+	matches := []models.SeasonMatch{
+		{HomeTeamID: 1, AwayTeamID: 2, HomeTeamName: "Chelsea", AwayTeamName: "Arsenal", HomeScore: 2, AwayScore: 0},
+		{HomeTeamID: 2, AwayTeamID: 1, HomeTeamName: "Arsenal", AwayTeamName: "Chelsea", HomeScore: 3, AwayScore: 1},
+		{HomeTeamID: 2, AwayTeamID: 1, HomeTeamName: "Arsenal", AwayTeamName: "Chelsea", HomeScore: 1, AwayScore: 1},
+	}
+
+	record := calculateHeadToHead(1, 2, matches)
+
+	if record.TeamAName != "Chelsea" || record.TeamBName != "Arsenal" {
+		t.Errorf("Expected Chelsea vs Arsenal, got %s vs %s", record.TeamAName, record.TeamBName)
+	}
+	if record.Played != 3 || record.TeamAWins != 1 || record.Draws != 1 || record.TeamBWins != 1 {
+		t.Errorf("Unexpected record: %+v", record)
+	}
+	if record.TeamAGoals != 4 || record.TeamBGoals != 4 {
+		t.Errorf("Expected 4-4 on goals, got %d-%d", record.TeamAGoals, record.TeamBGoals)
+	}
+}
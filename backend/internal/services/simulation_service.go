@@ -4,45 +4,152 @@ import (
 	"errors"
 	"math"
 	"math/rand"
+	"sync"
+	"time"
 
 	"github.com/zahidcakici/champions-league/internal/models"
 	"github.com/zahidcakici/champions-league/internal/repository"
+	"gorm.io/gorm"
 )
 
-const (
-	homeAdvantageFactor = 1.1
-	baseExpectedGoals   = 1.5
-	maxGoalsPerTeam     = 7
+var (
+	ErrMatchNotFound = errors.New("match not found")
+	ErrStateChanged  = errors.New("the league changed while the request was handled; reload it and try again")
+
+	// ErrInvalidSettings matches every settings change UpdateSettings turns down
+	ErrInvalidSettings = errors.New("invalid settings")
+
+	ErrKnockoutLocked       settingsError = "the knockout stage can only be changed before the league phase ends"
+	ErrTooManyKnockoutTeams settingsError = "knockout teams cannot exceed the number of teams in the league"
+	ErrGroupKnockoutTeams   settingsError = "with a group stage the top two of each group reach the knockout stage"
+	ErrTooFewForPlayoff     settingsError = "the knockout play-off needs half as many teams again as the bracket"
+	ErrScheduleLocked       settingsError = "the schedule can only be changed before fixtures are generated"
+	ErrFormatLocked         settingsError = "the format can only be changed before fixtures are generated"
+	ErrGroupsLocked         settingsError = "groups can only be changed before fixtures are generated"
+	ErrSwissGroups          settingsError = "a Swiss league phase can't be split into groups"
 )
 
+// settingsError is a settings change UpdateSettings turns down; it matches ErrInvalidSettings
+type settingsError string
+
+func (e settingsError) Error() string {
+	return string(e)
+}
+
+func (e settingsError) Is(target error) bool {
+	return target == ErrInvalidSettings
+}
+
+// maxGoalsPerTeam caps the goals of generateGoalsPoisson, like the default engine parameters
+const maxGoalsPerTeam = 7
+
 type SimulationService interface {
-	PlayNextWeek() ([]models.Match, error)
-	PlayAllWeeks() (map[int][]models.Match, error)
-	UpdateMatchResult(matchID uint, homeScore, awayScore int) error
-	ResetSimulation() error
-	GetCurrentState() (*models.SimulationState, error)
+	PlayNextWeek(leagueID uint, seed *int64) ([]models.Match, error)
+	PlayAllWeeks(leagueID uint, seed *int64) (map[int][]models.Match, error)
+	PlayLiveWeek(leagueID uint, seed *int64, duration time.Duration, watcher LiveWatcher) (*models.LiveWeek, error)
+	GetLiveWeek(leagueID uint) *models.LiveWeek
+	UpdateMatchResult(leagueID, matchID uint, homeScore, awayScore int) (*models.ResultEdit, error)
+	ClearMatchResult(leagueID, matchID uint) (*models.ResultEdit, error)
+	UpdateSettings(leagueID uint, settings models.LeagueSettings) (*models.LeagueState, error)
+	ResetSimulation(leagueID uint) error
+	GetCurrentState(leagueID uint) (*models.SimulationState, error)
+	GetMatchTimeline(leagueID, matchID uint) (*models.MatchTimeline, error)
+	GetWeekTimelines(leagueID uint, week int) ([]models.MatchTimeline, error)
 }
 
 type simulationService struct {
-	matchRepo  repository.MatchRepository
-	teamRepo   repository.TeamRepository
-	leagueRepo repository.LeagueStateRepository
+	matchRepo    repository.MatchRepository
+	teamRepo     repository.TeamRepository
+	leagueRepo   repository.LeagueStateRepository
+	groupRepo    repository.GroupRepository
+	seasons      SeasonService
+	knockout     KnockoutService
+	ratings      RatingService
+	eventRepo    repository.MatchEventRepository
+	availability AvailabilityService
+	transactor   repository.Transactor
+
+	liveMu sync.Mutex
+	live   map[uint]*liveRun // Weeks being played out in real time, by league
 }
 
 func NewSimulationService(
 	matchRepo repository.MatchRepository,
 	teamRepo repository.TeamRepository,
 	leagueRepo repository.LeagueStateRepository,
+	groupRepo repository.GroupRepository,
+	seasons SeasonService,
+	knockout KnockoutService,
+	ratings RatingService,
+	eventRepo repository.MatchEventRepository,
+	availability AvailabilityService,
+	transactor repository.Transactor,
 ) SimulationService {
 	return &simulationService{
-		matchRepo:  matchRepo,
-		teamRepo:   teamRepo,
-		leagueRepo: leagueRepo,
+		matchRepo:    matchRepo,
+		teamRepo:     teamRepo,
+		leagueRepo:   leagueRepo,
+		groupRepo:    groupRepo,
+		seasons:      seasons,
+		knockout:     knockout,
+		ratings:      ratings,
+		eventRepo:    eventRepo,
+		availability: availability,
+		transactor:   transactor,
+		live:         make(map[uint]*liveRun),
+	}
+}
+
+// transaction calls fn with a copy of the service whose repositories and services all work in
+// one database transaction, so either everything fn saves is kept or none of it is. A league
+// state another request updated in the meantime rolls it back with ErrStateChanged.
+func (s *simulationService) transaction(fn func(tx *simulationService) error) error {
+	err := s.transactor.Transaction(func(repos repository.Repositories) error {
+		return fn(&simulationService{
+			matchRepo:  repos.Matches,
+			teamRepo:   repos.Teams,
+			leagueRepo: repos.LeagueStates,
+			groupRepo:  repos.Groups,
+			seasons:    NewSeasonService(repos.Matches, repos.Teams, repos.LeagueStates, repos.Seasons, repos.Knockout),
+			knockout:   NewKnockoutService(repos.Matches, repos.Teams, repos.LeagueStates, repos.Knockout, repos.Groups),
+			ratings:    NewRatingService(repos.Teams, repos.Matches, repos.LeagueStates, repos.Ratings, nil),
+			eventRepo:  repos.MatchEvents,
+			availability: NewAvailabilityService(repos.Players, repos.Absences, repos.MatchEvents, repos.Matches,
+				repos.Teams, repos.LeagueStates),
+		})
+	})
+	if errors.Is(err, repository.ErrStaleState) {
+		return ErrStateChanged
+	}
+	return err
+}
+
+// PlayNextWeek simulates the next week. Each match is played with its own seed derived from
+// the given seed (or the league seed when nil), the week and the match's position in the week,
+// so the same seed and fixture list always reproduce the same results.
+func (s *simulationService) PlayNextWeek(leagueID uint, seed *int64) ([]models.Match, error) {
+	if s.GetLiveWeek(leagueID) != nil {
+		return nil, ErrWeekLive
+	}
+	play, err := s.simulateWeek(leagueID, seed)
+	if err != nil {
+		return nil, err
 	}
+	return s.saveWeek(leagueID, play)
+}
+
+// weekPlay is a simulated week waiting to be saved
+type weekPlay struct {
+	state     *models.LeagueState
+	week      int
+	matches   []models.Match
+	timelines map[uint][]models.MatchEvent // Events of the matches played, by match ID
+	ties      []*models.KnockoutTie        // Ties the week decides
 }
 
-func (s *simulationService) PlayNextWeek() ([]models.Match, error) {
-	state, err := s.leagueRepo.Get()
+// simulateWeek plays the next week without saving anything
+func (s *simulationService) simulateWeek(leagueID uint, seed *int64) (*weekPlay, error) {
+	state, err := s.leagueRepo.Get(leagueID)
 	if err != nil {
 		return nil, err
 	}
@@ -56,7 +163,7 @@ func (s *simulationService) PlayNextWeek() ([]models.Match, error) {
 	}
 
 	nextWeek := state.CurrentWeek + 1
-	matches, err := s.matchRepo.FindByWeek(nextWeek)
+	matches, err := s.matchRepo.FindByWeek(leagueID, nextWeek)
 	if err != nil {
 		return nil, err
 	}
@@ -65,34 +172,133 @@ func (s *simulationService) PlayNextWeek() ([]models.Match, error) {
 		return nil, errors.New("no matches found for this week")
 	}
 
-	// Simulate each match
+	baseSeed := state.Seed
+	if seed != nil {
+		baseSeed = *seed
+	}
+
+	availability, err := s.availability.GetWeekAvailability(leagueID, nextWeek)
+	if err != nil {
+		return nil, err
+	}
+	squads := make(map[uint]*models.TeamAvailability, len(availability))
+	for i := range availability {
+		squads[availability[i].TeamID] = &availability[i]
+	}
+
+	// Simulate each match with the league's engine
+	play := &weekPlay{state: state, week: nextWeek, matches: matches, timelines: make(map[uint][]models.MatchEvent)}
+	engine := newMatchEngine(state)
 	for i := range matches {
 		if !matches[i].Played {
-			homeScore, awayScore := s.simulateMatch(&matches[i].HomeTeam, &matches[i].AwayTeam)
+			matchSeed := deriveSeed(baseSeed, int64(nextWeek), int64(i))
+			rng := rand.New(rand.NewSource(matchSeed))
+			homeSheet := fieldTeam(&matches[i].HomeTeam, squads[matches[i].HomeTeamID])
+			awaySheet := fieldTeam(&matches[i].AwayTeam, squads[matches[i].AwayTeamID])
+			timeline := playMatch(engine, rng, matchSeed, &matches[i].HomeTeam, &matches[i].AwayTeam,
+				homeSheet, awaySheet, matches[i].Neutral)
+			homeScore, awayScore := timeline.score(false)
 			matches[i].HomeScore = &homeScore
 			matches[i].AwayScore = &awayScore
 			matches[i].Played = true
-			if err := s.matchRepo.Update(&matches[i]); err != nil {
-				return nil, err
+			matches[i].Seed = &matchSeed
+			if matches[i].IsKnockout() {
+				tie, err := s.knockout.DecideTie(leagueID, engine, rng, &matches[i])
+				if err != nil {
+					return nil, err
+				}
+				if tie != nil {
+					play.ties = append(play.ties, tie)
+				}
+			}
+			if matches[i].HomeExtraTimeScore != nil && matches[i].AwayExtraTimeScore != nil {
+				timeline.playExtraTime(*matches[i].HomeExtraTimeScore, *matches[i].AwayExtraTimeScore)
 			}
+			play.timelines[matches[i].ID] = timeline.events
+		}
+	}
+	return play, nil
+}
+
+// saveWeek saves a simulated week in one transaction: its results, timelines and absences, then
+// the league state and everything that follows from it. If the week was played by another request
+// since it was simulated, nothing is saved and ErrStateChanged is returned.
+func (s *simulationService) saveWeek(leagueID uint, play *weekPlay) ([]models.Match, error) {
+	err := s.transaction(func(tx *simulationService) error {
+		return tx.writeWeek(leagueID, play)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return play.matches, nil
+}
+
+// writeWeek writes what saveWeek saves
+func (s *simulationService) writeWeek(leagueID uint, play *weekPlay) error {
+	matches := play.matches
+	for i := range matches {
+		events, ok := play.timelines[matches[i].ID]
+		if !ok {
+			continue
+		}
+		if err := s.matchRepo.Update(&matches[i]); err != nil {
+			return err
+		}
+		if err := s.saveTimeline(leagueID, matches[i].ID, events); err != nil {
+			return err
+		}
+		if err := s.availability.RecordAbsences(leagueID, &matches[i], events); err != nil {
+			return err
+		}
+	}
+	for _, tie := range play.ties {
+		if err := s.knockout.SaveTie(tie); err != nil {
+			return err
 		}
 	}
 
 	// Update league state
-	state.CurrentWeek = nextWeek
+	state := play.state
+	state.CurrentWeek = play.week
+	if play.week < state.LeagueWeeks() {
+		// Weeks whose results were all entered ahead of time are over already
+		week, err := s.playedWeeks(leagueID, state)
+		if err != nil {
+			return err
+		}
+		state.CurrentWeek = max(week, play.week)
+	}
 	state.Started = true
-	if nextWeek >= state.TotalWeeks {
+	if state.CurrentWeek >= state.TotalWeeks {
 		state.Completed = true
 	}
+
+	// Draw the next knockout round once the league or the previous round is over
+	if err := s.knockout.Advance(leagueID, state); err != nil {
+		return err
+	}
+
 	if err := s.leagueRepo.Update(state); err != nil {
-		return nil, err
+		return err
 	}
 
-	return matches, nil
+	// Move the ratings by this week's results
+	if err := s.ratings.UpdateRatings(leagueID, state); err != nil {
+		return err
+	}
+
+	// Keep a record of the finished season
+	if state.Completed {
+		if _, err := s.seasons.ArchiveSeason(leagueID); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func (s *simulationService) PlayAllWeeks() (map[int][]models.Match, error) {
-	state, err := s.leagueRepo.Get()
+func (s *simulationService) PlayAllWeeks(leagueID uint, seed *int64) (map[int][]models.Match, error) {
+	state, err := s.leagueRepo.Get(leagueID)
 	if err != nil {
 		return nil, err
 	}
@@ -104,44 +310,28 @@ func (s *simulationService) PlayAllWeeks() (map[int][]models.Match, error) {
 	results := make(map[int][]models.Match)
 
 	for !state.Completed {
-		matches, err := s.PlayNextWeek()
+		week := state.CurrentWeek + 1
+		matches, err := s.PlayNextWeek(leagueID, seed)
 		if err != nil {
 			return nil, err
 		}
 
 		// Refresh state
-		state, err = s.leagueRepo.Get()
+		state, err = s.leagueRepo.Get(leagueID)
 		if err != nil {
 			return nil, err
 		}
 
-		results[state.CurrentWeek] = matches
+		results[week] = matches
 	}
 
 	return results, nil
 }
 
-// simulateMatch generates a match result based on team powers
-// Uses weighted random algorithm with home advantage
-// Each team's expected goals depends on their power relative to opponent's power
-func (s *simulationService) simulateMatch(homeTeam, awayTeam *models.Team) (int, int) {
-	// Calculate effective powers
-	homePower := float64(homeTeam.Power) * homeAdvantageFactor
-	awayPower := float64(awayTeam.Power)
-
-	// Total power for relative calculations
-	totalPower := homePower + awayPower
-
-	// Generate goals based on relative power
-	// Expected goals = base * (own power relative to total)
-	// This means stronger opponents reduce your expected goals
-	homeExpectedGoals := baseExpectedGoals * 2 * (homePower / totalPower)
-	awayExpectedGoals := baseExpectedGoals * 2 * (awayPower / totalPower)
-
-	homeGoals := generateGoalsPoisson(homeExpectedGoals)
-	awayGoals := generateGoalsPoisson(awayExpectedGoals)
-
-	return homeGoals, awayGoals
+// simulateMatch generates a match result based on team powers with the default engine: the
+// power-ratio Poisson model with home advantage
+func simulateMatch(rng *rand.Rand, homeTeam, awayTeam *models.Team) (int, int) {
+	return defaultMatchEngine.Simulate(rng, homeTeam, awayTeam, false)
 }
 
 // generateGoals generates a realistic goal count using a simplified Poisson-like distribution - Old method
@@ -171,7 +361,12 @@ func (s *simulationService) simulateMatch(homeTeam, awayTeam *models.Team) (int,
 // - 0-1 goals are most common
 // - 2-3 goals are fairly common
 // - 4+ goals are rare but possible
-func generateGoalsPoisson(lambda float64) int {
+func generateGoalsPoisson(rng *rand.Rand, lambda float64) int {
+	return poissonGoals(rng, lambda, maxGoalsPerTeam)
+}
+
+// poissonGoals draws a Poisson goal count with mean lambda, capped at maxGoals
+func poissonGoals(rng *rand.Rand, lambda float64, maxGoals int) int {
 	if lambda <= 0 {
 		return 0
 	}
@@ -183,41 +378,274 @@ func generateGoalsPoisson(lambda float64) int {
 
 	for p > L {
 		k++
-		p *= rand.Float64()
+		p *= rng.Float64()
 	}
 
-	return min(k-1, maxGoalsPerTeam)
+	return min(k-1, maxGoals)
 }
 
-func (s *simulationService) UpdateMatchResult(matchID uint, homeScore, awayScore int) error {
-	match, err := s.matchRepo.FindByID(matchID)
+// deriveSeed mixes a base seed with extra values (week, match index, ...) into a new seed
+// using the SplitMix64 finalizer. The result stays below models.MaxSeed so it can be shared as JSON.
+func deriveSeed(base int64, parts ...int64) int64 {
+	x := uint64(base)
+	for _, part := range parts {
+		x ^= uint64(part) + 0x9e3779b97f4a7c15 + (x << 6) + (x >> 2)
+		x ^= x >> 30
+		x *= 0xbf58476d1ce4e5b9
+		x ^= x >> 27
+		x *= 0x94d049bb133111eb
+		x ^= x >> 31
+	}
+	return int64(x % models.MaxSeed)
+}
+
+func (s *simulationService) UpdateSettings(leagueID uint, settings models.LeagueSettings) (*models.LeagueState, error) {
+	if s.GetLiveWeek(leagueID) != nil {
+		return nil, ErrWeekLive
+	}
+
+	state, err := s.leagueRepo.Get(leagueID)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if settings.Seed != nil {
+		if *settings.Seed < 0 || *settings.Seed >= models.MaxSeed {
+			return nil, settingsError("seed must be between 0 and 2^53-1")
+		}
+		state.Seed = *settings.Seed
+	}
+
+	if settings.TiebreakerPreset != nil && settings.Tiebreakers != nil {
+		return nil, settingsError("set either a tiebreaker preset or custom tiebreakers, not both")
+	}
+	if settings.TiebreakerPreset != nil {
+		if _, ok := models.TiebreakerPresets[*settings.TiebreakerPreset]; !ok {
+			return nil, settingsError("tiebreaker preset must be premier_league or uefa_group_stage")
+		}
+		state.TiebreakerPreset = *settings.TiebreakerPreset
+		state.Tiebreakers = ""
+	}
+	if settings.Tiebreakers != nil {
+		if err := validateTiebreakers(settings.Tiebreakers); err != nil {
+			return nil, settingsError(err.Error())
+		}
+		state.TiebreakerPreset = models.TiebreakerPresetCustom
+		state.Tiebreakers = models.FormatTiebreakerRules(settings.Tiebreakers)
 	}
 
-	match.HomeScore = &homeScore
-	match.AwayScore = &awayScore
-	match.Played = true
+	if settings.Engine != nil {
+		if !models.ValidMatchEngine(*settings.Engine) {
+			return nil, settingsError("engine must be poisson, dixon_coles, elo or minute_by_minute")
+		}
+		state.Engine = *settings.Engine
+	}
+	if settings.EngineParams != nil {
+		if !settings.EngineParams.Valid() {
+			return nil, settingsError("engine parameters out of range: homeAdvantage (0, 3], baseGoals (0, 5], maxGoals 1-20, rho -0.3-0.3, eloHomeAdvantage 0-400, eloDrawRate 0-0.5")
+		}
+		settings.EngineParams.Apply(&state.EngineParams)
+	}
 
-	return s.matchRepo.Update(match)
+	if settings.RatingUpdates != nil {
+		state.RatingUpdates = *settings.RatingUpdates
+	}
+	if settings.RatingK != nil {
+		if *settings.RatingK <= 0 || *settings.RatingK > 100 {
+			return nil, settingsError("rating K-factor must be above 0 and at most 100")
+		}
+		state.RatingK = *settings.RatingK
+	}
+
+	if settings.StartDate != nil || settings.MatchdayInterval != nil {
+		if err := updateSchedule(state, settings.StartDate, settings.MatchdayInterval); err != nil {
+			return nil, err
+		}
+	}
+	if settings.Fatigue != nil {
+		if !settings.Fatigue.Valid() {
+			return nil, settingsError("fatigue parameters out of range: recoveryHalfLife (0, 14], penalty 0-1, maxPenalty 0-0.9")
+		}
+		settings.Fatigue.Apply(&state.Fatigue)
+	}
+
+	if settings.FutureEdits != nil {
+		if !models.ValidFutureEdits(*settings.FutureEdits) {
+			return nil, settingsError("future edits must be reject or allow")
+		}
+		state.FutureEdits = *settings.FutureEdits
+	}
+
+	if settings.Format != nil {
+		if err := updateFormat(state, *settings.Format); err != nil {
+			return nil, err
+		}
+	}
+	if settings.Groups != nil {
+		if err := updateGroups(state, *settings.Groups); err != nil {
+			return nil, err
+		}
+	}
+	if state.Groups > 0 && state.Format == models.LeagueFormatSwiss {
+		return nil, ErrSwissGroups
+	}
+	if settings.KnockoutTeams != nil {
+		if err := s.updateKnockoutTeams(leagueID, state, *settings.KnockoutTeams); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.transaction(func(tx *simulationService) error {
+		if err := tx.leagueRepo.Update(state); err != nil {
+			return err
+		}
+
+		// Turning rating updates on replays the results so far, turning them off clears them
+		if settings.RatingUpdates != nil || settings.RatingK != nil {
+			return tx.ratings.UpdateRatings(leagueID, state)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return state, nil
 }
 
-func (s *simulationService) ResetSimulation() error {
-	// Delete all matches
-	if err := s.matchRepo.DeleteAll(); err != nil {
-		return err
+// updateKnockoutTeams changes the size of the knockout bracket while the league phase is running
+func (s *simulationService) updateKnockoutTeams(leagueID uint, state *models.LeagueState, teams int) error {
+	if !models.ValidKnockoutTeams(teams) {
+		return settingsError("knockout teams must be 0 or a power of two between 2 and 64")
+	}
+	if state.Completed || state.KnockoutStarted() {
+		return ErrKnockoutLocked
 	}
 
-	// Reset league state
-	if err := s.leagueRepo.Reset(); err != nil {
+	count, err := s.teamRepo.Count(leagueID)
+	if err != nil {
 		return err
 	}
+	if int64(teams) > count {
+		return ErrTooManyKnockoutTeams
+	}
+	if state.Groups > 0 && teams != state.Groups*groupQualifiers {
+		return ErrGroupKnockoutTeams
+	}
+
+	next := *state
+	next.KnockoutTeams = teams
+	if int64(next.KnockoutQualifiers()) > count {
+		return ErrTooFewForPlayoff
+	}
+
+	if state.FixturesCreated {
+		state.TotalWeeks = state.LeagueWeeks() + next.KnockoutWeeks()
+	}
+	state.KnockoutTeams = teams
+	return nil
+}
+
+// updateSchedule changes the start date and the days between weeks before fixtures are generated
+func updateSchedule(state *models.LeagueState, startDate *time.Time, interval *int) error {
+	if state.FixturesCreated {
+		return ErrScheduleLocked
+	}
+	if interval != nil {
+		if !models.ValidMatchdayInterval(*interval) {
+			return settingsError("matchday interval must be between 1 and 28 days")
+		}
+		state.MatchdayInterval = *interval
+	}
+	if startDate != nil {
+		date := startDate.UTC().Truncate(24 * time.Hour)
+		state.StartDate = &date
+	}
+	return nil
+}
 
+// updateFormat switches between a double round-robin and a Swiss league phase before fixtures
+// are generated
+func updateFormat(state *models.LeagueState, format string) error {
+	if !models.ValidLeagueFormat(format) {
+		return settingsError("format must be round_robin or swiss")
+	}
+	if state.FixturesCreated && format != state.Format {
+		return ErrFormatLocked
+	}
+
+	state.Format = format
 	return nil
 }
 
-func (s *simulationService) GetCurrentState() (*models.SimulationState, error) {
-	leagueState, err := s.leagueRepo.Get()
+// updateGroups switches between a single table and a group stage before fixtures are generated;
+// a group stage always feeds a knockout stage with the top two of each group
+func updateGroups(state *models.LeagueState, groups int) error {
+	if !models.ValidGroupCount(groups) {
+		return settingsError("groups must be 0 or a power of two up to 16")
+	}
+	if state.FixturesCreated {
+		return ErrGroupsLocked
+	}
+
+	state.Groups = groups
+	if groups > 0 {
+		state.KnockoutTeams = groups * groupQualifiers
+	}
+	return nil
+}
+
+func (s *simulationService) ResetSimulation(leagueID uint) error {
+	if s.GetLiveWeek(leagueID) != nil {
+		return ErrWeekLive
+	}
+
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return err
+	}
+
+	return s.transaction(func(tx *simulationService) error {
+		// Completed seasons were archived at the final whistle; archive an unfinished one now
+		if !state.Completed {
+			if _, err := tx.seasons.ArchiveSeason(leagueID); err != nil {
+				return err
+			}
+		}
+
+		// Delete all matches with their events and absences, then the knockout ties they belonged
+		// to and the group draw
+		if err := tx.eventRepo.DeleteAll(leagueID); err != nil {
+			return err
+		}
+		if err := tx.availability.Clear(leagueID); err != nil {
+			return err
+		}
+		if err := tx.matchRepo.DeleteAll(leagueID); err != nil {
+			return err
+		}
+		if err := tx.knockout.Clear(leagueID); err != nil {
+			return err
+		}
+		if err := tx.groupRepo.DeleteAll(leagueID); err != nil {
+			return err
+		}
+
+		// Reset league state
+		if err := tx.leagueRepo.Reset(leagueID); err != nil {
+			return err
+		}
+
+		// Start the new season from the teams' power again
+		state, err = tx.leagueRepo.Get(leagueID)
+		if err != nil {
+			return err
+		}
+		return tx.ratings.UpdateRatings(leagueID, state)
+	})
+}
+
+func (s *simulationService) GetCurrentState(leagueID uint) (*models.SimulationState, error) {
+	leagueState, err := s.leagueRepo.Get(leagueID)
 	if err != nil {
 		return nil, err
 	}
@@ -226,3 +654,92 @@ func (s *simulationService) GetCurrentState() (*models.SimulationState, error) {
 		LeagueState: *leagueState,
 	}, nil
 }
+
+// fieldTeam plays a team at the strength its available players and its tiredness leave it, and
+// picks its match-day squad from the available players; a team without a squad has no team sheet
+func fieldTeam(team *models.Team, squad *models.TeamAvailability) []*models.Player {
+	if squad == nil {
+		return nil
+	}
+	team.Freshness = squad.Freshness
+	if squad.SquadSize == 0 {
+		return nil
+	}
+	team.AttackAvailability = squad.AttackAvailability
+	team.DefenceAvailability = squad.DefenceAvailability
+	return models.TeamSheet(squad.Available)
+}
+
+// saveTimeline replaces a match's events with its newly simulated timeline
+func (s *simulationService) saveTimeline(leagueID, matchID uint, events []models.MatchEvent) error {
+	if err := s.eventRepo.DeleteByMatch(leagueID, matchID); err != nil {
+		return err
+	}
+	for i := range events {
+		events[i].LeagueID = leagueID
+		events[i].MatchID = matchID
+	}
+	return s.eventRepo.CreateBatch(events)
+}
+
+// GetMatchTimeline returns a match with its events. Matches that haven't been played, or whose
+// result was entered by hand, have no events.
+func (s *simulationService) GetMatchTimeline(leagueID, matchID uint) (*models.MatchTimeline, error) {
+	match, err := s.matchRepo.FindByID(leagueID, matchID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMatchNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.eventRepo.FindByMatch(leagueID, matchID)
+	if err != nil {
+		return nil, err
+	}
+	timeline := newTimeline(match, events)
+	return &timeline, nil
+}
+
+// GetWeekTimelines returns the week's matches with their events
+func (s *simulationService) GetWeekTimelines(leagueID uint, week int) ([]models.MatchTimeline, error) {
+	matches, err := s.matchRepo.FindByWeek(leagueID, week)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(matches))
+	for i := range matches {
+		ids[i] = matches[i].ID
+	}
+	events, err := s.eventRepo.FindByMatches(leagueID, ids)
+	if err != nil {
+		return nil, err
+	}
+	byMatch := make(map[uint][]models.MatchEvent)
+	for _, event := range events {
+		byMatch[event.MatchID] = append(byMatch[event.MatchID], event)
+	}
+
+	timelines := make([]models.MatchTimeline, len(matches))
+	for i := range matches {
+		timelines[i] = newTimeline(&matches[i], byMatch[matches[i].ID])
+	}
+	return timelines, nil
+}
+
+// newTimeline puts a match together with its events, reading the half-time score off the
+// half-time whistle
+func newTimeline(match *models.Match, events []models.MatchEvent) models.MatchTimeline {
+	timeline := models.MatchTimeline{Match: *match, Events: events}
+	if timeline.Events == nil {
+		timeline.Events = []models.MatchEvent{}
+	}
+	for i := range events {
+		if events[i].Type == models.MatchEventHalfTime {
+			timeline.HalfTimeHomeScore = &events[i].HomeScore
+			timeline.HalfTimeAwayScore = &events[i].AwayScore
+		}
+	}
+	return timeline
+}
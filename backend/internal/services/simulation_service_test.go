@@ -1,13 +1,17 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"math/rand"
 	"testing"
 
 	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
 )
 
 func TestSimulateMatch(t *testing.T) {
-	service := &simulationService{}
+	rng := rand.New(rand.NewSource(1))
 
 	homeTeam := &models.Team{ID: 1, Name: "Strong Team", Power: 95}
 	awayTeam := &models.Team{ID: 2, Name: "Weak Team", Power: 50}
@@ -19,7 +23,7 @@ func TestSimulateMatch(t *testing.T) {
 	iterations := 1000
 
 	for i := 0; i < iterations; i++ {
-		homeScore, awayScore := service.simulateMatch(homeTeam, awayTeam)
+		homeScore, awayScore := simulateMatch(rng, homeTeam, awayTeam)
 
 		// Scores should be non-negative
 		if homeScore < 0 || awayScore < 0 {
@@ -54,7 +58,7 @@ func TestSimulateMatch(t *testing.T) {
 }
 
 func TestSimulateMatchEqualTeams(t *testing.T) {
-	service := &simulationService{}
+	rng := rand.New(rand.NewSource(2))
 
 	homeTeam := &models.Team{ID: 1, Name: "Team A", Power: 80}
 	awayTeam := &models.Team{ID: 2, Name: "Team B", Power: 80}
@@ -64,7 +68,7 @@ func TestSimulateMatchEqualTeams(t *testing.T) {
 	iterations := 1000
 
 	for i := 0; i < iterations; i++ {
-		homeScore, awayScore := service.simulateMatch(homeTeam, awayTeam)
+		homeScore, awayScore := simulateMatch(rng, homeTeam, awayTeam)
 
 		if homeScore > awayScore {
 			homeWins++
@@ -87,7 +91,7 @@ func TestSimulateMatchEqualTeams(t *testing.T) {
 }
 
 func TestHomeAdvantage(t *testing.T) {
-	service := &simulationService{}
+	rng := rand.New(rand.NewSource(3))
 
 	// Same power teams, run many simulations
 	team := &models.Team{ID: 1, Name: "Team", Power: 75}
@@ -97,7 +101,7 @@ func TestHomeAdvantage(t *testing.T) {
 	iterations := 1000
 
 	for i := 0; i < iterations; i++ {
-		homeScore, awayScore := service.simulateMatch(team, team)
+		homeScore, awayScore := simulateMatch(rng, team, team)
 		homeScoreTotal += homeScore
 		awayScoreTotal += awayScore
 	}
@@ -115,6 +119,8 @@ func TestHomeAdvantage(t *testing.T) {
 }
 
 func TestGenerateGoalsPoisson(t *testing.T) {
+	rng := rand.New(rand.NewSource(4))
+
 	testCases := []struct {
 		name          string
 		lambda        float64
@@ -133,7 +139,7 @@ func TestGenerateGoalsPoisson(t *testing.T) {
 			goalCounts := make(map[int]int)
 
 			for i := 0; i < iterations; i++ {
-				goals := generateGoalsPoisson(tc.lambda)
+				goals := generateGoalsPoisson(rng, tc.lambda)
 
 				if goals < tc.expectedRange[0] || goals > tc.expectedRange[1] {
 					t.Errorf("Goals %d outside expected range [%d, %d]",
@@ -160,13 +166,15 @@ func TestGenerateGoalsPoisson(t *testing.T) {
 }
 
 func TestGenerateGoalsPoissonDistribution(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+
 	// Test that Poisson distribution produces expected frequencies
 	lambda := 1.5
 	iterations := 10000
 	goalCounts := make(map[int]int)
 
 	for i := 0; i < iterations; i++ {
-		goals := generateGoalsPoisson(lambda)
+		goals := generateGoalsPoisson(rng, lambda)
 		goalCounts[goals]++
 	}
 
@@ -197,22 +205,429 @@ func TestGenerateGoalsPoissonDistribution(t *testing.T) {
 }
 
 func TestGenerateGoalsPoissonNegativeLambda(t *testing.T) {
+	rng := rand.New(rand.NewSource(6))
+
 	// Negative lambda should return 0
-	goals := generateGoalsPoisson(-1.0)
+	goals := generateGoalsPoisson(rng, -1.0)
 	if goals != 0 {
 		t.Errorf("Expected 0 goals for negative lambda, got %d", goals)
 	}
 }
 
 func TestGenerateGoalsPoissonMaxCap(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+
 	// Very high lambda should still be capped at maxGoalsPerTeam
 	highLambda := 10.0
 	iterations := 100
 
 	for i := 0; i < iterations; i++ {
-		goals := generateGoalsPoisson(highLambda)
+		goals := generateGoalsPoisson(rng, highLambda)
 		if goals > maxGoalsPerTeam {
 			t.Errorf("Goals %d exceeds max %d", goals, maxGoalsPerTeam)
 		}
 	}
 }
+
+// mockMatchRepository implements repository.MatchRepository for testing
+type mockMatchRepository struct {
+	matches []models.Match
+	teams   map[uint]models.Team
+}
+
+func (m *mockMatchRepository) withTeams(match models.Match) models.Match {
+	match.HomeTeam = m.teams[match.HomeTeamID]
+	match.AwayTeam = m.teams[match.AwayTeamID]
+	return match
+}
+
+func (m *mockMatchRepository) Create(match *models.Match) error {
+	match.ID = uint(len(m.matches) + 1)
+	m.matches = append(m.matches, *match)
+	return nil
+}
+
+func (m *mockMatchRepository) CreateBatch(matches []models.Match) error {
+	for i := range matches {
+		if err := m.Create(&matches[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockMatchRepository) FindAll(_ uint) ([]models.Match, error) {
+	matches := make([]models.Match, len(m.matches))
+	for i, match := range m.matches {
+		matches[i] = m.withTeams(match)
+	}
+	return matches, nil
+}
+
+func (m *mockMatchRepository) FindByID(_, id uint) (*models.Match, error) {
+	for _, match := range m.matches {
+		if match.ID == id {
+			found := m.withTeams(match)
+			return &found, nil
+		}
+	}
+	return nil, errors.New("match not found")
+}
+
+func (m *mockMatchRepository) FindByWeek(_ uint, week int) ([]models.Match, error) {
+	var matches []models.Match
+	for _, match := range m.matches {
+		if match.Week == week {
+			matches = append(matches, m.withTeams(match))
+		}
+	}
+	return matches, nil
+}
+
+func (m *mockMatchRepository) FindPlayedMatches(_ uint) ([]models.Match, error) {
+	var matches []models.Match
+	for _, match := range m.matches {
+		if match.Played {
+			matches = append(matches, m.withTeams(match))
+		}
+	}
+	return matches, nil
+}
+
+func (m *mockMatchRepository) Update(match *models.Match) error {
+	for i := range m.matches {
+		if m.matches[i].ID == match.ID {
+			m.matches[i] = *match
+			return nil
+		}
+	}
+	return errors.New("match not found")
+}
+
+func (m *mockMatchRepository) DeleteAll(_ uint) error {
+	m.matches = nil
+	return nil
+}
+
+func (m *mockMatchRepository) GetMaxWeek(_ uint) (int, error) {
+	maxWeek := 0
+	for _, match := range m.matches {
+		maxWeek = max(maxWeek, match.Week)
+	}
+	return maxWeek, nil
+}
+
+// mockLeagueStateRepository implements repository.LeagueStateRepository for testing
+type mockLeagueStateRepository struct {
+	state *models.LeagueState
+}
+
+func (m *mockLeagueStateRepository) Get(leagueID uint) (*models.LeagueState, error) {
+	if m.state == nil {
+		m.state = &models.LeagueState{LeagueID: leagueID, TotalWeeks: 6}
+	}
+	state := *m.state
+	return &state, nil
+}
+
+func (m *mockLeagueStateRepository) Create(state *models.LeagueState) error {
+	m.state = state
+	return nil
+}
+
+func (m *mockLeagueStateRepository) Update(state *models.LeagueState) error {
+	if m.state != nil && state.Version != m.state.Version {
+		return repository.ErrStaleState
+	}
+	updated := *state
+	updated.Version++
+	state.Version = updated.Version
+	m.state = &updated
+	return nil
+}
+
+func (m *mockLeagueStateRepository) Reset(_ uint) error {
+	m.state = nil
+	return nil
+}
+
+// mockTransactor runs transactions straight on the mock repositories; nothing is rolled back
+type mockTransactor struct {
+	repos  repository.Repositories
+	before func() // Called as each transaction starts
+}
+
+func (m *mockTransactor) Transaction(fn func(repos repository.Repositories) error) error {
+	if m.before != nil {
+		m.before()
+	}
+	return fn(m.repos)
+}
+
+// newSeededLeague builds a simulation service over the default teams with generated fixtures
+func newSeededLeague(t *testing.T, seed int64) (SimulationService, *mockMatchRepository) {
+	t.Helper()
+
+	service, matchRepo, _ := newSeededLeagueWithSeasons(t, seed)
+	return service, matchRepo
+}
+
+// newSeededLeagueWithSeasons is newSeededLeague that also exposes the season archive
+func newSeededLeagueWithSeasons(t *testing.T, seed int64) (SimulationService, *mockMatchRepository, *mockSeasonRepository) {
+	t.Helper()
+
+	return newSeededLeagueWithSquads(t, seed, &mockPlayerRepository{}, &mockMatchEventRepository{}, &mockAbsenceRepository{})
+}
+
+// newSeededLeagueWithSquads is newSeededLeagueWithSeasons playing with the given squads and
+// keeping the match events in eventRepo and the absences in absenceRepo
+func newSeededLeagueWithSquads(
+	t *testing.T, seed int64, playerRepo *mockPlayerRepository, eventRepo *mockMatchEventRepository,
+	absenceRepo *mockAbsenceRepository,
+) (SimulationService, *mockMatchRepository, *mockSeasonRepository) {
+	t.Helper()
+
+	teamRepo := &mockTeamRepository{}
+	if err := teamRepo.SeedDefault(1); err != nil {
+		t.Fatalf("Failed to seed teams: %v", err)
+	}
+	teams := make(map[uint]models.Team)
+	for _, team := range teamRepo.teams {
+		teams[team.ID] = team
+	}
+
+	matchRepo := &mockMatchRepository{teams: teams}
+	leagueRepo := &mockLeagueStateRepository{state: &models.LeagueState{LeagueID: 1, Seed: seed}}
+
+	groupRepo := &mockGroupRepository{}
+
+	if _, err := newFixtureService(teamRepo, matchRepo, leagueRepo, groupRepo).GenerateFixtures(1); err != nil {
+		t.Fatalf("Failed to generate fixtures: %v", err)
+	}
+
+	seasonRepo := &mockSeasonRepository{}
+	knockoutRepo := &mockKnockoutRepository{matchRepo: matchRepo}
+	ratingRepo := &mockRatingRepository{}
+	seasons := NewSeasonService(matchRepo, teamRepo, leagueRepo, seasonRepo, knockoutRepo)
+	knockout := NewKnockoutService(matchRepo, teamRepo, leagueRepo, knockoutRepo, groupRepo)
+	availability := NewAvailabilityService(playerRepo, absenceRepo, eventRepo, matchRepo, teamRepo, leagueRepo)
+	transactor := &mockTransactor{repos: repository.Repositories{
+		Teams: teamRepo, Matches: matchRepo, LeagueStates: leagueRepo, Seasons: seasonRepo, Knockout: knockoutRepo,
+		Groups: groupRepo, Ratings: ratingRepo, MatchEvents: eventRepo, Players: playerRepo, Absences: absenceRepo,
+	}}
+	ratings := NewRatingService(teamRepo, matchRepo, leagueRepo, ratingRepo, transactor)
+	service := NewSimulationService(matchRepo, teamRepo, leagueRepo, groupRepo, seasons, knockout, ratings, eventRepo,
+		availability, transactor)
+	return service, matchRepo, seasonRepo
+}
+
+func scoreline(match *models.Match) string {
+	return fmt.Sprintf("%d-%d %d-%d", match.HomeTeamID, match.AwayTeamID, *match.HomeScore, *match.AwayScore)
+}
+
+func TestPlayAllWeeksGolden(t *testing.T) {
+	service, matchRepo := newSeededLeague(t, 42)
+
+	if _, err := service.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Fixture order with "home-away homeScore-awayScore" for seed 42
+	expected := []string{
+		"1-4 1-2", "2-3 1-1", "3-1 3-3", "2-4 1-0", "1-2 3-1", "3-4 5-4",
+		"4-1 0-2", "3-2 2-2", "1-3 1-0", "4-2 2-1", "2-1 0-2", "4-3 1-4",
+	}
+	if len(matchRepo.matches) != len(expected) {
+		t.Fatalf("Expected %d matches, got %d", len(expected), len(matchRepo.matches))
+	}
+
+	for i := range matchRepo.matches {
+		match := &matchRepo.matches[i]
+		if !match.Played || match.Seed == nil {
+			t.Fatalf("Match %d should be played with a recorded seed", match.ID)
+		}
+		if scoreline(match) != expected[i] {
+			t.Errorf("Match %d: expected %s, got %s", match.ID, expected[i], scoreline(match))
+		}
+	}
+}
+
+func TestPlayAllWeeksIsReproducible(t *testing.T) {
+	first, firstRepo := newSeededLeague(t, 7)
+	second, secondRepo := newSeededLeague(t, 7)
+
+	if _, err := first.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := second.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := range firstRepo.matches {
+		a, b := &firstRepo.matches[i], &secondRepo.matches[i]
+		if scoreline(a) != scoreline(b) || *a.Seed != *b.Seed {
+			t.Errorf("Match %d differs between runs with the same seed: %s vs %s", a.ID, scoreline(a), scoreline(b))
+		}
+	}
+}
+
+func TestPlayNextWeekRequestSeedOverridesLeagueSeed(t *testing.T) {
+	override := int64(1234)
+	first, firstRepo := newSeededLeague(t, 1)
+	second, secondRepo := newSeededLeague(t, 2)
+
+	if _, err := first.PlayNextWeek(1, &override); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := second.PlayNextWeek(1, &override); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	firstWeek, _ := firstRepo.FindByWeek(1, 1)
+	secondWeek, _ := secondRepo.FindByWeek(1, 1)
+	for i := range firstWeek {
+		if scoreline(&firstWeek[i]) != scoreline(&secondWeek[i]) {
+			t.Errorf("Expected identical results with the same request seed: %s vs %s",
+				scoreline(&firstWeek[i]), scoreline(&secondWeek[i]))
+		}
+	}
+}
+
+func TestReplayMatchFromRecordedSeed(t *testing.T) {
+	service, _ := newSeededLeague(t, 99)
+
+	played, err := service.PlayNextWeek(1, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := range played {
+		match := &played[i]
+		rng := rand.New(rand.NewSource(*match.Seed))
+		homeScore, awayScore := simulateMatch(rng, &match.HomeTeam, &match.AwayTeam)
+		if homeScore != *match.HomeScore || awayScore != *match.AwayScore {
+			t.Errorf("Replaying match %d gave %d-%d, recorded %d-%d",
+				match.ID, homeScore, awayScore, *match.HomeScore, *match.AwayScore)
+		}
+	}
+}
+
+func TestPlayNextWeekStaleState(t *testing.T) {
+	service, _ := newSeededLeague(t, 42)
+	simulation := service.(*simulationService)
+	leagueRepo := simulation.leagueRepo.(*mockLeagueStateRepository)
+	transactor := simulation.transactor.(*mockTransactor)
+	version := leagueRepo.state.Version
+
+	// Another request saves the league between this one reading it and saving the week
+	transactor.before = func() {
+		leagueRepo.state.Version++
+	}
+	if _, err := service.PlayNextWeek(1, nil); !errors.Is(err, ErrStateChanged) {
+		t.Fatalf("Expected ErrStateChanged, got %v", err)
+	}
+	if _, err := service.UpdateSettings(1, models.LeagueSettings{}); !errors.Is(err, ErrStateChanged) {
+		t.Fatalf("Expected ErrStateChanged updating settings, got %v", err)
+	}
+	state, _ := leagueRepo.Get(1)
+	if state.CurrentWeek != 0 || state.Version != version+2 {
+		t.Fatalf("Expected the state left as the other requests saved it, got week %d version %d",
+			state.CurrentWeek, state.Version)
+	}
+
+	// A request reading the league afresh goes through
+	transactor.before = nil
+	if _, err := service.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	state, _ = leagueRepo.Get(1)
+	if state.CurrentWeek != 1 || state.Version != version+3 {
+		t.Errorf("Expected week 1 saved as the next version, got week %d version %d", state.CurrentWeek, state.Version)
+	}
+}
+
+func TestUpdateSettingsTiebreakers(t *testing.T) {
+	service, _ := newSeededLeague(t, 1)
+
+	preset := models.TiebreakerPresetUEFAGroupStage
+	state, err := service.UpdateSettings(1, models.LeagueSettings{TiebreakerPreset: &preset})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if state.TiebreakerPreset != preset || state.TiebreakerRules()[0] != models.TiebreakerHeadToHeadPoints {
+		t.Errorf("Expected the UEFA chain, got %s %v", state.TiebreakerPreset, state.TiebreakerRules())
+	}
+
+	custom := []models.TiebreakerRule{models.TiebreakerWins, models.TiebreakerDrawingOfLots}
+	state, err = service.UpdateSettings(1, models.LeagueSettings{Tiebreakers: custom})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if state.TiebreakerPreset != models.TiebreakerPresetCustom || state.Tiebreakers != "wins,drawing_of_lots" {
+		t.Errorf("Expected custom chain, got %s %q", state.TiebreakerPreset, state.Tiebreakers)
+	}
+
+	invalid := [][]models.TiebreakerRule{
+		{},
+		{"coin_toss"},
+		{models.TiebreakerWins, models.TiebreakerWins},
+	}
+	for _, rules := range invalid {
+		if _, err := service.UpdateSettings(1, models.LeagueSettings{Tiebreakers: rules}); !errors.Is(err, ErrInvalidSettings) {
+			t.Errorf("Expected an error for tiebreakers %v", rules)
+		}
+	}
+
+	unknown := "la_liga"
+	if _, err := service.UpdateSettings(1, models.LeagueSettings{TiebreakerPreset: &unknown}); err == nil {
+		t.Errorf("Expected an error for an unknown preset")
+	}
+}
+
+// The handler leaves the settings' ranges to the service, so each one out of range has to be
+// rejected here as invalid settings
+func TestUpdateSettingsOutOfRange(t *testing.T) {
+	service, _ := newSeededLeague(t, 1)
+
+	seed, knockoutTeams, groups, format := int64(-1), 6, 3, "league"
+	engine, maxGoals, ratingK, interval := "coin_toss", 0, 0.0, 29
+	penalty, futureEdits := 1.5, "sometimes"
+	outOfRange := []models.LeagueSettings{
+		{Seed: &seed},
+		{KnockoutTeams: &knockoutTeams},
+		{Groups: &groups},
+		{Format: &format},
+		{Engine: &engine},
+		{EngineParams: &models.MatchEngineSettings{MaxGoals: &maxGoals}},
+		{RatingK: &ratingK},
+		{MatchdayInterval: &interval},
+		{Fatigue: &models.FatigueSettings{Penalty: &penalty}},
+		{FutureEdits: &futureEdits},
+	}
+	for _, settings := range outOfRange {
+		if _, err := service.UpdateSettings(1, settings); !errors.Is(err, ErrInvalidSettings) {
+			t.Errorf("Expected ErrInvalidSettings for %+v, got %v", settings, err)
+		}
+	}
+}
+
+func TestUpdateSettingsRejected(t *testing.T) {
+	service, _ := newSeededLeague(t, 1)
+
+	knockoutTeams, swiss, groups, interval := 8, models.LeagueFormatSwiss, 2, 3
+	rejected := []struct {
+		settings models.LeagueSettings
+		err      error
+	}{
+		{models.LeagueSettings{KnockoutTeams: &knockoutTeams}, ErrTooManyKnockoutTeams},
+		{models.LeagueSettings{Format: &swiss}, ErrFormatLocked},
+		{models.LeagueSettings{Groups: &groups}, ErrGroupsLocked},
+		{models.LeagueSettings{MatchdayInterval: &interval}, ErrScheduleLocked},
+	}
+	for _, test := range rejected {
+		_, err := service.UpdateSettings(1, test.settings)
+		if !errors.Is(err, test.err) || !errors.Is(err, ErrInvalidSettings) {
+			t.Errorf("Expected %v, got %v", test.err, err)
+		}
+	}
+}
@@ -1,48 +1,242 @@
 package services
 
 import (
+	"fmt"
+	"hash/fnv"
 	"math"
+	"math/rand"
 	"sort"
+	"sync"
 
 	"github.com/zahidcakici/champions-league/internal/models"
 	"github.com/zahidcakici/champions-league/internal/repository"
 )
 
+// predictionIterations is the number of simulated seasons behind each championship prediction
+const predictionIterations = 10000
+
 type StandingsService interface {
-	GetStandings() ([]models.TeamStanding, error)
-	GetPredictions() ([]models.ChampionshipPrediction, error)
-	GetFullState() (*models.SimulationState, error)
+	GetStandings(leagueID uint) ([]models.TeamStanding, error)
+	GetGroupStandings(leagueID uint) ([]models.GroupTable, error)
+	GetPredictions(leagueID uint) (*models.PredictionResult, error)
+	GetPositionPredictions(leagueID uint) (*models.PositionPredictionResult, error)
+	GetFullState(leagueID uint) (*models.SimulationState, error)
 }
 
 type standingsService struct {
-	matchRepo  repository.MatchRepository
-	teamRepo   repository.TeamRepository
-	leagueRepo repository.LeagueStateRepository
+	matchRepo    repository.MatchRepository
+	teamRepo     repository.TeamRepository
+	leagueRepo   repository.LeagueStateRepository
+	knockoutRepo repository.KnockoutRepository
+	groupRepo    repository.GroupRepository
+
+	// Predictions and races of each league, kept until the league changes
+	cacheMu     sync.Mutex
+	predictions map[uint]*predictionCache
+}
+
+// predictionKey identifies what a league's predictions and races were worked out from: every result,
+// fixture, draw and setting change moves the league state to a new version, and teams can change
+// without touching the state
+type predictionKey struct {
+	version int
+	teams   uint64 // Fingerprint of the teams' names and strengths
+}
+
+// predictionCache holds the predictions and races worked out for a key, nil until first asked
+// for. They are shared between callers and must not be changed.
+type predictionCache struct {
+	key        predictionKey
+	titles     *models.PredictionResult
+	positions  *models.PositionPredictionResult
+	races      map[uint]teamRaces // Of the single table, by team
+	groupRaces map[uint]teamRaces // Of the group tables, by team
+}
+
+// teamRaces is a team's races for the top places of its table
+type teamRaces struct {
+	title, qualification *models.PlaceRace
 }
 
 func NewStandingsService(
 	matchRepo repository.MatchRepository,
 	teamRepo repository.TeamRepository,
 	leagueRepo repository.LeagueStateRepository,
+	knockoutRepo repository.KnockoutRepository,
+	groupRepo repository.GroupRepository,
 ) StandingsService {
 	return &standingsService{
-		matchRepo:  matchRepo,
-		teamRepo:   teamRepo,
-		leagueRepo: leagueRepo,
+		matchRepo:    matchRepo,
+		teamRepo:     teamRepo,
+		leagueRepo:   leagueRepo,
+		knockoutRepo: knockoutRepo,
+		groupRepo:    groupRepo,
+		predictions:  make(map[uint]*predictionCache),
+	}
+}
+
+// newPredictionKey returns the key of predictions worked out from the state and teams
+func newPredictionKey(state *models.LeagueState, teams []models.Team) predictionKey {
+	hash := fnv.New64a()
+	for _, team := range teams {
+		fmt.Fprintf(hash, "%d|%s|%d|%d|%d|%g;", team.ID, team.Name, team.Power, team.Attack, team.Defence, team.Rating)
+	}
+	return predictionKey{version: state.Version, teams: hash.Sum64()}
+}
+
+// cachedPredictions returns the league's predictions kept for key, empty if there are none
+func (s *standingsService) cachedPredictions(leagueID uint, key predictionKey) predictionCache {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	cache, ok := s.predictions[leagueID]
+	if !ok || cache.key != key {
+		return predictionCache{key: key}
+	}
+	return *cache
+}
+
+// storePredictions keeps predictions worked out for key, dropping those of any other key
+func (s *standingsService) storePredictions(leagueID uint, key predictionKey, store func(cache *predictionCache)) {
+	s.cacheMu.Lock()
+	defer s.cacheMu.Unlock()
+	cache, ok := s.predictions[leagueID]
+	if !ok || cache.key != key {
+		cache = &predictionCache{key: key}
+		s.predictions[leagueID] = cache
 	}
+	store(cache)
 }
 
-func (s *standingsService) GetStandings() ([]models.TeamStanding, error) {
-	teams, err := s.teamRepo.FindAll()
+// markCachedRaces marks the races of tables with mark, or with those worked out before for key.
+// grouped tells group tables from the single table.
+func (s *standingsService) markCachedRaces(
+	leagueID uint,
+	key predictionKey,
+	grouped bool,
+	tables [][]models.TeamStanding,
+	mark func(table []models.TeamStanding),
+) {
+	cache := s.cachedPredictions(leagueID, key)
+	races := cache.races
+	if grouped {
+		races = cache.groupRaces
+	}
+	if races != nil {
+		for _, table := range tables {
+			for i := range table {
+				race := races[table[i].TeamID]
+				table[i].TitleRace, table[i].QualificationRace = race.title, race.qualification
+			}
+		}
+		return
+	}
+
+	races = make(map[uint]teamRaces)
+	for _, table := range tables {
+		mark(table)
+		for i := range table {
+			races[table[i].TeamID] = teamRaces{title: table[i].TitleRace, qualification: table[i].QualificationRace}
+		}
+	}
+	s.storePredictions(leagueID, key, func(cache *predictionCache) {
+		if grouped {
+			cache.groupRaces = races
+		} else {
+			cache.races = races
+		}
+	})
+}
+
+func (s *standingsService) GetStandings(leagueID uint) ([]models.TeamStanding, error) {
+	state, err := s.leagueRepo.Get(leagueID)
 	if err != nil {
 		return nil, err
 	}
 
-	matches, err := s.matchRepo.FindPlayedMatches()
+	teams, err := s.teamRepo.FindAll(leagueID)
 	if err != nil {
 		return nil, err
 	}
 
+	matches, err := s.matchRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	standings := calculateStandings(teams, matches, leagueTiebreakChain(state))
+	markQualification(standings, state)
+	if state.Groups == 0 {
+		tables := [][]models.TeamStanding{standings}
+		s.markCachedRaces(leagueID, newPredictionKey(state, teams), false, tables, func(table []models.TeamStanding) {
+			markRaces(table, matches, state.KnockoutTeams == 0, state.KnockoutQualifiers())
+		})
+	}
+	return standings, nil
+}
+
+// markQualification marks the knockout zones of a single league table: the bracket places, or
+// after a Swiss league phase the direct places and the play-off places. Group stages qualify
+// group by group instead.
+func markQualification(table []models.TeamStanding, state *models.LeagueState) {
+	if state.KnockoutTeams == 0 || state.Groups > 0 {
+		return
+	}
+
+	direct := state.KnockoutTeams
+	if state.HasPlayoff() {
+		direct = state.KnockoutTeams / 2
+	}
+	for i := range table {
+		switch {
+		case i < direct:
+			table[i].Qualification = models.QualificationKnockout
+		case i < state.KnockoutQualifiers():
+			table[i].Qualification = models.QualificationPlayoff
+		}
+	}
+}
+
+// GetGroupStandings returns the table of every group, in group order; empty until the groups are drawn
+func (s *standingsService) GetGroupStandings(leagueID uint) ([]models.GroupTable, error) {
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.groupRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return []models.GroupTable{}, nil
+	}
+
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := s.matchRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := leagueTiebreakChain(state)
+	table := calculateStandings(teams, matches, chain)
+	tables := calculateGroupTables(entries, table, playedScores(matches), chain)
+	standings := make([][]models.TeamStanding, len(tables))
+	for i := range tables {
+		standings[i] = tables[i].Standings
+	}
+	s.markCachedRaces(leagueID, newPredictionKey(state, teams), true, standings, func(table []models.TeamStanding) {
+		markRaces(table, matches, false, groupQualifiers)
+	})
+	return tables, nil
+}
+
+// calculateStandings builds the league table for the given teams from played league matches, ranked
+// with the tiebreaker chain; unplayed matches only count towards each team's remaining fixtures
+func calculateStandings(teams []models.Team, matches []models.Match, chain tiebreakChain) []models.TeamStanding {
 	// Initialize standings for all teams
 	standingsMap := make(map[uint]*models.TeamStanding)
 	for _, team := range teams {
@@ -54,144 +248,310 @@ func (s *standingsService) GetStandings() ([]models.TeamStanding, error) {
 
 	// Calculate standings from played matches
 	for _, match := range matches {
-		if match.HomeScore == nil || match.AwayScore == nil {
+		if match.IsKnockout() {
 			continue
 		}
 
-		homeStanding := standingsMap[match.HomeTeamID]
-		awayStanding := standingsMap[match.AwayTeamID]
-
-		homeStanding.Played++
-		awayStanding.Played++
-
-		homeStanding.GoalsFor += *match.HomeScore
-		homeStanding.GoalsAgainst += *match.AwayScore
-		awayStanding.GoalsFor += *match.AwayScore
-		awayStanding.GoalsAgainst += *match.HomeScore
+		homeStanding, awayStanding := standingsMap[match.HomeTeamID], standingsMap[match.AwayTeamID]
+		if homeStanding == nil || awayStanding == nil {
+			continue
+		}
 
-		switch {
-		case *match.HomeScore > *match.AwayScore:
-			// Home win
-			homeStanding.Won++
-			homeStanding.Points += 3
-			awayStanding.Lost++
-		case *match.HomeScore < *match.AwayScore:
-			// Away win
-			awayStanding.Won++
-			awayStanding.Points += 3
-			homeStanding.Lost++
-		default:
-			// Draw
-			homeStanding.Drawn++
-			awayStanding.Drawn++
-			homeStanding.Points++
-			awayStanding.Points++
+		if !match.Played || match.HomeScore == nil || match.AwayScore == nil {
+			homeStanding.Remaining++
+			awayStanding.Remaining++
+			continue
 		}
+
+		applyMatchResult(homeStanding, awayStanding, *match.HomeScore, *match.AwayScore)
 	}
 
-	// Calculate goal difference and convert to slice
-	var standings []models.TeamStanding
-	for _, standing := range standingsMap {
-		standing.GoalDifference = standing.GoalsFor - standing.GoalsAgainst
-		standings = append(standings, *standing)
+	// Convert to slice, keeping team order stable before sorting
+	standings := make([]models.TeamStanding, 0, len(teams))
+	for _, team := range teams {
+		standings = append(standings, *standingsMap[team.ID])
 	}
 
-	// Sort by points (desc), then goal difference (desc), then goals for (desc)
-	sort.Slice(standings, func(i, j int) bool {
-		if standings[i].Points != standings[j].Points {
-			return standings[i].Points > standings[j].Points
-		}
-		if standings[i].GoalDifference != standings[j].GoalDifference {
-			return standings[i].GoalDifference > standings[j].GoalDifference
-		}
-		return standings[i].GoalsFor > standings[j].GoalsFor
-	})
+	rankStandings(standings, playedScores(matches), chain)
 
-	return standings, nil
+	return standings
+}
+
+// applyMatchResult adds a single match result to both teams' standings
+func applyMatchResult(home, away *models.TeamStanding, homeScore, awayScore int) {
+	home.Played++
+	away.Played++
+
+	home.GoalsFor += homeScore
+	home.GoalsAgainst += awayScore
+	away.GoalsFor += awayScore
+	away.GoalsAgainst += homeScore
+
+	switch {
+	case homeScore > awayScore:
+		// Home win
+		home.Won++
+		home.Points += 3
+		away.Lost++
+	case homeScore < awayScore:
+		// Away win
+		away.Won++
+		away.Points += 3
+		home.Lost++
+	default:
+		// Draw
+		home.Drawn++
+		away.Drawn++
+		home.Points++
+		away.Points++
+	}
+
+	home.GoalDifference = home.GoalsFor - home.GoalsAgainst
+	away.GoalDifference = away.GoalsFor - away.GoalsAgainst
 }
 
-func (s *standingsService) GetPredictions() ([]models.ChampionshipPrediction, error) {
-	state, err := s.leagueRepo.Get()
+// GetPredictions estimates each team's championship probability with a Monte Carlo simulation:
+// the remaining unplayed fixtures are simulated predictionIterations times with the match engine
+// and we count how often each team finishes top of the table, or wins the knockout stage. The
+// result is kept until the league changes.
+func (s *standingsService) GetPredictions(leagueID uint) (*models.PredictionResult, error) {
+	state, err := s.leagueRepo.Get(leagueID)
 	if err != nil {
 		return nil, err
 	}
 
-	standings, err := s.GetStandings()
+	teams, err := s.teamRepo.FindAll(leagueID)
 	if err != nil {
 		return nil, err
 	}
 
-	predictions := make([]models.ChampionshipPrediction, len(standings))
+	key := newPredictionKey(state, teams)
+	if cached := s.cachedPredictions(leagueID, key); cached.titles != nil {
+		return cached.titles, nil
+	}
 
-	// Predictions only start when there are 3 or fewer weeks remaining
-	// This gives enough data for meaningful predictions while keeping excitement
-	remainingWeeks := state.TotalWeeks - state.CurrentWeek
-	if remainingWeeks > 3 {
-		for i, standing := range standings {
-			predictions[i] = models.ChampionshipPrediction{
-				TeamID:     standing.TeamID,
-				TeamName:   standing.TeamName,
-				Percentage: 0,
-			}
-		}
-		return predictions, nil
+	matches, err := s.matchRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Calculate max remaining points (each team plays 1 match per week)
-	maxRemainingPoints := remainingWeeks * 3
+	ties, err := s.knockoutRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
 
-	// Get the leader's points
-	if len(standings) == 0 {
-		return predictions, nil
+	groups, err := s.groupRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
 	}
 
-	leaderPoints := standings[0].Points
+	chain := leagueTiebreakChain(state)
+	knockout := newKnockoutPlan(state, groups, ties)
+	standings := calculateStandings(teams, matches, chain)
+	result := &models.PredictionResult{
+		Iterations:  predictionIterations,
+		Predictions: make([]models.ChampionshipPrediction, len(standings)),
+	}
+	if len(standings) == 0 {
+		return result, nil
+	}
 
-	// Calculate championship probability based on points gap and remaining matches
-	totalWeight := 0.0
-	weights := make([]float64, len(standings))
+	// Seed from the league state so the same table always yields the same predictions
+	rng := rand.New(rand.NewSource(deriveSeed(state.Seed, int64(state.CurrentWeek))))
+	titles := runChampionshipSimulations(rng, newMatchEngine(state), teams, matches, chain, knockout, predictionIterations)
 
 	for i, standing := range standings {
-		pointsGap := leaderPoints - standing.Points
+		percentage := float64(titles[standing.TeamID]) / float64(predictionIterations) * 100
+		result.Predictions[i] = models.ChampionshipPrediction{
+			TeamID:     standing.TeamID,
+			TeamName:   standing.TeamName,
+			Percentage: math.Round(percentage*10) / 10,
+		}
+	}
 
-		// If a team can't mathematically catch up, their chance is 0
-		if pointsGap > maxRemainingPoints {
-			weights[i] = 0
-		} else {
-			// Weight based on current points and ability to catch up
-			// Teams closer to the leader have higher probability
-			// Also factor in goal difference as tiebreaker potential
-			weight := float64(standing.Points+1) * math.Pow(0.7, float64(pointsGap))
-
-			// Bonus for positive goal difference
-			if standing.GoalDifference > 0 {
-				weight *= 1.1
-			}
+	// Ensure percentages sum to 100%
+	s.normalizePercentages(result.Predictions)
 
-			weights[i] = weight
-		}
-		totalWeight += weights[i]
+	s.storePredictions(leagueID, key, func(cache *predictionCache) { cache.titles = result })
+	return result, nil
+}
+
+// GetPositionPredictions estimates each team's chances of every final position in the league
+// table, its expected points and percentiles of its final points with the same Monte Carlo
+// simulation as GetPredictions. Knockout matches aren't played; the table is the one GetStandings
+// returns at the end of the league stage. The result is kept until the league changes.
+func (s *standingsService) GetPositionPredictions(leagueID uint) (*models.PositionPredictionResult, error) {
+	state, err := s.leagueRepo.Get(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	teams, err := s.teamRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := newPredictionKey(state, teams)
+	if cached := s.cachedPredictions(leagueID, key); cached.positions != nil {
+		return cached.positions, nil
+	}
+
+	matches, err := s.matchRepo.FindAll(leagueID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Normalize to percentages
+	chain := leagueTiebreakChain(state)
+	standings := calculateStandings(teams, matches, chain)
+	result := &models.PositionPredictionResult{
+		Iterations:  predictionIterations,
+		Predictions: make([]models.PositionPrediction, len(standings)),
+	}
+	if len(standings) == 0 {
+		return result, nil
+	}
+
+	// Seeded like the championship predictions, so a league without a knockout stage gets the same title odds
+	rng := rand.New(rand.NewSource(deriveSeed(state.Seed, int64(state.CurrentWeek))))
+	positions, points := runPositionSimulations(rng, newMatchEngine(state), teams, matches, chain, predictionIterations)
+
 	for i, standing := range standings {
-		percentage := 0.0
-		if totalWeight > 0 {
-			percentage = (weights[i] / totalWeight) * 100
+		prediction := models.PositionPrediction{
+			TeamID:    standing.TeamID,
+			TeamName:  standing.TeamName,
+			Positions: make([]float64, len(standings)),
+		}
+		for position, count := range positions[standing.TeamID] {
+			percentage := float64(count) / float64(predictionIterations) * 100
+			prediction.Positions[position] = math.Round(percentage*10) / 10
 		}
 
-		// Round to nearest integer
-		predictions[i] = models.ChampionshipPrediction{
-			TeamID:     standing.TeamID,
-			TeamName:   standing.TeamName,
-			Percentage: math.Round(percentage),
+		teamPoints := points[standing.TeamID]
+		total := 0
+		for _, p := range teamPoints {
+			total += p
 		}
+		prediction.ExpectedPoints = math.Round(float64(total)/float64(len(teamPoints))*10) / 10
+		sort.Ints(teamPoints)
+		prediction.Points = models.PointsBands{
+			P5:     percentile(teamPoints, 5),
+			P25:    percentile(teamPoints, 25),
+			Median: percentile(teamPoints, 50),
+			P75:    percentile(teamPoints, 75),
+			P95:    percentile(teamPoints, 95),
+		}
+		result.Predictions[i] = prediction
 	}
 
-	// Ensure percentages sum to 100%
-	s.normalizePercentages(predictions)
+	s.storePredictions(leagueID, key, func(cache *predictionCache) { cache.positions = result })
+	return result, nil
+}
+
+// percentile returns the nearest-rank pth percentile of sorted values
+func percentile(sorted []int, p int) int {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// runChampionshipSimulations plays out the unplayed matches the given number of times and returns
+// how many simulated seasons each team won: finishing first in the table ranked with the chain,
+// or winning the knockout stage when there is one
+func runChampionshipSimulations(
+	rng *rand.Rand,
+	engine MatchEngine,
+	teams []models.Team,
+	matches []models.Match,
+	chain tiebreakChain,
+	knockout knockoutPlan,
+	iterations int,
+) map[uint]int {
+	titles := make(map[uint]int, len(teams))
+	simulateSeasons(rng, engine, teams, matches, chain, iterations, func(table []models.TeamStanding, scores []matchScore, teamsByID map[uint]*models.Team) {
+		titles[knockout.champion(rng, table, scores, teamsByID)]++
+	})
+	return titles
+}
+
+// runPositionSimulations plays out the unplayed matches the given number of times and returns how
+// many simulated seasons each team finished in each position of the table ranked with the chain,
+// first place first, and its final points in every season
+func runPositionSimulations(
+	rng *rand.Rand,
+	engine MatchEngine,
+	teams []models.Team,
+	matches []models.Match,
+	chain tiebreakChain,
+	iterations int,
+) (map[uint][]int, map[uint][]int) {
+	positions := make(map[uint][]int, len(teams))
+	points := make(map[uint][]int, len(teams))
+	for _, team := range teams {
+		positions[team.ID] = make([]int, len(teams))
+		points[team.ID] = make([]int, 0, iterations)
+	}
+	simulateSeasons(rng, engine, teams, matches, chain, iterations, func(table []models.TeamStanding, _ []matchScore, _ map[uint]*models.Team) {
+		for position, standing := range table {
+			positions[standing.TeamID][position]++
+			points[standing.TeamID] = append(points[standing.TeamID], standing.Points)
+		}
+	})
+	return positions, points
+}
+
+// simulateSeasons plays out the unplayed league matches the given number of times, handing each
+// simulated season's final table, ranked with the chain, and all its scores to season
+func simulateSeasons(
+	rng *rand.Rand,
+	engine MatchEngine,
+	teams []models.Team,
+	matches []models.Match,
+	chain tiebreakChain,
+	iterations int,
+	season func(table []models.TeamStanding, scores []matchScore, teamsByID map[uint]*models.Team),
+) {
+	teamsByID := make(map[uint]*models.Team, len(teams))
+	for i := range teams {
+		teamsByID[teams[i].ID] = &teams[i]
+	}
 
-	return predictions, nil
+	var remaining []models.Match
+	for _, m := range matches {
+		if !m.Played && !m.IsKnockout() && teamsByID[m.HomeTeamID] != nil && teamsByID[m.AwayTeamID] != nil {
+			remaining = append(remaining, m)
+		}
+	}
+
+	base := calculateStandings(teams, matches, chain)
+	played := playedScores(matches)
+	scores := make([]matchScore, len(played), len(played)+len(remaining))
+	copy(scores, played)
+	table := make([]models.TeamStanding, len(base))
+	index := make(map[uint]int, len(base))
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		copy(table, base)
+		scores = scores[:len(played)]
+		for i := range table {
+			index[table[i].TeamID] = i
+		}
+
+		for _, m := range remaining {
+			homeScore, awayScore := engine.Simulate(rng, teamsByID[m.HomeTeamID], teamsByID[m.AwayTeamID], m.Neutral)
+			home, away := &table[index[m.HomeTeamID]], &table[index[m.AwayTeamID]]
+			applyMatchResult(home, away, homeScore, awayScore)
+			home.Remaining--
+			away.Remaining--
+			scores = append(scores, matchScore{
+				homeID:    m.HomeTeamID,
+				awayID:    m.AwayTeamID,
+				homeScore: homeScore,
+				awayScore: awayScore,
+			})
+		}
+
+		rankStandings(table, scores, chain)
+		season(table, scores, teamsByID)
+	}
 }
 
 func (s *standingsService) normalizePercentages(predictions []models.ChampionshipPrediction) {
@@ -213,22 +573,22 @@ func (s *standingsService) normalizePercentages(predictions []models.Championshi
 	if total != 100 {
 		diff := 100 - total
 		// Add/subtract difference from the leader
-		predictions[0].Percentage += diff
+		predictions[0].Percentage = math.Round((predictions[0].Percentage+diff)*10) / 10
 	}
 }
 
-func (s *standingsService) GetFullState() (*models.SimulationState, error) {
-	leagueState, err := s.leagueRepo.Get()
+func (s *standingsService) GetFullState(leagueID uint) (*models.SimulationState, error) {
+	leagueState, err := s.leagueRepo.Get(leagueID)
 	if err != nil {
 		return nil, err
 	}
 
-	standings, err := s.GetStandings()
+	standings, err := s.GetStandings(leagueID)
 	if err != nil {
 		return nil, err
 	}
 
-	predictions, err := s.GetPredictions()
+	predictions, err := s.GetPredictions(leagueID)
 	if err != nil {
 		return nil, err
 	}
@@ -236,7 +596,7 @@ func (s *standingsService) GetFullState() (*models.SimulationState, error) {
 	// Get current week results
 	var currentWeekResults []models.MatchResult
 	if leagueState.CurrentWeek > 0 {
-		weekMatches, matchErr := s.matchRepo.FindByWeek(leagueState.CurrentWeek)
+		weekMatches, matchErr := s.matchRepo.FindByWeek(leagueID, leagueState.CurrentWeek)
 		if matchErr != nil {
 			return nil, matchErr
 		}
@@ -254,7 +614,7 @@ func (s *standingsService) GetFullState() (*models.SimulationState, error) {
 
 	// Get all matches grouped by week
 	allMatches := make(map[int][]models.MatchResult)
-	matches, err := s.matchRepo.FindAll()
+	matches, err := s.matchRepo.FindAll(leagueID)
 	if err != nil {
 		return nil, err
 	}
@@ -271,10 +631,11 @@ func (s *standingsService) GetFullState() (*models.SimulationState, error) {
 	}
 
 	return &models.SimulationState{
-		LeagueState: *leagueState,
-		Standings:   standings,
-		CurrentWeek: currentWeekResults,
-		AllMatches:  allMatches,
-		Predictions: predictions,
+		LeagueState:          *leagueState,
+		Standings:            standings,
+		CurrentWeek:          currentWeekResults,
+		AllMatches:           allMatches,
+		Predictions:          predictions.Predictions,
+		PredictionIterations: predictions.Iterations,
 	}, nil
 }
@@ -1,6 +1,8 @@
 package services
 
 import (
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/zahidcakici/champions-league/internal/models"
@@ -204,3 +206,239 @@ func TestMatchResultToStandings(t *testing.T) {
 		})
 	}
 }
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestCalculateStandingsFromMatches(t *testing.T) {
+	teams := []models.Team{
+		{ID: 1, Name: "Team A", Power: 80},
+		{ID: 2, Name: "Team B", Power: 75},
+		{ID: 3, Name: "Team C", Power: 70},
+	}
+	matches := []models.Match{
+		{HomeTeamID: 1, AwayTeamID: 2, HomeScore: intPtr(2), AwayScore: intPtr(0), Played: true},
+		{HomeTeamID: 2, AwayTeamID: 3, HomeScore: intPtr(1), AwayScore: intPtr(1), Played: true},
+		{HomeTeamID: 3, AwayTeamID: 1}, // not played yet
+	}
+
+	standings := calculateStandings(teams, matches, leagueTiebreakChain(&models.LeagueState{}))
+
+	if len(standings) != 3 {
+		t.Fatalf("Expected 3 standings, got %d", len(standings))
+	}
+	if standings[0].TeamID != 1 || standings[0].Points != 3 || standings[0].GoalDifference != 2 {
+		t.Errorf("Expected Team A top with 3 points and GD 2, got %+v", standings[0])
+	}
+	if standings[1].TeamID != 3 || standings[1].Points != 1 {
+		t.Errorf("Expected Team C second with 1 point, got %+v", standings[1])
+	}
+	if standings[2].Played != 2 || standings[2].Lost != 1 || standings[2].Drawn != 1 {
+		t.Errorf("Expected Team B to have played 2 (1D 1L), got %+v", standings[2])
+	}
+	if standings[0].Remaining != 1 || standings[1].Remaining != 1 || standings[2].Remaining != 0 {
+		t.Errorf("Expected remaining fixtures 1/1/0, got %d/%d/%d",
+			standings[0].Remaining, standings[1].Remaining, standings[2].Remaining)
+	}
+}
+
+func TestRunChampionshipSimulations(t *testing.T) {
+	teams := []models.Team{
+		{ID: 1, Name: "Team A", Power: 80},
+		{ID: 2, Name: "Team B", Power: 80},
+	}
+	iterations := 500
+
+	t.Run("Completed season", func(t *testing.T) {
+		matches := []models.Match{
+			{HomeTeamID: 1, AwayTeamID: 2, HomeScore: intPtr(3), AwayScore: intPtr(0), Played: true},
+		}
+		titles := runChampionshipSimulations(rand.New(rand.NewSource(1)), defaultMatchEngine, teams, matches, leagueTiebreakChain(&models.LeagueState{}), knockoutPlan{}, iterations)
+		if titles[1] != iterations {
+			t.Errorf("Expected leader to win every simulation, got %d/%d", titles[1], iterations)
+		}
+	})
+
+	t.Run("Open season", func(t *testing.T) {
+		matches := []models.Match{
+			{HomeTeamID: 1, AwayTeamID: 2},
+			{HomeTeamID: 2, AwayTeamID: 1},
+		}
+		titles := runChampionshipSimulations(rand.New(rand.NewSource(1)), defaultMatchEngine, teams, matches, leagueTiebreakChain(&models.LeagueState{}), knockoutPlan{}, iterations)
+		if titles[1]+titles[2] != iterations {
+			t.Errorf("Expected every simulation to crown a champion, got %d", titles[1]+titles[2])
+		}
+		if titles[1] == 0 || titles[2] == 0 {
+			t.Errorf("Expected both equal teams to win some simulations, got %v", titles)
+		}
+	})
+}
+
+func TestRunPositionSimulations(t *testing.T) {
+	teams := []models.Team{
+		{ID: 1, Name: "Team A", Power: 90},
+		{ID: 2, Name: "Team B", Power: 60},
+		{ID: 3, Name: "Team C", Power: 60},
+	}
+	matches := []models.Match{
+		{HomeTeamID: 1, AwayTeamID: 2, HomeScore: intPtr(2), AwayScore: intPtr(0), Played: true},
+		{HomeTeamID: 2, AwayTeamID: 3},
+		{HomeTeamID: 3, AwayTeamID: 1},
+	}
+	iterations := 500
+
+	positions, points := runPositionSimulations(rand.New(rand.NewSource(1)), defaultMatchEngine, teams, matches, leagueTiebreakChain(&models.LeagueState{}), iterations)
+	for position := range teams {
+		total := 0
+		for _, team := range teams {
+			total += positions[team.ID][position]
+		}
+		if total != iterations {
+			t.Errorf("Position %d: expected a team in every simulation, got %d", position+1, total)
+		}
+	}
+	for _, team := range teams {
+		if len(points[team.ID]) != iterations {
+			t.Fatalf("Team %d: expected points for every simulation, got %d", team.ID, len(points[team.ID]))
+		}
+	}
+
+	// Team A has 3 points and one match to go, Team B none of either yet
+	for _, p := range points[1] {
+		if p != 3 && p != 4 && p != 6 {
+			t.Errorf("Expected Team A to finish on 3, 4 or 6 points, got %d", p)
+			break
+		}
+	}
+	if positions[1][0] <= positions[2][0] {
+		t.Errorf("Expected the stronger leader to finish first more often, got %v and %v", positions[1], positions[2])
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for p, want := range map[int]int{5: 1, 25: 3, 50: 5, 75: 8, 95: 10, 100: 10} {
+		if got := percentile(sorted, p); got != want {
+			t.Errorf("P%d: expected %d, got %d", p, want, got)
+		}
+	}
+}
+
+func TestGetPositionPredictions(t *testing.T) {
+	simulation, matchRepo := newSeededLeague(t, 42)
+	for range 3 {
+		if _, err := simulation.PlayNextWeek(1, nil); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	state, _ := simulation.GetCurrentState(1)
+	leagueRepo := &mockLeagueStateRepository{state: &state.LeagueState}
+	service := NewStandingsService(matchRepo, teamRepo, leagueRepo, &mockKnockoutRepository{matchRepo: matchRepo}, &mockGroupRepository{})
+
+	result, err := service.GetPositionPredictions(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	titles, err := service.GetPredictions(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Predictions) != 4 || result.Iterations != predictionIterations {
+		t.Fatalf("Expected predictions for 4 teams over %d seasons, got %+v", predictionIterations, result)
+	}
+
+	for i, prediction := range result.Predictions {
+		total := 0.0
+		for _, percentage := range prediction.Positions {
+			total += percentage
+		}
+		if math.Abs(total-100) > 0.5 {
+			t.Errorf("%s: expected the positions to add up to 100%%, got %.1f", prediction.TeamName, total)
+		}
+
+		// Without a knockout stage, finishing first is winning the title
+		if titles.Predictions[i].TeamID != prediction.TeamID || math.Abs(titles.Predictions[i].Percentage-prediction.Positions[0]) > 0.2 {
+			t.Errorf("%s: expected the title odds %.1f%% in first place, got %.1f%%",
+				prediction.TeamName, titles.Predictions[i].Percentage, prediction.Positions[0])
+		}
+
+		bands := prediction.Points
+		if bands.P5 > bands.P25 || bands.P25 > bands.Median || bands.Median > bands.P75 || bands.P75 > bands.P95 {
+			t.Errorf("%s: expected ordered percentiles, got %+v", prediction.TeamName, bands)
+		}
+		if prediction.ExpectedPoints < float64(bands.P5) || prediction.ExpectedPoints > float64(bands.P95) {
+			t.Errorf("%s: expected %.1f expected points between the outer percentiles %+v", prediction.TeamName, prediction.ExpectedPoints, bands)
+		}
+	}
+}
+
+func TestGetPredictionsCached(t *testing.T) {
+	simulation, matchRepo := newSeededLeague(t, 42)
+	if _, err := simulation.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	state, _ := simulation.GetCurrentState(1)
+	leagueRepo := &mockLeagueStateRepository{state: &state.LeagueState}
+	service := NewStandingsService(matchRepo, teamRepo, leagueRepo, &mockKnockoutRepository{matchRepo: matchRepo}, &mockGroupRepository{})
+
+	first, err := service.GetPredictions(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// A change that doesn't move the league to a new version is not seen
+	for i := range matchRepo.matches {
+		matchRepo.matches[i].Played, matchRepo.matches[i].HomeScore, matchRepo.matches[i].AwayScore = true, intPtr(5), intPtr(0)
+	}
+	if cached, _ := service.GetPredictions(1); cached != first {
+		t.Errorf("Expected the predictions kept, got %+v", cached)
+	}
+
+	// Every home side has won every match, so the title is settled once the new version is seen
+	leagueRepo.state.Version++
+	updated, _ := service.GetPredictions(1)
+	if updated == first || updated.Predictions[0].Percentage != 100 && updated.Predictions[0].Percentage != 0 {
+		t.Errorf("Expected predictions for the new results, got %+v", updated)
+	}
+
+	// So is a change to a team's strength
+	teamRepo.teams[0].Power = 10
+	if again, _ := service.GetPredictions(1); again == updated {
+		t.Error("Expected new predictions for the weakened team")
+	}
+}
+
+func TestGetStandingsRacesCached(t *testing.T) {
+	simulation, matchRepo := newSeededLeague(t, 42)
+	if _, err := simulation.PlayNextWeek(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	teamRepo := &mockTeamRepository{}
+	_ = teamRepo.SeedDefault(1)
+	state, _ := simulation.GetCurrentState(1)
+	leagueRepo := &mockLeagueStateRepository{state: &state.LeagueState}
+	service := NewStandingsService(matchRepo, teamRepo, leagueRepo, &mockKnockoutRepository{matchRepo: matchRepo}, &mockGroupRepository{})
+
+	first, err := service.GetStandings(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if again, _ := service.GetStandings(1); again[0].TitleRace != first[0].TitleRace {
+		t.Error("Expected the races kept")
+	}
+
+	// Every home side has won every match, so the title race is settled once the new version is seen
+	for i := range matchRepo.matches {
+		matchRepo.matches[i].Played, matchRepo.matches[i].HomeScore, matchRepo.matches[i].AwayScore = true, intPtr(5), intPtr(0)
+	}
+	leagueRepo.state.Version++
+	updated, _ := service.GetStandings(1)
+	if race := updated[0].TitleRace; race == first[0].TitleRace || !race.Clinched && !race.Eliminated {
+		t.Errorf("Expected the races of the new results, got %+v", race)
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+const (
+	// swissPots is the number of pots the league phase is drawn from
+	swissPots = 4
+	// swissMatchdays is the length of the league phase: one home and one away opponent from each pot
+	swissMatchdays = 2 * swissPots
+	// swissDrawAttempts bounds the redraws when a draw can't be spread over the matchdays
+	swissDrawAttempts = 50
+	// swissSearchSteps bounds each pairing search and each scheduling run
+	swissSearchSteps = 20000
+)
+
+// drawLeaguePhase draws a Swiss-system league phase. Teams are split into four pots by power,
+// the strongest in pot 1, and every team gets two different opponents from each pot (its own
+// included), hosting one and visiting the other, never against a team from its own country. The
+// matches are then spread over eight matchdays so that every team plays once a matchday.
+func drawLeaguePhase(rng *rand.Rand, teams []models.Team) ([]models.Match, error) {
+	if len(teams)%swissPots != 0 || len(teams) < 3*swissPots {
+		return nil, errors.New("the league phase needs four pots of at least three teams each")
+	}
+
+	sorted := sortByPower(teams)
+	size := len(sorted) / swissPots
+	pots := make([][]models.Team, swissPots)
+	for p := range pots {
+		pots[p] = sorted[p*size : (p+1)*size]
+	}
+
+	// A team needs two opponents from other countries in every pot
+	for _, team := range sorted {
+		for _, pot := range pots {
+			opponents := 0
+			for _, other := range pot {
+				if other.ID != team.ID && !sameCountry(&team, &other) {
+					opponents++
+				}
+			}
+			if opponents < 2 {
+				return nil, errors.New("too many teams from " + team.Country + " to keep them apart in the league phase")
+			}
+		}
+	}
+
+	for attempt := 0; attempt < swissDrawAttempts; attempt++ {
+		matches, ok := drawSwissOpponents(rng, pots)
+		if ok && scheduleMatchdays(rng, matches, swissMatchdays) {
+			sort.SliceStable(matches, func(i, j int) bool {
+				return matches[i].Week < matches[j].Week
+			})
+			return matches, nil
+		}
+	}
+	return nil, errors.New("no league phase draw keeps teams from the same country apart")
+}
+
+// drawSwissOpponents pairs every two pots. Within a pot each team hosts one team and visits
+// another; between two pots each team of one pot hosts a team of the other and visits a
+// different one. Pairs of pots never share a match, so they are drawn independently.
+func drawSwissOpponents(rng *rand.Rand, pots [][]models.Team) ([]models.Match, bool) {
+	var matches []models.Match
+	for p := range pots {
+		a := pots[p]
+
+		// Team i hosts guests[i]; no team hosts the team hosting it, so nobody meets twice
+		guests, ok := drawPairing(rng, len(a), func(guests []int, host, guest int) bool {
+			return guest != host && guests[guest] != host && !sameCountry(&a[host], &a[guest])
+		})
+		if !ok {
+			return nil, false
+		}
+		for host, guest := range guests {
+			matches = append(matches, models.Match{HomeTeamID: a[host].ID, AwayTeamID: a[guest].ID})
+		}
+
+		for q := p + 1; q < len(pots); q++ {
+			b := pots[q]
+			away, ok := drawPairing(rng, len(a), func(_ []int, host, guest int) bool {
+				return !sameCountry(&a[host], &b[guest])
+			})
+			if !ok {
+				return nil, false
+			}
+			// b's teams host a's teams other than the one they visit
+			home, ok := drawPairing(rng, len(b), func(_ []int, host, guest int) bool {
+				return away[guest] != host && !sameCountry(&b[host], &a[guest])
+			})
+			if !ok {
+				return nil, false
+			}
+			for i := range a {
+				matches = append(matches,
+					models.Match{HomeTeamID: a[i].ID, AwayTeamID: b[away[i]].ID},
+					models.Match{HomeTeamID: b[i].ID, AwayTeamID: a[home[i]].ID},
+				)
+			}
+		}
+	}
+	return matches, true
+}
+
+// drawPairing gives each of n hosts a different guest out of n, trying guests in random order
+// and backtracking when a host is left without an allowed guest. guests holds the guests of the
+// hosts drawn so far, -1 for the rest. Fails if the search takes more than swissSearchSteps.
+func drawPairing(rng *rand.Rand, n int, allowed func(guests []int, host, guest int) bool) ([]int, bool) {
+	guests := make([]int, n)
+	for i := range guests {
+		guests[i] = -1
+	}
+	taken := make([]bool, n)
+	steps := 0
+
+	var place func(host int) bool
+	place = func(host int) bool {
+		if host == n {
+			return true
+		}
+		for _, guest := range rng.Perm(n) {
+			if steps++; steps > swissSearchSteps {
+				return false
+			}
+			if taken[guest] || !allowed(guests, host, guest) {
+				continue
+			}
+			guests[host], taken[guest] = guest, true
+			if place(host + 1) {
+				return true
+			}
+			guests[host], taken[guest] = -1, false
+		}
+		return false
+	}
+
+	return guests, place(0)
+}
+
+// scheduleMatchdays sets each match's week so that every team plays once on each of the
+// matchdays. Matches are placed one by one on a matchday both teams have free; when there is
+// none, the match takes the matchday with the fewest clashes and the matches it displaces go
+// back in the queue, but never straight back to the matchday they were just moved from.
+// Returns false if the schedule hasn't settled after swissSearchSteps placements.
+func scheduleMatchdays(rng *rand.Rand, matches []models.Match, matchdays int) bool {
+	// Match each team plays on each matchday, -1 when free
+	playing := make(map[uint][]int)
+	for i := range matches {
+		matches[i].Week = 0
+		for _, id := range []uint{matches[i].HomeTeamID, matches[i].AwayTeamID} {
+			if playing[id] == nil {
+				playing[id] = make([]int, matchdays)
+				for day := range playing[id] {
+					playing[id][day] = -1
+				}
+			}
+		}
+	}
+
+	previous := make([]int, len(matches))
+	queue := rng.Perm(len(matches))
+	for steps := 0; len(queue) > 0; steps++ {
+		if steps == swissSearchSteps {
+			return false
+		}
+		i := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		match := &matches[i]
+		home, away := playing[match.HomeTeamID], playing[match.AwayTeamID]
+
+		best, fewest := -1, 3
+		for _, day := range rng.Perm(matchdays) {
+			clashes := 0
+			if home[day] >= 0 {
+				clashes++
+			}
+			if away[day] >= 0 {
+				clashes++
+			}
+			if clashes > 0 && day+1 == previous[i] {
+				continue
+			}
+			if clashes < fewest {
+				best, fewest = day, clashes
+			}
+		}
+
+		for _, j := range []int{home[best], away[best]} {
+			if j < 0 || matches[j].Week == 0 {
+				continue
+			}
+			displaced := &matches[j]
+			playing[displaced.HomeTeamID][best] = -1
+			playing[displaced.AwayTeamID][best] = -1
+			previous[j], displaced.Week = displaced.Week, 0
+			queue = append(queue, j)
+		}
+
+		home[best], away[best] = i, i
+		match.Week = best + 1
+	}
+	return true
+}
+
+// sameCountry reports whether two teams are known to come from the same country
+func sameCountry(a, b *models.Team) bool {
+	return a.Country != "" && a.Country == b.Country
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+	"github.com/zahidcakici/champions-league/internal/repository"
+)
+
+// swissTeams returns n teams with descending power, four per country
+func swissTeams(n int) []models.Team {
+	teams := make([]models.Team, n)
+	for i := range teams {
+		teams[i] = models.Team{
+			ID:      uint(i + 1),
+			Name:    fmt.Sprintf("Team %d", i+1),
+			Power:   100 - i,
+			Country: fmt.Sprintf("Country %d", i/4),
+		}
+	}
+	return teams
+}
+
+func TestDrawLeaguePhase(t *testing.T) {
+	teams := swissTeams(36)
+	byID := make(map[uint]models.Team, len(teams))
+	for _, team := range teams {
+		byID[team.ID] = team
+	}
+	// Pot 1 holds the nine strongest teams, pot 4 the nine weakest
+	pot := func(id uint) int {
+		return (100-byID[id].Power)/9 + 1
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		matches, err := drawLeaguePhase(rand.New(rand.NewSource(seed)), teams)
+		if err != nil {
+			t.Fatalf("Seed %d: expected no error, got %v", seed, err)
+		}
+		if len(matches) != 36*swissMatchdays/2 {
+			t.Fatalf("Seed %d: expected %d matches, got %d", seed, 36*swissMatchdays/2, len(matches))
+		}
+
+		home := make(map[uint]map[int]int)
+		away := make(map[uint]map[int]int)
+		met := make(map[[2]uint]bool)
+		played := make(map[[2]int]bool) // Team and week
+		for _, match := range matches {
+			h, a := match.HomeTeamID, match.AwayTeamID
+			if byID[h].Country == byID[a].Country {
+				t.Fatalf("Seed %d: %d and %d are both from %s", seed, h, a, byID[h].Country)
+			}
+			pair := [2]uint{min(h, a), max(h, a)}
+			if met[pair] {
+				t.Fatalf("Seed %d: %d and %d meet twice", seed, h, a)
+			}
+			met[pair] = true
+
+			if match.Week < 1 || match.Week > swissMatchdays {
+				t.Fatalf("Seed %d: match in week %d", seed, match.Week)
+			}
+			for _, id := range []uint{h, a} {
+				if played[[2]int{int(id), match.Week}] {
+					t.Fatalf("Seed %d: team %d plays twice in week %d", seed, id, match.Week)
+				}
+				played[[2]int{int(id), match.Week}] = true
+			}
+
+			if home[h] == nil {
+				home[h] = make(map[int]int)
+			}
+			if away[a] == nil {
+				away[a] = make(map[int]int)
+			}
+			home[h][pot(a)]++
+			away[a][pot(h)]++
+		}
+
+		// One home and one away opponent from each pot
+		for _, team := range teams {
+			for p := 1; p <= swissPots; p++ {
+				if home[team.ID][p] != 1 || away[team.ID][p] != 1 {
+					t.Fatalf("Seed %d: team %d has %d home and %d away opponents from pot %d",
+						seed, team.ID, home[team.ID][p], away[team.ID][p], p)
+				}
+			}
+		}
+	}
+}
+
+func TestDrawLeaguePhaseImpossible(t *testing.T) {
+	if _, err := drawLeaguePhase(rand.New(rand.NewSource(1)), swissTeams(10)); err == nil {
+		t.Error("Expected an error when the teams don't split into four pots")
+	}
+
+	// A pot of three teams from one country has nobody for them to play
+	teams := swissTeams(12)
+	for i := range teams {
+		teams[i].Country = "England"
+	}
+	if _, err := drawLeaguePhase(rand.New(rand.NewSource(1)), teams); err == nil {
+		t.Error("Expected an error when a pot has no opponents from other countries")
+	}
+}
+
+func TestLeaguePhaseFeedsPlayoff(t *testing.T) {
+	teamRepo := &mockTeamRepository{teams: swissTeams(36)}
+	teams := make(map[uint]models.Team)
+	for _, team := range teamRepo.teams {
+		teams[team.ID] = team
+	}
+	matchRepo := &mockMatchRepository{teams: teams}
+	leagueRepo := &mockLeagueStateRepository{state: &models.LeagueState{LeagueID: 1, Seed: 9}}
+	groupRepo := &mockGroupRepository{}
+	knockoutRepo := &mockKnockoutRepository{matchRepo: matchRepo}
+	seasonRepo, ratingRepo, eventRepo := &mockSeasonRepository{}, &mockRatingRepository{}, &mockMatchEventRepository{}
+	playerRepo, absenceRepo := &mockPlayerRepository{}, &mockAbsenceRepository{}
+	seasons := NewSeasonService(matchRepo, teamRepo, leagueRepo, seasonRepo, knockoutRepo)
+	knockout := NewKnockoutService(matchRepo, teamRepo, leagueRepo, knockoutRepo, groupRepo)
+	availability := NewAvailabilityService(playerRepo, absenceRepo, eventRepo, matchRepo, teamRepo, leagueRepo)
+	transactor := &mockTransactor{repos: repository.Repositories{
+		Teams: teamRepo, Matches: matchRepo, LeagueStates: leagueRepo, Seasons: seasonRepo, Knockout: knockoutRepo,
+		Groups: groupRepo, Ratings: ratingRepo, MatchEvents: eventRepo, Players: playerRepo, Absences: absenceRepo,
+	}}
+	ratings := NewRatingService(teamRepo, matchRepo, leagueRepo, ratingRepo, transactor)
+	simulation := NewSimulationService(matchRepo, teamRepo, leagueRepo, groupRepo, seasons, knockout, ratings, eventRepo,
+		availability, transactor)
+	standings := NewStandingsService(matchRepo, teamRepo, leagueRepo, knockoutRepo, groupRepo)
+
+	format, knockoutTeams := models.LeagueFormatSwiss, 16
+	if _, err := simulation.UpdateSettings(1, models.LeagueSettings{Format: &format, KnockoutTeams: &knockoutTeams}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := newFixtureService(teamRepo, matchRepo, leagueRepo, groupRepo).GenerateFixtures(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Eight league weeks, then the play-off, round of 16, quarter- and semi-finals over two legs and the final
+	state, _ := leagueRepo.Get(1)
+	if state.LeagueWeeks() != 8 || state.TotalWeeks != 8+2+7 {
+		t.Fatalf("Expected 8 league weeks and 17 in total, got %d/%d", state.LeagueWeeks(), state.TotalWeeks)
+	}
+
+	if _, err := simulation.PlayAllWeeks(1, nil); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	table, err := standings.GetStandings(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for i, standing := range table {
+		expected := ""
+		switch {
+		case i < 8:
+			expected = models.QualificationKnockout
+		case i < 24:
+			expected = models.QualificationPlayoff
+		}
+		if standing.Played != 8 || standing.Qualification != expected {
+			t.Fatalf("Place %d: expected 8 played and %q, got %d and %q", i+1, expected, standing.Played, standing.Qualification)
+		}
+	}
+
+	bracket, err := knockout.GetBracket(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(bracket.Rounds) != 5 || bracket.Rounds[0].Name != models.KnockoutPlayoffName ||
+		bracket.Rounds[1].Name != "Round of 16" || bracket.ChampionID == nil {
+		t.Fatalf("Unexpected bracket: %d rounds, champion %v", len(bracket.Rounds), bracket.ChampionID)
+	}
+
+	// The play-off pairs 9th with 24th, 10th with 23rd, ...
+	place := make(map[uint]int)
+	for i, standing := range table {
+		place[standing.TeamID] = i + 1
+	}
+	playoffWinners := make(map[uint]bool)
+	for slot, tie := range bracket.Rounds[0].Ties {
+		if place[tie.TeamAID] != 9+slot || place[tie.TeamBID] != 24-slot {
+			t.Errorf("Play-off tie %d: expected places %d and %d, got %d and %d",
+				slot, 9+slot, 24-slot, place[tie.TeamAID], place[tie.TeamBID])
+		}
+		playoffWinners[*tie.WinnerID] = true
+	}
+
+	// The round of 16 is the top eight against the play-off winners
+	for _, tie := range bracket.Rounds[1].Ties {
+		if place[tie.TeamAID] > 8 || !playoffWinners[tie.TeamBID] {
+			t.Errorf("Round of 16 tie %d-%d is not a direct qualifier against a play-off winner", tie.TeamAID, tie.TeamBID)
+		}
+	}
+}
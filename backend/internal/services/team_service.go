@@ -6,10 +6,10 @@ import (
 )
 
 type TeamService interface {
-	GetAllTeams() ([]models.Team, error)
-	CreateTeam(name string, power int) error
-	DeleteTeam(id uint) error
-	SeedTeams() error
+	GetAllTeams(leagueID uint) ([]models.Team, error)
+	CreateTeam(leagueID uint, name, country string, power, attack, defence int) (*models.Team, error)
+	DeleteTeam(leagueID, id uint) error
+	SeedTeams(leagueID uint) error
 }
 
 type teamService struct {
@@ -20,26 +20,32 @@ func NewTeamService(teamRepo repository.TeamRepository) TeamService {
 	return &teamService{teamRepo: teamRepo}
 }
 
-func (s *teamService) GetAllTeams() ([]models.Team, error) {
-	// Auto-seed if no teams exist
-	if err := s.teamRepo.SeedDefault(); err != nil {
-		return nil, err
-	}
-	return s.teamRepo.FindAll()
+func (s *teamService) GetAllTeams(leagueID uint) ([]models.Team, error) {
+	return s.teamRepo.FindAll(leagueID)
 }
 
-func (s *teamService) CreateTeam(name string, power int) error {
+// CreateTeam creates a team; attack and defence default to the power, and the power to their average
+func (s *teamService) CreateTeam(leagueID uint, name, country string, power, attack, defence int) (*models.Team, error) {
 	team := &models.Team{
-		Name:  name,
-		Power: power,
+		LeagueID: leagueID,
+		Name:     name,
+		Power:    power,
+		Attack:   attack,
+		Defence:  defence,
+		Country:  country,
+	}
+	team.FillStrengths()
+	if err := s.teamRepo.Create(team); err != nil {
+		return nil, err
 	}
-	return s.teamRepo.Create(team)
+	return team, nil
 }
 
-func (s *teamService) DeleteTeam(id uint) error {
-	return s.teamRepo.Delete(id)
+func (s *teamService) DeleteTeam(leagueID, id uint) error {
+	return s.teamRepo.Delete(leagueID, id)
 }
 
-func (s *teamService) SeedTeams() error {
-	return s.teamRepo.SeedDefault()
+// SeedTeams adds the default teams to a league without any
+func (s *teamService) SeedTeams(leagueID uint) error {
+	return s.teamRepo.SeedDefault(leagueID)
 }
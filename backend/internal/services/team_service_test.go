@@ -27,14 +27,14 @@ func (m *mockTeamRepository) Create(team *models.Team) error {
 	return nil
 }
 
-func (m *mockTeamRepository) FindAll() ([]models.Team, error) {
+func (m *mockTeamRepository) FindAll(_ uint) ([]models.Team, error) {
 	if m.findAllErr != nil {
 		return nil, m.findAllErr
 	}
 	return m.teams, nil
 }
 
-func (m *mockTeamRepository) FindByID(id uint) (*models.Team, error) {
+func (m *mockTeamRepository) FindByID(_, id uint) (*models.Team, error) {
 	for _, team := range m.teams {
 		if team.ID == id {
 			return &team, nil
@@ -43,7 +43,7 @@ func (m *mockTeamRepository) FindByID(id uint) (*models.Team, error) {
 	return nil, errors.New("team not found")
 }
 
-func (m *mockTeamRepository) FindByName(name string) (*models.Team, error) {
+func (m *mockTeamRepository) FindByName(_ uint, name string) (*models.Team, error) {
 	for _, team := range m.teams {
 		if team.Name == name {
 			return &team, nil
@@ -52,11 +52,31 @@ func (m *mockTeamRepository) FindByName(name string) (*models.Team, error) {
 	return nil, errors.New("team not found")
 }
 
-func (m *mockTeamRepository) Count() (int64, error) {
+func (m *mockTeamRepository) Count(_ uint) (int64, error) {
 	return int64(len(m.teams)), nil
 }
 
-func (m *mockTeamRepository) Delete(id uint) error {
+func (m *mockTeamRepository) UpdateRating(_, id uint, rating float64) error {
+	for i := range m.teams {
+		if m.teams[i].ID == id {
+			m.teams[i].Rating = rating
+			return nil
+		}
+	}
+	return errors.New("team not found")
+}
+
+func (m *mockTeamRepository) Update(team *models.Team) error {
+	for i := range m.teams {
+		if m.teams[i].ID == team.ID {
+			m.teams[i] = *team
+			return nil
+		}
+	}
+	return errors.New("team not found")
+}
+
+func (m *mockTeamRepository) Delete(_, id uint) error {
 	if m.deleteErr != nil {
 		return m.deleteErr
 	}
@@ -71,12 +91,12 @@ func (m *mockTeamRepository) Delete(id uint) error {
 	return nil
 }
 
-func (m *mockTeamRepository) DeleteAll() error {
+func (m *mockTeamRepository) DeleteAll(_ uint) error {
 	m.teams = []models.Team{}
 	return nil
 }
 
-func (m *mockTeamRepository) SeedDefault() error {
+func (m *mockTeamRepository) SeedDefault(leagueID uint) error {
 	m.seedCalled = true
 	if m.seedErr != nil {
 		return m.seedErr
@@ -86,6 +106,7 @@ func (m *mockTeamRepository) SeedDefault() error {
 		m.teams = models.DefaultTeams()
 		for i := range m.teams {
 			m.teams[i].ID = uint(i + 1)
+			m.teams[i].LeagueID = leagueID
 		}
 	}
 	return nil
@@ -95,28 +116,28 @@ func TestTeamService_GetAllTeams(t *testing.T) {
 	mockRepo := &mockTeamRepository{}
 	service := NewTeamService(mockRepo)
 
-	teams, err := service.GetAllTeams()
+	teams, err := service.GetAllTeams(1)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 
-	// Should auto-seed default teams
-	if !mockRepo.seedCalled {
-		t.Error("Expected SeedDefault to be called")
+	// Reading the teams never writes any: a league gets its default teams when it is set up
+	if mockRepo.seedCalled {
+		t.Error("Expected SeedDefault not to be called")
 	}
 
-	if len(teams) != 4 {
-		t.Errorf("Expected 4 default teams, got %d", len(teams))
+	if len(teams) != 0 {
+		t.Errorf("Expected no teams, got %d", len(teams))
 	}
 }
 
-func TestTeamService_GetAllTeams_SeedError(t *testing.T) {
+func TestTeamService_SeedTeams_Error(t *testing.T) {
 	mockRepo := &mockTeamRepository{
 		seedErr: errors.New("seed failed"),
 	}
 	service := NewTeamService(mockRepo)
 
-	_, err := service.GetAllTeams()
+	err := service.SeedTeams(1)
 	if err == nil {
 		t.Error("Expected error when seed fails")
 	}
@@ -128,7 +149,7 @@ func TestTeamService_GetAllTeams_FindAllError(t *testing.T) {
 	}
 	service := NewTeamService(mockRepo)
 
-	_, err := service.GetAllTeams()
+	_, err := service.GetAllTeams(1)
 	if err == nil {
 		t.Error("Expected error when FindAll fails")
 	}
@@ -138,10 +159,13 @@ func TestTeamService_CreateTeam(t *testing.T) {
 	mockRepo := &mockTeamRepository{}
 	service := NewTeamService(mockRepo)
 
-	err := service.CreateTeam("New Team", 75)
+	team, err := service.CreateTeam(1, "New Team", "", 75, 0, 0)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
+	if team.ID != 1 || team.Attack != 75 || team.Defence != 75 {
+		t.Errorf("Expected team 1 with attack and defence at its power, got %+v", team)
+	}
 
 	if len(mockRepo.teams) != 1 {
 		t.Errorf("Expected 1 team, got %d", len(mockRepo.teams))
@@ -162,12 +186,35 @@ func TestTeamService_CreateTeam_Error(t *testing.T) {
 	}
 	service := NewTeamService(mockRepo)
 
-	err := service.CreateTeam("New Team", 75)
+	_, err := service.CreateTeam(1, "New Team", "", 75, 0, 0)
 	if err == nil {
 		t.Error("Expected error when create fails")
 	}
 }
 
+func TestTeamService_CreateTeamWithAttackAndDefence(t *testing.T) {
+	mockRepo := &mockTeamRepository{}
+	service := NewTeamService(mockRepo)
+
+	// The power is derived from attack and defence
+	team, err := service.CreateTeam(1, "Entertainers", "", 0, 90, 61)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if team.Power != 76 || team.Attack != 90 || team.Defence != 61 {
+		t.Errorf("Expected power 76 from attack 90 and defence 61, got %+v", team)
+	}
+
+	// Either one defaults to the power
+	team, err = service.CreateTeam(1, "Wall", "", 70, 0, 88)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if team.Power != 70 || team.Attack != 70 || team.Defence != 88 {
+		t.Errorf("Expected attack 70 from the power, got %+v", team)
+	}
+}
+
 func TestTeamService_DeleteTeam(t *testing.T) {
 	mockRepo := &mockTeamRepository{
 		teams: []models.Team{
@@ -177,7 +224,7 @@ func TestTeamService_DeleteTeam(t *testing.T) {
 	}
 	service := NewTeamService(mockRepo)
 
-	err := service.DeleteTeam(1)
+	err := service.DeleteTeam(1, 1)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -197,7 +244,7 @@ func TestTeamService_DeleteTeam_Error(t *testing.T) {
 	}
 	service := NewTeamService(mockRepo)
 
-	err := service.DeleteTeam(1)
+	err := service.DeleteTeam(1, 1)
 	if err == nil {
 		t.Error("Expected error when delete fails")
 	}
@@ -207,7 +254,7 @@ func TestTeamService_SeedTeams(t *testing.T) {
 	mockRepo := &mockTeamRepository{}
 	service := NewTeamService(mockRepo)
 
-	err := service.SeedTeams()
+	err := service.SeedTeams(1)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -231,7 +278,7 @@ func TestTeamService_CreateMultipleTeams(t *testing.T) {
 	}
 
 	for _, tc := range teamsToCreate {
-		err := service.CreateTeam(tc.name, tc.power)
+		_, err := service.CreateTeam(1, tc.name, "", tc.power, 0, 0)
 		if err != nil {
 			t.Fatalf("Failed to create team %s: %v", tc.name, err)
 		}
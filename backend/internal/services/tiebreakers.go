@@ -0,0 +1,222 @@
+package services
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+// tiebreakChain is a league's tiebreaker rules together with the seed used for drawing lots
+type tiebreakChain struct {
+	rules []models.TiebreakerRule
+	seed  int64
+}
+
+// leagueTiebreakChain returns the tiebreaker chain configured on the league state
+func leagueTiebreakChain(state *models.LeagueState) tiebreakChain {
+	return tiebreakChain{rules: state.TiebreakerRules(), seed: state.Seed}
+}
+
+// validateTiebreakers checks a custom chain: known rules, each used once
+func validateTiebreakers(rules []models.TiebreakerRule) error {
+	if len(rules) == 0 {
+		return errors.New("custom tiebreakers need at least one rule")
+	}
+	seen := make(map[models.TiebreakerRule]bool, len(rules))
+	for _, rule := range rules {
+		if !rule.Valid() {
+			return errors.New("unknown tiebreaker rule: " + string(rule))
+		}
+		if seen[rule] {
+			return errors.New("duplicate tiebreaker rule: " + string(rule))
+		}
+		seen[rule] = true
+	}
+	return nil
+}
+
+// matchScore is a played result reduced to what the tiebreakers need
+type matchScore struct {
+	homeID, awayID       uint
+	homeScore, awayScore int
+}
+
+// playedScores collects the results of the played league matches
+func playedScores(matches []models.Match) []matchScore {
+	scores := make([]matchScore, 0, len(matches))
+	for _, m := range matches {
+		if m.Played && m.HomeScore != nil && m.AwayScore != nil && !m.IsKnockout() {
+			scores = append(scores, matchScore{
+				homeID:    m.HomeTeamID,
+				awayID:    m.AwayTeamID,
+				homeScore: *m.HomeScore,
+				awayScore: *m.AwayScore,
+			})
+		}
+	}
+	return scores
+}
+
+// rankStandings orders the table by points and breaks ties with the chain. Each team's DecidedBy
+// is set to the rule that ranked the team directly above it ahead, or left empty when still level.
+func rankStandings(standings []models.TeamStanding, scores []matchScore, chain tiebreakChain) {
+	r := &tableRanker{
+		standings: standings,
+		scores:    scores,
+		chain:     chain,
+		rules:     append([]models.TiebreakerRule{models.TiebreakerPoints}, chain.rules...),
+		awayGoals: make(map[uint]int),
+		awayWins:  make(map[uint]int),
+	}
+	for _, score := range scores {
+		r.awayGoals[score.awayID] += score.awayScore
+		if score.awayScore > score.homeScore {
+			r.awayWins[score.awayID]++
+		}
+	}
+
+	teams := make([]int, len(standings))
+	for i := range teams {
+		teams[i] = i
+	}
+	order, decidedBy := r.resolve(teams)
+
+	ranked := make([]models.TeamStanding, len(standings))
+	for pos, i := range order {
+		ranked[pos] = standings[i]
+		ranked[pos].DecidedBy = decidedBy[pos]
+	}
+	copy(standings, ranked)
+}
+
+// tableRanker holds what the tiebreakers look at while ranking one table
+type tableRanker struct {
+	standings []models.TeamStanding
+	scores    []matchScore
+	chain     tiebreakChain
+	rules     []models.TiebreakerRule
+	awayGoals map[uint]int
+	awayWins  map[uint]int
+}
+
+// resolve orders the given teams (indexes into the standings) by applying the rules in turn.
+// As soon as a rule splits the teams, each still-tied subgroup is resolved again from the top
+// of the chain, so head-to-head rules are re-applied to a mini-league of just those teams.
+// It returns the order and, per position, the rule that separated the team from the one above.
+func (r *tableRanker) resolve(teams []int) ([]int, []models.TiebreakerRule) {
+	decidedBy := make([]models.TiebreakerRule, len(teams))
+	if len(teams) < 2 {
+		return teams, decidedBy
+	}
+
+	for _, rule := range r.rules {
+		groups := r.split(teams, rule)
+		if len(groups) == 1 {
+			continue
+		}
+
+		order := make([]int, 0, len(teams))
+		decidedBy = decidedBy[:0]
+		for i, group := range groups {
+			subOrder, subDecidedBy := r.resolve(group)
+			if i > 0 {
+				subDecidedBy[0] = rule
+			}
+			order = append(order, subOrder...)
+			decidedBy = append(decidedBy, subDecidedBy...)
+		}
+		return order, decidedBy
+	}
+
+	// Nothing separates them: keep the incoming order
+	return teams, decidedBy
+}
+
+// split sorts the teams by the rule's value (best first) and groups the teams that are level on it
+func (r *tableRanker) split(teams []int, rule models.TiebreakerRule) [][]int {
+	values := r.values(teams, rule)
+
+	sorted := make([]int, len(teams))
+	copy(sorted, teams)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return values[sorted[i]] > values[sorted[j]]
+	})
+
+	var groups [][]int
+	for i, team := range sorted {
+		if i == 0 || values[team] != values[sorted[i-1]] {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], team)
+	}
+	return groups
+}
+
+// values computes the rule's value for each team, higher is better
+func (r *tableRanker) values(teams []int, rule models.TiebreakerRule) map[int]int64 {
+	values := make(map[int]int64, len(teams))
+
+	if rule.IsHeadToHead() {
+		miniLeague := r.miniLeague(teams)
+		for _, i := range teams {
+			h2h := miniLeague[r.standings[i].TeamID]
+			switch rule {
+			case models.TiebreakerHeadToHeadPoints:
+				values[i] = int64(h2h.Points)
+			case models.TiebreakerHeadToHeadGoalDifference:
+				values[i] = int64(h2h.GoalDifference)
+			case models.TiebreakerHeadToHeadGoalsFor:
+				values[i] = int64(h2h.GoalsFor)
+			case models.TiebreakerHeadToHeadAwayGoals:
+				values[i] = int64(h2h.awayGoals)
+			}
+		}
+		return values
+	}
+
+	for _, i := range teams {
+		standing := &r.standings[i]
+		switch rule {
+		case models.TiebreakerPoints:
+			values[i] = int64(standing.Points)
+		case models.TiebreakerGoalDifference:
+			values[i] = int64(standing.GoalDifference)
+		case models.TiebreakerGoalsFor:
+			values[i] = int64(standing.GoalsFor)
+		case models.TiebreakerAwayGoals:
+			values[i] = int64(r.awayGoals[standing.TeamID])
+		case models.TiebreakerWins:
+			values[i] = int64(standing.Won)
+		case models.TiebreakerAwayWins:
+			values[i] = int64(r.awayWins[standing.TeamID])
+		case models.TiebreakerDrawingOfLots:
+			values[i] = deriveSeed(r.chain.seed, int64(standing.TeamID))
+		}
+	}
+	return values
+}
+
+// miniLeagueStanding is a team's record in the matches between the tied teams only
+type miniLeagueStanding struct {
+	models.TeamStanding
+	awayGoals int
+}
+
+// miniLeague builds the table of the matches played between the given teams
+func (r *tableRanker) miniLeague(teams []int) map[uint]*miniLeagueStanding {
+	table := make(map[uint]*miniLeagueStanding, len(teams))
+	for _, i := range teams {
+		table[r.standings[i].TeamID] = &miniLeagueStanding{}
+	}
+
+	for _, score := range r.scores {
+		home, away := table[score.homeID], table[score.awayID]
+		if home == nil || away == nil {
+			continue
+		}
+		applyMatchResult(&home.TeamStanding, &away.TeamStanding, score.homeScore, score.awayScore)
+		away.awayGoals += score.awayScore
+	}
+	return table
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/zahidcakici/champions-league/internal/models"
+)
+
+func playedMatch(home, away uint, homeScore, awayScore int) models.Match {
+	return models.Match{HomeTeamID: home, AwayTeamID: away, HomeScore: intPtr(homeScore), AwayScore: intPtr(awayScore), Played: true}
+}
+
+func presetChain(preset string) tiebreakChain {
+	return leagueTiebreakChain(&models.LeagueState{TiebreakerPreset: preset})
+}
+
+func assertOrder(t *testing.T, standings []models.TeamStanding, ids []uint, decidedBy []models.TiebreakerRule) {
+	t.Helper()
+	for i := range ids {
+		if standings[i].TeamID != ids[i] || standings[i].DecidedBy != decidedBy[i] {
+			t.Errorf("Position %d: expected team %d (%q), got team %d (%q)",
+				i+1, ids[i], decidedBy[i], standings[i].TeamID, standings[i].DecidedBy)
+		}
+	}
+}
+
+func TestTiebreakerPresets(t *testing.T) {
+	teams := []models.Team{{ID: 1, Name: "X"}, {ID: 2, Name: "Y"}, {ID: 3, Name: "Z"}, {ID: 4, Name: "W"}}
+	// X and Y finish on 4 points; X won the meeting, Y has the better goal difference
+	matches := []models.Match{
+		playedMatch(1, 2, 1, 0),
+		playedMatch(2, 3, 5, 0),
+		playedMatch(1, 4, 0, 0),
+		playedMatch(4, 2, 0, 0),
+	}
+
+	t.Run("UEFA group stage", func(t *testing.T) {
+		standings := calculateStandings(teams, matches, presetChain(models.TiebreakerPresetUEFAGroupStage))
+		assertOrder(t, standings, []uint{1, 2, 4, 3},
+			[]models.TiebreakerRule{"", models.TiebreakerHeadToHeadPoints, models.TiebreakerPoints, models.TiebreakerPoints})
+	})
+
+	t.Run("Premier League", func(t *testing.T) {
+		standings := calculateStandings(teams, matches, presetChain(models.TiebreakerPresetPremierLeague))
+		assertOrder(t, standings, []uint{2, 1, 4, 3},
+			[]models.TiebreakerRule{"", models.TiebreakerGoalDifference, models.TiebreakerPoints, models.TiebreakerPoints})
+	})
+}
+
+func TestTiebreakerMiniLeagueReapplied(t *testing.T) {
+	teams := []models.Team{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}, {ID: 3, Name: "C"}, {ID: 4, Name: "D"}}
+	// A, B and C beat each other in a cycle and draw with D, all finishing on 4 points.
+	// Among the three A has the best goal difference; B and C are level until their own
+	// meeting is looked at in a two-team mini-league.
+	matches := []models.Match{
+		playedMatch(1, 2, 3, 0),
+		playedMatch(3, 1, 2, 1),
+		playedMatch(2, 3, 2, 0),
+		playedMatch(1, 4, 0, 0),
+		playedMatch(2, 4, 0, 0),
+		playedMatch(3, 4, 0, 0),
+	}
+
+	standings := calculateStandings(teams, matches, presetChain(models.TiebreakerPresetUEFAGroupStage))
+	assertOrder(t, standings, []uint{1, 2, 3, 4}, []models.TiebreakerRule{
+		"",
+		models.TiebreakerHeadToHeadGoalDifference,
+		models.TiebreakerHeadToHeadPoints,
+		models.TiebreakerPoints,
+	})
+}
+
+func TestTiebreakerDrawingOfLots(t *testing.T) {
+	teams := []models.Team{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}
+
+	leaders := make(map[uint]bool)
+	for seed := int64(0); seed < 20; seed++ {
+		chain := tiebreakChain{rules: models.TiebreakerPresets[models.TiebreakerPresetPremierLeague], seed: seed}
+		first := calculateStandings(teams, nil, chain)
+		second := calculateStandings(teams, nil, chain)
+
+		if first[0].TeamID != second[0].TeamID {
+			t.Fatalf("Seed %d: drawing of lots should be deterministic", seed)
+		}
+		if first[1].DecidedBy != models.TiebreakerDrawingOfLots {
+			t.Errorf("Seed %d: expected drawing of lots to decide, got %q", seed, first[1].DecidedBy)
+		}
+		leaders[first[0].TeamID] = true
+	}
+
+	if len(leaders) != 2 {
+		t.Errorf("Expected different seeds to favor different teams, got %v", leaders)
+	}
+}
+
+func TestTiebreakerLevelWithoutDecidingRule(t *testing.T) {
+	teams := []models.Team{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}
+	matches := []models.Match{playedMatch(1, 2, 1, 1)}
+	chain := tiebreakChain{rules: []models.TiebreakerRule{models.TiebreakerGoalDifference, models.TiebreakerWins}}
+
+	standings := calculateStandings(teams, matches, chain)
+	assertOrder(t, standings, []uint{1, 2}, []models.TiebreakerRule{"", ""})
+}